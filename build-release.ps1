@@ -19,6 +19,13 @@ if ($Version -eq "") {
     }
 }
 
+$Commit = git rev-parse --short HEAD 2>$null
+if (-not $Commit) {
+    $Commit = "unknown"
+}
+$BuildTime = (Get-Date).ToUniversalTime().ToString("yyyy-MM-ddTHH:mm:ssZ")
+$BuildInfo = "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/buildinfo"
+
 Write-Host "Building ETC Meisai Scraper $Version for Windows..." -ForegroundColor Cyan
 
 # Build for Windows amd64
@@ -30,7 +37,7 @@ $env:CGO_ENABLED = "0"
 
 $outputName = "etc_meisai_scraper-$Version-windows-amd64.exe"
 
-go build -o $outputName -ldflags="-s -w -X main.Version=$Version" .
+go build -o $outputName -ldflags="-s -w -X $BuildInfo.Version=$Version -X $BuildInfo.Commit=$Commit -X $BuildInfo.BuildTime=$BuildTime" .
 
 if ($LASTEXITCODE -eq 0) {
     Write-Host "✅ Build completed: $outputName" -ForegroundColor Green
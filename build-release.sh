@@ -5,6 +5,9 @@ set -e
 
 # Get version from git tag or use default
 VERSION=${1:-$(git describe --tags --always 2>/dev/null || echo "dev")}
+COMMIT=$(git rev-parse --short HEAD 2>/dev/null || echo "unknown")
+BUILD_TIME=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+BUILDINFO=github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/buildinfo
 
 echo "Building ETC Meisai Scraper ${VERSION} for Windows..."
 
@@ -12,7 +15,7 @@ echo "Building ETC Meisai Scraper ${VERSION} for Windows..."
 echo "Building Windows amd64..."
 GOOS=windows GOARCH=amd64 CGO_ENABLED=0 go build \
   -o "etc_meisai_scraper-${VERSION}-windows-amd64.exe" \
-  -ldflags="-s -w -X main.Version=${VERSION}" \
+  -ldflags="-s -w -X ${BUILDINFO}.Version=${VERSION} -X ${BUILDINFO}.Commit=${COMMIT} -X ${BUILDINFO}.BuildTime=${BUILD_TIME}" \
   .
 
 echo "✅ Build completed: etc_meisai_scraper-${VERSION}-windows-amd64.exe"
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"bufio"
 	"database/sql"
 	"flag"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/buildinfo"
 	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/grpc"
 	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/handlers"
 	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
@@ -19,8 +24,9 @@ func main() {
 	var (
 		useGRPC    = flag.Bool("grpc", true, "Use gRPC server (default: true)")
 		grpcPort   = flag.String("grpc-port", "50052", "gRPC server port for etc_meisai_scraper")
-		httpPort   = flag.String("http-port", "8080", "HTTP server port (legacy mode)")
+		httpPort   = flag.String("http-port", "8080", "HTTP server port (legacy mode, or the HTTP gateway in gRPC mode)")
 		showHelp   = flag.Bool("help", false, "Show help message")
+		encrypt    = flag.Bool("encrypt-accounts", false, "Encrypt accountID:password lines from stdin with ETC_CRED_KEY and exit")
 	)
 	flag.Parse()
 
@@ -29,16 +35,34 @@ func main() {
 		return
 	}
 
+	if *encrypt {
+		if err := encryptAccounts(os.Stdin, os.Stdout); err != nil {
+			log.Fatalf("Failed to encrypt accounts: %v", err)
+		}
+		return
+	}
+
+	// gRPCモードのHTTPゲートウェイはポートが指定された場合のみ起動する
+	gatewayPort := ""
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "http-port" {
+			gatewayPort = *httpPort
+		}
+	})
+
 	// 環境変数を優先
 	if envPort := os.Getenv("GRPC_PORT"); envPort != "" {
 		grpcPort = &envPort
 	}
 	if envPort := os.Getenv("HTTP_PORT"); envPort != "" {
 		httpPort = &envPort
+		gatewayPort = envPort
 	}
 
 	// ロガー設定
 	logger := log.New(os.Stdout, "[ETC-MEISAI] ", log.LstdFlags)
+	info := buildinfo.Get()
+	logger.Printf("ETC Meisai Scraper %s (commit %s, built %s)", info.Version, info.Commit, info.BuildTime)
 
 	// DB接続は不要（スクレイピング専用サービス）
 	var db *sql.DB
@@ -46,7 +70,7 @@ func main() {
 	if *useGRPC {
 		// gRPCサーバーモード（推奨）
 		logger.Println("Starting in gRPC server mode (recommended for desktop-server integration)")
-		runGRPCServer(db, logger, *grpcPort)
+		runGRPCServer(db, logger, *grpcPort, gatewayPort)
 	} else {
 		// HTTPサーバーモード（レガシー）
 		logger.Println("Starting in HTTP server mode (legacy)")
@@ -54,6 +78,34 @@ func main() {
 	}
 }
 
+// encryptAccounts は1行に1つのアカウント（accountID:password形式）を読み込み、
+// ETC_CRED_KEYで暗号化したenc:形式を1行ずつ出力する（空行と#で始まる行は無視）
+func encryptAccounts(r io.Reader, w io.Writer) error {
+	var accounts []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		accounts = append(accounts, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	encrypted, err := services.EncryptAccounts(accounts)
+	if err != nil {
+		return err
+	}
+	for _, account := range encrypted {
+		if _, err := fmt.Fprintln(w, account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func printHelp() {
 	log.Println("ETC Meisai Scraper - Standalone gRPC Server")
 	log.Println()
@@ -70,17 +122,32 @@ func printHelp() {
 	log.Println("  # Start with custom port")
 	log.Println("  etc_meisai_scraper.exe --grpc-port 50052")
 	log.Println()
+	log.Println("  # Start as gRPC server with the HTTP/JSON gateway")
+	log.Println("  etc_meisai_scraper.exe --http-port 8080")
+	log.Println()
 	log.Println("  # Start as HTTP server (legacy)")
 	log.Println("  etc_meisai_scraper.exe --grpc=false --http-port 8080")
 	log.Println()
+	log.Println("  # Encrypt accounts for ETC_CORP_ACCOUNTS / ETC_ACCOUNTS_FILE")
+	log.Println("  etc_meisai_scraper.exe --encrypt-accounts < accounts.txt > accounts.enc")
+	log.Println()
 	log.Println("Integration with desktop-server:")
 	log.Println("  This service is designed to run as a separate process and be called")
 	log.Println("  by desktop-server via gRPC. See README.md for integration details.")
 }
 
-func runGRPCServer(db *sql.DB, logger *log.Logger, port string) {
+// runGRPCServer はgRPCサーバーを起動する（httpPortを指定した場合はHTTPゲートウェイも起動する）
+func runGRPCServer(db *sql.DB, logger *log.Logger, port, httpPort string) {
 	server := grpc.NewServer(db, logger)
 
+	if httpPort != "" {
+		go func() {
+			if err := server.StartHTTP(httpPort); err != nil {
+				logger.Fatalf("Failed to start HTTP gateway: %v", err)
+			}
+		}()
+	}
+
 	// シグナルハンドリング
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
@@ -106,6 +173,9 @@ func runHTTPServer(db *sql.DB, logger *log.Logger, port string) {
 	http.HandleFunc("/api/download/sync", downloadHandler.DownloadSync)
 	http.HandleFunc("/api/download/async", downloadHandler.DownloadAsync)
 	http.HandleFunc("/api/download/status", downloadHandler.GetDownloadStatus)
+	if metrics := downloadService.Metrics(); metrics != nil {
+		http.Handle("/metrics", metrics)
+	}
 
 	logger.Printf("Starting HTTP server on port %s", port)
 	logger.Printf("GitHub repository: https://github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper")
@@ -113,6 +183,7 @@ func runHTTPServer(db *sql.DB, logger *log.Logger, port string) {
 	logger.Printf("  POST /api/download/sync  - 同期ダウンロード")
 	logger.Printf("  POST /api/download/async - 非同期ダウンロード")
 	logger.Printf("  GET  /api/download/status?job_id={id} - ステータス確認")
+	logger.Printf("  GET  /metrics - Prometheusメトリクス")
 
 	if err := http.ListenAndServe(":"+port, nil); err != nil {
 		logger.Fatalf("HTTP server failed to start: %v", err)
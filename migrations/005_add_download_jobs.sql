@@ -0,0 +1,20 @@
+-- Migration: Add download_jobs table
+-- Persists download job status so it survives process restarts.
+-- Jobs left in 'processing' or 'paused' are marked 'interrupted' on startup.
+
+CREATE TABLE IF NOT EXISTS download_jobs (
+    id VARCHAR(64) PRIMARY KEY COMMENT 'ジョブID',
+    status VARCHAR(20) NOT NULL COMMENT 'ジョブステータス: processing, paused, completed, failed, cancelled, interrupted',
+    progress INT NOT NULL DEFAULT 0 COMMENT '進捗（%）',
+    total_records INT NOT NULL DEFAULT 0 COMMENT '総レコード数',
+    error_message TEXT COMMENT 'エラーメッセージ',
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'ログイン確認のみのジョブ',
+    details TEXT COMMENT 'アカウント単位の状態（JSON）',
+    started_at TIMESTAMP NOT NULL COMMENT '開始時刻',
+    completed_at TIMESTAMP NULL COMMENT '完了時刻',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '作成日時',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新日時',
+    INDEX idx_status (status),
+    INDEX idx_started_at (started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+COMMENT='ダウンロードジョブ管理テーブル';
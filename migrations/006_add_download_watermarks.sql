@@ -0,0 +1,11 @@
+-- Migration: Add download_watermarks table
+-- Remembers the last date downloaded per account so that incremental mode
+-- (ETC_INCREMENTAL=true with ETC_INCREMENTAL_WITHOUT_SAVE=true) can start the next download from there.
+
+CREATE TABLE IF NOT EXISTS download_watermarks (
+    account_id VARCHAR(255) PRIMARY KEY COMMENT 'アカウントID',
+    last_download_date DATE NOT NULL COMMENT '最後にダウンロードに成功した期間の終了日',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '作成日時',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新日時'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+COMMENT='アカウントごとのダウンロード済み期間管理テーブル';
@@ -0,0 +1,11 @@
+-- Migration: Add account_run_status table
+-- Keeps the last run of each account (last success, last error, record count)
+-- so that GetAccountStatus survives process restarts.
+
+CREATE TABLE IF NOT EXISTS account_run_status (
+    account_id VARCHAR(255) PRIMARY KEY COMMENT 'アカウントID',
+    details TEXT NOT NULL COMMENT '最後の実行結果（JSON）',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '作成日時',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新日時'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+COMMENT='アカウントごとの最後の実行結果テーブル';
@@ -0,0 +1,57 @@
+// Package buildinfo はビルド時に -ldflags で埋め込まれるバージョン情報を保持する
+//
+//	go build -ldflags "-X github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/buildinfo.Version=v0.0.25 \
+//	  -X github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// リンカ（-X）で設定される値。未設定の場合はdev/unknownのまま
+var (
+	// Version はリリースバージョン（git describe --tags など）
+	Version = "dev"
+	// Commit はビルドしたgitコミット
+	Commit = "unknown"
+	// BuildTime はビルド日時（RFC3339）
+	BuildTime = "unknown"
+)
+
+// Info はビルド情報
+type Info struct {
+	Version   string
+	Commit    string
+	BuildTime string
+	GoVersion string
+}
+
+// Get はビルド情報を返す
+// -ldflagsでCommitが設定されていない場合は、go build が埋め込むVCS情報があればそれを使う
+func Get() Info {
+	info := Info{
+		Version:   orDefault(Version, "dev"),
+		Commit:    orDefault(Commit, "unknown"),
+		BuildTime: orDefault(BuildTime, "unknown"),
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			if setting.Key == "vcs.revision" && info.Commit == "unknown" {
+				info.Commit = setting.Value
+			}
+		}
+	}
+	return info
+}
+
+// orDefault は-Xで空文字が設定された場合も既定値を返す
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// healthCheckInterval は依存関係のヘルスチェックを実行する間隔
+	healthCheckInterval = 30 * time.Second
+	// healthCheckTimeout はDB Pingのタイムアウト
+	healthCheckTimeout = 5 * time.Second
+)
+
+// checkHealth は依存関係を確認してヘルスステータスを更新する
+//   - ""（サーバー全体）: DBにPingできればSERVING
+//   - DownloadService: DBに加えてPlaywrightドライバが利用可能ならSERVING
+func (s *Server) checkHealth(ctx context.Context) {
+	dbErr := pingDB(ctx, s.db)
+	browserErr := s.checkBrowser()
+
+	overall := healthpb.HealthCheckResponse_SERVING
+	if dbErr != nil {
+		overall = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	download := overall
+	if browserErr != nil {
+		download = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	// 状態が変わったときだけログに出す
+	if overall != s.lastHealth[""] && dbErr != nil {
+		s.logger.Printf("Health: database unavailable: %v", dbErr)
+	}
+	if download != s.lastHealth[pb.DownloadService_ServiceDesc.ServiceName] && browserErr != nil {
+		s.logger.Printf("Health: browser unavailable: %v", browserErr)
+	}
+	s.lastHealth[""] = overall
+	s.lastHealth[pb.DownloadService_ServiceDesc.ServiceName] = download
+
+	s.healthServer.SetServingStatus("", overall)
+	s.healthServer.SetServingStatus(pb.DownloadService_ServiceDesc.ServiceName, download)
+}
+
+// startHealthChecks は定期的なヘルスチェックを開始し、停止用の関数を返す
+func (s *Server) startHealthChecks(interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.runHealthCheck()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// runHealthCheck はタイムアウト付きでヘルスチェックを1回実行する
+func (s *Server) runHealthCheck() {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	s.checkHealth(ctx)
+}
+
+// pingDB はDBにPingする（DBを使用しない構成ではnil）
+func pingDB(ctx context.Context, db *sql.DB) error {
+	if db == nil {
+		return nil
+	}
+	return db.PingContext(ctx)
+}
+
+// defaultBrowserCheck はPlaywrightドライバの有無を確認する
+func defaultBrowserCheck() error {
+	return scraper.CheckPlaywrightDriver()
+}
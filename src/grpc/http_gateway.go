@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// httpReadHeaderTimeout はHTTPゲートウェイがリクエストヘッダーの受信を待つ最大時間
+const httpReadHeaderTimeout = 10 * time.Second
+
+// gatewayService はHTTPゲートウェイで公開するRPC（DownloadAsync・GetJobStatus・GetServerLogs）を
+// gRPCと同じインターセプタ（ログ・panicの変換・ETC_RPC_RATE）を通して呼び出す
+// それ以外のRPCはUnimplemented（HTTP 501）を返す
+type gatewayService struct {
+	pb.UnimplementedDownloadServiceServer
+	service      pb.DownloadServiceServer
+	interceptors []grpc.UnaryServerInterceptor
+}
+
+// call はinterceptorsを順に通してhandlerを呼び出す（grpc.ChainUnaryInterceptorと同じ順序）
+func (g *gatewayService) call(ctx context.Context, method string, req interface{}, handler grpc.UnaryHandler) (interface{}, error) {
+	info := &grpc.UnaryServerInfo{Server: g.service, FullMethod: method}
+	for i := len(g.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := g.interceptors[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return handler(ctx, req)
+}
+
+func (g *gatewayService) DownloadAsync(ctx context.Context, req *pb.DownloadRequest) (*pb.DownloadJobResponse, error) {
+	resp, err := g.call(ctx, pb.DownloadService_DownloadAsync_FullMethodName, req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return g.service.DownloadAsync(ctx, req.(*pb.DownloadRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.DownloadJobResponse), nil
+}
+
+func (g *gatewayService) GetJobStatus(ctx context.Context, req *pb.GetJobStatusRequest) (*pb.JobStatus, error) {
+	resp, err := g.call(ctx, pb.DownloadService_GetJobStatus_FullMethodName, req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return g.service.GetJobStatus(ctx, req.(*pb.GetJobStatusRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.JobStatus), nil
+}
+
+func (g *gatewayService) GetServerLogs(ctx context.Context, req *pb.GetServerLogsRequest) (*pb.GetServerLogsResponse, error) {
+	resp, err := g.call(ctx, pb.DownloadService_GetServerLogs_FullMethodName, req, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return g.service.GetServerLogs(ctx, req.(*pb.GetServerLogsRequest))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.GetServerLogsResponse), nil
+}
+
+// remoteAddr はHTTPリクエストの接続元をgRPCのpeerとして扱うためのnet.Addr
+type remoteAddr string
+
+func (a remoteAddr) Network() string { return "tcp" }
+func (a remoteAddr) String() string  { return string(a) }
+
+// HTTPHandler はHTTPゲートウェイのハンドラーを返す
+// download_api.yamlのHTTPルール（POST /etc_meisai_scraper/v1/download/async、
+// GET /etc_meisai_scraper/v1/download/jobs/{job_id}、GET /etc_meisai_scraper/v1/logs）で
+// 同じプロセスのgRPCサービスを呼び出すため、ジョブの状態はgRPCで取得した場合と常に一致する
+func (s *Server) HTTPHandler() (http.Handler, error) {
+	mux := runtime.NewServeMux()
+	gateway := &gatewayService{service: s.downloadService, interceptors: s.unaryInterceptors}
+	if err := pb.RegisterDownloadServiceHandlerServer(context.Background(), mux, gateway); err != nil {
+		return nil, fmt.Errorf("failed to register HTTP gateway: %w", err)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// RPCのログに接続元を出力できるよう、HTTPの接続元をpeerとして渡す
+		ctx := peer.NewContext(r.Context(), &peer.Peer{Addr: remoteAddr(r.RemoteAddr)})
+		mux.ServeHTTP(w, r.WithContext(ctx))
+	}), nil
+}
+
+// StartHTTP はHTTPゲートウェイを起動する（Stopで停止するまで戻らない）
+// gRPCサーバーと同じTLSの設定（ETC_TLS_CERT・ETC_TLS_KEY・ETC_TLS_CLIENT_CA）で待ち受ける
+func (s *Server) StartHTTP(port string) error {
+	if port == "" {
+		port = "8080"
+	}
+	if s.tlsErr != nil {
+		return fmt.Errorf("invalid TLS configuration: %w", s.tlsErr)
+	}
+
+	handler, err := s.HTTPHandler()
+	if err != nil {
+		return err
+	}
+	// Listenより前に登録し、起動中にStopが呼ばれた場合もServeがすぐに終わるようにする
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: httpReadHeaderTimeout}
+	s.httpMu.Lock()
+	s.httpServer = server
+	s.httpMu.Unlock()
+
+	if s.netListener == nil {
+		s.netListener = &DefaultNetListener{}
+	}
+	lis, err := s.netListener.Listen("tcp", ":"+port)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	if s.tlsConfig != nil {
+		lis = tls.NewListener(lis, s.tlsConfig)
+		s.logger.Printf("Starting HTTP gateway on port %s (TLS)", port)
+	} else {
+		s.logger.Printf("Starting HTTP gateway on port %s", port)
+	}
+
+	if err := server.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("HTTP gateway failed: %w", err)
+	}
+	return nil
+}
+
+// stopHTTP はHTTPゲートウェイを停止する（処理中のリクエストはctxの期限まで待つ）
+func (s *Server) stopHTTP(ctx context.Context) {
+	s.httpMu.Lock()
+	server := s.httpServer
+	s.httpMu.Unlock()
+	if server == nil {
+		return
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		s.logger.Printf("Warning: failed to stop HTTP gateway: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// redactedValue はログに出力しない値の置き換え
+const redactedValue = "*******"
+
+// RecoveryUnaryInterceptor はハンドラのpanicをInternalエラーに変換し、スタックトレースをログに出力する
+func RecoveryUnaryInterceptor(logger *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Printf("panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+				resp, err = nil, status.Errorf(codes.Internal, "internal error: %v", r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamInterceptor はストリームのハンドラのpanicをInternalエラーに変換する
+func RecoveryStreamInterceptor(logger *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Printf("panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+				err = status.Errorf(codes.Internal, "internal error: %v", r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// LoggingUnaryInterceptor はRPCごとにメソッド・所要時間・ステータスコード・接続元・リクエストをログに出力する
+// リクエストのパスワードとアカウント文字列（accountID:password）のパスワードはマスクする
+func LoggingUnaryInterceptor(logger *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		startedAt := time.Now()
+		resp, err := handler(ctx, req)
+		logger.Printf("rpc method=%s code=%s duration=%v peer=%s request=%s",
+			info.FullMethod, status.Code(err), time.Since(startedAt).Round(time.Millisecond), peerAddr(ctx), redactedRequest(req))
+		return resp, err
+	}
+}
+
+// LoggingStreamInterceptor はストリームごとにメソッド・所要時間・ステータスコード・接続元をログに出力する
+func LoggingStreamInterceptor(logger *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		startedAt := time.Now()
+		err := handler(srv, ss)
+		logger.Printf("rpc method=%s code=%s duration=%v peer=%s",
+			info.FullMethod, status.Code(err), time.Since(startedAt).Round(time.Millisecond), peerAddr(ss.Context()))
+		return err
+	}
+}
+
+// peerAddr は接続元のアドレスを返す（不明な場合は"unknown"）
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return "unknown"
+}
+
+// redactedRequest はパスワードをマスクしたリクエストをJSONで返す
+func redactedRequest(req interface{}) string {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return "-"
+	}
+	msg = proto.Clone(msg)
+	redactMessage(msg.ProtoReflect())
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return "-"
+	}
+	return string(data)
+}
+
+// redactMessage は名前にpasswordを含むフィールドをマスクし、accountsのアカウント文字列はパスワード部分をマスクする
+func redactMessage(m protoreflect.Message) {
+	// Rangeの途中でメッセージを変更できないため、設定されているフィールドを集めてから書き換える
+	var fields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+
+	for _, fd := range fields {
+		name := string(fd.Name())
+		switch {
+		case fd.IsMap():
+			continue
+		case fd.Kind() == protoreflect.StringKind && !fd.IsList() && strings.Contains(name, "password"):
+			m.Set(fd, protoreflect.ValueOfString(redactedValue))
+		case fd.Kind() == protoreflect.StringKind && fd.IsList() && name == "accounts":
+			list := m.Mutable(fd).List()
+			for i := 0; i < list.Len(); i++ {
+				list.Set(i, protoreflect.ValueOfString(redactAccount(list.Get(i).String())))
+			}
+		case fd.Kind() == protoreflect.MessageKind && fd.IsList():
+			list := m.Mutable(fd).List()
+			for i := 0; i < list.Len(); i++ {
+				redactMessage(list.Get(i).Message())
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			redactMessage(m.Mutable(fd).Message())
+		}
+	}
+}
+
+// redactAccount はaccountID:password形式のパスワード部分をマスクする
+func redactAccount(account string) string {
+	if accountID, _, found := strings.Cut(account, ":"); found {
+		return accountID + ":" + redactedValue
+	}
+	return account
+}
@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// defaultMaxMsgMB はgRPCメッセージの最大サイズ（MB）のデフォルト（gRPCのデフォルトと同じ4MB）
+const defaultMaxMsgMB = 4
+
+// maxMsgMBLimit はETC_GRPC_MAX_MSG_MBに指定できる上限（gRPCのメッセージ長はint32）
+const maxMsgMBLimit = 2047
+
+// getMaxMsgMB は環境変数ETC_GRPC_MAX_MSG_MBからgRPCメッセージの最大サイズ（MB）を取得
+// 未設定・不正値の場合はdefaultMaxMsgMB
+func getMaxMsgMB() int {
+	value := strings.TrimSpace(os.Getenv("ETC_GRPC_MAX_MSG_MB"))
+	if value == "" {
+		return defaultMaxMsgMB
+	}
+	mb, err := strconv.Atoi(value)
+	if err != nil || mb <= 0 || mb > maxMsgMBLimit {
+		log.Printf("[GRPC-SERVER] Invalid ETC_GRPC_MAX_MSG_MB value %q, using default: %d", value, defaultMaxMsgMB)
+		return defaultMaxMsgMB
+	}
+	return mb
+}
+
+// MaxMsgSize はサーバーが送受信するgRPCメッセージの最大サイズ（バイト、ETC_GRPC_MAX_MSG_MB）
+func MaxMsgSize() int {
+	return getMaxMsgMB() << 20
+}
+
+// messageSizeServerOptions はサーバーの送受信メッセージの最大サイズをMaxMsgSizeにするオプション
+func messageSizeServerOptions() []grpc.ServerOption {
+	size := MaxMsgSize()
+	return []grpc.ServerOption{grpc.MaxRecvMsgSize(size), grpc.MaxSendMsgSize(size)}
+}
+
+// ClientDialOptions はクライアント（desktop-serverなど）がサーバーと同じ最大メッセージサイズで
+// 送受信するためのオプション。クライアントの受信上限はデフォルト4MBのため、
+// ETC_GRPC_MAX_MSG_MBを大きくした場合はクライアントにも同じ値を設定する
+//
+//	conn, err := grpc.NewClient(addr, append(etcgrpc.ClientDialOptions(), creds)...)
+func ClientDialOptions() []grpc.DialOption {
+	size := MaxMsgSize()
+	return []grpc.DialOption{grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(size), grpc.MaxCallSendMsgSize(size))}
+}
@@ -0,0 +1,152 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"math"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RPCRate はメソッドごとのレート制限（PerSecondが0以下の場合は制限なし）
+type RPCRate struct {
+	PerSecond float64 // 1秒あたりに補充するトークン数
+	Burst     int     // 連続して受け付ける最大数（バケットの容量）
+}
+
+// defaultRPCRates はETC_RPC_RATEで指定しなかったメソッドのレート制限
+// ブラウザを起動するDownloadAsync・DownloadSyncのみ制限し、参照系のメソッドは制限しない
+var defaultRPCRates = map[string]RPCRate{
+	"DownloadAsync": {PerSecond: 0.2, Burst: 5},
+	"DownloadSync":  {PerSecond: 0.2, Burst: 5},
+}
+
+// RateLimiter はメソッドごとのトークンバケットでRPCの頻度を制限する（全クライアントで共有）
+type RateLimiter struct {
+	buckets map[string]*tokenBucket // メソッド名（DownloadAsyncなど） -> バケット
+}
+
+// NewRateLimiter はメソッド名（サービス名を除いた名前）ごとのレート制限からRateLimiterを作成する
+func NewRateLimiter(rates map[string]RPCRate) *RateLimiter {
+	l := &RateLimiter{buckets: make(map[string]*tokenBucket)}
+	for method, rate := range rates {
+		if rate.PerSecond <= 0 {
+			continue
+		}
+		burst := float64(max(rate.Burst, 1))
+		l.buckets[method] = &tokenBucket{rate: rate.PerSecond, burst: burst, tokens: burst, last: time.Now()}
+	}
+	return l
+}
+
+// Allow はRPCを受け付けてよければtrueを返す（fullMethodは/package.Service/Method形式）
+func (l *RateLimiter) Allow(fullMethod string) bool {
+	bucket, ok := l.buckets[path.Base(fullMethod)]
+	if !ok {
+		return true
+	}
+	return bucket.take(time.Now())
+}
+
+// UnaryInterceptor はレート制限を超えたRPCをResourceExhaustedで拒否する
+func (l *RateLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !l.Allow(info.FullMethod) {
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", path.Base(info.FullMethod))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor はレート制限を超えたストリームをResourceExhaustedで拒否する
+func (l *RateLimiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !l.Allow(info.FullMethod) {
+			return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", path.Base(info.FullMethod))
+		}
+		return handler(srv, ss)
+	}
+}
+
+// tokenBucket は経過時間に応じてトークンを補充し、1回のRPCごとに1つ消費する
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// getRPCRates は環境変数からメソッドごとのレート制限を取得（指定しなかったメソッドはdefaultRPCRates）
+// ETC_RPC_RATE: メソッド名=1秒あたりの回数[:バースト] のカンマ区切り（例: DownloadAsync=0.5:10,GetJobStatus=20）
+// 回数に0を指定するとそのメソッドは制限しない。不正なエントリは無視する
+func getRPCRates() map[string]RPCRate {
+	rates := make(map[string]RPCRate, len(defaultRPCRates))
+	for method, rate := range defaultRPCRates {
+		rates[method] = rate
+	}
+
+	value := strings.TrimSpace(os.Getenv("ETC_RPC_RATE"))
+	if value == "" {
+		return rates
+	}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		method, rate, ok := parseRPCRate(entry)
+		if !ok {
+			log.Printf("[GRPC-SERVER] Invalid ETC_RPC_RATE entry %q, ignoring (expected Method=perSecond[:burst])", entry)
+			continue
+		}
+		rates[method] = rate
+	}
+	return rates
+}
+
+// parseRPCRate はETC_RPC_RATEの1エントリ（Method=perSecond[:burst]）を解析する
+// バーストを省略した場合は1秒あたりの回数（切り上げ、最低1）
+func parseRPCRate(entry string) (string, RPCRate, bool) {
+	method, spec, found := strings.Cut(entry, "=")
+	method = strings.TrimSpace(method)
+	if !found || method == "" {
+		return "", RPCRate{}, false
+	}
+	perSecondStr, burstStr, hasBurst := strings.Cut(strings.TrimSpace(spec), ":")
+	perSecond, err := strconv.ParseFloat(strings.TrimSpace(perSecondStr), 64)
+	if err != nil || perSecond < 0 || math.IsInf(perSecond, 0) || math.IsNaN(perSecond) {
+		return "", RPCRate{}, false
+	}
+	burst := max(int(math.Ceil(perSecond)), 1)
+	if hasBurst {
+		burst, err = strconv.Atoi(strings.TrimSpace(burstStr))
+		if err != nil || burst < 1 {
+			return "", RPCRate{}, false
+		}
+	}
+	return method, RPCRate{PerSecond: perSecond, Burst: burst}, true
+}
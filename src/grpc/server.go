@@ -1,47 +1,130 @@
 package grpc
 
 import (
+	"context"
+	"crypto/tls"
 	"database/sql"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	reflector "github.com/yhonda-ohishi-pub-dev/grpc-service-reflector"
 	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
 	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
+// jobReaperInterval は終了済みジョブの削除処理を実行する間隔
+const jobReaperInterval = time.Minute
+
+// jobShutdownTimeout は停止時に実行中のジョブの終了を待つ最大時間
+const jobShutdownTimeout = 30 * time.Second
+
 // Server はgRPCサーバー
 type Server struct {
 	grpcServer      *grpc.Server
 	downloadService *services.DownloadServiceGRPC
 	logger          *log.Logger
+	logFile         io.Closer // ETC_LOG_OUTPUTでファイルを指定した場合の出力先（Stopで閉じる）
 	netListener     NetListener
+	stopJobReaper   func()
+	tlsConfig       *tls.Config // TLSで待ち受ける場合の設定（ETC_TLS_CERT・ETC_TLS_KEY、nilの場合は平文）
+	tlsErr          error       // ETC_TLS_*の設定エラー（平文で起動しないようStartで返す）
+
+	// unaryInterceptors はgRPCのRPCに適用するインターセプタ（HTTPゲートウェイ経由の呼び出しにも適用する）
+	unaryInterceptors []grpc.UnaryServerInterceptor
+	httpMu            sync.Mutex
+	httpServer        *http.Server // StartHTTPで起動したHTTPゲートウェイ（Stopで停止する）
+
+	db               *sql.DB
+	healthServer     *health.Server
+	checkBrowser     func() error
+	lastHealth       map[string]healthpb.HealthCheckResponse_ServingStatus
+	stopHealthChecks func()
 }
 
 // NewServerWithListener creates a new gRPC server with custom NetListener
 func NewServerWithListener(db *sql.DB, logger *log.Logger, listener NetListener) *Server {
+	var logFile io.Closer
 	if logger == nil {
-		logger = log.New(os.Stdout, "[GRPC-SERVER] ", log.LstdFlags|log.Lshortfile)
+		var output io.Writer
+		output, logFile = openLogOutput()
+		logger = log.New(output, "[GRPC-SERVER] ", log.LstdFlags|log.Lshortfile)
 	}
 
-	grpcServer := grpc.NewServer()
+	// RPCごとのログ出力（パスワードはマスク）、panicのInternalエラーへの変換、メソッドごとのレート制限（ETC_RPC_RATE）
+	limiter := NewRateLimiter(getRPCRates())
+	unaryInterceptors := []grpc.UnaryServerInterceptor{LoggingUnaryInterceptor(logger), RecoveryUnaryInterceptor(logger), limiter.UnaryInterceptor()}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(LoggingStreamInterceptor(logger), RecoveryStreamInterceptor(logger), limiter.StreamInterceptor()),
+	}
+	// 大きなDownloadResponseを返せるよう送受信メッセージの最大サイズを設定（ETC_GRPC_MAX_MSG_MB、デフォルト4MB）
+	opts = append(opts, messageSizeServerOptions()...)
+	// ETC_TLS_CERT・ETC_TLS_KEYが設定されていればTLS（ETC_TLS_CLIENT_CAでmTLS）、未設定なら平文
+	tlsConfig, tlsErr := serverTLSConfig()
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcServer := grpc.NewServer(opts...)
 	downloadService := services.NewDownloadServiceGRPC(db, logger)
 
 	// サービスを登録
 	pb.RegisterDownloadServiceServer(grpcServer, downloadService)
 
+	// ヘルスチェックサービスを登録（grpc.health.v1.Health）
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
 	// リフレクションを有効化（開発用）
 	reflection.Register(grpcServer)
 
-	return &Server{
+	s := &Server{
 		grpcServer:      grpcServer,
 		downloadService: downloadService,
 		logger:          logger,
+		logFile:         logFile,
 		netListener:     listener,
+		tlsConfig:       tlsConfig,
+		tlsErr:          tlsErr,
+		db:              db,
+		healthServer:    healthServer,
+		checkBrowser:    defaultBrowserCheck,
+		lastHealth:      make(map[string]healthpb.HealthCheckResponse_ServingStatus),
+
+		unaryInterceptors: unaryInterceptors,
+	}
+	s.runHealthCheck()
+	return s
+}
+
+// openLogOutput はETC_LOG_OUTPUTからロガーの出力先を開く
+// stdout（デフォルト）・stderr・ファイルパスを指定でき、ファイルは追記モードで開く（無ければ作成）
+// ファイルを開けない場合は標準出力に出力する。返り値のio.Closerはファイルの場合のみnil以外
+func openLogOutput() (io.Writer, io.Closer) {
+	output := strings.TrimSpace(os.Getenv("ETC_LOG_OUTPUT"))
+	switch strings.ToLower(output) {
+	case "", "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	}
+
+	file, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		log.Printf("[GRPC-SERVER] Failed to open ETC_LOG_OUTPUT %q, using stdout: %v", output, err)
+		return os.Stdout, nil
 	}
+	return file, file
 }
 
 // NewServer creates a new gRPC server
@@ -54,6 +137,9 @@ func (s *Server) Start(port string) error {
 	if port == "" {
 		port = "50051"
 	}
+	if s.tlsErr != nil {
+		return fmt.Errorf("invalid TLS configuration: %w", s.tlsErr)
+	}
 
 	if s.netListener == nil {
 		s.netListener = &DefaultNetListener{}
@@ -63,7 +149,11 @@ func (s *Server) Start(port string) error {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
-	s.logger.Printf("Starting gRPC server on port %s", port)
+	if s.tlsConfig != nil {
+		s.logger.Printf("Starting gRPC server on port %s (TLS)", port)
+	} else {
+		s.logger.Printf("Starting gRPC server on port %s", port)
+	}
 	s.logger.Printf("GitHub repository: https://github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper")
 
 	// Use grpc-service-reflector to automatically list all services and methods
@@ -78,11 +168,44 @@ func (s *Server) Start(port string) error {
 	// ログバッファにサーバー起動メッセージを追加
 	s.downloadService.LogMessage(fmt.Sprintf("Starting gRPC server on port %s", port))
 
+	// 前回までの実行で残った古いセッションフォルダを削除（ETC_CLEANUP_ON_START）
+	if _, err := s.downloadService.CleanupDownloadsOnStart(); err != nil {
+		s.logger.Printf("Warning: failed to clean up old session folders: %v", err)
+	}
+
+	// 終了済みジョブの定期削除を開始
+	s.stopJobReaper = s.downloadService.StartJobReaper(jobReaperInterval)
+
+	// 依存関係の定期ヘルスチェックを開始
+	s.stopHealthChecks = s.startHealthChecks(healthCheckInterval)
+
 	return s.grpcServer.Serve(lis)
 }
 
 // Stop はgRPCサーバーを停止
 func (s *Server) Stop() {
 	s.logger.Println("Stopping gRPC server...")
+	if s.stopJobReaper != nil {
+		s.stopJobReaper()
+	}
+	if s.stopHealthChecks != nil {
+		s.stopHealthChecks()
+	}
+	// 停止中であることをヘルスチェックで通知
+	s.healthServer.Shutdown()
+
+	// 実行中のジョブをキャンセルし、ゴルーチンの終了を待つ
+	ctx, cancel := context.WithTimeout(context.Background(), jobShutdownTimeout)
+	defer cancel()
+	// HTTPゲートウェイから新しいジョブを受け付けないよう先に止める
+	s.stopHTTP(ctx)
+	if err := s.downloadService.Shutdown(ctx); err != nil {
+		s.logger.Printf("Warning: %v", err)
+	}
+
 	s.grpcServer.GracefulStop()
+
+	if s.logFile != nil {
+		s.logFile.Close()
+	}
 }
\ No newline at end of file
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// serverTLSConfig は環境変数からTLSの設定を作成する（gRPCとHTTPゲートウェイで共通）
+// ETC_TLS_CERT・ETC_TLS_KEY（PEM形式のサーバー証明書と秘密鍵）が未設定の場合はnil（平文、ローカル開発用）
+// ETC_TLS_CLIENT_CA（PEM形式のCA証明書）を指定すると、そのCAが発行したクライアント証明書を必須にする（mTLS）
+func serverTLSConfig() (*tls.Config, error) {
+	certFile := strings.TrimSpace(os.Getenv("ETC_TLS_CERT"))
+	keyFile := strings.TrimSpace(os.Getenv("ETC_TLS_KEY"))
+	clientCAFile := strings.TrimSpace(os.Getenv("ETC_TLS_CLIENT_CA"))
+
+	if certFile == "" && keyFile == "" {
+		if clientCAFile != "" {
+			return nil, errors.New("ETC_TLS_CLIENT_CA requires ETC_TLS_CERT and ETC_TLS_KEY")
+		}
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both ETC_TLS_CERT and ETC_TLS_KEY must be set")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in client CA %s", clientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
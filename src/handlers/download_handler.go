@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -110,8 +111,8 @@ func (h *DownloadHandler) DownloadAsync(w http.ResponseWriter, r *http.Request)
 	// ジョブIDを生成
 	jobID := uuid.New().String()
 
-	// 非同期でダウンロード開始
-	h.DownloadService.ProcessAsync(jobID, req.Accounts, req.FromDate, req.ToDate)
+	// 非同期でダウンロード開始（ジョブはレスポンス後も続けるため、リクエストのキャンセルは引き継がない）
+	h.DownloadService.ProcessAsync(context.WithoutCancel(r.Context()), jobID, req.Accounts, req.FromDate, req.ToDate)
 
 	response := map[string]interface{}{
 		"job_id":  jobID,
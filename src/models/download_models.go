@@ -2,12 +2,22 @@ package models
 
 import "time"
 
+// AccountType はETCアカウントの種別（ログイン画面が異なる）
+type AccountType string
+
+const (
+	// AccountTypeCorporate は法人アカウント（デフォルト）
+	AccountTypeCorporate AccountType = "corporate"
+	// AccountTypePersonal は個人（非法人）アカウント
+	AccountTypePersonal AccountType = "personal"
+)
+
 // ETCAccount はETCアカウント情報
 type ETCAccount struct {
 	ID       string
 	Username string
 	Password string
-	Type     string // "corporate" or "personal"
+	Type     AccountType // 空の場合は法人として扱う
 }
 
 // ETCMeisaiRecord はETC明細レコード
@@ -22,13 +22,226 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// DownloadSyncで返す内容
+type OutputFormat int32
+
+const (
+	// OUTPUT_FORMAT_BOTHと同じ
+	OutputFormat_OUTPUT_FORMAT_UNSPECIFIED OutputFormat = 0
+	// 明細のみ返す（解析に成功したCSVはセッションフォルダから削除する）
+	OutputFormat_OUTPUT_FORMAT_RECORDS OutputFormat = 1
+	// CSVのパスのみ返す（明細を解析しないためrecordsは空、record_countはCSVの件数。card_numbersとは同時に指定できない）
+	OutputFormat_OUTPUT_FORMAT_CSV_PATH OutputFormat = 2
+	// 明細とCSVのパスを返す
+	OutputFormat_OUTPUT_FORMAT_BOTH OutputFormat = 3
+)
+
+// Enum value maps for OutputFormat.
+var (
+	OutputFormat_name = map[int32]string{
+		0: "OUTPUT_FORMAT_UNSPECIFIED",
+		1: "OUTPUT_FORMAT_RECORDS",
+		2: "OUTPUT_FORMAT_CSV_PATH",
+		3: "OUTPUT_FORMAT_BOTH",
+	}
+	OutputFormat_value = map[string]int32{
+		"OUTPUT_FORMAT_UNSPECIFIED": 0,
+		"OUTPUT_FORMAT_RECORDS":     1,
+		"OUTPUT_FORMAT_CSV_PATH":    2,
+		"OUTPUT_FORMAT_BOTH":        3,
+	}
+)
+
+func (x OutputFormat) Enum() *OutputFormat {
+	p := new(OutputFormat)
+	*p = x
+	return p
+}
+
+func (x OutputFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutputFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_download_proto_enumTypes[0].Descriptor()
+}
+
+func (OutputFormat) Type() protoreflect.EnumType {
+	return &file_download_proto_enumTypes[0]
+}
+
+func (x OutputFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutputFormat.Descriptor instead.
+func (OutputFormat) EnumDescriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{0}
+}
+
+// ダウンロード失敗の種別
+type ErrorCode int32
+
+const (
+	ErrorCode_ERROR_CODE_UNSPECIFIED ErrorCode = 0
+	// ログインを拒否された（認証情報の誤り）
+	ErrorCode_ERROR_CODE_AUTH_FAILED ErrorCode = 1
+	// サイトの応答やダウンロードがタイムアウトした
+	ErrorCode_ERROR_CODE_TIMEOUT ErrorCode = 2
+	// アカウントが指定・設定されていない
+	ErrorCode_ERROR_CODE_NO_ACCOUNTS ErrorCode = 3
+	// ダウンロードしたCSVを解析できなかった
+	ErrorCode_ERROR_CODE_PARSE_ERROR ErrorCode = 4
+	// 上記以外の理由でダウンロードに失敗した
+	ErrorCode_ERROR_CODE_DOWNLOAD_FAILED ErrorCode = 5
+	// 呼び出し元のdeadlineまでにジョブが終わらなかった（truncatedがtrue）
+	ErrorCode_ERROR_CODE_DEADLINE_EXCEEDED ErrorCode = 6
+	// ETCサイトがメンテナンス中だった
+	ErrorCode_ERROR_CODE_MAINTENANCE ErrorCode = 7
+)
+
+// Enum value maps for ErrorCode.
+var (
+	ErrorCode_name = map[int32]string{
+		0: "ERROR_CODE_UNSPECIFIED",
+		1: "ERROR_CODE_AUTH_FAILED",
+		2: "ERROR_CODE_TIMEOUT",
+		3: "ERROR_CODE_NO_ACCOUNTS",
+		4: "ERROR_CODE_PARSE_ERROR",
+		5: "ERROR_CODE_DOWNLOAD_FAILED",
+		6: "ERROR_CODE_DEADLINE_EXCEEDED",
+		7: "ERROR_CODE_MAINTENANCE",
+	}
+	ErrorCode_value = map[string]int32{
+		"ERROR_CODE_UNSPECIFIED":       0,
+		"ERROR_CODE_AUTH_FAILED":       1,
+		"ERROR_CODE_TIMEOUT":           2,
+		"ERROR_CODE_NO_ACCOUNTS":       3,
+		"ERROR_CODE_PARSE_ERROR":       4,
+		"ERROR_CODE_DOWNLOAD_FAILED":   5,
+		"ERROR_CODE_DEADLINE_EXCEEDED": 6,
+		"ERROR_CODE_MAINTENANCE":       7,
+	}
+)
+
+func (x ErrorCode) Enum() *ErrorCode {
+	p := new(ErrorCode)
+	*p = x
+	return p
+}
+
+func (x ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_download_proto_enumTypes[1].Descriptor()
+}
+
+func (ErrorCode) Type() protoreflect.EnumType {
+	return &file_download_proto_enumTypes[1]
+}
+
+func (x ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCode.Descriptor instead.
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{1}
+}
+
+// ログレベル
+type LogLevel int32
+
+const (
+	LogLevel_LOG_LEVEL_UNSPECIFIED LogLevel = 0
+	LogLevel_LOG_LEVEL_DEBUG       LogLevel = 1
+	LogLevel_LOG_LEVEL_INFO        LogLevel = 2
+	LogLevel_LOG_LEVEL_WARN        LogLevel = 3
+	LogLevel_LOG_LEVEL_ERROR       LogLevel = 4
+)
+
+// Enum value maps for LogLevel.
+var (
+	LogLevel_name = map[int32]string{
+		0: "LOG_LEVEL_UNSPECIFIED",
+		1: "LOG_LEVEL_DEBUG",
+		2: "LOG_LEVEL_INFO",
+		3: "LOG_LEVEL_WARN",
+		4: "LOG_LEVEL_ERROR",
+	}
+	LogLevel_value = map[string]int32{
+		"LOG_LEVEL_UNSPECIFIED": 0,
+		"LOG_LEVEL_DEBUG":       1,
+		"LOG_LEVEL_INFO":        2,
+		"LOG_LEVEL_WARN":        3,
+		"LOG_LEVEL_ERROR":       4,
+	}
+)
+
+func (x LogLevel) Enum() *LogLevel {
+	p := new(LogLevel)
+	*p = x
+	return p
+}
+
+func (x LogLevel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LogLevel) Descriptor() protoreflect.EnumDescriptor {
+	return file_download_proto_enumTypes[2].Descriptor()
+}
+
+func (LogLevel) Type() protoreflect.EnumType {
+	return &file_download_proto_enumTypes[2]
+}
+
+func (x LogLevel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LogLevel.Descriptor instead.
+func (LogLevel) EnumDescriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{2}
+}
+
 // ダウンロードリクエスト
 type DownloadRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Accounts      []string               `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"`
-	FromDate      string                 `protobuf:"bytes,2,opt,name=from_date,json=fromDate,proto3" json:"from_date,omitempty"`
-	ToDate        string                 `protobuf:"bytes,3,opt,name=to_date,json=toDate,proto3" json:"to_date,omitempty"`
-	Mode          string                 `protobuf:"bytes,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Accounts []string               `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	FromDate string                 `protobuf:"bytes,2,opt,name=from_date,json=fromDate,proto3" json:"from_date,omitempty"`
+	ToDate   string                 `protobuf:"bytes,3,opt,name=to_date,json=toDate,proto3" json:"to_date,omitempty"`
+	Mode     string                 `protobuf:"bytes,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	// trueの場合、不正な形式のアカウントが1つでもあればジョブを開始せずInvalidArgumentを返す
+	// falseの場合、不正な形式のアカウントをスキップしてwarningsで報告する
+	StrictAccounts bool `protobuf:"varint,5,opt,name=strict_accounts,json=strictAccounts,proto3" json:"strict_accounts,omitempty"`
+	// trueの場合、各アカウントのログインのみ確認しダウンロードしない（認証情報の事前確認用）
+	DryRun bool `protobuf:"varint,6,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Playwrightの操作タイムアウト（ミリ秒、5000〜300000）。未指定時はETC_TIMEOUT_MS
+	TimeoutMs int32 `protobuf:"varint,7,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	// ブラウザのHeadlessモード。未指定時はETC_HEADLESS（優先順位: リクエスト > ETC_HEADLESS > true）
+	Headless *bool `protobuf:"varint,8,opt,name=headless,proto3,oneof" json:"headless,omitempty"`
+	// ジョブ終了時（completed/partial/failed/cancelled）に結果をJSONでPOSTするURL（http/https）
+	// 送信内容: {"job_id", "status", "record_count", "failed_accounts": [{"account_id", "reason"}]}
+	CallbackUrl string `protobuf:"bytes,9,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`
+	// 指定した場合、同じキーのジョブが実行中またはETC_JOB_TTL以内に終了していれば
+	// 新しいジョブを開始せずそのジョブのIDを返す（DownloadAsyncのみ、リトライによる重複実行の防止用）
+	IdempotencyKey string `protobuf:"bytes,10,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// 指定した場合、これらのETCカード番号の明細のみ返す（空の場合はすべてのカード）
+	// 全角・半角、空白・ハイフンの違いは無視する
+	CardNumbers []string `protobuf:"bytes,11,rep,name=card_numbers,json=cardNumbers,proto3" json:"card_numbers,omitempty"`
+	// 指定した場合、ETC_ACCOUNT_GROUPSのこのグループに属する設定済みアカウントを使う（accountsとは同時に指定できない）
+	Group string `protobuf:"bytes,12,opt,name=group,proto3" json:"group,omitempty"`
+	// DownloadSyncで明細をレスポンスに含める件数の上限（0の場合は無制限）
+	// 超えた場合は明細を返さずrecord_count・job_idとtruncated=trueのみを返す（GetJobResult・ExportJobCSVで取得）
+	MaxInlineRecords int32 `protobuf:"varint,13,opt,name=max_inline_records,json=maxInlineRecords,proto3" json:"max_inline_records,omitempty"`
+	// trueの場合、明細に加えて利用証明書のPDFもセッションフォルダに保存する
+	// 保存したPDFのパスはJobStatus.account_results[].certificate_pathsで確認できる
+	DownloadCertificates bool `protobuf:"varint,14,opt,name=download_certificates,json=downloadCertificates,proto3" json:"download_certificates,omitempty"`
+	// DownloadSyncで返す内容（未指定の場合はOUTPUT_FORMAT_BOTH）
+	OutputFormat  OutputFormat `protobuf:"varint,15,opt,name=output_format,json=outputFormat,proto3,enum=etc_meisai.download.v1.OutputFormat" json:"output_format,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -91,14 +304,109 @@ func (x *DownloadRequest) GetMode() string {
 	return ""
 }
 
+func (x *DownloadRequest) GetStrictAccounts() bool {
+	if x != nil {
+		return x.StrictAccounts
+	}
+	return false
+}
+
+func (x *DownloadRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *DownloadRequest) GetTimeoutMs() int32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *DownloadRequest) GetHeadless() bool {
+	if x != nil && x.Headless != nil {
+		return *x.Headless
+	}
+	return false
+}
+
+func (x *DownloadRequest) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *DownloadRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *DownloadRequest) GetCardNumbers() []string {
+	if x != nil {
+		return x.CardNumbers
+	}
+	return nil
+}
+
+func (x *DownloadRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *DownloadRequest) GetMaxInlineRecords() int32 {
+	if x != nil {
+		return x.MaxInlineRecords
+	}
+	return 0
+}
+
+func (x *DownloadRequest) GetDownloadCertificates() bool {
+	if x != nil {
+		return x.DownloadCertificates
+	}
+	return false
+}
+
+func (x *DownloadRequest) GetOutputFormat() OutputFormat {
+	if x != nil {
+		return x.OutputFormat
+	}
+	return OutputFormat_OUTPUT_FORMAT_UNSPECIFIED
+}
+
 // ダウンロードレスポンス
 type DownloadResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	RecordCount   int32                  `protobuf:"varint,2,opt,name=record_count,json=recordCount,proto3" json:"record_count,omitempty"`
-	CsvPath       string                 `protobuf:"bytes,3,opt,name=csv_path,json=csvPath,proto3" json:"csv_path,omitempty"`
-	Records       []*ETCMeisaiRecord     `protobuf:"bytes,4,rep,name=records,proto3" json:"records,omitempty"`
-	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Success     bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	RecordCount int32                  `protobuf:"varint,2,opt,name=record_count,json=recordCount,proto3" json:"record_count,omitempty"`
+	CsvPath     string                 `protobuf:"bytes,3,opt,name=csv_path,json=csvPath,proto3" json:"csv_path,omitempty"`
+	Records     []*ETCMeisaiRecord     `protobuf:"bytes,4,rep,name=records,proto3" json:"records,omitempty"`
+	// エラーの詳細メッセージ（successがtrueの場合は空、error_messageと同じ。互換性のため残す）
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// 失敗の種別（successがtrueの場合はERROR_CODE_UNSPECIFIED）
+	ErrorCode ErrorCode `protobuf:"varint,6,opt,name=error_code,json=errorCode,proto3,enum=etc_meisai.download.v1.ErrorCode" json:"error_code,omitempty"`
+	// recordsがrecord_countの一部またはすべてを含まない場合true
+	// - 呼び出し元のdeadlineまでにジョブが終わらず、それまでに完了したアカウントの明細のみを返した（error_codeがERROR_CODE_DEADLINE_EXCEEDED）
+	// - 明細がmax_inline_recordsを超えたため返さなかった（successは変わらない。job_idでGetJobResult・ExportJobCSVから取得する）
+	Truncated bool `protobuf:"varint,7,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	// 明細を記録したジョブのID（GetJobResult・ExportJobCSVに指定できる）
+	JobId string `protobuf:"bytes,8,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// ダウンロードしたCSVのパス（アカウントごと、OUTPUT_FORMAT_RECORDSの場合は空）
+	// csv_pathは最初のアカウントのCSVのパス
+	CsvPaths []string `protobuf:"bytes,9,rep,name=csv_paths,json=csvPaths,proto3" json:"csv_paths,omitempty"`
+	// ジョブで使った期間（YYYY-MM-DD。from_date・to_dateを省略した場合はサーバーで決めた既定値）
+	// 増分モード（ETC_INCREMENTALとETC_INCREMENTAL_WITHOUT_SAVE）では、前回ダウンロードしたアカウントはfrom_dateより後の日付から取得する
+	FromDate string `protobuf:"bytes,10,opt,name=from_date,json=fromDate,proto3" json:"from_date,omitempty"`
+	ToDate   string `protobuf:"bytes,11,opt,name=to_date,json=toDate,proto3" json:"to_date,omitempty"`
+	// error_codeの失敗の詳細メッセージ（successがtrueの場合は空）
+	ErrorMessage  string `protobuf:"bytes,12,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -168,12 +476,66 @@ func (x *DownloadResponse) GetError() string {
 	return ""
 }
 
+func (x *DownloadResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+func (x *DownloadResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *DownloadResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *DownloadResponse) GetCsvPaths() []string {
+	if x != nil {
+		return x.CsvPaths
+	}
+	return nil
+}
+
+func (x *DownloadResponse) GetFromDate() string {
+	if x != nil {
+		return x.FromDate
+	}
+	return ""
+}
+
+func (x *DownloadResponse) GetToDate() string {
+	if x != nil {
+		return x.ToDate
+	}
+	return ""
+}
+
+func (x *DownloadResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
 // ダウンロードジョブレスポンス
 type DownloadJobResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	JobId  string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// 開始したジョブの期間を含む（例: "Download job started for 2024-01-01 to 2024-01-31 (default from_date)"）
+	Message  string   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Warnings []string `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// ジョブで使う期間（DownloadResponseのfrom_date・to_dateと同じ。idempotency_keyで既存のジョブを返した場合はそのジョブの期間）
+	FromDate      string `protobuf:"bytes,5,opt,name=from_date,json=fromDate,proto3" json:"from_date,omitempty"`
+	ToDate        string `protobuf:"bytes,6,opt,name=to_date,json=toDate,proto3" json:"to_date,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -229,6 +591,27 @@ func (x *DownloadJobResponse) GetMessage() string {
 	return ""
 }
 
+func (x *DownloadJobResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *DownloadJobResponse) GetFromDate() string {
+	if x != nil {
+		return x.FromDate
+	}
+	return ""
+}
+
+func (x *DownloadJobResponse) GetToDate() string {
+	if x != nil {
+		return x.ToDate
+	}
+	return ""
+}
+
 // ジョブステータス取得リクエスト
 type GetJobStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -274,35 +657,2052 @@ func (x *GetJobStatusRequest) GetJobId() string {
 	return ""
 }
 
-// ジョブステータス
-type JobStatus struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Progress      int32                  `protobuf:"varint,3,opt,name=progress,proto3" json:"progress,omitempty"`
-	TotalRecords  int32                  `protobuf:"varint,4,opt,name=total_records,json=totalRecords,proto3" json:"total_records,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
-	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+// ジョブの状態の変化待ちリクエスト
+type WaitForJobRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	JobId string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// 前回受け取ったstatus。指定した場合、現在のstatusと異なればすぐに返す（取得の合間の変化を見逃さないため）
+	LastStatus    string `protobuf:"bytes,2,opt,name=last_status,json=lastStatus,proto3" json:"last_status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobStatus) Reset() {
-	*x = JobStatus{}
+func (x *WaitForJobRequest) Reset() {
+	*x = WaitForJobRequest{}
+	mi := &file_download_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WaitForJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaitForJobRequest) ProtoMessage() {}
+
+func (x *WaitForJobRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_download_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaitForJobRequest.ProtoReflect.Descriptor instead.
+func (*WaitForJobRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WaitForJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *WaitForJobRequest) GetLastStatus() string {
+	if x != nil {
+		return x.LastStatus
+	}
+	return ""
+}
+
+// ジョブステータス一括取得リクエスト
+type GetJobStatusesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobIds        []string               `protobuf:"bytes,1,rep,name=job_ids,json=jobIds,proto3" json:"job_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobStatusesRequest) Reset() {
+	*x = GetJobStatusesRequest{}
+	mi := &file_download_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobStatusesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobStatusesRequest) ProtoMessage() {}
+
+func (x *GetJobStatusesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobStatusesRequest.ProtoReflect.Descriptor instead.
+func (*GetJobStatusesRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetJobStatusesRequest) GetJobIds() []string {
+	if x != nil {
+		return x.JobIds
+	}
+	return nil
+}
+
+// ジョブステータス一括取得レスポンス（job_idsと同じ順）
+type GetJobStatusesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*JobStatusEntry      `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobStatusesResponse) Reset() {
+	*x = GetJobStatusesResponse{}
+	mi := &file_download_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobStatusesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobStatusesResponse) ProtoMessage() {}
+
+func (x *GetJobStatusesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobStatusesResponse.ProtoReflect.Descriptor instead.
+func (*GetJobStatusesResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetJobStatusesResponse) GetEntries() []*JobStatusEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// 一括取得したジョブ1件分のステータス
+type JobStatusEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`  // ジョブが存在しない（期限切れで削除された場合を含む）場合false
+	Status        *JobStatus             `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // foundがfalseの場合は未設定
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStatusEntry) Reset() {
+	*x = JobStatusEntry{}
+	mi := &file_download_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatusEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatusEntry) ProtoMessage() {}
+
+func (x *JobStatusEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatusEntry.ProtoReflect.Descriptor instead.
+func (*JobStatusEntry) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *JobStatusEntry) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobStatusEntry) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *JobStatusEntry) GetStatus() *JobStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+// ジョブ一時停止リクエスト
+type PauseJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseJobRequest) Reset() {
+	*x = PauseJobRequest{}
+	mi := &file_download_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseJobRequest) ProtoMessage() {}
+
+func (x *PauseJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseJobRequest.ProtoReflect.Descriptor instead.
+func (*PauseJobRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PauseJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// ジョブ再開リクエスト
+type ResumeJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeJobRequest) Reset() {
+	*x = ResumeJobRequest{}
+	mi := &file_download_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeJobRequest) ProtoMessage() {}
+
+func (x *ResumeJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeJobRequest.ProtoReflect.Descriptor instead.
+func (*ResumeJobRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ResumeJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// ジョブキャンセルリクエスト
+type CancelJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobRequest) Reset() {
+	*x = CancelJobRequest{}
+	mi := &file_download_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobRequest) ProtoMessage() {}
+
+func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CancelJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// ジョブ結果取得リクエスト
+type GetJobResultRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // 1ページの件数（デフォルト: 100、最大: 1000）
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // 前回のレスポンスのnext_page_token（未指定時は先頭から）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobResultRequest) Reset() {
+	*x = GetJobResultRequest{}
+	mi := &file_download_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobResultRequest) ProtoMessage() {}
+
+func (x *GetJobResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobResultRequest.ProtoReflect.Descriptor instead.
+func (*GetJobResultRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetJobResultRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *GetJobResultRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetJobResultRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// ジョブ結果取得レスポンス
+type GetJobResultResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*ETCMeisaiRecord     `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // 次のページのトークン（最後のページでは空）
+	TotalRecords  int32                  `protobuf:"varint,3,opt,name=total_records,json=totalRecords,proto3" json:"total_records,omitempty"`     // ジョブの明細の総件数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobResultResponse) Reset() {
+	*x = GetJobResultResponse{}
+	mi := &file_download_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobResultResponse) ProtoMessage() {}
+
+func (x *GetJobResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobResultResponse.ProtoReflect.Descriptor instead.
+func (*GetJobResultResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetJobResultResponse) GetRecords() []*ETCMeisaiRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *GetJobResultResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetJobResultResponse) GetTotalRecords() int32 {
+	if x != nil {
+		return x.TotalRecords
+	}
+	return 0
+}
+
+// ジョブ明細CSVエクスポートリクエスト
+type ExportJobCSVRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportJobCSVRequest) Reset() {
+	*x = ExportJobCSVRequest{}
+	mi := &file_download_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportJobCSVRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportJobCSVRequest) ProtoMessage() {}
+
+func (x *ExportJobCSVRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportJobCSVRequest.ProtoReflect.Descriptor instead.
+func (*ExportJobCSVRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ExportJobCSVRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// ジョブ明細CSVの断片（順に連結するとCSV全体になる）
+type ExportJobCSVChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportJobCSVChunk) Reset() {
+	*x = ExportJobCSVChunk{}
+	mi := &file_download_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportJobCSVChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportJobCSVChunk) ProtoMessage() {}
+
+func (x *ExportJobCSVChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportJobCSVChunk.ProtoReflect.Descriptor instead.
+func (*ExportJobCSVChunk) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ExportJobCSVChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// ジョブのファイル一覧取得リクエスト
+type GetJobFilesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobFilesRequest) Reset() {
+	*x = GetJobFilesRequest{}
+	mi := &file_download_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobFilesRequest) ProtoMessage() {}
+
+func (x *GetJobFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobFilesRequest.ProtoReflect.Descriptor instead.
+func (*GetJobFilesRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetJobFilesRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// ジョブのファイル一覧取得レスポンス（ファイル名順）
+type GetJobFilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Files         []*JobFile             `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobFilesResponse) Reset() {
+	*x = GetJobFilesResponse{}
+	mi := &file_download_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobFilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobFilesResponse) ProtoMessage() {}
+
+func (x *GetJobFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobFilesResponse.ProtoReflect.Descriptor instead.
+func (*GetJobFilesResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetJobFilesResponse) GetFiles() []*JobFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// ジョブのセッションフォルダに保存されたファイル
+type JobFile struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Size  int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// ファイルを保存したアカウント（判別できない場合は空）
+	AccountId     string `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobFile) Reset() {
+	*x = JobFile{}
+	mi := &file_download_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobFile) ProtoMessage() {}
+
+func (x *JobFile) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobFile.ProtoReflect.Descriptor instead.
+func (*JobFile) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *JobFile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobFile) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *JobFile) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+// ジョブのファイル取得リクエスト
+type DownloadFileRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	JobId string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// セッションフォルダ直下のファイル名（パスの区切りや..を含む場合はINVALID_ARGUMENT）
+	Name          string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadFileRequest) Reset() {
+	*x = DownloadFileRequest{}
+	mi := &file_download_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadFileRequest) ProtoMessage() {}
+
+func (x *DownloadFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadFileRequest.ProtoReflect.Descriptor instead.
+func (*DownloadFileRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DownloadFileRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *DownloadFileRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// ジョブのファイルの断片（順に連結するとファイル全体になる）
+type DownloadFileChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadFileChunk) Reset() {
+	*x = DownloadFileChunk{}
+	mi := &file_download_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadFileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadFileChunk) ProtoMessage() {}
+
+func (x *DownloadFileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadFileChunk.ProtoReflect.Descriptor instead.
+func (*DownloadFileChunk) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DownloadFileChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// ジョブステータス
+type JobStatus struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	JobId          string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status         string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // pending（同時実行数の上限で開始待ち）/processing/paused/completed/partial（一部のアカウントが失敗）/failed/cancelled/interrupted
+	Progress       int32                  `protobuf:"varint,3,opt,name=progress,proto3" json:"progress,omitempty"`
+	TotalRecords   int32                  `protobuf:"varint,4,opt,name=total_records,json=totalRecords,proto3" json:"total_records,omitempty"`
+	ErrorMessage   string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	StartedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	AccountResults []*AccountResult       `protobuf:"bytes,8,rep,name=account_results,json=accountResults,proto3" json:"account_results,omitempty"`
+	CurrentAccount string                 `protobuf:"bytes,9,opt,name=current_account,json=currentAccount,proto3" json:"current_account,omitempty"`                                                                // 最後に処理を開始したアカウントID
+	PerAccount     map[string]string      `protobuf:"bytes,10,rep,name=per_account,json=perAccount,proto3" json:"per_account,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // アカウントIDごとの状態（pending/processing/completed/failed/maintenance）
+	FailedAccounts []*FailedAccount       `protobuf:"bytes,11,rep,name=failed_accounts,json=failedAccounts,proto3" json:"failed_accounts,omitempty"`
+	DryRun         bool                   `protobuf:"varint,12,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // ログイン確認のみのジョブ（成功したアカウントはper_accountでauthenticated）
+	// メンテナンス中だったアカウントを再実行するジョブのID（ETC_MAINTENANCE_RETRY_DELAY設定時、再実行しない場合は空）
+	RescheduledJobId string                 `protobuf:"bytes,13,opt,name=rescheduled_job_id,json=rescheduledJobId,proto3" json:"rescheduled_job_id,omitempty"`
+	RescheduledAt    *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=rescheduled_at,json=rescheduledAt,proto3" json:"rescheduled_at,omitempty"` // 再実行するジョブの開始予定日時
+	// 実行したパス数（最初の実行で1、ETC_JOB_RETRY_PASSESで失敗したアカウントを再実行するたびに1増える）
+	Passes        int32 `protobuf:"varint,15,opt,name=passes,proto3" json:"passes,omitempty"`
+	TotalBytes    int64 `protobuf:"varint,16,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"` // 完了したアカウントで保存したファイル（CSVと利用証明書のPDF）の合計サイズ
+	FileCount     int32 `protobuf:"varint,17,opt,name=file_count,json=fileCount,proto3" json:"file_count,omitempty"`    // 完了したアカウントで保存したファイルの数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStatus) Reset() {
+	*x = JobStatus{}
+	mi := &file_download_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatus) ProtoMessage() {}
+
+func (x *JobStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
+func (*JobStatus) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *JobStatus) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobStatus) GetProgress() int32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *JobStatus) GetTotalRecords() int32 {
+	if x != nil {
+		return x.TotalRecords
+	}
+	return 0
+}
+
+func (x *JobStatus) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *JobStatus) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *JobStatus) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *JobStatus) GetAccountResults() []*AccountResult {
+	if x != nil {
+		return x.AccountResults
+	}
+	return nil
+}
+
+func (x *JobStatus) GetCurrentAccount() string {
+	if x != nil {
+		return x.CurrentAccount
+	}
+	return ""
+}
+
+func (x *JobStatus) GetPerAccount() map[string]string {
+	if x != nil {
+		return x.PerAccount
+	}
+	return nil
+}
+
+func (x *JobStatus) GetFailedAccounts() []*FailedAccount {
+	if x != nil {
+		return x.FailedAccounts
+	}
+	return nil
+}
+
+func (x *JobStatus) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *JobStatus) GetRescheduledJobId() string {
+	if x != nil {
+		return x.RescheduledJobId
+	}
+	return ""
+}
+
+func (x *JobStatus) GetRescheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RescheduledAt
+	}
+	return nil
+}
+
+func (x *JobStatus) GetPasses() int32 {
+	if x != nil {
+		return x.Passes
+	}
+	return 0
+}
+
+func (x *JobStatus) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *JobStatus) GetFileCount() int32 {
+	if x != nil {
+		return x.FileCount
+	}
+	return 0
+}
+
+// 失敗したアカウント
+type FailedAccount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	AuthError     bool                   `protobuf:"varint,3,opt,name=auth_error,json=authError,proto3" json:"auth_error,omitempty"` // 認証情報が誤っている場合true（利用者に修正を促す）
+	Maintenance   bool                   `protobuf:"varint,4,opt,name=maintenance,proto3" json:"maintenance,omitempty"`              // ETCサイトのメンテナンス中で失敗した場合true（時間をおいて再実行する）
+	Timeout       bool                   `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"`                      // サイトの応答やダウンロードがタイムアウトして失敗した場合true（DownloadSyncのERROR_CODE_TIMEOUTと同じ判定）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailedAccount) Reset() {
+	*x = FailedAccount{}
+	mi := &file_download_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailedAccount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailedAccount) ProtoMessage() {}
+
+func (x *FailedAccount) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailedAccount.ProtoReflect.Descriptor instead.
+func (*FailedAccount) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *FailedAccount) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *FailedAccount) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *FailedAccount) GetAuthError() bool {
+	if x != nil {
+		return x.AuthError
+	}
+	return false
+}
+
+func (x *FailedAccount) GetMaintenance() bool {
+	if x != nil {
+		return x.Maintenance
+	}
+	return false
+}
+
+func (x *FailedAccount) GetTimeout() bool {
+	if x != nil {
+		return x.Timeout
+	}
+	return false
+}
+
+// アカウントごとのダウンロード結果
+type AccountResult struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	AccountId          string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	ExpectedRecords    int32                  `protobuf:"varint,2,opt,name=expected_records,json=expectedRecords,proto3" json:"expected_records,omitempty"`          // サイト上に表示された件数
+	ExpectedKnown      bool                   `protobuf:"varint,3,opt,name=expected_known,json=expectedKnown,proto3" json:"expected_known,omitempty"`                // サイト上の件数を取得できたか
+	ActualRecords      int32                  `protobuf:"varint,4,opt,name=actual_records,json=actualRecords,proto3" json:"actual_records,omitempty"`                // CSVから読み取った件数
+	CountMismatch      bool                   `protobuf:"varint,5,opt,name=count_mismatch,json=countMismatch,proto3" json:"count_mismatch,omitempty"`                // 件数が一致しない場合true
+	Truncated          bool                   `protobuf:"varint,6,opt,name=truncated,proto3" json:"truncated,omitempty"`                                             // ETC_MAX_RECORDSを超えたため明細を打ち切った場合true（actual_recordsは打ち切り後の件数）
+	CertificatePaths   []string               `protobuf:"bytes,7,rep,name=certificate_paths,json=certificatePaths,proto3" json:"certificate_paths,omitempty"`        // download_certificatesで保存した利用証明書のPDFのパス
+	CertificatesFailed bool                   `protobuf:"varint,8,opt,name=certificates_failed,json=certificatesFailed,proto3" json:"certificates_failed,omitempty"` // 利用証明書のダウンロードに失敗した場合true（明細は取得済み）
+	CsvPath            string                 `protobuf:"bytes,9,opt,name=csv_path,json=csvPath,proto3" json:"csv_path,omitempty"`                                   // ダウンロードしたCSVのパス（OUTPUT_FORMAT_RECORDSで削除した場合は空）
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *AccountResult) Reset() {
+	*x = AccountResult{}
+	mi := &file_download_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountResult) ProtoMessage() {}
+
+func (x *AccountResult) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountResult.ProtoReflect.Descriptor instead.
+func (*AccountResult) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AccountResult) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *AccountResult) GetExpectedRecords() int32 {
+	if x != nil {
+		return x.ExpectedRecords
+	}
+	return 0
+}
+
+func (x *AccountResult) GetExpectedKnown() bool {
+	if x != nil {
+		return x.ExpectedKnown
+	}
+	return false
+}
+
+func (x *AccountResult) GetActualRecords() int32 {
+	if x != nil {
+		return x.ActualRecords
+	}
+	return 0
+}
+
+func (x *AccountResult) GetCountMismatch() bool {
+	if x != nil {
+		return x.CountMismatch
+	}
+	return false
+}
+
+func (x *AccountResult) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *AccountResult) GetCertificatePaths() []string {
+	if x != nil {
+		return x.CertificatePaths
+	}
+	return nil
+}
+
+func (x *AccountResult) GetCertificatesFailed() bool {
+	if x != nil {
+		return x.CertificatesFailed
+	}
+	return false
+}
+
+func (x *AccountResult) GetCsvPath() string {
+	if x != nil {
+		return x.CsvPath
+	}
+	return ""
+}
+
+// アカウントID取得リクエスト
+type GetAllAccountIDsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 指定した場合、ETC_ACCOUNT_GROUPSのこのグループに属するアカウントのみ返す
+	Group         string `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllAccountIDsRequest) Reset() {
+	*x = GetAllAccountIDsRequest{}
+	mi := &file_download_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllAccountIDsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllAccountIDsRequest) ProtoMessage() {}
+
+func (x *GetAllAccountIDsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllAccountIDsRequest.ProtoReflect.Descriptor instead.
+func (*GetAllAccountIDsRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetAllAccountIDsRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+// アカウントID取得レスポンス
+type GetAllAccountIDsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountIds    []string               `protobuf:"bytes,1,rep,name=account_ids,json=accountIds,proto3" json:"account_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllAccountIDsResponse) Reset() {
+	*x = GetAllAccountIDsResponse{}
+	mi := &file_download_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllAccountIDsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllAccountIDsResponse) ProtoMessage() {}
+
+func (x *GetAllAccountIDsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllAccountIDsResponse.ProtoReflect.Descriptor instead.
+func (*GetAllAccountIDsResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetAllAccountIDsResponse) GetAccountIds() []string {
+	if x != nil {
+		return x.AccountIds
+	}
+	return nil
+}
+
+// アカウントの実行結果取得リクエスト
+type GetAccountStatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 指定した場合、これらのアカウントのみ返す（空の場合は設定済みの全アカウント）
+	AccountIds    []string `protobuf:"bytes,1,rep,name=account_ids,json=accountIds,proto3" json:"account_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountStatusRequest) Reset() {
+	*x = GetAccountStatusRequest{}
+	mi := &file_download_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountStatusRequest) ProtoMessage() {}
+
+func (x *GetAccountStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountStatusRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetAccountStatusRequest) GetAccountIds() []string {
+	if x != nil {
+		return x.AccountIds
+	}
+	return nil
+}
+
+// アカウントの実行結果取得レスポンス（account_idsの指定順、未指定の場合はGetAllAccountIDsの順）
+type GetAccountStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accounts      []*AccountStatus       `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountStatusResponse) Reset() {
+	*x = GetAccountStatusResponse{}
+	mi := &file_download_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountStatusResponse) ProtoMessage() {}
+
+func (x *GetAccountStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetAccountStatusResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetAccountStatusResponse) GetAccounts() []*AccountStatus {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+// アカウントの最後の実行結果（一度も実行していない場合はaccount_id以外が空）
+type AccountStatus struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AccountId       string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	LastJobId       string                 `protobuf:"bytes,2,opt,name=last_job_id,json=lastJobId,proto3" json:"last_job_id,omitempty"`
+	LastRunAt       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"`
+	LastOutcome     string                 `protobuf:"bytes,4,opt,name=last_outcome,json=lastOutcome,proto3" json:"last_outcome,omitempty"`                // completed, failed, maintenance, authenticated（ドライラン）
+	LastSuccessAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_success_at,json=lastSuccessAt,proto3" json:"last_success_at,omitempty"`        // 最後にダウンロードに成功した日時
+	LastRecordCount int32                  `protobuf:"varint,6,opt,name=last_record_count,json=lastRecordCount,proto3" json:"last_record_count,omitempty"` // 最後に成功したダウンロードの明細件数
+	LastError       string                 `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`                      // 最後に失敗した理由（その後に成功しても残す）
+	LastErrorAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_error_at,json=lastErrorAt,proto3" json:"last_error_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AccountStatus) Reset() {
+	*x = AccountStatus{}
+	mi := &file_download_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountStatus) ProtoMessage() {}
+
+func (x *AccountStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountStatus.ProtoReflect.Descriptor instead.
+func (*AccountStatus) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *AccountStatus) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *AccountStatus) GetLastJobId() string {
+	if x != nil {
+		return x.LastJobId
+	}
+	return ""
+}
+
+func (x *AccountStatus) GetLastRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRunAt
+	}
+	return nil
+}
+
+func (x *AccountStatus) GetLastOutcome() string {
+	if x != nil {
+		return x.LastOutcome
+	}
+	return ""
+}
+
+func (x *AccountStatus) GetLastSuccessAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSuccessAt
+	}
+	return nil
+}
+
+func (x *AccountStatus) GetLastRecordCount() int32 {
+	if x != nil {
+		return x.LastRecordCount
+	}
+	return 0
+}
+
+func (x *AccountStatus) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *AccountStatus) GetLastErrorAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastErrorAt
+	}
+	return nil
+}
+
+// アカウントのログイン確認リクエスト
+// account_id・passwordを指定するか、account_indexで設定済みアカウント（GetAllAccountIDsの順）を指定する
+type TestAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	AccountIndex  *int32                 `protobuf:"varint,3,opt,name=account_index,json=accountIndex,proto3,oneof" json:"account_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestAccountRequest) Reset() {
+	*x = TestAccountRequest{}
+	mi := &file_download_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestAccountRequest) ProtoMessage() {}
+
+func (x *TestAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestAccountRequest.ProtoReflect.Descriptor instead.
+func (*TestAccountRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TestAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *TestAccountRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *TestAccountRequest) GetAccountIndex() int32 {
+	if x != nil && x.AccountIndex != nil {
+		return *x.AccountIndex
+	}
+	return 0
+}
+
+// アカウントのログイン確認レスポンス（認証情報は含めない）
+type TestAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	LatencyMs     int64                  `protobuf:"varint,2,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestAccountResponse) Reset() {
+	*x = TestAccountResponse{}
+	mi := &file_download_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestAccountResponse) ProtoMessage() {}
+
+func (x *TestAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestAccountResponse.ProtoReflect.Descriptor instead.
+func (*TestAccountResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *TestAccountResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *TestAccountResponse) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *TestAccountResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// パスワード更新リクエスト
+type UpdateCredentialRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // 環境変数に設定されているアカウントID
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCredentialRequest) Reset() {
+	*x = UpdateCredentialRequest{}
+	mi := &file_download_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCredentialRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCredentialRequest) ProtoMessage() {}
+
+func (x *UpdateCredentialRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCredentialRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCredentialRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UpdateCredentialRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *UpdateCredentialRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+// パスワード更新レスポンス（パスワードは含めない）
+type UpdateCredentialResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCredentialResponse) Reset() {
+	*x = UpdateCredentialResponse{}
+	mi := &file_download_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCredentialResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCredentialResponse) ProtoMessage() {}
+
+func (x *UpdateCredentialResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCredentialResponse.ProtoReflect.Descriptor instead.
+func (*UpdateCredentialResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UpdateCredentialResponse) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+// 環境変数取得リクエスト
+type GetEnvironmentVariablesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentVariablesRequest) Reset() {
+	*x = GetEnvironmentVariablesRequest{}
+	mi := &file_download_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentVariablesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentVariablesRequest) ProtoMessage() {}
+
+func (x *GetEnvironmentVariablesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentVariablesRequest.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentVariablesRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{32}
+}
+
+// 環境変数取得レスポンス
+type GetEnvironmentVariablesResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	EtcCorpAccounts      string                 `protobuf:"bytes,1,opt,name=etc_corp_accounts,json=etcCorpAccounts,proto3" json:"etc_corp_accounts,omitempty"`                // ETC_CORP_ACCOUNTS (マスク済み)
+	EtcHeadless          string                 `protobuf:"bytes,2,opt,name=etc_headless,json=etcHeadless,proto3" json:"etc_headless,omitempty"`                              // ETC_HEADLESS
+	GrpcPort             string                 `protobuf:"bytes,3,opt,name=grpc_port,json=grpcPort,proto3" json:"grpc_port,omitempty"`                                       // GRPC_PORT
+	HttpPort             string                 `protobuf:"bytes,4,opt,name=http_port,json=httpPort,proto3" json:"http_port,omitempty"`                                       // HTTP_PORT
+	EtcCorporateAccounts string                 `protobuf:"bytes,5,opt,name=etc_corporate_accounts,json=etcCorporateAccounts,proto3" json:"etc_corporate_accounts,omitempty"` // ETC_CORPORATE_ACCOUNTS (レガシー、マスク済み)
+	EtcPersonalAccounts  string                 `protobuf:"bytes,6,opt,name=etc_personal_accounts,json=etcPersonalAccounts,proto3" json:"etc_personal_accounts,omitempty"`    // ETC_PERSONAL_ACCOUNTS (レガシー、マスク済み)
+	EtcDownloadDir       string                 `protobuf:"bytes,7,opt,name=etc_download_dir,json=etcDownloadDir,proto3" json:"etc_download_dir,omitempty"`                   // ETC_DOWNLOAD_DIR
+	EtcMaxConcurrency    string                 `protobuf:"bytes,8,opt,name=etc_max_concurrency,json=etcMaxConcurrency,proto3" json:"etc_max_concurrency,omitempty"`          // ETC_MAX_CONCURRENCY
+	EtcTimeoutMs         string                 `protobuf:"bytes,9,opt,name=etc_timeout_ms,json=etcTimeoutMs,proto3" json:"etc_timeout_ms,omitempty"`                         // ETC_TIMEOUT_MS
+	CredentialOverrides  []string               `protobuf:"bytes,10,rep,name=credential_overrides,json=credentialOverrides,proto3" json:"credential_overrides,omitempty"`     // UpdateCredentialでパスワードを更新したアカウント (マスク済み)
+	EtcAccountsFile      string                 `protobuf:"bytes,11,opt,name=etc_accounts_file,json=etcAccountsFile,proto3" json:"etc_accounts_file,omitempty"`               // ETC_ACCOUNTS_FILE (ファイルのパスのみ)
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentVariablesResponse) Reset() {
+	*x = GetEnvironmentVariablesResponse{}
+	mi := &file_download_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentVariablesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentVariablesResponse) ProtoMessage() {}
+
+func (x *GetEnvironmentVariablesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentVariablesResponse.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentVariablesResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetEnvironmentVariablesResponse) GetEtcCorpAccounts() string {
+	if x != nil {
+		return x.EtcCorpAccounts
+	}
+	return ""
+}
+
+func (x *GetEnvironmentVariablesResponse) GetEtcHeadless() string {
+	if x != nil {
+		return x.EtcHeadless
+	}
+	return ""
+}
+
+func (x *GetEnvironmentVariablesResponse) GetGrpcPort() string {
+	if x != nil {
+		return x.GrpcPort
+	}
+	return ""
+}
+
+func (x *GetEnvironmentVariablesResponse) GetHttpPort() string {
+	if x != nil {
+		return x.HttpPort
+	}
+	return ""
+}
+
+func (x *GetEnvironmentVariablesResponse) GetEtcCorporateAccounts() string {
+	if x != nil {
+		return x.EtcCorporateAccounts
+	}
+	return ""
+}
+
+func (x *GetEnvironmentVariablesResponse) GetEtcPersonalAccounts() string {
+	if x != nil {
+		return x.EtcPersonalAccounts
+	}
+	return ""
+}
+
+func (x *GetEnvironmentVariablesResponse) GetEtcDownloadDir() string {
+	if x != nil {
+		return x.EtcDownloadDir
+	}
+	return ""
+}
+
+func (x *GetEnvironmentVariablesResponse) GetEtcMaxConcurrency() string {
+	if x != nil {
+		return x.EtcMaxConcurrency
+	}
+	return ""
+}
+
+func (x *GetEnvironmentVariablesResponse) GetEtcTimeoutMs() string {
+	if x != nil {
+		return x.EtcTimeoutMs
+	}
+	return ""
+}
+
+func (x *GetEnvironmentVariablesResponse) GetCredentialOverrides() []string {
+	if x != nil {
+		return x.CredentialOverrides
+	}
+	return nil
+}
+
+func (x *GetEnvironmentVariablesResponse) GetEtcAccountsFile() string {
+	if x != nil {
+		return x.EtcAccountsFile
+	}
+	return ""
+}
+
+// サーバーログ取得リクエスト
+type GetServerLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TailLines     int32                  `protobuf:"varint,1,opt,name=tail_lines,json=tailLines,proto3" json:"tail_lines,omitempty"`                                   // 末尾から取得する行数（デフォルト: 100）
+	MinLevel      LogLevel               `protobuf:"varint,2,opt,name=min_level,json=minLevel,proto3,enum=etc_meisai.download.v1.LogLevel" json:"min_level,omitempty"` // 取得する最小ログレベル（未指定時は全レベル）
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`                                                          // 末尾から読み飛ばす行数（古いログを遡る場合は前回のnext_offsetを指定）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServerLogsRequest) Reset() {
+	*x = GetServerLogsRequest{}
+	mi := &file_download_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServerLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServerLogsRequest) ProtoMessage() {}
+
+func (x *GetServerLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServerLogsRequest.ProtoReflect.Descriptor instead.
+func (*GetServerLogsRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetServerLogsRequest) GetTailLines() int32 {
+	if x != nil {
+		return x.TailLines
+	}
+	return 0
+}
+
+func (x *GetServerLogsRequest) GetMinLevel() LogLevel {
+	if x != nil {
+		return x.MinLevel
+	}
+	return LogLevel_LOG_LEVEL_UNSPECIFIED
+}
+
+func (x *GetServerLogsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// サーバーログ取得レスポンス
+type GetServerLogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LogLines      []string               `protobuf:"bytes,1,rep,name=log_lines,json=logLines,proto3" json:"log_lines,omitempty"`        // ログ行の配列
+	TotalLines    int32                  `protobuf:"varint,2,opt,name=total_lines,json=totalLines,proto3" json:"total_lines,omitempty"` // 総行数
+	Entries       []*LogEntry            `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`                          // レベルと時刻付きのログ（log_linesと同順）
+	NextOffset    int32                  `protobuf:"varint,4,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"` // さらに古いログを取得する場合のoffset
+	HasMore       bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`          // さらに古いログが残っている場合true
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetServerLogsResponse) Reset() {
+	*x = GetServerLogsResponse{}
+	mi := &file_download_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetServerLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetServerLogsResponse) ProtoMessage() {}
+
+func (x *GetServerLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetServerLogsResponse.ProtoReflect.Descriptor instead.
+func (*GetServerLogsResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetServerLogsResponse) GetLogLines() []string {
+	if x != nil {
+		return x.LogLines
+	}
+	return nil
+}
+
+func (x *GetServerLogsResponse) GetTotalLines() int32 {
+	if x != nil {
+		return x.TotalLines
+	}
+	return 0
+}
+
+func (x *GetServerLogsResponse) GetEntries() []*LogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *GetServerLogsResponse) GetNextOffset() int32 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+func (x *GetServerLogsResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+// ジョブのログ取得リクエスト
+type GetJobLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	TailLines     int32                  `protobuf:"varint,2,opt,name=tail_lines,json=tailLines,proto3" json:"tail_lines,omitempty"` // 末尾から取得する行数（デフォルト: 100）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobLogsRequest) Reset() {
+	*x = GetJobLogsRequest{}
+	mi := &file_download_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobLogsRequest) ProtoMessage() {}
+
+func (x *GetJobLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobLogsRequest.ProtoReflect.Descriptor instead.
+func (*GetJobLogsRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetJobLogsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *GetJobLogsRequest) GetTailLines() int32 {
+	if x != nil {
+		return x.TailLines
+	}
+	return 0
+}
+
+// ジョブのログ取得レスポンス
+type GetJobLogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	LogLines      []string               `protobuf:"bytes,2,rep,name=log_lines,json=logLines,proto3" json:"log_lines,omitempty"` // ログ行の配列
+	Entries       []*LogEntry            `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`                   // レベルと時刻付きのログ（log_linesと同順）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobLogsResponse) Reset() {
+	*x = GetJobLogsResponse{}
+	mi := &file_download_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobStatus) String() string {
+func (x *GetJobLogsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobStatus) ProtoMessage() {}
+func (*GetJobLogsResponse) ProtoMessage() {}
 
-func (x *JobStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_download_proto_msgTypes[4]
+func (x *GetJobLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -313,82 +2713,115 @@ func (x *JobStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
-func (*JobStatus) Descriptor() ([]byte, []int) {
-	return file_download_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use GetJobLogsResponse.ProtoReflect.Descriptor instead.
+func (*GetJobLogsResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *JobStatus) GetJobId() string {
+func (x *GetJobLogsResponse) GetJobId() string {
 	if x != nil {
 		return x.JobId
 	}
 	return ""
 }
 
-func (x *JobStatus) GetStatus() string {
+func (x *GetJobLogsResponse) GetLogLines() []string {
 	if x != nil {
-		return x.Status
+		return x.LogLines
 	}
-	return ""
+	return nil
 }
 
-func (x *JobStatus) GetProgress() int32 {
+func (x *GetJobLogsResponse) GetEntries() []*LogEntry {
 	if x != nil {
-		return x.Progress
+		return x.Entries
 	}
-	return 0
+	return nil
 }
 
-func (x *JobStatus) GetTotalRecords() int32 {
+// レベルと時刻付きのログ
+type LogEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Level         LogLevel               `protobuf:"varint,2,opt,name=level,proto3,enum=etc_meisai.download.v1.LogLevel" json:"level,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	mi := &file_download_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[38]
 	if x != nil {
-		return x.TotalRecords
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *JobStatus) GetErrorMessage() string {
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *LogEntry) GetTimestamp() *timestamppb.Timestamp {
 	if x != nil {
-		return x.ErrorMessage
+		return x.Timestamp
 	}
-	return ""
+	return nil
 }
 
-func (x *JobStatus) GetStartedAt() *timestamppb.Timestamp {
+func (x *LogEntry) GetLevel() LogLevel {
 	if x != nil {
-		return x.StartedAt
+		return x.Level
 	}
-	return nil
+	return LogLevel_LOG_LEVEL_UNSPECIFIED
 }
 
-func (x *JobStatus) GetCompletedAt() *timestamppb.Timestamp {
+func (x *LogEntry) GetMessage() string {
 	if x != nil {
-		return x.CompletedAt
+		return x.Message
 	}
-	return nil
+	return ""
 }
 
-// アカウントID取得リクエスト
-type GetAllAccountIDsRequest struct {
+// ビルド情報取得リクエスト
+type GetVersionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAllAccountIDsRequest) Reset() {
-	*x = GetAllAccountIDsRequest{}
-	mi := &file_download_proto_msgTypes[5]
+func (x *GetVersionRequest) Reset() {
+	*x = GetVersionRequest{}
+	mi := &file_download_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAllAccountIDsRequest) String() string {
+func (x *GetVersionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAllAccountIDsRequest) ProtoMessage() {}
+func (*GetVersionRequest) ProtoMessage() {}
 
-func (x *GetAllAccountIDsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_download_proto_msgTypes[5]
+func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -399,34 +2832,38 @@ func (x *GetAllAccountIDsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAllAccountIDsRequest.ProtoReflect.Descriptor instead.
-func (*GetAllAccountIDsRequest) Descriptor() ([]byte, []int) {
-	return file_download_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use GetVersionRequest.ProtoReflect.Descriptor instead.
+func (*GetVersionRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{39}
 }
 
-// アカウントID取得レスポンス
-type GetAllAccountIDsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccountIds    []string               `protobuf:"bytes,1,rep,name=account_ids,json=accountIds,proto3" json:"account_ids,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ビルド情報取得レスポンス（-ldflagsで埋め込まれていない値はdev/unknown）
+type GetVersionResponse struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Version                 string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Commit                  string                 `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`
+	BuildTime               string                 `protobuf:"bytes,3,opt,name=build_time,json=buildTime,proto3" json:"build_time,omitempty"`
+	GoVersion               string                 `protobuf:"bytes,4,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	PlaywrightDriverVersion string                 `protobuf:"bytes,5,opt,name=playwright_driver_version,json=playwrightDriverVersion,proto3" json:"playwright_driver_version,omitempty"` // 取得できない場合は空
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
 }
 
-func (x *GetAllAccountIDsResponse) Reset() {
-	*x = GetAllAccountIDsResponse{}
-	mi := &file_download_proto_msgTypes[6]
+func (x *GetVersionResponse) Reset() {
+	*x = GetVersionResponse{}
+	mi := &file_download_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAllAccountIDsResponse) String() string {
+func (x *GetVersionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAllAccountIDsResponse) ProtoMessage() {}
+func (*GetVersionResponse) ProtoMessage() {}
 
-func (x *GetAllAccountIDsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_download_proto_msgTypes[6]
+func (x *GetVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -437,40 +2874,68 @@ func (x *GetAllAccountIDsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAllAccountIDsResponse.ProtoReflect.Descriptor instead.
-func (*GetAllAccountIDsResponse) Descriptor() ([]byte, []int) {
-	return file_download_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use GetVersionResponse.ProtoReflect.Descriptor instead.
+func (*GetVersionResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *GetAllAccountIDsResponse) GetAccountIds() []string {
+func (x *GetVersionResponse) GetVersion() string {
 	if x != nil {
-		return x.AccountIds
+		return x.Version
 	}
-	return nil
+	return ""
 }
 
-// 環境変数取得リクエスト
-type GetEnvironmentVariablesRequest struct {
+func (x *GetVersionResponse) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *GetVersionResponse) GetBuildTime() string {
+	if x != nil {
+		return x.BuildTime
+	}
+	return ""
+}
+
+func (x *GetVersionResponse) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+func (x *GetVersionResponse) GetPlaywrightDriverVersion() string {
+	if x != nil {
+		return x.PlaywrightDriverVersion
+	}
+	return ""
+}
+
+// 稼働状況取得リクエスト
+type GetRuntimeMetricsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetEnvironmentVariablesRequest) Reset() {
-	*x = GetEnvironmentVariablesRequest{}
-	mi := &file_download_proto_msgTypes[7]
+func (x *GetRuntimeMetricsRequest) Reset() {
+	*x = GetRuntimeMetricsRequest{}
+	mi := &file_download_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetEnvironmentVariablesRequest) String() string {
+func (x *GetRuntimeMetricsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetEnvironmentVariablesRequest) ProtoMessage() {}
+func (*GetRuntimeMetricsRequest) ProtoMessage() {}
 
-func (x *GetEnvironmentVariablesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_download_proto_msgTypes[7]
+func (x *GetRuntimeMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -481,39 +2946,44 @@ func (x *GetEnvironmentVariablesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetEnvironmentVariablesRequest.ProtoReflect.Descriptor instead.
-func (*GetEnvironmentVariablesRequest) Descriptor() ([]byte, []int) {
-	return file_download_proto_rawDescGZIP(), []int{7}
-}
-
-// 環境変数取得レスポンス
-type GetEnvironmentVariablesResponse struct {
-	state                protoimpl.MessageState `protogen:"open.v1"`
-	EtcCorpAccounts      string                 `protobuf:"bytes,1,opt,name=etc_corp_accounts,json=etcCorpAccounts,proto3" json:"etc_corp_accounts,omitempty"`                // ETC_CORP_ACCOUNTS (マスク済み)
-	EtcHeadless          string                 `protobuf:"bytes,2,opt,name=etc_headless,json=etcHeadless,proto3" json:"etc_headless,omitempty"`                              // ETC_HEADLESS
-	GrpcPort             string                 `protobuf:"bytes,3,opt,name=grpc_port,json=grpcPort,proto3" json:"grpc_port,omitempty"`                                       // GRPC_PORT
-	HttpPort             string                 `protobuf:"bytes,4,opt,name=http_port,json=httpPort,proto3" json:"http_port,omitempty"`                                       // HTTP_PORT
-	EtcCorporateAccounts string                 `protobuf:"bytes,5,opt,name=etc_corporate_accounts,json=etcCorporateAccounts,proto3" json:"etc_corporate_accounts,omitempty"` // ETC_CORPORATE_ACCOUNTS (レガシー、マスク済み)
-	EtcPersonalAccounts  string                 `protobuf:"bytes,6,opt,name=etc_personal_accounts,json=etcPersonalAccounts,proto3" json:"etc_personal_accounts,omitempty"`    // ETC_PERSONAL_ACCOUNTS (レガシー、マスク済み)
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
-}
-
-func (x *GetEnvironmentVariablesResponse) Reset() {
-	*x = GetEnvironmentVariablesResponse{}
-	mi := &file_download_proto_msgTypes[8]
+// Deprecated: Use GetRuntimeMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetRuntimeMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{41}
+}
+
+// 稼働状況のスナップショット
+type RuntimeMetrics struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	QueuedJobs          int32                  `protobuf:"varint,1,opt,name=queued_jobs,json=queuedJobs,proto3" json:"queued_jobs,omitempty"`                              // 開始待ちのジョブ数
+	RunningJobs         int32                  `protobuf:"varint,2,opt,name=running_jobs,json=runningJobs,proto3" json:"running_jobs,omitempty"`                           // 実行中のジョブ数
+	PausedJobs          int32                  `protobuf:"varint,3,opt,name=paused_jobs,json=pausedJobs,proto3" json:"paused_jobs,omitempty"`                              // 一時停止中のジョブ数
+	QueuedAccounts      int32                  `protobuf:"varint,4,opt,name=queued_accounts,json=queuedAccounts,proto3" json:"queued_accounts,omitempty"`                  // ワーカーの割り当て待ちのアカウント数
+	BusyWorkers         int32                  `protobuf:"varint,5,opt,name=busy_workers,json=busyWorkers,proto3" json:"busy_workers,omitempty"`                           // ダウンロード処理中のワーカー数
+	IdleWorkers         int32                  `protobuf:"varint,6,opt,name=idle_workers,json=idleWorkers,proto3" json:"idle_workers,omitempty"`                           // 待機中のワーカー数
+	ActiveBrowsers      int32                  `protobuf:"varint,7,opt,name=active_browsers,json=activeBrowsers,proto3" json:"active_browsers,omitempty"`                  // 起動中のブラウザ数
+	RateBudgetRemaining int32                  `protobuf:"varint,8,opt,name=rate_budget_remaining,json=rateBudgetRemaining,proto3" json:"rate_budget_remaining,omitempty"` // レート制限の残り枠（-1: レート制限なし）
+	LogBufferLines      int32                  `protobuf:"varint,9,opt,name=log_buffer_lines,json=logBufferLines,proto3" json:"log_buffer_lines,omitempty"`                // ログバッファの使用行数
+	LogBufferCapacity   int32                  `protobuf:"varint,10,opt,name=log_buffer_capacity,json=logBufferCapacity,proto3" json:"log_buffer_capacity,omitempty"`      // ログバッファの最大行数
+	LogDroppedLines     uint64                 `protobuf:"varint,11,opt,name=log_dropped_lines,json=logDroppedLines,proto3" json:"log_dropped_lines,omitempty"`            // ストリーミング購読者の遅延で破棄した行数
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RuntimeMetrics) Reset() {
+	*x = RuntimeMetrics{}
+	mi := &file_download_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetEnvironmentVariablesResponse) String() string {
+func (x *RuntimeMetrics) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetEnvironmentVariablesResponse) ProtoMessage() {}
+func (*RuntimeMetrics) ProtoMessage() {}
 
-func (x *GetEnvironmentVariablesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_download_proto_msgTypes[8]
+func (x *RuntimeMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -524,76 +2994,111 @@ func (x *GetEnvironmentVariablesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetEnvironmentVariablesResponse.ProtoReflect.Descriptor instead.
-func (*GetEnvironmentVariablesResponse) Descriptor() ([]byte, []int) {
-	return file_download_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use RuntimeMetrics.ProtoReflect.Descriptor instead.
+func (*RuntimeMetrics) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *GetEnvironmentVariablesResponse) GetEtcCorpAccounts() string {
+func (x *RuntimeMetrics) GetQueuedJobs() int32 {
 	if x != nil {
-		return x.EtcCorpAccounts
+		return x.QueuedJobs
 	}
-	return ""
+	return 0
 }
 
-func (x *GetEnvironmentVariablesResponse) GetEtcHeadless() string {
+func (x *RuntimeMetrics) GetRunningJobs() int32 {
 	if x != nil {
-		return x.EtcHeadless
+		return x.RunningJobs
 	}
-	return ""
+	return 0
 }
 
-func (x *GetEnvironmentVariablesResponse) GetGrpcPort() string {
+func (x *RuntimeMetrics) GetPausedJobs() int32 {
 	if x != nil {
-		return x.GrpcPort
+		return x.PausedJobs
 	}
-	return ""
+	return 0
 }
 
-func (x *GetEnvironmentVariablesResponse) GetHttpPort() string {
+func (x *RuntimeMetrics) GetQueuedAccounts() int32 {
 	if x != nil {
-		return x.HttpPort
+		return x.QueuedAccounts
 	}
-	return ""
+	return 0
 }
 
-func (x *GetEnvironmentVariablesResponse) GetEtcCorporateAccounts() string {
+func (x *RuntimeMetrics) GetBusyWorkers() int32 {
 	if x != nil {
-		return x.EtcCorporateAccounts
+		return x.BusyWorkers
 	}
-	return ""
+	return 0
 }
 
-func (x *GetEnvironmentVariablesResponse) GetEtcPersonalAccounts() string {
+func (x *RuntimeMetrics) GetIdleWorkers() int32 {
 	if x != nil {
-		return x.EtcPersonalAccounts
+		return x.IdleWorkers
 	}
-	return ""
+	return 0
 }
 
-// サーバーログ取得リクエスト
-type GetServerLogsRequest struct {
+func (x *RuntimeMetrics) GetActiveBrowsers() int32 {
+	if x != nil {
+		return x.ActiveBrowsers
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetRateBudgetRemaining() int32 {
+	if x != nil {
+		return x.RateBudgetRemaining
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetLogBufferLines() int32 {
+	if x != nil {
+		return x.LogBufferLines
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetLogBufferCapacity() int32 {
+	if x != nil {
+		return x.LogBufferCapacity
+	}
+	return 0
+}
+
+func (x *RuntimeMetrics) GetLogDroppedLines() uint64 {
+	if x != nil {
+		return x.LogDroppedLines
+	}
+	return 0
+}
+
+// サーバーログストリーミングリクエスト
+type StreamServerLogsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TailLines     int32                  `protobuf:"varint,1,opt,name=tail_lines,json=tailLines,proto3" json:"tail_lines,omitempty"` // 末尾から取得する行数（デフォルト: 100）
+	TailLines     int32                  `protobuf:"varint,1,opt,name=tail_lines,json=tailLines,proto3" json:"tail_lines,omitempty"` // 最初に送信する既存ログの行数（デフォルト: 100）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetServerLogsRequest) Reset() {
-	*x = GetServerLogsRequest{}
-	mi := &file_download_proto_msgTypes[9]
+func (x *StreamServerLogsRequest) Reset() {
+	*x = StreamServerLogsRequest{}
+	mi := &file_download_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetServerLogsRequest) String() string {
+func (x *StreamServerLogsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetServerLogsRequest) ProtoMessage() {}
+func (*StreamServerLogsRequest) ProtoMessage() {}
 
-func (x *GetServerLogsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_download_proto_msgTypes[9]
+func (x *StreamServerLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -604,42 +3109,41 @@ func (x *GetServerLogsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetServerLogsRequest.ProtoReflect.Descriptor instead.
-func (*GetServerLogsRequest) Descriptor() ([]byte, []int) {
-	return file_download_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use StreamServerLogsRequest.ProtoReflect.Descriptor instead.
+func (*StreamServerLogsRequest) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *GetServerLogsRequest) GetTailLines() int32 {
+func (x *StreamServerLogsRequest) GetTailLines() int32 {
 	if x != nil {
 		return x.TailLines
 	}
 	return 0
 }
 
-// サーバーログ取得レスポンス
-type GetServerLogsResponse struct {
+// サーバーログストリーミングレスポンス（1行ごと）
+type StreamServerLogsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LogLines      []string               `protobuf:"bytes,1,rep,name=log_lines,json=logLines,proto3" json:"log_lines,omitempty"`        // ログ行の配列
-	TotalLines    int32                  `protobuf:"varint,2,opt,name=total_lines,json=totalLines,proto3" json:"total_lines,omitempty"` // 総行数
+	Line          string                 `protobuf:"bytes,1,opt,name=line,proto3" json:"line,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetServerLogsResponse) Reset() {
-	*x = GetServerLogsResponse{}
-	mi := &file_download_proto_msgTypes[10]
+func (x *StreamServerLogsResponse) Reset() {
+	*x = StreamServerLogsResponse{}
+	mi := &file_download_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetServerLogsResponse) String() string {
+func (x *StreamServerLogsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetServerLogsResponse) ProtoMessage() {}
+func (*StreamServerLogsResponse) ProtoMessage() {}
 
-func (x *GetServerLogsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_download_proto_msgTypes[10]
+func (x *StreamServerLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_download_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -650,23 +3154,16 @@ func (x *GetServerLogsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetServerLogsResponse.ProtoReflect.Descriptor instead.
-func (*GetServerLogsResponse) Descriptor() ([]byte, []int) {
-	return file_download_proto_rawDescGZIP(), []int{10}
-}
-
-func (x *GetServerLogsResponse) GetLogLines() []string {
-	if x != nil {
-		return x.LogLines
-	}
-	return nil
+// Deprecated: Use StreamServerLogsResponse.ProtoReflect.Descriptor instead.
+func (*StreamServerLogsResponse) Descriptor() ([]byte, []int) {
+	return file_download_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *GetServerLogsResponse) GetTotalLines() int32 {
+func (x *StreamServerLogsResponse) GetLine() string {
 	if x != nil {
-		return x.TotalLines
+		return x.Line
 	}
-	return 0
+	return ""
 }
 
 // ETC明細レコード
@@ -690,7 +3187,7 @@ type ETCMeisaiRecord struct {
 
 func (x *ETCMeisaiRecord) Reset() {
 	*x = ETCMeisaiRecord{}
-	mi := &file_download_proto_msgTypes[11]
+	mi := &file_download_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -702,7 +3199,7 @@ func (x *ETCMeisaiRecord) String() string {
 func (*ETCMeisaiRecord) ProtoMessage() {}
 
 func (x *ETCMeisaiRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_download_proto_msgTypes[11]
+	mi := &file_download_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -715,7 +3212,7 @@ func (x *ETCMeisaiRecord) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ETCMeisaiRecord.ProtoReflect.Descriptor instead.
 func (*ETCMeisaiRecord) Descriptor() ([]byte, []int) {
-	return file_download_proto_rawDescGZIP(), []int{11}
+	return file_download_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *ETCMeisaiRecord) GetId() int64 {
@@ -806,24 +3303,95 @@ var File_download_proto protoreflect.FileDescriptor
 
 const file_download_proto_rawDesc = "" +
 	"\n" +
-	"\x0edownload.proto\x12\x16etc_meisai.download.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"w\n" +
+	"\x0edownload.proto\x12\x16etc_meisai.download.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb9\x04\n" +
 	"\x0fDownloadRequest\x12\x1a\n" +
 	"\baccounts\x18\x01 \x03(\tR\baccounts\x12\x1b\n" +
 	"\tfrom_date\x18\x02 \x01(\tR\bfromDate\x12\x17\n" +
 	"\ato_date\x18\x03 \x01(\tR\x06toDate\x12\x12\n" +
-	"\x04mode\x18\x04 \x01(\tR\x04mode\"\xc3\x01\n" +
+	"\x04mode\x18\x04 \x01(\tR\x04mode\x12'\n" +
+	"\x0fstrict_accounts\x18\x05 \x01(\bR\x0estrictAccounts\x12\x17\n" +
+	"\adry_run\x18\x06 \x01(\bR\x06dryRun\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\a \x01(\x05R\ttimeoutMs\x12\x1f\n" +
+	"\bheadless\x18\b \x01(\bH\x00R\bheadless\x88\x01\x01\x12!\n" +
+	"\fcallback_url\x18\t \x01(\tR\vcallbackUrl\x12'\n" +
+	"\x0fidempotency_key\x18\n" +
+	" \x01(\tR\x0eidempotencyKey\x12!\n" +
+	"\fcard_numbers\x18\v \x03(\tR\vcardNumbers\x12\x14\n" +
+	"\x05group\x18\f \x01(\tR\x05group\x12,\n" +
+	"\x12max_inline_records\x18\r \x01(\x05R\x10maxInlineRecords\x123\n" +
+	"\x15download_certificates\x18\x0e \x01(\bR\x14downloadCertificates\x12I\n" +
+	"\routput_format\x18\x0f \x01(\x0e2$.etc_meisai.download.v1.OutputFormatR\foutputFormatB\v\n" +
+	"\t_headless\"\xb2\x03\n" +
 	"\x10DownloadResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12!\n" +
 	"\frecord_count\x18\x02 \x01(\x05R\vrecordCount\x12\x19\n" +
 	"\bcsv_path\x18\x03 \x01(\tR\acsvPath\x12A\n" +
 	"\arecords\x18\x04 \x03(\v2'.etc_meisai.download.v1.ETCMeisaiRecordR\arecords\x12\x14\n" +
-	"\x05error\x18\x05 \x01(\tR\x05error\"^\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12@\n" +
+	"\n" +
+	"error_code\x18\x06 \x01(\x0e2!.etc_meisai.download.v1.ErrorCodeR\terrorCode\x12\x1c\n" +
+	"\ttruncated\x18\a \x01(\bR\ttruncated\x12\x15\n" +
+	"\x06job_id\x18\b \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tcsv_paths\x18\t \x03(\tR\bcsvPaths\x12\x1b\n" +
+	"\tfrom_date\x18\n" +
+	" \x01(\tR\bfromDate\x12\x17\n" +
+	"\ato_date\x18\v \x01(\tR\x06toDate\x12#\n" +
+	"\rerror_message\x18\f \x01(\tR\ferrorMessage\"\xb0\x01\n" +
 	"\x13DownloadJobResponse\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\",\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1a\n" +
+	"\bwarnings\x18\x04 \x03(\tR\bwarnings\x12\x1b\n" +
+	"\tfrom_date\x18\x05 \x01(\tR\bfromDate\x12\x17\n" +
+	"\ato_date\x18\x06 \x01(\tR\x06toDate\",\n" +
 	"\x13GetJobStatusRequest\x12\x15\n" +
-	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\x9a\x02\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"K\n" +
+	"\x11WaitForJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1f\n" +
+	"\vlast_status\x18\x02 \x01(\tR\n" +
+	"lastStatus\"0\n" +
+	"\x15GetJobStatusesRequest\x12\x17\n" +
+	"\ajob_ids\x18\x01 \x03(\tR\x06jobIds\"Z\n" +
+	"\x16GetJobStatusesResponse\x12@\n" +
+	"\aentries\x18\x01 \x03(\v2&.etc_meisai.download.v1.JobStatusEntryR\aentries\"x\n" +
+	"\x0eJobStatusEntry\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x129\n" +
+	"\x06status\x18\x03 \x01(\v2!.etc_meisai.download.v1.JobStatusR\x06status\"(\n" +
+	"\x0fPauseJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\")\n" +
+	"\x10ResumeJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\")\n" +
+	"\x10CancelJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"h\n" +
+	"\x13GetJobResultRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"\xa6\x01\n" +
+	"\x14GetJobResultResponse\x12A\n" +
+	"\arecords\x18\x01 \x03(\v2'.etc_meisai.download.v1.ETCMeisaiRecordR\arecords\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12#\n" +
+	"\rtotal_records\x18\x03 \x01(\x05R\ftotalRecords\",\n" +
+	"\x13ExportJobCSVRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"'\n" +
+	"\x11ExportJobCSVChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"+\n" +
+	"\x12GetJobFilesRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"L\n" +
+	"\x13GetJobFilesResponse\x125\n" +
+	"\x05files\x18\x01 \x03(\v2\x1f.etc_meisai.download.v1.JobFileR\x05files\"P\n" +
+	"\aJobFile\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\"@\n" +
+	"\x13DownloadFileRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"'\n" +
+	"\x11DownloadFileChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"\xd8\x06\n" +
 	"\tJobStatus\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1a\n" +
@@ -832,26 +3400,151 @@ const file_download_proto_rawDesc = "" +
 	"\rerror_message\x18\x05 \x01(\tR\ferrorMessage\x129\n" +
 	"\n" +
 	"started_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
-	"\fcompleted_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"\x19\n" +
-	"\x17GetAllAccountIDsRequest\";\n" +
+	"\fcompleted_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12N\n" +
+	"\x0faccount_results\x18\b \x03(\v2%.etc_meisai.download.v1.AccountResultR\x0eaccountResults\x12'\n" +
+	"\x0fcurrent_account\x18\t \x01(\tR\x0ecurrentAccount\x12R\n" +
+	"\vper_account\x18\n" +
+	" \x03(\v21.etc_meisai.download.v1.JobStatus.PerAccountEntryR\n" +
+	"perAccount\x12N\n" +
+	"\x0ffailed_accounts\x18\v \x03(\v2%.etc_meisai.download.v1.FailedAccountR\x0efailedAccounts\x12\x17\n" +
+	"\adry_run\x18\f \x01(\bR\x06dryRun\x12,\n" +
+	"\x12rescheduled_job_id\x18\r \x01(\tR\x10rescheduledJobId\x12A\n" +
+	"\x0erescheduled_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\rrescheduledAt\x12\x16\n" +
+	"\x06passes\x18\x0f \x01(\x05R\x06passes\x12\x1f\n" +
+	"\vtotal_bytes\x18\x10 \x01(\x03R\n" +
+	"totalBytes\x12\x1d\n" +
+	"\n" +
+	"file_count\x18\x11 \x01(\x05R\tfileCount\x1a=\n" +
+	"\x0fPerAccountEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa1\x01\n" +
+	"\rFailedAccount\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"auth_error\x18\x03 \x01(\bR\tauthError\x12 \n" +
+	"\vmaintenance\x18\x04 \x01(\bR\vmaintenance\x12\x18\n" +
+	"\atimeout\x18\x05 \x01(\bR\atimeout\"\xe5\x02\n" +
+	"\rAccountResult\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12)\n" +
+	"\x10expected_records\x18\x02 \x01(\x05R\x0fexpectedRecords\x12%\n" +
+	"\x0eexpected_known\x18\x03 \x01(\bR\rexpectedKnown\x12%\n" +
+	"\x0eactual_records\x18\x04 \x01(\x05R\ractualRecords\x12%\n" +
+	"\x0ecount_mismatch\x18\x05 \x01(\bR\rcountMismatch\x12\x1c\n" +
+	"\ttruncated\x18\x06 \x01(\bR\ttruncated\x12+\n" +
+	"\x11certificate_paths\x18\a \x03(\tR\x10certificatePaths\x12/\n" +
+	"\x13certificates_failed\x18\b \x01(\bR\x12certificatesFailed\x12\x19\n" +
+	"\bcsv_path\x18\t \x01(\tR\acsvPath\"/\n" +
+	"\x17GetAllAccountIDsRequest\x12\x14\n" +
+	"\x05group\x18\x01 \x01(\tR\x05group\";\n" +
 	"\x18GetAllAccountIDsResponse\x12\x1f\n" +
 	"\vaccount_ids\x18\x01 \x03(\tR\n" +
-	"accountIds\" \n" +
-	"\x1eGetEnvironmentVariablesRequest\"\x94\x02\n" +
+	"accountIds\":\n" +
+	"\x17GetAccountStatusRequest\x12\x1f\n" +
+	"\vaccount_ids\x18\x01 \x03(\tR\n" +
+	"accountIds\"]\n" +
+	"\x18GetAccountStatusResponse\x12A\n" +
+	"\baccounts\x18\x01 \x03(\v2%.etc_meisai.download.v1.AccountStatusR\baccounts\"\xfc\x02\n" +
+	"\rAccountStatus\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1e\n" +
+	"\vlast_job_id\x18\x02 \x01(\tR\tlastJobId\x12:\n" +
+	"\vlast_run_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tlastRunAt\x12!\n" +
+	"\flast_outcome\x18\x04 \x01(\tR\vlastOutcome\x12B\n" +
+	"\x0flast_success_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\rlastSuccessAt\x12*\n" +
+	"\x11last_record_count\x18\x06 \x01(\x05R\x0flastRecordCount\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\a \x01(\tR\tlastError\x12>\n" +
+	"\rlast_error_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vlastErrorAt\"\x8b\x01\n" +
+	"\x12TestAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12(\n" +
+	"\raccount_index\x18\x03 \x01(\x05H\x00R\faccountIndex\x88\x01\x01B\x10\n" +
+	"\x0e_account_index\"Z\n" +
+	"\x13TestAccountResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x02 \x01(\x03R\tlatencyMs\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"[\n" +
+	"\x17UpdateCredentialRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"9\n" +
+	"\x18UpdateCredentialResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\" \n" +
+	"\x1eGetEnvironmentVariablesRequest\"\xf3\x03\n" +
 	"\x1fGetEnvironmentVariablesResponse\x12*\n" +
 	"\x11etc_corp_accounts\x18\x01 \x01(\tR\x0fetcCorpAccounts\x12!\n" +
 	"\fetc_headless\x18\x02 \x01(\tR\vetcHeadless\x12\x1b\n" +
 	"\tgrpc_port\x18\x03 \x01(\tR\bgrpcPort\x12\x1b\n" +
 	"\thttp_port\x18\x04 \x01(\tR\bhttpPort\x124\n" +
 	"\x16etc_corporate_accounts\x18\x05 \x01(\tR\x14etcCorporateAccounts\x122\n" +
-	"\x15etc_personal_accounts\x18\x06 \x01(\tR\x13etcPersonalAccounts\"5\n" +
+	"\x15etc_personal_accounts\x18\x06 \x01(\tR\x13etcPersonalAccounts\x12(\n" +
+	"\x10etc_download_dir\x18\a \x01(\tR\x0eetcDownloadDir\x12.\n" +
+	"\x13etc_max_concurrency\x18\b \x01(\tR\x11etcMaxConcurrency\x12$\n" +
+	"\x0eetc_timeout_ms\x18\t \x01(\tR\fetcTimeoutMs\x121\n" +
+	"\x14credential_overrides\x18\n" +
+	" \x03(\tR\x13credentialOverrides\x12*\n" +
+	"\x11etc_accounts_file\x18\v \x01(\tR\x0fetcAccountsFile\"\x8c\x01\n" +
 	"\x14GetServerLogsRequest\x12\x1d\n" +
 	"\n" +
-	"tail_lines\x18\x01 \x01(\x05R\ttailLines\"U\n" +
+	"tail_lines\x18\x01 \x01(\x05R\ttailLines\x12=\n" +
+	"\tmin_level\x18\x02 \x01(\x0e2 .etc_meisai.download.v1.LogLevelR\bminLevel\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"\xcd\x01\n" +
 	"\x15GetServerLogsResponse\x12\x1b\n" +
 	"\tlog_lines\x18\x01 \x03(\tR\blogLines\x12\x1f\n" +
 	"\vtotal_lines\x18\x02 \x01(\x05R\n" +
-	"totalLines\"\xf1\x03\n" +
+	"totalLines\x12:\n" +
+	"\aentries\x18\x03 \x03(\v2 .etc_meisai.download.v1.LogEntryR\aentries\x12\x1f\n" +
+	"\vnext_offset\x18\x04 \x01(\x05R\n" +
+	"nextOffset\x12\x19\n" +
+	"\bhas_more\x18\x05 \x01(\bR\ahasMore\"I\n" +
+	"\x11GetJobLogsRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1d\n" +
+	"\n" +
+	"tail_lines\x18\x02 \x01(\x05R\ttailLines\"\x84\x01\n" +
+	"\x12GetJobLogsResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tlog_lines\x18\x02 \x03(\tR\blogLines\x12:\n" +
+	"\aentries\x18\x03 \x03(\v2 .etc_meisai.download.v1.LogEntryR\aentries\"\x96\x01\n" +
+	"\bLogEntry\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x126\n" +
+	"\x05level\x18\x02 \x01(\x0e2 .etc_meisai.download.v1.LogLevelR\x05level\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\x13\n" +
+	"\x11GetVersionRequest\"\xc0\x01\n" +
+	"\x12GetVersionResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x16\n" +
+	"\x06commit\x18\x02 \x01(\tR\x06commit\x12\x1d\n" +
+	"\n" +
+	"build_time\x18\x03 \x01(\tR\tbuildTime\x12\x1d\n" +
+	"\n" +
+	"go_version\x18\x04 \x01(\tR\tgoVersion\x12:\n" +
+	"\x19playwright_driver_version\x18\x05 \x01(\tR\x17playwrightDriverVersion\"\x1a\n" +
+	"\x18GetRuntimeMetricsRequest\"\xc7\x03\n" +
+	"\x0eRuntimeMetrics\x12\x1f\n" +
+	"\vqueued_jobs\x18\x01 \x01(\x05R\n" +
+	"queuedJobs\x12!\n" +
+	"\frunning_jobs\x18\x02 \x01(\x05R\vrunningJobs\x12\x1f\n" +
+	"\vpaused_jobs\x18\x03 \x01(\x05R\n" +
+	"pausedJobs\x12'\n" +
+	"\x0fqueued_accounts\x18\x04 \x01(\x05R\x0equeuedAccounts\x12!\n" +
+	"\fbusy_workers\x18\x05 \x01(\x05R\vbusyWorkers\x12!\n" +
+	"\fidle_workers\x18\x06 \x01(\x05R\vidleWorkers\x12'\n" +
+	"\x0factive_browsers\x18\a \x01(\x05R\x0eactiveBrowsers\x122\n" +
+	"\x15rate_budget_remaining\x18\b \x01(\x05R\x13rateBudgetRemaining\x12(\n" +
+	"\x10log_buffer_lines\x18\t \x01(\x05R\x0elogBufferLines\x12.\n" +
+	"\x13log_buffer_capacity\x18\n" +
+	" \x01(\x05R\x11logBufferCapacity\x12*\n" +
+	"\x11log_dropped_lines\x18\v \x01(\x04R\x0flogDroppedLines\"8\n" +
+	"\x17StreamServerLogsRequest\x12\x1d\n" +
+	"\n" +
+	"tail_lines\x18\x01 \x01(\x05R\ttailLines\".\n" +
+	"\x18StreamServerLogsResponse\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\tR\x04line\"\xf1\x03\n" +
 	"\x0fETCMeisaiRecord\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
 	"\n" +
@@ -869,14 +3562,53 @@ const file_download_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt2\xad\x05\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt*|\n" +
+	"\fOutputFormat\x12\x1d\n" +
+	"\x19OUTPUT_FORMAT_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15OUTPUT_FORMAT_RECORDS\x10\x01\x12\x1a\n" +
+	"\x16OUTPUT_FORMAT_CSV_PATH\x10\x02\x12\x16\n" +
+	"\x12OUTPUT_FORMAT_BOTH\x10\x03*\xf1\x01\n" +
+	"\tErrorCode\x12\x1a\n" +
+	"\x16ERROR_CODE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16ERROR_CODE_AUTH_FAILED\x10\x01\x12\x16\n" +
+	"\x12ERROR_CODE_TIMEOUT\x10\x02\x12\x1a\n" +
+	"\x16ERROR_CODE_NO_ACCOUNTS\x10\x03\x12\x1a\n" +
+	"\x16ERROR_CODE_PARSE_ERROR\x10\x04\x12\x1e\n" +
+	"\x1aERROR_CODE_DOWNLOAD_FAILED\x10\x05\x12 \n" +
+	"\x1cERROR_CODE_DEADLINE_EXCEEDED\x10\x06\x12\x1a\n" +
+	"\x16ERROR_CODE_MAINTENANCE\x10\a*w\n" +
+	"\bLogLevel\x12\x19\n" +
+	"\x15LOG_LEVEL_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fLOG_LEVEL_DEBUG\x10\x01\x12\x12\n" +
+	"\x0eLOG_LEVEL_INFO\x10\x02\x12\x12\n" +
+	"\x0eLOG_LEVEL_WARN\x10\x03\x12\x13\n" +
+	"\x0fLOG_LEVEL_ERROR\x10\x042\xb5\x12\n" +
 	"\x0fDownloadService\x12a\n" +
 	"\fDownloadSync\x12'.etc_meisai.download.v1.DownloadRequest\x1a(.etc_meisai.download.v1.DownloadResponse\x12e\n" +
 	"\rDownloadAsync\x12'.etc_meisai.download.v1.DownloadRequest\x1a+.etc_meisai.download.v1.DownloadJobResponse\x12^\n" +
-	"\fGetJobStatus\x12+.etc_meisai.download.v1.GetJobStatusRequest\x1a!.etc_meisai.download.v1.JobStatus\x12u\n" +
-	"\x10GetAllAccountIDs\x12/.etc_meisai.download.v1.GetAllAccountIDsRequest\x1a0.etc_meisai.download.v1.GetAllAccountIDsResponse\x12\x8a\x01\n" +
+	"\fGetJobStatus\x12+.etc_meisai.download.v1.GetJobStatusRequest\x1a!.etc_meisai.download.v1.JobStatus\x12o\n" +
+	"\x0eGetJobStatuses\x12-.etc_meisai.download.v1.GetJobStatusesRequest\x1a..etc_meisai.download.v1.GetJobStatusesResponse\x12Z\n" +
+	"\n" +
+	"WaitForJob\x12).etc_meisai.download.v1.WaitForJobRequest\x1a!.etc_meisai.download.v1.JobStatus\x12V\n" +
+	"\bPauseJob\x12'.etc_meisai.download.v1.PauseJobRequest\x1a!.etc_meisai.download.v1.JobStatus\x12X\n" +
+	"\tResumeJob\x12(.etc_meisai.download.v1.ResumeJobRequest\x1a!.etc_meisai.download.v1.JobStatus\x12X\n" +
+	"\tCancelJob\x12(.etc_meisai.download.v1.CancelJobRequest\x1a!.etc_meisai.download.v1.JobStatus\x12i\n" +
+	"\fGetJobResult\x12+.etc_meisai.download.v1.GetJobResultRequest\x1a,.etc_meisai.download.v1.GetJobResultResponse\x12h\n" +
+	"\fExportJobCSV\x12+.etc_meisai.download.v1.ExportJobCSVRequest\x1a).etc_meisai.download.v1.ExportJobCSVChunk0\x01\x12f\n" +
+	"\vGetJobFiles\x12*.etc_meisai.download.v1.GetJobFilesRequest\x1a+.etc_meisai.download.v1.GetJobFilesResponse\x12h\n" +
+	"\fDownloadFile\x12+.etc_meisai.download.v1.DownloadFileRequest\x1a).etc_meisai.download.v1.DownloadFileChunk0\x01\x12u\n" +
+	"\x10GetAllAccountIDs\x12/.etc_meisai.download.v1.GetAllAccountIDsRequest\x1a0.etc_meisai.download.v1.GetAllAccountIDsResponse\x12u\n" +
+	"\x10GetAccountStatus\x12/.etc_meisai.download.v1.GetAccountStatusRequest\x1a0.etc_meisai.download.v1.GetAccountStatusResponse\x12f\n" +
+	"\vTestAccount\x12*.etc_meisai.download.v1.TestAccountRequest\x1a+.etc_meisai.download.v1.TestAccountResponse\x12u\n" +
+	"\x10UpdateCredential\x12/.etc_meisai.download.v1.UpdateCredentialRequest\x1a0.etc_meisai.download.v1.UpdateCredentialResponse\x12\x8a\x01\n" +
 	"\x17GetEnvironmentVariables\x126.etc_meisai.download.v1.GetEnvironmentVariablesRequest\x1a7.etc_meisai.download.v1.GetEnvironmentVariablesResponse\x12l\n" +
-	"\rGetServerLogs\x12,.etc_meisai.download.v1.GetServerLogsRequest\x1a-.etc_meisai.download.v1.GetServerLogsResponseB<Z:github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pbb\x06proto3"
+	"\rGetServerLogs\x12,.etc_meisai.download.v1.GetServerLogsRequest\x1a-.etc_meisai.download.v1.GetServerLogsResponse\x12c\n" +
+	"\n" +
+	"GetJobLogs\x12).etc_meisai.download.v1.GetJobLogsRequest\x1a*.etc_meisai.download.v1.GetJobLogsResponse\x12m\n" +
+	"\x11GetRuntimeMetrics\x120.etc_meisai.download.v1.GetRuntimeMetricsRequest\x1a&.etc_meisai.download.v1.RuntimeMetrics\x12c\n" +
+	"\n" +
+	"GetVersion\x12).etc_meisai.download.v1.GetVersionRequest\x1a*.etc_meisai.download.v1.GetVersionResponse\x12w\n" +
+	"\x10StreamServerLogs\x12/.etc_meisai.download.v1.StreamServerLogsRequest\x1a0.etc_meisai.download.v1.StreamServerLogsResponse0\x01B<Z:github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pbb\x06proto3"
 
 var (
 	file_download_proto_rawDescOnce sync.Once
@@ -890,47 +3622,137 @@ func file_download_proto_rawDescGZIP() []byte {
 	return file_download_proto_rawDescData
 }
 
-var file_download_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_download_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_download_proto_msgTypes = make([]protoimpl.MessageInfo, 47)
 var file_download_proto_goTypes = []any{
-	(*DownloadRequest)(nil),                 // 0: etc_meisai.download.v1.DownloadRequest
-	(*DownloadResponse)(nil),                // 1: etc_meisai.download.v1.DownloadResponse
-	(*DownloadJobResponse)(nil),             // 2: etc_meisai.download.v1.DownloadJobResponse
-	(*GetJobStatusRequest)(nil),             // 3: etc_meisai.download.v1.GetJobStatusRequest
-	(*JobStatus)(nil),                       // 4: etc_meisai.download.v1.JobStatus
-	(*GetAllAccountIDsRequest)(nil),         // 5: etc_meisai.download.v1.GetAllAccountIDsRequest
-	(*GetAllAccountIDsResponse)(nil),        // 6: etc_meisai.download.v1.GetAllAccountIDsResponse
-	(*GetEnvironmentVariablesRequest)(nil),  // 7: etc_meisai.download.v1.GetEnvironmentVariablesRequest
-	(*GetEnvironmentVariablesResponse)(nil), // 8: etc_meisai.download.v1.GetEnvironmentVariablesResponse
-	(*GetServerLogsRequest)(nil),            // 9: etc_meisai.download.v1.GetServerLogsRequest
-	(*GetServerLogsResponse)(nil),           // 10: etc_meisai.download.v1.GetServerLogsResponse
-	(*ETCMeisaiRecord)(nil),                 // 11: etc_meisai.download.v1.ETCMeisaiRecord
-	(*timestamppb.Timestamp)(nil),           // 12: google.protobuf.Timestamp
+	(OutputFormat)(0),                       // 0: etc_meisai.download.v1.OutputFormat
+	(ErrorCode)(0),                          // 1: etc_meisai.download.v1.ErrorCode
+	(LogLevel)(0),                           // 2: etc_meisai.download.v1.LogLevel
+	(*DownloadRequest)(nil),                 // 3: etc_meisai.download.v1.DownloadRequest
+	(*DownloadResponse)(nil),                // 4: etc_meisai.download.v1.DownloadResponse
+	(*DownloadJobResponse)(nil),             // 5: etc_meisai.download.v1.DownloadJobResponse
+	(*GetJobStatusRequest)(nil),             // 6: etc_meisai.download.v1.GetJobStatusRequest
+	(*WaitForJobRequest)(nil),               // 7: etc_meisai.download.v1.WaitForJobRequest
+	(*GetJobStatusesRequest)(nil),           // 8: etc_meisai.download.v1.GetJobStatusesRequest
+	(*GetJobStatusesResponse)(nil),          // 9: etc_meisai.download.v1.GetJobStatusesResponse
+	(*JobStatusEntry)(nil),                  // 10: etc_meisai.download.v1.JobStatusEntry
+	(*PauseJobRequest)(nil),                 // 11: etc_meisai.download.v1.PauseJobRequest
+	(*ResumeJobRequest)(nil),                // 12: etc_meisai.download.v1.ResumeJobRequest
+	(*CancelJobRequest)(nil),                // 13: etc_meisai.download.v1.CancelJobRequest
+	(*GetJobResultRequest)(nil),             // 14: etc_meisai.download.v1.GetJobResultRequest
+	(*GetJobResultResponse)(nil),            // 15: etc_meisai.download.v1.GetJobResultResponse
+	(*ExportJobCSVRequest)(nil),             // 16: etc_meisai.download.v1.ExportJobCSVRequest
+	(*ExportJobCSVChunk)(nil),               // 17: etc_meisai.download.v1.ExportJobCSVChunk
+	(*GetJobFilesRequest)(nil),              // 18: etc_meisai.download.v1.GetJobFilesRequest
+	(*GetJobFilesResponse)(nil),             // 19: etc_meisai.download.v1.GetJobFilesResponse
+	(*JobFile)(nil),                         // 20: etc_meisai.download.v1.JobFile
+	(*DownloadFileRequest)(nil),             // 21: etc_meisai.download.v1.DownloadFileRequest
+	(*DownloadFileChunk)(nil),               // 22: etc_meisai.download.v1.DownloadFileChunk
+	(*JobStatus)(nil),                       // 23: etc_meisai.download.v1.JobStatus
+	(*FailedAccount)(nil),                   // 24: etc_meisai.download.v1.FailedAccount
+	(*AccountResult)(nil),                   // 25: etc_meisai.download.v1.AccountResult
+	(*GetAllAccountIDsRequest)(nil),         // 26: etc_meisai.download.v1.GetAllAccountIDsRequest
+	(*GetAllAccountIDsResponse)(nil),        // 27: etc_meisai.download.v1.GetAllAccountIDsResponse
+	(*GetAccountStatusRequest)(nil),         // 28: etc_meisai.download.v1.GetAccountStatusRequest
+	(*GetAccountStatusResponse)(nil),        // 29: etc_meisai.download.v1.GetAccountStatusResponse
+	(*AccountStatus)(nil),                   // 30: etc_meisai.download.v1.AccountStatus
+	(*TestAccountRequest)(nil),              // 31: etc_meisai.download.v1.TestAccountRequest
+	(*TestAccountResponse)(nil),             // 32: etc_meisai.download.v1.TestAccountResponse
+	(*UpdateCredentialRequest)(nil),         // 33: etc_meisai.download.v1.UpdateCredentialRequest
+	(*UpdateCredentialResponse)(nil),        // 34: etc_meisai.download.v1.UpdateCredentialResponse
+	(*GetEnvironmentVariablesRequest)(nil),  // 35: etc_meisai.download.v1.GetEnvironmentVariablesRequest
+	(*GetEnvironmentVariablesResponse)(nil), // 36: etc_meisai.download.v1.GetEnvironmentVariablesResponse
+	(*GetServerLogsRequest)(nil),            // 37: etc_meisai.download.v1.GetServerLogsRequest
+	(*GetServerLogsResponse)(nil),           // 38: etc_meisai.download.v1.GetServerLogsResponse
+	(*GetJobLogsRequest)(nil),               // 39: etc_meisai.download.v1.GetJobLogsRequest
+	(*GetJobLogsResponse)(nil),              // 40: etc_meisai.download.v1.GetJobLogsResponse
+	(*LogEntry)(nil),                        // 41: etc_meisai.download.v1.LogEntry
+	(*GetVersionRequest)(nil),               // 42: etc_meisai.download.v1.GetVersionRequest
+	(*GetVersionResponse)(nil),              // 43: etc_meisai.download.v1.GetVersionResponse
+	(*GetRuntimeMetricsRequest)(nil),        // 44: etc_meisai.download.v1.GetRuntimeMetricsRequest
+	(*RuntimeMetrics)(nil),                  // 45: etc_meisai.download.v1.RuntimeMetrics
+	(*StreamServerLogsRequest)(nil),         // 46: etc_meisai.download.v1.StreamServerLogsRequest
+	(*StreamServerLogsResponse)(nil),        // 47: etc_meisai.download.v1.StreamServerLogsResponse
+	(*ETCMeisaiRecord)(nil),                 // 48: etc_meisai.download.v1.ETCMeisaiRecord
+	nil,                                     // 49: etc_meisai.download.v1.JobStatus.PerAccountEntry
+	(*timestamppb.Timestamp)(nil),           // 50: google.protobuf.Timestamp
 }
 var file_download_proto_depIdxs = []int32{
-	11, // 0: etc_meisai.download.v1.DownloadResponse.records:type_name -> etc_meisai.download.v1.ETCMeisaiRecord
-	12, // 1: etc_meisai.download.v1.JobStatus.started_at:type_name -> google.protobuf.Timestamp
-	12, // 2: etc_meisai.download.v1.JobStatus.completed_at:type_name -> google.protobuf.Timestamp
-	12, // 3: etc_meisai.download.v1.ETCMeisaiRecord.usage_date:type_name -> google.protobuf.Timestamp
-	12, // 4: etc_meisai.download.v1.ETCMeisaiRecord.downloaded_at:type_name -> google.protobuf.Timestamp
-	12, // 5: etc_meisai.download.v1.ETCMeisaiRecord.created_at:type_name -> google.protobuf.Timestamp
-	12, // 6: etc_meisai.download.v1.ETCMeisaiRecord.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 7: etc_meisai.download.v1.DownloadService.DownloadSync:input_type -> etc_meisai.download.v1.DownloadRequest
-	0,  // 8: etc_meisai.download.v1.DownloadService.DownloadAsync:input_type -> etc_meisai.download.v1.DownloadRequest
-	3,  // 9: etc_meisai.download.v1.DownloadService.GetJobStatus:input_type -> etc_meisai.download.v1.GetJobStatusRequest
-	5,  // 10: etc_meisai.download.v1.DownloadService.GetAllAccountIDs:input_type -> etc_meisai.download.v1.GetAllAccountIDsRequest
-	7,  // 11: etc_meisai.download.v1.DownloadService.GetEnvironmentVariables:input_type -> etc_meisai.download.v1.GetEnvironmentVariablesRequest
-	9,  // 12: etc_meisai.download.v1.DownloadService.GetServerLogs:input_type -> etc_meisai.download.v1.GetServerLogsRequest
-	1,  // 13: etc_meisai.download.v1.DownloadService.DownloadSync:output_type -> etc_meisai.download.v1.DownloadResponse
-	2,  // 14: etc_meisai.download.v1.DownloadService.DownloadAsync:output_type -> etc_meisai.download.v1.DownloadJobResponse
-	4,  // 15: etc_meisai.download.v1.DownloadService.GetJobStatus:output_type -> etc_meisai.download.v1.JobStatus
-	6,  // 16: etc_meisai.download.v1.DownloadService.GetAllAccountIDs:output_type -> etc_meisai.download.v1.GetAllAccountIDsResponse
-	8,  // 17: etc_meisai.download.v1.DownloadService.GetEnvironmentVariables:output_type -> etc_meisai.download.v1.GetEnvironmentVariablesResponse
-	10, // 18: etc_meisai.download.v1.DownloadService.GetServerLogs:output_type -> etc_meisai.download.v1.GetServerLogsResponse
-	13, // [13:19] is the sub-list for method output_type
-	7,  // [7:13] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	0,  // 0: etc_meisai.download.v1.DownloadRequest.output_format:type_name -> etc_meisai.download.v1.OutputFormat
+	48, // 1: etc_meisai.download.v1.DownloadResponse.records:type_name -> etc_meisai.download.v1.ETCMeisaiRecord
+	1,  // 2: etc_meisai.download.v1.DownloadResponse.error_code:type_name -> etc_meisai.download.v1.ErrorCode
+	10, // 3: etc_meisai.download.v1.GetJobStatusesResponse.entries:type_name -> etc_meisai.download.v1.JobStatusEntry
+	23, // 4: etc_meisai.download.v1.JobStatusEntry.status:type_name -> etc_meisai.download.v1.JobStatus
+	48, // 5: etc_meisai.download.v1.GetJobResultResponse.records:type_name -> etc_meisai.download.v1.ETCMeisaiRecord
+	20, // 6: etc_meisai.download.v1.GetJobFilesResponse.files:type_name -> etc_meisai.download.v1.JobFile
+	50, // 7: etc_meisai.download.v1.JobStatus.started_at:type_name -> google.protobuf.Timestamp
+	50, // 8: etc_meisai.download.v1.JobStatus.completed_at:type_name -> google.protobuf.Timestamp
+	25, // 9: etc_meisai.download.v1.JobStatus.account_results:type_name -> etc_meisai.download.v1.AccountResult
+	49, // 10: etc_meisai.download.v1.JobStatus.per_account:type_name -> etc_meisai.download.v1.JobStatus.PerAccountEntry
+	24, // 11: etc_meisai.download.v1.JobStatus.failed_accounts:type_name -> etc_meisai.download.v1.FailedAccount
+	50, // 12: etc_meisai.download.v1.JobStatus.rescheduled_at:type_name -> google.protobuf.Timestamp
+	30, // 13: etc_meisai.download.v1.GetAccountStatusResponse.accounts:type_name -> etc_meisai.download.v1.AccountStatus
+	50, // 14: etc_meisai.download.v1.AccountStatus.last_run_at:type_name -> google.protobuf.Timestamp
+	50, // 15: etc_meisai.download.v1.AccountStatus.last_success_at:type_name -> google.protobuf.Timestamp
+	50, // 16: etc_meisai.download.v1.AccountStatus.last_error_at:type_name -> google.protobuf.Timestamp
+	2,  // 17: etc_meisai.download.v1.GetServerLogsRequest.min_level:type_name -> etc_meisai.download.v1.LogLevel
+	41, // 18: etc_meisai.download.v1.GetServerLogsResponse.entries:type_name -> etc_meisai.download.v1.LogEntry
+	41, // 19: etc_meisai.download.v1.GetJobLogsResponse.entries:type_name -> etc_meisai.download.v1.LogEntry
+	50, // 20: etc_meisai.download.v1.LogEntry.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 21: etc_meisai.download.v1.LogEntry.level:type_name -> etc_meisai.download.v1.LogLevel
+	50, // 22: etc_meisai.download.v1.ETCMeisaiRecord.usage_date:type_name -> google.protobuf.Timestamp
+	50, // 23: etc_meisai.download.v1.ETCMeisaiRecord.downloaded_at:type_name -> google.protobuf.Timestamp
+	50, // 24: etc_meisai.download.v1.ETCMeisaiRecord.created_at:type_name -> google.protobuf.Timestamp
+	50, // 25: etc_meisai.download.v1.ETCMeisaiRecord.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 26: etc_meisai.download.v1.DownloadService.DownloadSync:input_type -> etc_meisai.download.v1.DownloadRequest
+	3,  // 27: etc_meisai.download.v1.DownloadService.DownloadAsync:input_type -> etc_meisai.download.v1.DownloadRequest
+	6,  // 28: etc_meisai.download.v1.DownloadService.GetJobStatus:input_type -> etc_meisai.download.v1.GetJobStatusRequest
+	8,  // 29: etc_meisai.download.v1.DownloadService.GetJobStatuses:input_type -> etc_meisai.download.v1.GetJobStatusesRequest
+	7,  // 30: etc_meisai.download.v1.DownloadService.WaitForJob:input_type -> etc_meisai.download.v1.WaitForJobRequest
+	11, // 31: etc_meisai.download.v1.DownloadService.PauseJob:input_type -> etc_meisai.download.v1.PauseJobRequest
+	12, // 32: etc_meisai.download.v1.DownloadService.ResumeJob:input_type -> etc_meisai.download.v1.ResumeJobRequest
+	13, // 33: etc_meisai.download.v1.DownloadService.CancelJob:input_type -> etc_meisai.download.v1.CancelJobRequest
+	14, // 34: etc_meisai.download.v1.DownloadService.GetJobResult:input_type -> etc_meisai.download.v1.GetJobResultRequest
+	16, // 35: etc_meisai.download.v1.DownloadService.ExportJobCSV:input_type -> etc_meisai.download.v1.ExportJobCSVRequest
+	18, // 36: etc_meisai.download.v1.DownloadService.GetJobFiles:input_type -> etc_meisai.download.v1.GetJobFilesRequest
+	21, // 37: etc_meisai.download.v1.DownloadService.DownloadFile:input_type -> etc_meisai.download.v1.DownloadFileRequest
+	26, // 38: etc_meisai.download.v1.DownloadService.GetAllAccountIDs:input_type -> etc_meisai.download.v1.GetAllAccountIDsRequest
+	28, // 39: etc_meisai.download.v1.DownloadService.GetAccountStatus:input_type -> etc_meisai.download.v1.GetAccountStatusRequest
+	31, // 40: etc_meisai.download.v1.DownloadService.TestAccount:input_type -> etc_meisai.download.v1.TestAccountRequest
+	33, // 41: etc_meisai.download.v1.DownloadService.UpdateCredential:input_type -> etc_meisai.download.v1.UpdateCredentialRequest
+	35, // 42: etc_meisai.download.v1.DownloadService.GetEnvironmentVariables:input_type -> etc_meisai.download.v1.GetEnvironmentVariablesRequest
+	37, // 43: etc_meisai.download.v1.DownloadService.GetServerLogs:input_type -> etc_meisai.download.v1.GetServerLogsRequest
+	39, // 44: etc_meisai.download.v1.DownloadService.GetJobLogs:input_type -> etc_meisai.download.v1.GetJobLogsRequest
+	44, // 45: etc_meisai.download.v1.DownloadService.GetRuntimeMetrics:input_type -> etc_meisai.download.v1.GetRuntimeMetricsRequest
+	42, // 46: etc_meisai.download.v1.DownloadService.GetVersion:input_type -> etc_meisai.download.v1.GetVersionRequest
+	46, // 47: etc_meisai.download.v1.DownloadService.StreamServerLogs:input_type -> etc_meisai.download.v1.StreamServerLogsRequest
+	4,  // 48: etc_meisai.download.v1.DownloadService.DownloadSync:output_type -> etc_meisai.download.v1.DownloadResponse
+	5,  // 49: etc_meisai.download.v1.DownloadService.DownloadAsync:output_type -> etc_meisai.download.v1.DownloadJobResponse
+	23, // 50: etc_meisai.download.v1.DownloadService.GetJobStatus:output_type -> etc_meisai.download.v1.JobStatus
+	9,  // 51: etc_meisai.download.v1.DownloadService.GetJobStatuses:output_type -> etc_meisai.download.v1.GetJobStatusesResponse
+	23, // 52: etc_meisai.download.v1.DownloadService.WaitForJob:output_type -> etc_meisai.download.v1.JobStatus
+	23, // 53: etc_meisai.download.v1.DownloadService.PauseJob:output_type -> etc_meisai.download.v1.JobStatus
+	23, // 54: etc_meisai.download.v1.DownloadService.ResumeJob:output_type -> etc_meisai.download.v1.JobStatus
+	23, // 55: etc_meisai.download.v1.DownloadService.CancelJob:output_type -> etc_meisai.download.v1.JobStatus
+	15, // 56: etc_meisai.download.v1.DownloadService.GetJobResult:output_type -> etc_meisai.download.v1.GetJobResultResponse
+	17, // 57: etc_meisai.download.v1.DownloadService.ExportJobCSV:output_type -> etc_meisai.download.v1.ExportJobCSVChunk
+	19, // 58: etc_meisai.download.v1.DownloadService.GetJobFiles:output_type -> etc_meisai.download.v1.GetJobFilesResponse
+	22, // 59: etc_meisai.download.v1.DownloadService.DownloadFile:output_type -> etc_meisai.download.v1.DownloadFileChunk
+	27, // 60: etc_meisai.download.v1.DownloadService.GetAllAccountIDs:output_type -> etc_meisai.download.v1.GetAllAccountIDsResponse
+	29, // 61: etc_meisai.download.v1.DownloadService.GetAccountStatus:output_type -> etc_meisai.download.v1.GetAccountStatusResponse
+	32, // 62: etc_meisai.download.v1.DownloadService.TestAccount:output_type -> etc_meisai.download.v1.TestAccountResponse
+	34, // 63: etc_meisai.download.v1.DownloadService.UpdateCredential:output_type -> etc_meisai.download.v1.UpdateCredentialResponse
+	36, // 64: etc_meisai.download.v1.DownloadService.GetEnvironmentVariables:output_type -> etc_meisai.download.v1.GetEnvironmentVariablesResponse
+	38, // 65: etc_meisai.download.v1.DownloadService.GetServerLogs:output_type -> etc_meisai.download.v1.GetServerLogsResponse
+	40, // 66: etc_meisai.download.v1.DownloadService.GetJobLogs:output_type -> etc_meisai.download.v1.GetJobLogsResponse
+	45, // 67: etc_meisai.download.v1.DownloadService.GetRuntimeMetrics:output_type -> etc_meisai.download.v1.RuntimeMetrics
+	43, // 68: etc_meisai.download.v1.DownloadService.GetVersion:output_type -> etc_meisai.download.v1.GetVersionResponse
+	47, // 69: etc_meisai.download.v1.DownloadService.StreamServerLogs:output_type -> etc_meisai.download.v1.StreamServerLogsResponse
+	48, // [48:70] is the sub-list for method output_type
+	26, // [26:48] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_download_proto_init() }
@@ -938,18 +3760,21 @@ func file_download_proto_init() {
 	if File_download_proto != nil {
 		return
 	}
+	file_download_proto_msgTypes[0].OneofWrappers = []any{}
+	file_download_proto_msgTypes[28].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_download_proto_rawDesc), len(file_download_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   12,
+			NumEnums:      3,
+			NumMessages:   47,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_download_proto_goTypes,
 		DependencyIndexes: file_download_proto_depIdxs,
+		EnumInfos:         file_download_proto_enumTypes,
 		MessageInfos:      file_download_proto_msgTypes,
 	}.Build()
 	File_download_proto = out.File
@@ -22,9 +22,25 @@ const (
 	DownloadService_DownloadSync_FullMethodName            = "/etc_meisai.download.v1.DownloadService/DownloadSync"
 	DownloadService_DownloadAsync_FullMethodName           = "/etc_meisai.download.v1.DownloadService/DownloadAsync"
 	DownloadService_GetJobStatus_FullMethodName            = "/etc_meisai.download.v1.DownloadService/GetJobStatus"
+	DownloadService_GetJobStatuses_FullMethodName          = "/etc_meisai.download.v1.DownloadService/GetJobStatuses"
+	DownloadService_WaitForJob_FullMethodName              = "/etc_meisai.download.v1.DownloadService/WaitForJob"
+	DownloadService_PauseJob_FullMethodName                = "/etc_meisai.download.v1.DownloadService/PauseJob"
+	DownloadService_ResumeJob_FullMethodName               = "/etc_meisai.download.v1.DownloadService/ResumeJob"
+	DownloadService_CancelJob_FullMethodName               = "/etc_meisai.download.v1.DownloadService/CancelJob"
+	DownloadService_GetJobResult_FullMethodName            = "/etc_meisai.download.v1.DownloadService/GetJobResult"
+	DownloadService_ExportJobCSV_FullMethodName            = "/etc_meisai.download.v1.DownloadService/ExportJobCSV"
+	DownloadService_GetJobFiles_FullMethodName             = "/etc_meisai.download.v1.DownloadService/GetJobFiles"
+	DownloadService_DownloadFile_FullMethodName            = "/etc_meisai.download.v1.DownloadService/DownloadFile"
 	DownloadService_GetAllAccountIDs_FullMethodName        = "/etc_meisai.download.v1.DownloadService/GetAllAccountIDs"
+	DownloadService_GetAccountStatus_FullMethodName        = "/etc_meisai.download.v1.DownloadService/GetAccountStatus"
+	DownloadService_TestAccount_FullMethodName             = "/etc_meisai.download.v1.DownloadService/TestAccount"
+	DownloadService_UpdateCredential_FullMethodName        = "/etc_meisai.download.v1.DownloadService/UpdateCredential"
 	DownloadService_GetEnvironmentVariables_FullMethodName = "/etc_meisai.download.v1.DownloadService/GetEnvironmentVariables"
 	DownloadService_GetServerLogs_FullMethodName           = "/etc_meisai.download.v1.DownloadService/GetServerLogs"
+	DownloadService_GetJobLogs_FullMethodName              = "/etc_meisai.download.v1.DownloadService/GetJobLogs"
+	DownloadService_GetRuntimeMetrics_FullMethodName       = "/etc_meisai.download.v1.DownloadService/GetRuntimeMetrics"
+	DownloadService_GetVersion_FullMethodName              = "/etc_meisai.download.v1.DownloadService/GetVersion"
+	DownloadService_StreamServerLogs_FullMethodName        = "/etc_meisai.download.v1.DownloadService/StreamServerLogs"
 )
 
 // DownloadServiceClient is the client API for DownloadService service.
@@ -34,17 +50,55 @@ const (
 // ダウンロードサービス
 type DownloadServiceClient interface {
 	// 同期ダウンロード
+	// ダウンロードの失敗はgRPCのエラーではなく、success=falseとerror_codeで返す（gRPCのステータスはOK）
+	// 呼び出し元のdeadlineまでに終わらなかった場合もERROR_CODE_DEADLINE_EXCEEDEDとtruncated=trueで完了したアカウントの明細を返すため、
+	// 呼び出し元は必ずsuccessとerror_codeを確認すること（gRPCのエラーになるのはリクエストの不備・呼び出し元のキャンセル・サーバー内部のエラーのみ）
 	DownloadSync(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (*DownloadResponse, error)
 	// 非同期ダウンロード開始
 	DownloadAsync(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (*DownloadJobResponse, error)
 	// ジョブステータス取得
 	GetJobStatus(ctx context.Context, in *GetJobStatusRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	// 複数ジョブのステータス一括取得（存在しないジョブはfound=falseとして返す）
+	GetJobStatuses(ctx context.Context, in *GetJobStatusesRequest, opts ...grpc.CallOption) (*GetJobStatusesResponse, error)
+	// ジョブのstatusが変わるか終了するまで待ってステータスを返す（GetJobStatusのロングポーリング）
+	// 呼び出し元のdeadlineの少し前までに変わらなければ、エラーにせずその時点のステータスを返す
+	WaitForJob(ctx context.Context, in *WaitForJobRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	// ジョブ一時停止（処理中のアカウント完了後に停止）
+	PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	// 一時停止中のジョブを再開
+	// プロセスの再起動で中断した（interruptedの）ジョブは、完了していないアカウントのみを元のセッションフォルダで処理する
+	ResumeJob(ctx context.Context, in *ResumeJobRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	// ジョブをキャンセル（処理中のアカウント完了後に未処理のアカウントを処理せず終了）
+	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	// 終了したジョブでダウンロードした明細を取得（ページング）
+	// 複数のアカウントで同じ利用（利用日時・IC・料金・ETCカード番号が同じ）をダウンロードした場合は、先に処理したアカウントの明細のみ返す
+	GetJobResult(ctx context.Context, in *GetJobResultRequest, opts ...grpc.CallOption) (*GetJobResultResponse, error)
+	// 終了したジョブの全アカウントの明細をCSV（UTF-8、ヘッダー行付き）で分割して送信
+	ExportJobCSV(ctx context.Context, in *ExportJobCSVRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportJobCSVChunk], error)
+	// 終了したジョブのセッションフォルダのファイル一覧取得（ファイル名・サイズ・保存したアカウント）
+	GetJobFiles(ctx context.Context, in *GetJobFilesRequest, opts ...grpc.CallOption) (*GetJobFilesResponse, error)
+	// 終了したジョブのセッションフォルダのファイルを分割して送信（nameはGetJobFilesのファイル名）
+	DownloadFile(ctx context.Context, in *DownloadFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadFileChunk], error)
 	// 全アカウントID取得
 	GetAllAccountIDs(ctx context.Context, in *GetAllAccountIDsRequest, opts ...grpc.CallOption) (*GetAllAccountIDsResponse, error)
+	// アカウントごとの最後の実行結果取得（最後の成功・エラー・件数、認証情報は含めない）
+	GetAccountStatus(ctx context.Context, in *GetAccountStatusRequest, opts ...grpc.CallOption) (*GetAccountStatusResponse, error)
+	// 単一アカウントのログイン確認（ジョブを作成せずInitialize・Loginのみ実行）
+	TestAccount(ctx context.Context, in *TestAccountRequest, opts ...grpc.CallOption) (*TestAccountResponse, error)
+	// アカウントのパスワード更新（環境変数の値をメモリ上で上書きし、再起動すると元に戻る）
+	UpdateCredential(ctx context.Context, in *UpdateCredentialRequest, opts ...grpc.CallOption) (*UpdateCredentialResponse, error)
 	// 環境変数取得（デバッグ用）
 	GetEnvironmentVariables(ctx context.Context, in *GetEnvironmentVariablesRequest, opts ...grpc.CallOption) (*GetEnvironmentVariablesResponse, error)
 	// サーバーログ取得（デバッグ用）
 	GetServerLogs(ctx context.Context, in *GetServerLogsRequest, opts ...grpc.CallOption) (*GetServerLogsResponse, error)
+	// ジョブごとのログ取得（他のジョブやサーバー全体のログを含まない）
+	GetJobLogs(ctx context.Context, in *GetJobLogsRequest, opts ...grpc.CallOption) (*GetJobLogsResponse, error)
+	// 稼働状況のスナップショット取得（監視用）
+	GetRuntimeMetrics(ctx context.Context, in *GetRuntimeMetricsRequest, opts ...grpc.CallOption) (*RuntimeMetrics, error)
+	// ビルド情報取得（バージョン・コミット・ビルド日時）
+	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error)
+	// サーバーログのストリーミング（末尾の既存ログを送信後、新しいログを逐次送信）
+	StreamServerLogs(ctx context.Context, in *StreamServerLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamServerLogsResponse], error)
 }
 
 type downloadServiceClient struct {
@@ -85,6 +139,114 @@ func (c *downloadServiceClient) GetJobStatus(ctx context.Context, in *GetJobStat
 	return out, nil
 }
 
+func (c *downloadServiceClient) GetJobStatuses(ctx context.Context, in *GetJobStatusesRequest, opts ...grpc.CallOption) (*GetJobStatusesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobStatusesResponse)
+	err := c.cc.Invoke(ctx, DownloadService_GetJobStatuses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) WaitForJob(ctx context.Context, in *WaitForJobRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, DownloadService_WaitForJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, DownloadService_PauseJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) ResumeJob(ctx context.Context, in *ResumeJobRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, DownloadService_ResumeJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, DownloadService_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) GetJobResult(ctx context.Context, in *GetJobResultRequest, opts ...grpc.CallOption) (*GetJobResultResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobResultResponse)
+	err := c.cc.Invoke(ctx, DownloadService_GetJobResult_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) ExportJobCSV(ctx context.Context, in *ExportJobCSVRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportJobCSVChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DownloadService_ServiceDesc.Streams[0], DownloadService_ExportJobCSV_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportJobCSVRequest, ExportJobCSVChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DownloadService_ExportJobCSVClient = grpc.ServerStreamingClient[ExportJobCSVChunk]
+
+func (c *downloadServiceClient) GetJobFiles(ctx context.Context, in *GetJobFilesRequest, opts ...grpc.CallOption) (*GetJobFilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobFilesResponse)
+	err := c.cc.Invoke(ctx, DownloadService_GetJobFiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) DownloadFile(ctx context.Context, in *DownloadFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadFileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DownloadService_ServiceDesc.Streams[1], DownloadService_DownloadFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadFileRequest, DownloadFileChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DownloadService_DownloadFileClient = grpc.ServerStreamingClient[DownloadFileChunk]
+
 func (c *downloadServiceClient) GetAllAccountIDs(ctx context.Context, in *GetAllAccountIDsRequest, opts ...grpc.CallOption) (*GetAllAccountIDsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetAllAccountIDsResponse)
@@ -95,6 +257,36 @@ func (c *downloadServiceClient) GetAllAccountIDs(ctx context.Context, in *GetAll
 	return out, nil
 }
 
+func (c *downloadServiceClient) GetAccountStatus(ctx context.Context, in *GetAccountStatusRequest, opts ...grpc.CallOption) (*GetAccountStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAccountStatusResponse)
+	err := c.cc.Invoke(ctx, DownloadService_GetAccountStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) TestAccount(ctx context.Context, in *TestAccountRequest, opts ...grpc.CallOption) (*TestAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TestAccountResponse)
+	err := c.cc.Invoke(ctx, DownloadService_TestAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) UpdateCredential(ctx context.Context, in *UpdateCredentialRequest, opts ...grpc.CallOption) (*UpdateCredentialResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateCredentialResponse)
+	err := c.cc.Invoke(ctx, DownloadService_UpdateCredential_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *downloadServiceClient) GetEnvironmentVariables(ctx context.Context, in *GetEnvironmentVariablesRequest, opts ...grpc.CallOption) (*GetEnvironmentVariablesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetEnvironmentVariablesResponse)
@@ -115,6 +307,55 @@ func (c *downloadServiceClient) GetServerLogs(ctx context.Context, in *GetServer
 	return out, nil
 }
 
+func (c *downloadServiceClient) GetJobLogs(ctx context.Context, in *GetJobLogsRequest, opts ...grpc.CallOption) (*GetJobLogsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobLogsResponse)
+	err := c.cc.Invoke(ctx, DownloadService_GetJobLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) GetRuntimeMetrics(ctx context.Context, in *GetRuntimeMetricsRequest, opts ...grpc.CallOption) (*RuntimeMetrics, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RuntimeMetrics)
+	err := c.cc.Invoke(ctx, DownloadService_GetRuntimeMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVersionResponse)
+	err := c.cc.Invoke(ctx, DownloadService_GetVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *downloadServiceClient) StreamServerLogs(ctx context.Context, in *StreamServerLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamServerLogsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DownloadService_ServiceDesc.Streams[2], DownloadService_StreamServerLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamServerLogsRequest, StreamServerLogsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DownloadService_StreamServerLogsClient = grpc.ServerStreamingClient[StreamServerLogsResponse]
+
 // DownloadServiceServer is the server API for DownloadService service.
 // All implementations should embed UnimplementedDownloadServiceServer
 // for forward compatibility.
@@ -122,17 +363,55 @@ func (c *downloadServiceClient) GetServerLogs(ctx context.Context, in *GetServer
 // ダウンロードサービス
 type DownloadServiceServer interface {
 	// 同期ダウンロード
+	// ダウンロードの失敗はgRPCのエラーではなく、success=falseとerror_codeで返す（gRPCのステータスはOK）
+	// 呼び出し元のdeadlineまでに終わらなかった場合もERROR_CODE_DEADLINE_EXCEEDEDとtruncated=trueで完了したアカウントの明細を返すため、
+	// 呼び出し元は必ずsuccessとerror_codeを確認すること（gRPCのエラーになるのはリクエストの不備・呼び出し元のキャンセル・サーバー内部のエラーのみ）
 	DownloadSync(context.Context, *DownloadRequest) (*DownloadResponse, error)
 	// 非同期ダウンロード開始
 	DownloadAsync(context.Context, *DownloadRequest) (*DownloadJobResponse, error)
 	// ジョブステータス取得
 	GetJobStatus(context.Context, *GetJobStatusRequest) (*JobStatus, error)
+	// 複数ジョブのステータス一括取得（存在しないジョブはfound=falseとして返す）
+	GetJobStatuses(context.Context, *GetJobStatusesRequest) (*GetJobStatusesResponse, error)
+	// ジョブのstatusが変わるか終了するまで待ってステータスを返す（GetJobStatusのロングポーリング）
+	// 呼び出し元のdeadlineの少し前までに変わらなければ、エラーにせずその時点のステータスを返す
+	WaitForJob(context.Context, *WaitForJobRequest) (*JobStatus, error)
+	// ジョブ一時停止（処理中のアカウント完了後に停止）
+	PauseJob(context.Context, *PauseJobRequest) (*JobStatus, error)
+	// 一時停止中のジョブを再開
+	// プロセスの再起動で中断した（interruptedの）ジョブは、完了していないアカウントのみを元のセッションフォルダで処理する
+	ResumeJob(context.Context, *ResumeJobRequest) (*JobStatus, error)
+	// ジョブをキャンセル（処理中のアカウント完了後に未処理のアカウントを処理せず終了）
+	CancelJob(context.Context, *CancelJobRequest) (*JobStatus, error)
+	// 終了したジョブでダウンロードした明細を取得（ページング）
+	// 複数のアカウントで同じ利用（利用日時・IC・料金・ETCカード番号が同じ）をダウンロードした場合は、先に処理したアカウントの明細のみ返す
+	GetJobResult(context.Context, *GetJobResultRequest) (*GetJobResultResponse, error)
+	// 終了したジョブの全アカウントの明細をCSV（UTF-8、ヘッダー行付き）で分割して送信
+	ExportJobCSV(*ExportJobCSVRequest, grpc.ServerStreamingServer[ExportJobCSVChunk]) error
+	// 終了したジョブのセッションフォルダのファイル一覧取得（ファイル名・サイズ・保存したアカウント）
+	GetJobFiles(context.Context, *GetJobFilesRequest) (*GetJobFilesResponse, error)
+	// 終了したジョブのセッションフォルダのファイルを分割して送信（nameはGetJobFilesのファイル名）
+	DownloadFile(*DownloadFileRequest, grpc.ServerStreamingServer[DownloadFileChunk]) error
 	// 全アカウントID取得
 	GetAllAccountIDs(context.Context, *GetAllAccountIDsRequest) (*GetAllAccountIDsResponse, error)
+	// アカウントごとの最後の実行結果取得（最後の成功・エラー・件数、認証情報は含めない）
+	GetAccountStatus(context.Context, *GetAccountStatusRequest) (*GetAccountStatusResponse, error)
+	// 単一アカウントのログイン確認（ジョブを作成せずInitialize・Loginのみ実行）
+	TestAccount(context.Context, *TestAccountRequest) (*TestAccountResponse, error)
+	// アカウントのパスワード更新（環境変数の値をメモリ上で上書きし、再起動すると元に戻る）
+	UpdateCredential(context.Context, *UpdateCredentialRequest) (*UpdateCredentialResponse, error)
 	// 環境変数取得（デバッグ用）
 	GetEnvironmentVariables(context.Context, *GetEnvironmentVariablesRequest) (*GetEnvironmentVariablesResponse, error)
 	// サーバーログ取得（デバッグ用）
 	GetServerLogs(context.Context, *GetServerLogsRequest) (*GetServerLogsResponse, error)
+	// ジョブごとのログ取得（他のジョブやサーバー全体のログを含まない）
+	GetJobLogs(context.Context, *GetJobLogsRequest) (*GetJobLogsResponse, error)
+	// 稼働状況のスナップショット取得（監視用）
+	GetRuntimeMetrics(context.Context, *GetRuntimeMetricsRequest) (*RuntimeMetrics, error)
+	// ビルド情報取得（バージョン・コミット・ビルド日時）
+	GetVersion(context.Context, *GetVersionRequest) (*GetVersionResponse, error)
+	// サーバーログのストリーミング（末尾の既存ログを送信後、新しいログを逐次送信）
+	StreamServerLogs(*StreamServerLogsRequest, grpc.ServerStreamingServer[StreamServerLogsResponse]) error
 }
 
 // UnimplementedDownloadServiceServer should be embedded to have
@@ -151,15 +430,63 @@ func (UnimplementedDownloadServiceServer) DownloadAsync(context.Context, *Downlo
 func (UnimplementedDownloadServiceServer) GetJobStatus(context.Context, *GetJobStatusRequest) (*JobStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetJobStatus not implemented")
 }
+func (UnimplementedDownloadServiceServer) GetJobStatuses(context.Context, *GetJobStatusesRequest) (*GetJobStatusesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobStatuses not implemented")
+}
+func (UnimplementedDownloadServiceServer) WaitForJob(context.Context, *WaitForJobRequest) (*JobStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WaitForJob not implemented")
+}
+func (UnimplementedDownloadServiceServer) PauseJob(context.Context, *PauseJobRequest) (*JobStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseJob not implemented")
+}
+func (UnimplementedDownloadServiceServer) ResumeJob(context.Context, *ResumeJobRequest) (*JobStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeJob not implemented")
+}
+func (UnimplementedDownloadServiceServer) CancelJob(context.Context, *CancelJobRequest) (*JobStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (UnimplementedDownloadServiceServer) GetJobResult(context.Context, *GetJobResultRequest) (*GetJobResultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobResult not implemented")
+}
+func (UnimplementedDownloadServiceServer) ExportJobCSV(*ExportJobCSVRequest, grpc.ServerStreamingServer[ExportJobCSVChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportJobCSV not implemented")
+}
+func (UnimplementedDownloadServiceServer) GetJobFiles(context.Context, *GetJobFilesRequest) (*GetJobFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobFiles not implemented")
+}
+func (UnimplementedDownloadServiceServer) DownloadFile(*DownloadFileRequest, grpc.ServerStreamingServer[DownloadFileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadFile not implemented")
+}
 func (UnimplementedDownloadServiceServer) GetAllAccountIDs(context.Context, *GetAllAccountIDsRequest) (*GetAllAccountIDsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllAccountIDs not implemented")
 }
+func (UnimplementedDownloadServiceServer) GetAccountStatus(context.Context, *GetAccountStatusRequest) (*GetAccountStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountStatus not implemented")
+}
+func (UnimplementedDownloadServiceServer) TestAccount(context.Context, *TestAccountRequest) (*TestAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TestAccount not implemented")
+}
+func (UnimplementedDownloadServiceServer) UpdateCredential(context.Context, *UpdateCredentialRequest) (*UpdateCredentialResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCredential not implemented")
+}
 func (UnimplementedDownloadServiceServer) GetEnvironmentVariables(context.Context, *GetEnvironmentVariablesRequest) (*GetEnvironmentVariablesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEnvironmentVariables not implemented")
 }
 func (UnimplementedDownloadServiceServer) GetServerLogs(context.Context, *GetServerLogsRequest) (*GetServerLogsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetServerLogs not implemented")
 }
+func (UnimplementedDownloadServiceServer) GetJobLogs(context.Context, *GetJobLogsRequest) (*GetJobLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobLogs not implemented")
+}
+func (UnimplementedDownloadServiceServer) GetRuntimeMetrics(context.Context, *GetRuntimeMetricsRequest) (*RuntimeMetrics, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRuntimeMetrics not implemented")
+}
+func (UnimplementedDownloadServiceServer) GetVersion(context.Context, *GetVersionRequest) (*GetVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
+func (UnimplementedDownloadServiceServer) StreamServerLogs(*StreamServerLogsRequest, grpc.ServerStreamingServer[StreamServerLogsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamServerLogs not implemented")
+}
 func (UnimplementedDownloadServiceServer) testEmbeddedByValue() {}
 
 // UnsafeDownloadServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -234,6 +561,154 @@ func _DownloadService_GetJobStatus_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DownloadService_GetJobStatuses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobStatusesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).GetJobStatuses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_GetJobStatuses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).GetJobStatuses(ctx, req.(*GetJobStatusesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_WaitForJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitForJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).WaitForJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_WaitForJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).WaitForJob(ctx, req.(*WaitForJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_PauseJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).PauseJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_PauseJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).PauseJob(ctx, req.(*PauseJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_ResumeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).ResumeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_ResumeJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).ResumeJob(ctx, req.(*ResumeJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).CancelJob(ctx, req.(*CancelJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_GetJobResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).GetJobResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_GetJobResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).GetJobResult(ctx, req.(*GetJobResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_ExportJobCSV_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportJobCSVRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DownloadServiceServer).ExportJobCSV(m, &grpc.GenericServerStream[ExportJobCSVRequest, ExportJobCSVChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DownloadService_ExportJobCSVServer = grpc.ServerStreamingServer[ExportJobCSVChunk]
+
+func _DownloadService_GetJobFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).GetJobFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_GetJobFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).GetJobFiles(ctx, req.(*GetJobFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_DownloadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DownloadServiceServer).DownloadFile(m, &grpc.GenericServerStream[DownloadFileRequest, DownloadFileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DownloadService_DownloadFileServer = grpc.ServerStreamingServer[DownloadFileChunk]
+
 func _DownloadService_GetAllAccountIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetAllAccountIDsRequest)
 	if err := dec(in); err != nil {
@@ -252,6 +727,60 @@ func _DownloadService_GetAllAccountIDs_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DownloadService_GetAccountStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).GetAccountStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_GetAccountStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).GetAccountStatus(ctx, req.(*GetAccountStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_TestAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).TestAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_TestAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).TestAccount(ctx, req.(*TestAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_UpdateCredential_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCredentialRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).UpdateCredential(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_UpdateCredential_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).UpdateCredential(ctx, req.(*UpdateCredentialRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DownloadService_GetEnvironmentVariables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetEnvironmentVariablesRequest)
 	if err := dec(in); err != nil {
@@ -288,6 +817,71 @@ func _DownloadService_GetServerLogs_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DownloadService_GetJobLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).GetJobLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_GetJobLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).GetJobLogs(ctx, req.(*GetJobLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_GetRuntimeMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRuntimeMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).GetRuntimeMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_GetRuntimeMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).GetRuntimeMetrics(ctx, req.(*GetRuntimeMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DownloadServiceServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DownloadService_GetVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DownloadServiceServer).GetVersion(ctx, req.(*GetVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DownloadService_StreamServerLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamServerLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DownloadServiceServer).StreamServerLogs(m, &grpc.GenericServerStream[StreamServerLogsRequest, StreamServerLogsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DownloadService_StreamServerLogsServer = grpc.ServerStreamingServer[StreamServerLogsResponse]
+
 // DownloadService_ServiceDesc is the grpc.ServiceDesc for DownloadService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -307,10 +901,50 @@ var DownloadService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetJobStatus",
 			Handler:    _DownloadService_GetJobStatus_Handler,
 		},
+		{
+			MethodName: "GetJobStatuses",
+			Handler:    _DownloadService_GetJobStatuses_Handler,
+		},
+		{
+			MethodName: "WaitForJob",
+			Handler:    _DownloadService_WaitForJob_Handler,
+		},
+		{
+			MethodName: "PauseJob",
+			Handler:    _DownloadService_PauseJob_Handler,
+		},
+		{
+			MethodName: "ResumeJob",
+			Handler:    _DownloadService_ResumeJob_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _DownloadService_CancelJob_Handler,
+		},
+		{
+			MethodName: "GetJobResult",
+			Handler:    _DownloadService_GetJobResult_Handler,
+		},
+		{
+			MethodName: "GetJobFiles",
+			Handler:    _DownloadService_GetJobFiles_Handler,
+		},
 		{
 			MethodName: "GetAllAccountIDs",
 			Handler:    _DownloadService_GetAllAccountIDs_Handler,
 		},
+		{
+			MethodName: "GetAccountStatus",
+			Handler:    _DownloadService_GetAccountStatus_Handler,
+		},
+		{
+			MethodName: "TestAccount",
+			Handler:    _DownloadService_TestAccount_Handler,
+		},
+		{
+			MethodName: "UpdateCredential",
+			Handler:    _DownloadService_UpdateCredential_Handler,
+		},
 		{
 			MethodName: "GetEnvironmentVariables",
 			Handler:    _DownloadService_GetEnvironmentVariables_Handler,
@@ -319,7 +953,35 @@ var DownloadService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetServerLogs",
 			Handler:    _DownloadService_GetServerLogs_Handler,
 		},
+		{
+			MethodName: "GetJobLogs",
+			Handler:    _DownloadService_GetJobLogs_Handler,
+		},
+		{
+			MethodName: "GetRuntimeMetrics",
+			Handler:    _DownloadService_GetRuntimeMetrics_Handler,
+		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _DownloadService_GetVersion_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportJobCSV",
+			Handler:       _DownloadService_ExportJobCSV_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DownloadFile",
+			Handler:       _DownloadService_DownloadFile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamServerLogs",
+			Handler:       _DownloadService_StreamServerLogs_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "download.proto",
 }
@@ -3,6 +3,7 @@ package registry
 import (
 	"database/sql"
 	"log"
+	"net/http"
 
 	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
 	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
@@ -31,6 +32,19 @@ func (r *ServiceRegistry) RegisterAll(server *grpc.Server) {
 	}
 }
 
+// MetricsHandler returns an http.Handler serving job metrics in the Prometheus text format,
+// so the host's existing /metrics endpoint can expose them. Returns nil if unavailable.
+func (r *ServiceRegistry) MetricsHandler() http.Handler {
+	ds, ok := r.DownloadService.(interface{ Metrics() *services.JobMetrics })
+	if !ok {
+		return nil
+	}
+	if m := ds.Metrics(); m != nil {
+		return m
+	}
+	return nil
+}
+
 // Register is a convenience function that creates a registry and registers all services
 func Register(server *grpc.Server, db *sql.DB, logger *log.Logger) *ServiceRegistry {
 	registry := NewServiceRegistry(db, logger)
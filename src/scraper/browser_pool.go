@@ -0,0 +1,223 @@
+package scraper
+
+import (
+	"fmt"
+	"sync"
+)
+
+// BrowserPool is a PlaywrightFactory that shares launched browsers between
+// scrapers. Each scraper still creates its own browser context, so cookies and
+// storage never leak from one account to the next; closing the scraper closes
+// its context and returns the browser to the pool instead of closing it.
+//
+// Browsers are pooled per launch configuration (headless, slow-mo, proxy), and
+// at most Size browsers are launched for each configuration. When all of them
+// are in use, the browser with the fewest open leases is shared.
+type BrowserPool struct {
+	factory PlaywrightFactory
+	size    int
+
+	mu       sync.Mutex
+	pw       PlaywrightInterface
+	browsers map[string][]*pooledBrowser
+	closed   bool
+}
+
+type pooledBrowser struct {
+	browser BrowserInterface
+	leases  int
+}
+
+// NewBrowserPool creates a pool that launches browsers through factory.
+// A size below 1 is treated as 1.
+func NewBrowserPool(factory PlaywrightFactory, size int) *BrowserPool {
+	if size < 1 {
+		size = 1
+	}
+	return &BrowserPool{
+		factory:  factory,
+		size:     size,
+		browsers: make(map[string][]*pooledBrowser),
+	}
+}
+
+// Size returns the maximum number of browsers per launch configuration
+func (p *BrowserPool) Size() int {
+	return p.size
+}
+
+// Install installs the Playwright driver through the underlying factory
+func (p *BrowserPool) Install() error {
+	return p.factory.Install()
+}
+
+// Run starts Playwright on first use and returns a handle whose Stop is a
+// no-op; Playwright keeps running until the pool is closed
+func (p *BrowserPool) Run() (PlaywrightInterface, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, fmt.Errorf("browser pool is closed")
+	}
+	if p.pw == nil {
+		pw, err := p.factory.Run()
+		if err != nil {
+			return nil, err
+		}
+		p.pw = pw
+	}
+	return &pooledPlaywright{pool: p}, nil
+}
+
+// Close closes every pooled browser and stops Playwright. Scrapers that are
+// still running lose their browser, so call it once no job is running.
+func (p *BrowserPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+
+	var firstErr error
+	for key, browsers := range p.browsers {
+		for _, b := range browsers {
+			if err := b.browser.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		delete(p.browsers, key)
+	}
+	if p.pw != nil {
+		if err := p.pw.Stop(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		p.pw = nil
+	}
+	return firstErr
+}
+
+// acquire returns a lease on a browser for the launch options, launching a new
+// one while the configuration has fewer than Size browsers
+func (p *BrowserPool) acquire(options BrowserTypeLaunchOptions) (BrowserInterface, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed || p.pw == nil {
+		return nil, fmt.Errorf("browser pool is closed")
+	}
+
+	key := launchKey(options)
+	browsers := p.browsers[key]
+
+	var chosen *pooledBrowser
+	for _, b := range browsers {
+		if chosen == nil || b.leases < chosen.leases {
+			chosen = b
+		}
+	}
+	if chosen == nil || (chosen.leases > 0 && len(browsers) < p.size) {
+		browser, err := p.pw.GetChromium().Launch(options)
+		if err != nil {
+			return nil, err
+		}
+		chosen = &pooledBrowser{browser: browser}
+		p.browsers[key] = append(browsers, chosen)
+	}
+
+	chosen.leases++
+	return &browserLease{pool: p, key: key, pooled: chosen}, nil
+}
+
+// release returns a lease to the pool
+func (p *BrowserPool) release(b *pooledBrowser) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b.leases--
+}
+
+// discard removes a browser that can no longer create contexts (e.g. it
+// crashed) so that the next lease launches a fresh one
+func (p *BrowserPool) discard(key string, b *pooledBrowser) {
+	p.mu.Lock()
+	browsers := p.browsers[key]
+	for i, candidate := range browsers {
+		if candidate == b {
+			p.browsers[key] = append(browsers[:i:i], browsers[i+1:]...)
+			break
+		}
+	}
+	p.mu.Unlock()
+
+	b.browser.Close()
+}
+
+// launchKey identifies browsers that can be shared between launch options
+func launchKey(options BrowserTypeLaunchOptions) string {
+	headless, slowMo := true, 0.0
+	if options.Headless != nil {
+		headless = *options.Headless
+	}
+	if options.SlowMo != nil {
+		slowMo = *options.SlowMo
+	}
+	key := fmt.Sprintf("headless=%t slowmo=%g", headless, slowMo)
+	if proxy := options.Proxy; proxy != nil {
+		username, password := "", ""
+		if proxy.Username != nil {
+			username = *proxy.Username
+		}
+		if proxy.Password != nil {
+			password = *proxy.Password
+		}
+		key += fmt.Sprintf(" proxy=%q user=%q pass=%q", proxy.Server, username, password)
+	}
+	return key
+}
+
+// pooledPlaywright hands out pooled browsers; Stop leaves Playwright running
+type pooledPlaywright struct {
+	pool *BrowserPool
+}
+
+func (p *pooledPlaywright) Stop() error { return nil }
+
+func (p *pooledPlaywright) GetChromium() BrowserTypeInterface {
+	return &pooledBrowserType{pool: p.pool}
+}
+
+type pooledBrowserType struct {
+	pool *BrowserPool
+}
+
+func (t *pooledBrowserType) Launch(options BrowserTypeLaunchOptions) (BrowserInterface, error) {
+	return t.pool.acquire(options)
+}
+
+// browserLease is one scraper's use of a pooled browser. Close returns the
+// browser to the pool; the scraper closes its own context beforehand.
+type browserLease struct {
+	pool   *BrowserPool
+	key    string
+	pooled *pooledBrowser
+	once   sync.Once
+}
+
+func (l *browserLease) NewContext(options BrowserNewContextOptions) (BrowserContextInterface, error) {
+	ctx, err := l.pooled.browser.NewContext(options)
+	if err != nil {
+		l.once.Do(func() {
+			l.pool.release(l.pooled)
+			l.pool.discard(l.key, l.pooled)
+		})
+		return nil, err
+	}
+	return ctx, nil
+}
+
+func (l *browserLease) Close() error {
+	l.once.Do(func() { l.pool.release(l.pooled) })
+	return nil
+}
@@ -0,0 +1,89 @@
+package scraper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// pageHTMLExpression returns the current page's HTML
+const pageHTMLExpression = "() => document.documentElement.outerHTML"
+
+// CaptureError wraps a Login or DownloadMeisai error with the files saved for
+// inspecting the page at the time of the failure. Error() and Unwrap() return
+// the original error, so callers can keep matching it as before.
+type CaptureError struct {
+	Err error
+	// ScreenshotPath is empty when the screenshot could not be saved
+	ScreenshotPath string
+	// HTMLPath is empty when the HTML could not be saved
+	HTMLPath string
+}
+
+func (e *CaptureError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CaptureError) Unwrap() error {
+	return e.Err
+}
+
+// captureOnError saves the page when err is non-nil and CaptureOnError is set.
+// Capture failures are only logged so the original error is always returned.
+func (s *ETCScraper) captureOnError(operation string, err error) error {
+	if err == nil || !s.config.CaptureOnError || s.page == nil {
+		return err
+	}
+
+	screenshotPath, htmlPath := s.capturePage(operation)
+	if screenshotPath == "" && htmlPath == "" {
+		return err
+	}
+	s.logger.Printf("📸 Saved page after %s error: screenshot=%q html=%q", operation, screenshotPath, htmlPath)
+	return &CaptureError{Err: err, ScreenshotPath: screenshotPath, HTMLPath: htmlPath}
+}
+
+// capturePage saves a screenshot and the HTML of the current page into the session
+// folder and returns the paths of the files that were written
+func (s *ETCScraper) capturePage(operation string) (screenshotPath, htmlPath string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Printf("Failed to capture page after %s error: %v", operation, r)
+		}
+	}()
+
+	dir := s.config.SessionFolder
+	if dir == "" {
+		dir = s.config.DownloadPath
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		s.logger.Printf("Failed to create capture folder %s: %v", dir, err)
+		return "", ""
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("error_%s_%s_%s",
+		safeFileName(s.config.UserID), operation, time.Now().Format("20060102_150405.000")))
+
+	if _, err := s.page.Screenshot(PageScreenshotOptions{Path: base + ".png"}); err != nil {
+		s.logger.Printf("Failed to save screenshot after %s error: %v", operation, err)
+	} else {
+		screenshotPath = base + ".png"
+	}
+
+	html, err := s.page.Evaluate(pageHTMLExpression)
+	if err != nil {
+		s.logger.Printf("Failed to read page HTML after %s error: %v", operation, err)
+		return screenshotPath, ""
+	}
+	content, ok := html.(string)
+	if !ok {
+		s.logger.Printf("Failed to read page HTML after %s error: unexpected %T", operation, html)
+		return screenshotPath, ""
+	}
+	if err := os.WriteFile(base+".html", []byte(content), 0644); err != nil {
+		s.logger.Printf("Failed to save page HTML after %s error: %v", operation, err)
+		return screenshotPath, ""
+	}
+	return screenshotPath, base + ".html"
+}
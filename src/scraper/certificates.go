@@ -0,0 +1,138 @@
+package scraper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// selectAllResultsExpression checks every result checkbox matching the selector
+// and returns how many there are. The site issues the usage certificate for the
+// checked results only.
+const selectAllResultsExpression = `(selector) => { const items = document.querySelectorAll(selector); items.forEach(i => { i.checked = true; }); return items.length; }`
+
+// DownloadCertificates saves the usage certificate (利用証明書) PDFs for
+// fromDate..toDate into the session folder and returns their paths. Like
+// DownloadMeisai, a range spanning several months is searched one month at a
+// time and each month gets its own PDF; months without results are skipped,
+// so a range without any results returns no paths and no error.
+// With CaptureOnError set, a failure saves a screenshot and HTML dump (see CaptureError).
+func (s *ETCScraper) DownloadCertificates(fromDate, toDate string) ([]string, error) {
+	paths, err := s.downloadCertificates(fromDate, toDate)
+	return paths, s.captureOnError("certificates", err)
+}
+
+// downloadCertificates performs the search and PDF download steps
+func (s *ETCScraper) downloadCertificates(fromDate, toDate string) ([]string, error) {
+	if s.page == nil {
+		return nil, fmt.Errorf("scraper not initialized")
+	}
+
+	s.logger.Printf("Downloading usage certificates from %s to %s", fromDate, toDate)
+	restore, err := s.useSessionFolder()
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	s.openSearchPage()
+	if err := s.checkMaintenance(); err != nil {
+		return nil, err
+	}
+	downloadComplete := s.downloadChannel()
+
+	months, ok := splitStatementMonths(fromDate, toDate)
+	if !ok {
+		s.logger.Printf("⚠️ Could not parse date range %q - %q, searching with the conditions on the page", fromDate, toDate)
+		path, err := s.searchAndDownloadCertificate(downloadComplete)
+		if err != nil || path == "" {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+
+	paths := []string{}
+	for i, month := range months {
+		if i > 0 {
+			s.openSearchPage()
+		}
+		s.selectStatementMonth(month)
+
+		path, err := s.searchAndDownloadCertificate(downloadComplete)
+		if err != nil {
+			return nil, fmt.Errorf("month %s: %w", month.label(), err)
+		}
+		if path == "" {
+			s.logger.Printf("No results for %s, skipping usage certificate", month.label())
+			continue
+		}
+
+		certificatePath, err := keepMonthlyCertificate(path, s.config.UserID, month)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, certificatePath)
+	}
+	s.logger.Printf("Downloaded %d usage certificates", len(paths))
+	return paths, nil
+}
+
+// searchAndDownloadCertificate runs the search with the conditions on the page,
+// selects every result and downloads their usage certificate PDF. The returned
+// path is empty when the site shows no results.
+func (s *ETCScraper) searchAndDownloadCertificate(downloadComplete chan string) (string, error) {
+	s.logger.Println("Clicking search button...")
+	if err := s.page.Locator(s.config.Selectors.SearchButton).First().Click(LocatorClickOptions{}); err != nil {
+		return "", fmt.Errorf("failed to click search button: %w", err)
+	}
+	s.waitForNavigation()
+	if err := s.page.WaitForLoadState(PageWaitForLoadStateOptions{
+		State: LoadStateNetworkidle,
+	}); err != nil {
+		return "", fmt.Errorf("failed to wait for search results: %w", err)
+	}
+
+	resultCount, err := s.page.Locator(s.config.Selectors.ResultItem).Count()
+	if err == nil && resultCount == 0 {
+		return "", nil
+	}
+	selected, err := s.page.Evaluate(selectAllResultsExpression, s.config.Selectors.ResultItem)
+	if err != nil {
+		return "", fmt.Errorf("failed to select search results: %w", err)
+	}
+	s.logger.Printf("Selected %v results for the usage certificate", selected)
+
+	link := s.findElement(s.config.Selectors.CertificateLinks)
+	if link == nil {
+		return "", fmt.Errorf("usage certificate link not found with any selector - possibly no search results or different page structure")
+	}
+	if err := link.Click(LocatorClickOptions{}); err != nil {
+		return "", fmt.Errorf("failed to click usage certificate link: %w", err)
+	}
+
+	s.logger.Println("Waiting for usage certificate download to complete...")
+	select {
+	case path := <-downloadComplete:
+		s.logger.Printf("Download completed: %s", path)
+		return path, nil
+	case <-time.After(60 * time.Second):
+		return "", fmt.Errorf("usage certificate download timeout after 60 seconds")
+	}
+}
+
+// keepMonthlyCertificate renames a downloaded PDF to <account>_certificate_<YYYYMM>.pdf
+// so that the next month's download, which the site usually gives the same
+// file name, does not overwrite it
+func keepMonthlyCertificate(path, userID string, month statementMonth) (string, error) {
+	ext := filepath.Ext(path)
+	if ext == "" {
+		ext = ".pdf"
+	}
+	certificatePath := accountFilePath(filepath.Dir(path), userID, fmt.Sprintf("certificate_%s%s", month.label(), strings.ToLower(ext)))
+	if err := os.Rename(path, certificatePath); err != nil {
+		return "", fmt.Errorf("failed to keep usage certificate for %s: %w", month.label(), err)
+	}
+	return certificatePath, nil
+}
@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// DownloadResult describes the CSV file downloaded by DownloadMeisai
+type DownloadResult struct {
+	Path  string // downloaded CSV; a range spanning several months is combined into one file
+	Bytes int64  // size of the file (0 when it could not be read)
+	Pages int    // search pages (months) the range was searched in
+	// RowCountEstimate is the number of result rows the site displayed, or -1
+	// when it could not be read. The CSV may still differ (see ExpectedRecordCount).
+	RowCountEstimate int
+	// ActualFrom and ActualTo are the date range left in the search form after
+	// the search, i.e. what the site actually searched. Zero when unknown.
+	ActualFrom time.Time
+	ActualTo   time.Time
+}
+
+// CoversRange reports whether the site searched at least fromDate..toDate.
+// It also returns true when the searched range or the requested dates are unknown.
+func (r DownloadResult) CoversRange(fromDate, toDate string) bool {
+	if r.ActualFrom.IsZero() || r.ActualTo.IsZero() {
+		return true
+	}
+	from, fromOK := parseStatementDate(fromDate)
+	to, toOK := parseStatementDate(toDate)
+	if !fromOK || !toOK {
+		return true
+	}
+	return !r.ActualFrom.After(from) && !r.ActualTo.Before(to)
+}
+
+// newDownloadResult adds the file size and the site's result count to a downloaded CSV
+func (s *ETCScraper) newDownloadResult(path string, pages int, from, to time.Time) DownloadResult {
+	result := DownloadResult{Path: path, Pages: pages, RowCountEstimate: -1, ActualFrom: from, ActualTo: to}
+	if info, err := os.Stat(path); err == nil {
+		result.Bytes = info.Size()
+	}
+	if s.expectedRecordCountKnown {
+		result.RowCountEstimate = s.expectedRecordCount
+	}
+	return result
+}
+
+// searchedRange reads the date range from the search form after a search. The
+// site may clamp the requested range (e.g. to the months it keeps statements
+// for), so this is what was actually searched. Zero times mean it could not be read.
+func (s *ETCScraper) searchedRange() (from, to time.Time) {
+	selectors := s.config.Selectors
+	value, err := s.page.Evaluate(`(selectors) => selectors.map(s => { const e = document.querySelector(s); return e ? e.value : ""; })`,
+		[]string{selectors.FromYear, selectors.FromMonth, selectors.FromDay, selectors.ToYear, selectors.ToMonth, selectors.ToDay})
+	if err != nil {
+		return time.Time{}, time.Time{}
+	}
+	values, ok := value.([]interface{})
+	if !ok || len(values) != 6 {
+		return time.Time{}, time.Time{}
+	}
+
+	var parts [6]int
+	for i, v := range values {
+		str, _ := v.(string)
+		n, err := strconv.Atoi(str)
+		if err != nil {
+			return time.Time{}, time.Time{}
+		}
+		parts[i] = n
+	}
+	from = time.Date(parts[0], time.Month(parts[1]), parts[2], 0, 0, 0, 0, time.UTC)
+	to = time.Date(parts[3], time.Month(parts[4]), parts[5], 0, 0, 0, 0, time.UTC)
+	return from, to
+}
@@ -0,0 +1,157 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// ErrAuthentication matches (via errors.Is) any AuthError returned by Login
+var ErrAuthentication = errors.New("authentication failed")
+
+// AuthError is returned by Login when the site rejects the credentials
+type AuthError struct {
+	// Reason is the error message shown by the site
+	Reason string
+}
+
+func (e *AuthError) Error() string {
+	return "login failed: " + e.Reason
+}
+
+// Is makes errors.Is(err, ErrAuthentication) true for AuthError
+func (e *AuthError) Is(target error) bool {
+	return target == ErrAuthentication
+}
+
+// IsAuthError reports whether err (or any error it wraps) is an authentication failure
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrAuthentication)
+}
+
+// ErrMaintenance matches (via errors.Is) any MaintenanceError
+var ErrMaintenance = errors.New("site under maintenance")
+
+// MaintenanceError is returned by Login and DownloadMeisai when the ETC site shows
+// its maintenance page instead of the expected page
+type MaintenanceError struct {
+	// Message is the text of the maintenance page that matched
+	Message string
+}
+
+func (e *MaintenanceError) Error() string {
+	return "ETC site under maintenance: " + e.Message
+}
+
+// Is makes errors.Is(err, ErrMaintenance) true for MaintenanceError
+func (e *MaintenanceError) Is(target error) bool {
+	return target == ErrMaintenance
+}
+
+// IsMaintenanceError reports whether err (or any error it wraps) is a maintenance window
+func IsMaintenanceError(err error) bool {
+	return errors.Is(err, ErrMaintenance)
+}
+
+var (
+	// ErrDriverNotInstalled is returned by Initialize when the Playwright driver or
+	// browser is missing. Retrying does not help until it is installed.
+	ErrDriverNotInstalled = errors.New("playwright driver not installed")
+	// ErrDriverNotReady is returned by Initialize when the driver is installed but
+	// could not be started, e.g. just after the container starts. It is worth retrying.
+	ErrDriverNotReady = errors.New("playwright driver not ready")
+)
+
+// driverNotInstalledMessages are error message fragments from playwright-go that
+// mean the driver or the browser executable is missing
+var driverNotInstalledMessages = []string{
+	"please install the driver",
+	"executable doesn't exist",
+	"not installed",
+}
+
+// driverError wraps an error from starting Playwright or launching the browser
+// with ErrDriverNotInstalled or ErrDriverNotReady
+func driverError(action string, err error) error {
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range driverNotInstalledMessages {
+		if strings.Contains(msg, fragment) {
+			return fmt.Errorf("could not %s: %w: %w", action, ErrDriverNotInstalled, err)
+		}
+	}
+	return fmt.Errorf("could not %s: %w: %w", action, ErrDriverNotReady, err)
+}
+
+// transientMessages are error message fragments that indicate a temporary site or network problem
+var transientMessages = []string{
+	"timeout",
+	"net::err_",
+	"navigation",
+	"failed to navigate",
+	"connection reset",
+	"connection refused",
+}
+
+// IsTransientError reports whether err is likely temporary (timeouts, navigation or network
+// failures) so that the operation is worth retrying. Login rejections are never transient,
+// and neither are maintenance windows, which last far longer than a retry delay.
+func IsTransientError(err error) bool {
+	if err == nil || IsMaintenanceError(err) {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	if IsAuthError(err) || strings.Contains(msg, "login failed") {
+		return false
+	}
+
+	if errors.Is(err, playwright.ErrTimeout) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	for _, fragment := range transientMessages {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTimeoutError reports whether err is a timeout, either from Playwright, a context
+// deadline or a network operation, or one of the scraper's own download timeouts.
+// Login rejections are never timeouts.
+func IsTimeoutError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	if IsAuthError(err) || strings.Contains(msg, "login failed") {
+		return false
+	}
+
+	if errors.Is(err, playwright.ErrTimeout) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return strings.Contains(msg, "timeout")
+}
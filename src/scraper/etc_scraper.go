@@ -5,7 +5,10 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/models"
 )
 
 // ETCScraper handles web scraping for ETC meisai service
@@ -17,8 +20,23 @@ type ETCScraper struct {
 	config  *ScraperConfig
 	logger  *log.Logger
 	factory PlaywrightFactory
+
+	expectedRecordCount      int
+	expectedRecordCountKnown bool
+	// loggedIn is set once Login has confirmed the session on this page
+	loggedIn bool
+	// downloads receives the paths of files saved by the page's download handler
+	downloads chan string
 }
 
+// Browser identity used when ScraperConfig leaves UserAgent or Viewport unset.
+// A fixed desktop browser keeps the site from serving its mobile layout.
+const (
+	DefaultUserAgent      = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+	DefaultViewportWidth  = 1920
+	DefaultViewportHeight = 1080
+)
+
 // ScraperConfig holds configuration for the scraper
 type ScraperConfig struct {
 	UserID        string
@@ -28,9 +46,30 @@ type ScraperConfig struct {
 	Headless      bool
 	Timeout       float64
 	RetryCount    int
-	UserAgent     string
+	UserAgent     string // empty = DefaultUserAgent
+	Viewport      Size   // zero = DefaultViewportWidth x DefaultViewportHeight
 	SlowMo        float64
-	TestMode      bool // Skip time.Sleep in tests
+	TestMode      bool   // Skip time.Sleep in tests
+	ProxyURL      string // e.g. http://proxy.example.com:8080 (empty = no proxy)
+	ProxyUsername string
+	ProxyPassword string
+	// CaptureOnError saves a screenshot and HTML dump of the page into the
+	// session folder when Login or DownloadMeisai fails
+	CaptureOnError bool
+	// AccountType selects the login flow (empty = corporate)
+	AccountType models.AccountType
+	// OnProgress, when set, is called by DownloadMeisai after each searched page
+	OnProgress func(DownloadProgress)
+	// Selectors locate the site's elements (empty fields = DefaultSelectors)
+	Selectors Selectors
+}
+
+// DownloadProgress reports how far DownloadMeisai has got within one account.
+// A range spanning several months is searched one month (page) at a time.
+type DownloadProgress struct {
+	Page  int // pages searched so far
+	Pages int // pages to search in total
+	Rows  int // result rows the site reported so far
 }
 
 // NewETCScraper creates a new ETC scraper instance (for production use)
@@ -58,8 +97,12 @@ func NewETCScraperWithFactory(config *ScraperConfig, logger *log.Logger, factory
 		config.RetryCount = 3
 	}
 	if config.UserAgent == "" {
-		config.UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"
+		config.UserAgent = DefaultUserAgent
+	}
+	if config.Viewport.Width <= 0 || config.Viewport.Height <= 0 {
+		config.Viewport = Size{Width: DefaultViewportWidth, Height: DefaultViewportHeight}
 	}
+	config.Selectors = config.Selectors.WithDefaults()
 
 	// Skip directory creation for better testability
 
@@ -81,13 +124,13 @@ func (s *ETCScraper) Initialize() error {
 	// Install playwright browsers if needed
 	err = s.factory.Install()
 	if err != nil {
-		return fmt.Errorf("could not install playwright: %w", err)
+		return fmt.Errorf("could not install playwright: %w: %w", ErrDriverNotInstalled, err)
 	}
 
 	// Start Playwright
 	s.pw, err = s.factory.Run()
 	if err != nil {
-		return fmt.Errorf("could not start playwright: %w", err)
+		return driverError("start playwright", err)
 	}
 
 	// Launch browser
@@ -99,6 +142,11 @@ func (s *ETCScraper) Initialize() error {
 		launchOptions.SlowMo = Float(s.config.SlowMo)
 	}
 
+	if s.config.ProxyURL != "" {
+		launchOptions.Proxy = s.proxyOptions()
+		s.logger.Printf("🌐 Routing browser traffic through proxy %s", MaskProxyURL(s.config.ProxyURL))
+	}
+
 	// Log Headless mode setting
 	if s.config.Headless {
 		s.logger.Println("🔒 Launching browser in Headless mode (browser not visible)")
@@ -109,15 +157,15 @@ func (s *ETCScraper) Initialize() error {
 	chromium := s.pw.GetChromium()
 	s.browser, err = chromium.Launch(launchOptions)
 	if err != nil {
-		return fmt.Errorf("could not launch browser: %w", err)
+		return driverError("launch browser", err)
 	}
 
 	// Create browser context with download settings
 	contextOptions := BrowserNewContextOptions{
 		AcceptDownloads: Bool(true),
 		Viewport: &Size{
-			Width:  1920,
-			Height: 1080,
+			Width:  s.config.Viewport.Width,
+			Height: s.config.Viewport.Height,
 		},
 		UserAgent: String(s.config.UserAgent),
 	}
@@ -135,6 +183,7 @@ func (s *ETCScraper) Initialize() error {
 	if err != nil {
 		return fmt.Errorf("could not create page: %w", err)
 	}
+	s.downloads = nil
 
 	// Setup dialog handler to auto-accept all dialogs (for CSV download confirmation)
 	s.logger.Println("Setting up global dialog handler...")
@@ -159,8 +208,14 @@ func (s *ETCScraper) Initialize() error {
 	return nil
 }
 
-// Login performs login to ETC meisai service
+// Login performs login to ETC meisai service.
+// With CaptureOnError set, a failure saves a screenshot and HTML dump (see CaptureError).
 func (s *ETCScraper) Login() error {
+	return s.captureOnError("login", s.login())
+}
+
+// login performs the login steps
+func (s *ETCScraper) login() error {
 	if s.page == nil {
 		return fmt.Errorf("scraper not initialized")
 	}
@@ -174,11 +229,14 @@ func (s *ETCScraper) Login() error {
 	if err != nil {
 		return fmt.Errorf("failed to navigate to top page: %w", err)
 	}
+	if err := s.checkMaintenance(); err != nil {
+		return err
+	}
 
 	// Click login link
-	s.logger.Println("Clicking login link...")
-	loginLinkSelector := "a[href*='funccode=1013000000']"
-	loginLink := s.page.Locator(loginLinkSelector).First()
+	flow := loginFlowFor(s.config.AccountType, s.config.Selectors)
+	s.logger.Printf("Clicking %s login link...", flow.name)
+	loginLink := s.page.Locator(flow.Link).First()
 	if err := loginLink.Click(LocatorClickOptions{}); err != nil {
 		return fmt.Errorf("failed to click login link: %w", err)
 	}
@@ -194,8 +252,8 @@ func (s *ETCScraper) Login() error {
 
 	// Wait for login form with correct field names
 	s.logger.Println("Waiting for login form...")
-	userIDField := s.page.Locator("input[name='risLoginId']")
-	passwordField := s.page.Locator("input[name='risPassword']")
+	userIDField := s.page.Locator(flow.UserID)
+	passwordField := s.page.Locator(flow.Password)
 
 	// Fill user ID
 	s.logger.Println("Filling login credentials...")
@@ -210,7 +268,7 @@ func (s *ETCScraper) Login() error {
 
 	// Click login button
 	s.logger.Println("Clicking login button...")
-	loginButton := s.page.Locator("input[type='button'][value='ログイン']")
+	loginButton := s.page.Locator(flow.Button)
 	if err := loginButton.Click(LocatorClickOptions{}); err != nil {
 		return fmt.Errorf("failed to click login button: %w", err)
 	}
@@ -223,74 +281,85 @@ func (s *ETCScraper) Login() error {
 	if err != nil {
 		return fmt.Errorf("failed to wait for login completion: %w", err)
 	}
+	if err := s.checkMaintenance(); err != nil {
+		return err
+	}
 
 	// Check if login was successful
-	logoutLocator := s.page.Locator("a:has-text('ログアウト')")
+	logoutLocator := s.page.Locator(s.config.Selectors.LoggedIn)
 	logoutExists, _ := logoutLocator.Count()
 	if logoutExists > 0 {
 		s.logger.Println("Login successful!")
+		s.loggedIn = true
 		return nil
 	}
 
 	// Check for error messages
-	errorLocator := s.page.Locator(".error-message, .alert-danger, .error").First()
+	errorLocator := s.page.Locator(s.config.Selectors.LoginError).First()
 	errorMsg, _ := errorLocator.TextContent(LocatorTextContentOptions{})
 	if errorMsg != "" {
-		return fmt.Errorf("login failed: %s", errorMsg)
+		return &AuthError{Reason: errorMsg}
 	}
 
 	s.logger.Println("Login completed")
 	return nil
 }
 
-// DownloadMeisai downloads ETC meisai data for specified date range
-func (s *ETCScraper) DownloadMeisai(fromDate, toDate string) (string, error) {
+// IsLoggedIn reports whether the current page still belongs to a logged-in session,
+// so a reused browser context can skip Login. A scraper that has not logged in yet
+// reports false without touching the page; an error means the check was inconclusive
+// and the caller should log in.
+func (s *ETCScraper) IsLoggedIn() (bool, error) {
 	if s.page == nil {
-		return "", fmt.Errorf("scraper not initialized")
+		return false, fmt.Errorf("scraper not initialized")
+	}
+	if !s.loggedIn {
+		return false, nil
 	}
 
-	s.logger.Printf("Downloading meisai from %s to %s", fromDate, toDate)
+	count, err := s.page.Locator(s.config.Selectors.LoggedIn).Count()
+	if err != nil {
+		return false, fmt.Errorf("failed to check login session: %w", err)
+	}
+	if count == 0 {
+		// the session expired or the site logged us out
+		s.loggedIn = false
+	}
+	return count > 0, nil
+}
 
-	// Use existing session folder or create a new one
-	var sessionFolder string
-	if s.config.SessionFolder != "" {
-		// Use existing session folder (for multiple downloads in same session)
-		sessionFolder = s.config.SessionFolder
-		s.logger.Printf("Using existing session folder: %s", sessionFolder)
-	} else {
-		// Create new timestamped subfolder for this download session
-		timestamp := time.Now().Format("20060102_150405")
-		sessionFolder = filepath.Join(s.config.DownloadPath, timestamp)
-		if err := os.MkdirAll(sessionFolder, 0755); err != nil {
-			return "", fmt.Errorf("failed to create session folder: %w", err)
-		}
-		s.logger.Printf("Created new session folder: %s", sessionFolder)
-		s.config.SessionFolder = sessionFolder
+// DownloadMeisai downloads ETC meisai data for specified date range.
+// With CaptureOnError set, a failure saves a screenshot and HTML dump (see CaptureError).
+func (s *ETCScraper) DownloadMeisai(fromDate, toDate string) (DownloadResult, error) {
+	result, err := s.downloadMeisai(fromDate, toDate)
+	return result, s.captureOnError("download", err)
+}
+
+// downloadMeisai performs the search and CSV download steps
+func (s *ETCScraper) downloadMeisai(fromDate, toDate string) (DownloadResult, error) {
+	if s.page == nil {
+		return DownloadResult{}, fmt.Errorf("scraper not initialized")
 	}
 
-	// Update download path to use session folder
-	originalDownloadPath := s.config.DownloadPath
-	s.config.DownloadPath = sessionFolder
-	defer func() {
-		s.config.DownloadPath = originalDownloadPath
-	}()
+	s.logger.Printf("Downloading meisai from %s to %s", fromDate, toDate)
+	s.expectedRecordCount = 0
+	s.expectedRecordCountKnown = false
+
+	restore, err := s.useSessionFolder()
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	defer restore()
 
 	// Navigate to search page (検索条件の指定)
-	s.logger.Println("Navigating to search page...")
-	searchPageLink := s.page.Locator("a:has-text('検索条件の指定')").First()
-	if err := searchPageLink.Click(LocatorClickOptions{}); err != nil {
-		// If link not found, we might already be on search page
-		s.logger.Println("Search link not found, assuming already on search page")
-	} else {
-		s.waitForNavigation()
-		s.page.WaitForLoadState(PageWaitForLoadStateOptions{
-			State: LoadStateNetworkidle,
-		})
+	s.openSearchPage()
+	if err := s.checkMaintenance(); err != nil {
+		return DownloadResult{}, err
 	}
 
 	// Select "全て" (All) radio button for 走行区分 (sokoKbn)
 	s.logger.Println("Selecting '全て' (All) option for 走行区分...")
-	allRadioButton := s.page.Locator("input[name='sokoKbn'][value='0']").First()
+	allRadioButton := s.page.Locator(s.config.Selectors.AllTrips).First()
 
 	// Check if already selected
 	isChecked, err := allRadioButton.IsChecked(LocatorIsCheckedOptions{})
@@ -311,7 +380,7 @@ func (s *ETCScraper) DownloadMeisai(fromDate, toDate string) (string, error) {
 
 	// Click "この条件を記憶する" (Save this condition) button to save the search settings
 	s.logger.Println("Clicking 'この条件を記憶する' button to save search settings...")
-	saveButton := s.page.Locator("input[name='focusTarget_Save']").First()
+	saveButton := s.page.Locator(s.config.Selectors.SaveConditions).First()
 	if err := saveButton.Click(LocatorClickOptions{}); err != nil {
 		s.logger.Printf("⚠️ Failed to click 'この条件を記憶する' button: %v", err)
 	} else {
@@ -323,11 +392,206 @@ func (s *ETCScraper) DownloadMeisai(fromDate, toDate string) (string, error) {
 		})
 	}
 
+	downloadComplete := s.downloadChannel()
+
+	months, ok := splitStatementMonths(fromDate, toDate)
+	if !ok {
+		s.logger.Printf("⚠️ Could not parse date range %q - %q, searching with the conditions on the page", fromDate, toDate)
+		path, count, countKnown, err := s.searchAndDownloadCSV(downloadComplete, false)
+		s.expectedRecordCount, s.expectedRecordCountKnown = count, countKnown
+		if err != nil {
+			return DownloadResult{}, err
+		}
+		s.reportProgress(1, 1, count)
+		from, to := s.searchedRange()
+		return s.newDownloadResult(path, 1, from, to), nil
+	}
+	if len(months) == 1 {
+		s.selectStatementMonth(months[0])
+		path, count, countKnown, err := s.searchAndDownloadCSV(downloadComplete, false)
+		s.expectedRecordCount, s.expectedRecordCountKnown = count, countKnown
+		if err != nil {
+			return DownloadResult{}, err
+		}
+		s.reportProgress(1, 1, count)
+		from, to := s.searchedRange()
+		return s.newDownloadResult(path, 1, from, to), nil
+	}
+	return s.downloadStatementMonths(months, downloadComplete)
+}
+
+// downloadStatementMonths searches and downloads each month of a range that spans
+// several months, then concatenates the CSVs into one file. Months without
+// results are skipped.
+func (s *ETCScraper) downloadStatementMonths(months []statementMonth, downloadComplete chan string) (DownloadResult, error) {
+	s.logger.Printf("Date range spans %d months, downloading each month separately", len(months))
+
+	var paths []string
+	total, totalKnown := 0, true
+	// the searched range runs from the first month's start to the last month's end
+	var actualFrom, actualTo time.Time
+	rangeKnown := true
+	for i, month := range months {
+		if i > 0 {
+			s.openSearchPage()
+		}
+		s.selectStatementMonth(month)
+
+		path, count, countKnown, err := s.searchAndDownloadCSV(downloadComplete, true)
+		if err != nil {
+			return DownloadResult{}, fmt.Errorf("month %s: %w", month.label(), err)
+		}
+		from, to := s.searchedRange()
+		if i == 0 {
+			actualFrom = from
+		}
+		actualTo = to
+		rangeKnown = rangeKnown && !from.IsZero() && !to.IsZero()
+		total += count
+		totalKnown = totalKnown && countKnown
+		s.reportProgress(i+1, len(months), total)
+		if path == "" {
+			s.logger.Printf("No results for %s, skipping CSV download", month.label())
+			continue
+		}
+
+		monthlyPath, err := keepMonthlyCSV(path, month)
+		if err != nil {
+			return DownloadResult{}, err
+		}
+		paths = append(paths, monthlyPath)
+	}
+	s.expectedRecordCount = total
+	s.expectedRecordCountKnown = totalKnown
+
+	first, last := months[0], months[len(months)-1]
+	if len(paths) == 0 {
+		return DownloadResult{}, fmt.Errorf("CSV download link not found for any month from %s to %s - possibly no search results",
+			first.from.Format("2006/01/02"), last.to.Format("2006/01/02"))
+	}
+
+	combinedPath := accountFilePath(s.config.DownloadPath, s.config.UserID, fmt.Sprintf("meisai_%s_%s.csv",
+		first.from.Format("20060102"), last.to.Format("20060102")))
+	if err := concatStatementCSVs(combinedPath, paths); err != nil {
+		return DownloadResult{}, err
+	}
+	s.logger.Printf("Combined %d monthly CSV files into %s", len(paths), combinedPath)
+	if !rangeKnown {
+		actualFrom, actualTo = time.Time{}, time.Time{}
+	}
+	return s.newDownloadResult(combinedPath, len(months), actualFrom, actualTo), nil
+}
+
+// useSessionFolder points DownloadPath at the session folder, creating a new
+// timestamped one when SessionFolder is unset, and returns a func that restores it
+func (s *ETCScraper) useSessionFolder() (restore func(), err error) {
+	// Use existing session folder or create a new one
+	var sessionFolder string
+	if s.config.SessionFolder != "" {
+		// Use existing session folder (for multiple downloads in same session)
+		sessionFolder = s.config.SessionFolder
+		s.logger.Printf("Using existing session folder: %s", sessionFolder)
+	} else {
+		// Create new timestamped subfolder for this download session
+		timestamp := time.Now().Format("20060102_150405")
+		sessionFolder = filepath.Join(s.config.DownloadPath, timestamp)
+		if err := os.MkdirAll(sessionFolder, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create session folder: %w", err)
+		}
+		s.logger.Printf("Created new session folder: %s", sessionFolder)
+		s.config.SessionFolder = sessionFolder
+	}
+
+	// Update download path to use session folder
+	originalDownloadPath := s.config.DownloadPath
+	s.config.DownloadPath = sessionFolder
+	return func() {
+		s.config.DownloadPath = originalDownloadPath
+	}, nil
+}
+
+// downloadChannel returns the channel the paths of saved downloads are sent to.
+// The page's download handler is registered only once, so a later DownloadMeisai
+// or DownloadCertificates on the same page does not save every file twice.
+// Paths left over from an earlier download that timed out are dropped.
+func (s *ETCScraper) downloadChannel() chan string {
+	if s.downloads == nil {
+		s.downloads = make(chan string, 1)
+		downloads := s.downloads
+		s.logger.Println("Setting up download handler...")
+		s.page.On("download", func(download Download) {
+			s.logger.Println("📥 Download event triggered!")
+			s.HandleDownload(download, downloads)
+		})
+	}
+	for {
+		select {
+		case path := <-s.downloads:
+			s.logger.Printf("Discarding earlier download: %s", path)
+		default:
+			return s.downloads
+		}
+	}
+}
+
+// reportProgress passes the download progress to the OnProgress callback, if any
+func (s *ETCScraper) reportProgress(page, pages, rows int) {
+	if s.config.OnProgress != nil {
+		s.config.OnProgress(DownloadProgress{Page: page, Pages: pages, Rows: rows})
+	}
+}
+
+// maintenanceMessages are text fragments of the page the ETC site shows during
+// its maintenance windows (matched against the page title and body text)
+var maintenanceMessages = []string{
+	"メンテナンス中",
+	"システムメンテナンス",
+	"サービスを停止しております",
+	"ただいまご利用いただけません",
+}
+
+// checkMaintenance returns a MaintenanceError when the current page is the
+// site's maintenance page
+func (s *ETCScraper) checkMaintenance() error {
+	text, err := s.page.Evaluate(`() => document.title + "\n" + (document.body ? document.body.innerText : "")`)
+	if err != nil {
+		return nil
+	}
+	content, _ := text.(string)
+	for _, fragment := range maintenanceMessages {
+		if strings.Contains(content, fragment) {
+			s.logger.Printf("⚠️ ETC site is under maintenance (%q)", fragment)
+			return &MaintenanceError{Message: fragment}
+		}
+	}
+	return nil
+}
+
+// openSearchPage navigates to the search page (検索条件の指定)
+func (s *ETCScraper) openSearchPage() {
+	s.logger.Println("Navigating to search page...")
+	searchPageLink := s.page.Locator(s.config.Selectors.SearchPageLink).First()
+	if err := searchPageLink.Click(LocatorClickOptions{}); err != nil {
+		// If link not found, we might already be on search page
+		s.logger.Println("Search link not found, assuming already on search page")
+	} else {
+		s.waitForNavigation()
+		s.page.WaitForLoadState(PageWaitForLoadStateOptions{
+			State: LoadStateNetworkidle,
+		})
+	}
+}
+
+// searchAndDownloadCSV runs the search with the conditions on the page and downloads
+// the CSV of the results. It also returns the number of result items the site showed.
+// With skipEmpty set, no download is attempted when the site shows no results and
+// the returned path is empty.
+func (s *ETCScraper) searchAndDownloadCSV(downloadComplete chan string, skipEmpty bool) (path string, resultCount int, countKnown bool, err error) {
 	// Click search button to execute search with current date range
 	s.logger.Println("Clicking search button...")
-	searchButton := s.page.Locator("input[name='focusTarget']").First()
+	searchButton := s.page.Locator(s.config.Selectors.SearchButton).First()
 	if err := searchButton.Click(LocatorClickOptions{}); err != nil {
-		return "", fmt.Errorf("failed to click search button: %w", err)
+		return "", 0, false, fmt.Errorf("failed to click search button: %w", err)
 	}
 
 	// Wait for results page to load
@@ -335,36 +599,28 @@ func (s *ETCScraper) DownloadMeisai(fromDate, toDate string) (string, error) {
 	if err := s.page.WaitForLoadState(PageWaitForLoadStateOptions{
 		State: LoadStateNetworkidle,
 	}); err != nil {
-		return "", fmt.Errorf("failed to wait for search results: %w", err)
+		return "", 0, false, fmt.Errorf("failed to wait for search results: %w", err)
 	}
 
 	// Check if there are any results
 	s.logger.Println("Checking for search results...")
-	resultCount, _ := s.page.Locator("input[name='hakkoMeisai']").Count()
+	resultCount, countErr := s.page.Locator(s.config.Selectors.ResultItem).Count()
 	s.logger.Printf("Found %d result items", resultCount)
+	// Keep the site-reported count so callers can compare it with the parsed CSV
+	countKnown = countErr == nil
 
 	if resultCount == 0 {
+		if skipEmpty && countKnown {
+			return "", 0, true, nil
+		}
 		s.logger.Println("⚠️ No search results found. CSV link may not be available.")
 	}
 
-	// Setup download handler
-	downloadComplete := make(chan string, 1)
-	s.logger.Println("Setting up download handler...")
-	s.page.On("download", func(download Download) {
-		s.logger.Println("📥 Download event triggered!")
-		s.HandleDownload(download, downloadComplete)
-	})
-
 	// Click CSV download link
 	s.logger.Println("Clicking CSV download link...")
 
 	// Try multiple selectors for CSV link
-	// Note: onclick funccode varies by account type (1032500000 or other)
-	csvSelectors := []string{
-		"a:has-text('明細ＣＳＶ')",    // Text match (most reliable, ignores spacing)
-		"a[onclick*='goOutput'][onclick*='hakkoMeisai']", // goOutput function call
-		"a[onclick*='1032500000']",  // 明細CSV funccode (pattern 1)
-	}
+	csvSelectors := s.config.Selectors.CSVLinks
 
 	var csvLink LocatorInterface
 	var csvLinkCount int
@@ -380,12 +636,12 @@ func (s *ETCScraper) DownloadMeisai(fromDate, toDate string) (string, error) {
 	}
 
 	if csvLinkCount == 0 {
-		return "", fmt.Errorf("CSV download link not found with any selector - possibly no search results or different page structure")
+		return "", resultCount, countKnown, fmt.Errorf("CSV download link not found with any selector - possibly no search results or different page structure")
 	}
 
 	s.logger.Println("CSV link located, attempting click...")
 	if err := csvLink.Click(LocatorClickOptions{}); err != nil {
-		return "", fmt.Errorf("failed to click CSV link: %w", err)
+		return "", resultCount, countKnown, fmt.Errorf("failed to click CSV link: %w", err)
 	}
 	s.logger.Println("CSV link clicked successfully!")
 
@@ -395,19 +651,25 @@ func (s *ETCScraper) DownloadMeisai(fromDate, toDate string) (string, error) {
 	select {
 	case path := <-downloadComplete:
 		s.logger.Printf("Download completed: %s", path)
-		return path, nil
+		return path, resultCount, countKnown, nil
 	case <-time.After(60 * time.Second):
-		return "", fmt.Errorf("download timeout after 60 seconds")
+		return "", resultCount, countKnown, fmt.Errorf("download timeout after 60 seconds")
 	}
 }
 
+// ExpectedRecordCount returns the number of result items the site displayed during the
+// last DownloadMeisai call, and whether the count could be read
+func (s *ETCScraper) ExpectedRecordCount() (int, bool) {
+	return s.expectedRecordCount, s.expectedRecordCountKnown
+}
+
 // HandleDownload processes download events (exported for testing)
 func (s *ETCScraper) HandleDownload(download Download, downloadComplete chan<- string) {
 	suggestedFilename := download.SuggestedFilename()
 
-	// Add account name prefix to filename
-	filenameWithAccount := s.config.UserID + "_" + suggestedFilename
-	downloadPath := filepath.Join(s.config.DownloadPath, filenameWithAccount)
+	// Add account name prefix to filename; the name comes from the site and the
+	// account ID from the request, so neither may point outside the download folder
+	downloadPath := accountFilePath(s.config.DownloadPath, s.config.UserID, suggestedFilename)
 
 	s.logger.Printf("Downloading file: %s", suggestedFilename)
 	s.logger.Printf("Saving to: %s", downloadPath)
@@ -489,13 +751,13 @@ func ReadAndDeleteFile(path string) ([]byte, error) {
 // DownloadMeisaiToBuffer downloads ETC meisai data and returns it as a byte buffer
 func (s *ETCScraper) DownloadMeisaiToBuffer(fromDate, toDate string) ([]byte, error) {
 	// Download CSV file
-	csvPath, err := s.DownloadMeisai(fromDate, toDate)
+	result, err := s.DownloadMeisai(fromDate, toDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download CSV: %w", err)
 	}
 
 	// Read and delete file
-	data, err := ReadAndDeleteFile(csvPath)
+	data, err := ReadAndDeleteFile(result.Path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read CSV file: %w", err)
 	}
@@ -4,6 +4,15 @@ package scraper
 type ScraperInterface interface {
 	Initialize() error
 	Login() error
-	DownloadMeisai(fromDate, toDate string) (string, error)
+	// IsLoggedIn reports whether the session is still logged in, so a reused
+	// browser context can skip Login. An error means the check was inconclusive.
+	IsLoggedIn() (bool, error)
+	DownloadMeisai(fromDate, toDate string) (DownloadResult, error)
+	// ExpectedRecordCount returns the record count displayed by the site during the
+	// last DownloadMeisai call and whether it could be read
+	ExpectedRecordCount() (int, bool)
+	// DownloadCertificates saves the usage certificate PDFs of the range into
+	// the session folder and returns their paths
+	DownloadCertificates(fromDate, toDate string) ([]string, error)
 	Close() error
 }
\ No newline at end of file
@@ -0,0 +1,19 @@
+package scraper
+
+import "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/models"
+
+// loginFlow holds the page elements of one login flow. Corporate and personal
+// accounts log in from different links and forms on the ETC meisai site.
+type loginFlow struct {
+	name string
+	LoginSelectors
+}
+
+// loginFlowFor returns the login flow for the account type; anything other
+// than personal uses the corporate flow
+func loginFlowFor(accountType models.AccountType, selectors Selectors) loginFlow {
+	if accountType == models.AccountTypePersonal {
+		return loginFlow{name: "personal", LoginSelectors: selectors.PersonalLogin}
+	}
+	return loginFlow{name: "corporate", LoginSelectors: selectors.CorporateLogin}
+}
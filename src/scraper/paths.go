@@ -0,0 +1,36 @@
+package scraper
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IsSafePathComponent reports whether name can be used as a single file name
+// component: it is not empty, "." or "..", and has no path separator or NUL.
+// Account IDs are used as the prefix of the files saved in the session folder,
+// so an account ID that is not a safe component could place files outside it
+func IsSafePathComponent(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	return !strings.ContainsAny(name, "/\\\x00")
+}
+
+// safeFileName reduces name, built from an account ID or a file name suggested
+// by the site, to its last path element so that joining it to a folder cannot
+// escape the folder. Backslashes are treated as separators on every platform
+func safeFileName(name string) string {
+	name = strings.ReplaceAll(strings.ReplaceAll(name, "\\", "/"), "\x00", "")
+	name = path.Base(name)
+	if name == "." || name == ".." || name == "/" {
+		return "_"
+	}
+	return name
+}
+
+// accountFilePath returns the path of the file <account>_<name> in dir, with
+// both parts reduced by safeFileName so that the file always stays in dir
+func accountFilePath(dir, userID, name string) string {
+	return filepath.Join(dir, safeFileName(userID)+"_"+safeFileName(name))
+}
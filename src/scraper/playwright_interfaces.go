@@ -12,6 +12,14 @@ type Size struct {
 type BrowserTypeLaunchOptions struct {
 	Headless *bool
 	SlowMo   *float64
+	Proxy    *Proxy
+}
+
+// Proxy represents proxy settings for browser launch
+type Proxy struct {
+	Server   string
+	Username *string
+	Password *string
 }
 
 // BrowserNewContextOptions represents browser context options
@@ -116,6 +124,8 @@ type LocatorInterface interface {
 	TextContent(options LocatorTextContentOptions) (string, error)
 	Check(options LocatorCheckOptions) error
 	IsChecked(options LocatorIsCheckedOptions) (bool, error)
+	// SelectOption selects the options of a <select> whose value or label matches
+	SelectOption(values []string) error
 }
 
 // Helper functions for creating option structs
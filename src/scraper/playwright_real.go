@@ -1,6 +1,10 @@
 package scraper
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+
 	"github.com/playwright-community/playwright-go"
 )
 
@@ -29,6 +33,42 @@ func (r *RealPlaywright) GetChromium() BrowserTypeInterface {
 	return &RealBrowserType{bt: r.pw.Chromium}
 }
 
+// CheckPlaywrightDriver reports whether the Playwright driver is installed.
+// DefaultPlaywrightFactory does not install it on demand, so scraping cannot
+// start without it. The lookup mirrors playwright-go: PLAYWRIGHT_DRIVER_PATH,
+// falling back to <user cache dir>/ms-playwright-go/<version>.
+func CheckPlaywrightDriver() error {
+	driver, err := playwright.NewDriver(&playwright.RunOptions{SkipInstallBrowsers: true})
+	if err != nil {
+		return fmt.Errorf("could not resolve playwright driver: %w", err)
+	}
+
+	dir := os.Getenv("PLAYWRIGHT_DRIVER_PATH")
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return fmt.Errorf("could not resolve playwright driver directory: %w", err)
+		}
+		dir = filepath.Join(cacheDir, "ms-playwright-go", driver.Version)
+	}
+
+	cli := filepath.Join(dir, "package", "cli.js")
+	if _, err := os.Stat(cli); err != nil {
+		return fmt.Errorf("playwright driver %s not installed in %s: %w", driver.Version, dir, err)
+	}
+	return nil
+}
+
+// PlaywrightDriverVersion returns the Playwright driver version this build
+// uses, or an empty string when it cannot be resolved.
+func PlaywrightDriverVersion() string {
+	driver, err := playwright.NewDriver(&playwright.RunOptions{SkipInstallBrowsers: true})
+	if err != nil {
+		return ""
+	}
+	return driver.Version
+}
+
 // RealBrowserType wraps playwright.BrowserType
 type RealBrowserType struct {
 	bt playwright.BrowserType
@@ -42,6 +82,13 @@ func (r *RealBrowserType) Launch(options BrowserTypeLaunchOptions) (BrowserInter
 	if options.SlowMo != nil {
 		opts.SlowMo = options.SlowMo
 	}
+	if options.Proxy != nil {
+		opts.Proxy = &playwright.Proxy{
+			Server:   options.Proxy.Server,
+			Username: options.Proxy.Username,
+			Password: options.Proxy.Password,
+		}
+	}
 
 	browser, err := r.bt.Launch(opts)
 	if err != nil {
@@ -196,6 +243,11 @@ func (r *RealLocator) IsChecked(options LocatorIsCheckedOptions) (bool, error) {
 	return r.locator.IsChecked()
 }
 
+func (r *RealLocator) SelectOption(values []string) error {
+	_, err := r.locator.SelectOption(playwright.SelectOptionValues{ValuesOrLabels: &values})
+	return err
+}
+
 // RealDownload wraps playwright.Download
 type RealDownload struct {
 	download playwright.Download
@@ -0,0 +1,150 @@
+package scraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoginSelectors are the elements of one login flow (see loginFlow)
+type LoginSelectors struct {
+	Link     string `json:"link,omitempty"`     // link on the top page that opens the login form
+	UserID   string `json:"user_id,omitempty"`  // user ID input
+	Password string `json:"password,omitempty"` // password input
+	Button   string `json:"button,omitempty"`   // submit button
+}
+
+// Selectors holds the CSS selectors of the ETC site elements the scraper uses.
+// The site changes its markup from time to time; overriding a selector (see
+// LoadSelectors) fixes the scraper without a new release. Empty fields use
+// the value from DefaultSelectors.
+type Selectors struct {
+	CorporateLogin LoginSelectors `json:"corporate_login"`
+	PersonalLogin  LoginSelectors `json:"personal_login"`
+	// LoggedIn is present only after a successful login; LoginError holds the
+	// site's message after a rejected one
+	LoggedIn   string `json:"logged_in,omitempty"`
+	LoginError string `json:"login_error,omitempty"`
+
+	SearchPageLink string `json:"search_page_link,omitempty"` // 検索条件の指定
+	AllTrips       string `json:"all_trips,omitempty"`        // 走行区分: 全て
+	SaveConditions string `json:"save_conditions,omitempty"`  // この条件を記憶する
+	SearchButton   string `json:"search_button,omitempty"`
+	ResultItem     string `json:"result_item,omitempty"` // one per search result, used to count them
+
+	// Date range inputs of the search form
+	FromYear  string `json:"from_year,omitempty"`
+	FromMonth string `json:"from_month,omitempty"`
+	FromDay   string `json:"from_day,omitempty"`
+	ToYear    string `json:"to_year,omitempty"`
+	ToMonth   string `json:"to_month,omitempty"`
+	ToDay     string `json:"to_day,omitempty"`
+
+	// CSVLinks are tried in order; the first one found is clicked
+	CSVLinks []string `json:"csv_links,omitempty"`
+	// CertificateLinks issue the usage certificate (利用証明書) PDF of the
+	// checked results; tried in order like CSVLinks
+	CertificateLinks []string `json:"certificate_links,omitempty"`
+}
+
+// DefaultSelectors returns the selectors for the current markup of the ETC site
+func DefaultSelectors() Selectors {
+	return Selectors{
+		CorporateLogin: LoginSelectors{
+			Link:     "a[href*='funccode=1013000000']",
+			UserID:   "input[name='risLoginId']",
+			Password: "input[name='risPassword']",
+			Button:   "input[type='button'][value='ログイン']",
+		},
+		PersonalLogin: LoginSelectors{
+			Link:     "a[href*='funccode=1011000000']",
+			UserID:   "input[name='loginId']",
+			Password: "input[name='password']",
+			Button:   "input[type='submit'][value='ログイン'], input[type='button'][value='ログイン']",
+		},
+		LoggedIn:       "a:has-text('ログアウト')",
+		LoginError:     ".error-message, .alert-danger, .error",
+		SearchPageLink: "a:has-text('検索条件の指定')",
+		AllTrips:       "input[name='sokoKbn'][value='0']",
+		SaveConditions: "input[name='focusTarget_Save']",
+		SearchButton:   "input[name='focusTarget']",
+		ResultItem:     "input[name='hakkoMeisai']",
+		FromYear:       "select[name='fromYYYY']",
+		FromMonth:      "select[name='fromMM']",
+		FromDay:        "select[name='fromDD']",
+		ToYear:         "select[name='toYYYY']",
+		ToMonth:        "select[name='toMM']",
+		ToDay:          "select[name='toDD']",
+		// Note: onclick funccode varies by account type (1032500000 or other)
+		CSVLinks: []string{
+			"a:has-text('明細ＣＳＶ')",                            // Text match (most reliable, ignores spacing)
+			"a[onclick*='goOutput'][onclick*='hakkoMeisai']", // goOutput function call
+			"a[onclick*='1032500000']",                       // 明細CSV funccode (pattern 1)
+		},
+		CertificateLinks: []string{
+			"a:has-text('利用証明書')",
+			"input[type='button'][value*='利用証明書']",
+		},
+	}
+}
+
+// WithDefaults returns the selectors with every empty field set from DefaultSelectors
+func (s Selectors) WithDefaults() Selectors {
+	d := DefaultSelectors()
+	s.CorporateLogin = s.CorporateLogin.withDefaults(d.CorporateLogin)
+	s.PersonalLogin = s.PersonalLogin.withDefaults(d.PersonalLogin)
+	setDefault(&s.LoggedIn, d.LoggedIn)
+	setDefault(&s.LoginError, d.LoginError)
+	setDefault(&s.SearchPageLink, d.SearchPageLink)
+	setDefault(&s.AllTrips, d.AllTrips)
+	setDefault(&s.SaveConditions, d.SaveConditions)
+	setDefault(&s.SearchButton, d.SearchButton)
+	setDefault(&s.ResultItem, d.ResultItem)
+	setDefault(&s.FromYear, d.FromYear)
+	setDefault(&s.FromMonth, d.FromMonth)
+	setDefault(&s.FromDay, d.FromDay)
+	setDefault(&s.ToYear, d.ToYear)
+	setDefault(&s.ToMonth, d.ToMonth)
+	setDefault(&s.ToDay, d.ToDay)
+	if len(s.CSVLinks) == 0 {
+		s.CSVLinks = d.CSVLinks
+	}
+	if len(s.CertificateLinks) == 0 {
+		s.CertificateLinks = d.CertificateLinks
+	}
+	return s
+}
+
+func (l LoginSelectors) withDefaults(d LoginSelectors) LoginSelectors {
+	setDefault(&l.Link, d.Link)
+	setDefault(&l.UserID, d.UserID)
+	setDefault(&l.Password, d.Password)
+	setDefault(&l.Button, d.Button)
+	return l
+}
+
+func setDefault(value *string, def string) {
+	if *value == "" {
+		*value = def
+	}
+}
+
+// LoadSelectors reads selector overrides from a JSON file with the field names
+// of Selectors, e.g. {"search_button": "input[name='search']"}. Selectors not
+// in the file keep their defaults. Unknown keys are rejected so that a typo
+// does not silently leave the default in place.
+func LoadSelectors(path string) (Selectors, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Selectors{}, fmt.Errorf("failed to read selectors file: %w", err)
+	}
+
+	var selectors Selectors
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&selectors); err != nil {
+		return Selectors{}, fmt.Errorf("invalid selectors file %s: %w", path, err)
+	}
+	return selectors.WithDefaults(), nil
+}
@@ -0,0 +1,153 @@
+package scraper
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// statementDateLayouts are the date formats accepted by DownloadMeisai
+var statementDateLayouts = []string{"2006-01-02", "2006/01/02", "20060102"}
+
+// statementMonth is the part of the requested range that falls into one month.
+// The site shows statements month by month, so each one is searched separately.
+type statementMonth struct {
+	from time.Time
+	to   time.Time
+}
+
+// label returns the month as YYYYMM, used to name the per-month CSV files
+func (m statementMonth) label() string {
+	return m.from.Format("200601")
+}
+
+// splitStatementMonths splits fromDate..toDate into calendar months. It returns
+// false when either date cannot be parsed or the range is reversed, in which
+// case the search runs once with the conditions already on the page.
+func splitStatementMonths(fromDate, toDate string) ([]statementMonth, bool) {
+	from, ok := parseStatementDate(fromDate)
+	if !ok {
+		return nil, false
+	}
+	to, ok := parseStatementDate(toDate)
+	if !ok || to.Before(from) {
+		return nil, false
+	}
+
+	var months []statementMonth
+	for start := from; !start.After(to); {
+		end := time.Date(start.Year(), start.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+		if end.After(to) {
+			end = to
+		}
+		months = append(months, statementMonth{from: start, to: end})
+		start = end.AddDate(0, 0, 1)
+	}
+	return months, true
+}
+
+func parseStatementDate(value string) (time.Time, bool) {
+	for _, layout := range statementDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// selectStatementMonth sets the search form's date selectors to the month.
+// Selectors that cannot be set are logged and left as they are.
+func (s *ETCScraper) selectStatementMonth(month statementMonth) {
+	s.logger.Printf("Selecting date range %s - %s", month.from.Format("2006/01/02"), month.to.Format("2006/01/02"))
+	selectors := s.config.Selectors
+	fields := []struct {
+		selector string
+		value    string
+	}{
+		{selectors.FromYear, fmt.Sprintf("%04d", month.from.Year())},
+		{selectors.FromMonth, fmt.Sprintf("%02d", int(month.from.Month()))},
+		{selectors.FromDay, fmt.Sprintf("%02d", month.from.Day())},
+		{selectors.ToYear, fmt.Sprintf("%04d", month.to.Year())},
+		{selectors.ToMonth, fmt.Sprintf("%02d", int(month.to.Month()))},
+		{selectors.ToDay, fmt.Sprintf("%02d", month.to.Day())},
+	}
+	for _, field := range fields {
+		selector := s.page.Locator(field.selector).First()
+		if err := selector.SelectOption([]string{field.value}); err != nil {
+			s.logger.Printf("⚠️ Failed to select %s=%s: %v", field.selector, field.value, err)
+		}
+	}
+}
+
+// keepMonthlyCSV renames a downloaded CSV so that the next month's download,
+// which the site usually gives the same file name, does not overwrite it
+func keepMonthlyCSV(path string, month statementMonth) (string, error) {
+	ext := filepath.Ext(path)
+	monthlyPath := strings.TrimSuffix(path, ext) + "_" + month.label() + ext
+	if err := os.Rename(path, monthlyPath); err != nil {
+		return "", fmt.Errorf("failed to keep CSV for %s: %w", month.label(), err)
+	}
+	return monthlyPath, nil
+}
+
+// concatStatementCSVs writes the CSV files into dst one after another. The
+// header row of every file after the first is dropped when it matches the
+// first file's header.
+func concatStatementCSVs(dst string, paths []string) error {
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create combined CSV: %w", err)
+	}
+	defer out.Close()
+
+	w := bufio.NewWriter(out)
+	var header []byte
+	for i, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		first, rest := splitFirstLine(data)
+		if i == 0 {
+			header = normalizeHeaderLine(first)
+		} else if bytes.Equal(normalizeHeaderLine(first), header) {
+			data = rest
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write combined CSV: %w", err)
+		}
+		if data[len(data)-1] != '\n' {
+			if _, err := io.WriteString(w, "\r\n"); err != nil {
+				return fmt.Errorf("failed to write combined CSV: %w", err)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write combined CSV: %w", err)
+	}
+	return out.Close()
+}
+
+// splitFirstLine returns the first line (including its line break) and the rest
+func splitFirstLine(data []byte) (first, rest []byte) {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return data[:i+1], data[i+1:]
+	}
+	return data, nil
+}
+
+// normalizeHeaderLine strips the BOM and line break so headers compare equal
+func normalizeHeaderLine(line []byte) []byte {
+	line = bytes.TrimPrefix(line, []byte("\xef\xbb\xbf"))
+	return bytes.TrimRight(line, "\r\n")
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// AccountTestResult はTestAccountの結果
+type AccountTestResult struct {
+	OK bool
+	// Latency はスクレイパーの初期化からログイン完了（失敗）までの時間
+	Latency time.Duration
+	// Error は失敗した理由（パスワードは含めない）
+	Error string
+}
+
+// TestAccount はジョブを作成せずに単一アカウントの初期化とログインのみを行い、結果を返す
+// accountはID:パスワード形式。ほかのジョブが同じアカウントを処理中の場合は終わるまで待つ
+func (s *DownloadService) TestAccount(ctx context.Context, account string) AccountTestResult {
+	if err := ValidateAccountFormat(account); err != nil {
+		return AccountTestResult{Error: err.Error()}
+	}
+	userID := accountUserID(account)
+	password := strings.SplitN(account, ":", 2)[1]
+
+	release, err := s.lockAccount(ctx, "", userID)
+	if err != nil {
+		return AccountTestResult{Error: err.Error()}
+	}
+	defer release()
+
+	s.logJobf(LogLevelInfo, "", userID, "Testing login for account %s", userID)
+	// エラー時の画面キャプチャはジョブと同じくDownloadDir配下に保存する
+	sessionFolder := filepath.Join(s.DownloadDir, "account_test_"+time.Now().Format("20060102_150405"))
+	startedAt := time.Now()
+	_, err = s.downloadAccountDataSafe(ctx, "", account, "", "", sessionFolder, JobOptions{DryRun: true}, nil, nil)
+	result := AccountTestResult{OK: err == nil, Latency: time.Since(startedAt)}
+	if err != nil {
+		result.Error = redactPassword(err.Error(), password)
+		s.logJobf(LogLevelWarn, "", userID, "Login test failed for account %s: %s", userID, result.Error)
+	}
+	return result
+}
+
+// redactPassword はメッセージに含まれるパスワードを伏せ字にする
+func redactPassword(message, password string) string {
+	if password == "" {
+		return message
+	}
+	return strings.ReplaceAll(message, password, "*******")
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrAccountNotFound は指定されたアカウントが環境変数に設定されていない場合のエラー
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrInvalidCredential はアカウントIDまたはパスワードが空の場合のエラー
+	ErrInvalidCredential = errors.New("invalid credential")
+)
+
+// credentialOverrides はUpdateAccountCredentialで更新したパスワード
+// 環境変数の認証情報は起動時の値のままなので、パスワード変更後も再起動せずにログインできるよう
+// 環境変数のアカウントに重ねて適用する（メモリ上のみ保持し、再起動すると環境変数の値に戻る）
+type credentialOverrides struct {
+	mu        sync.RWMutex
+	passwords map[string]string // アカウントID -> パスワード
+}
+
+// set はアカウントのパスワードを上書きする
+func (c *credentialOverrides) set(accountID, password string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.passwords == nil {
+		c.passwords = make(map[string]string)
+	}
+	c.passwords[accountID] = password
+}
+
+// apply は上書きされたアカウントのパスワードを差し替えたアカウント一覧を返す
+func (c *credentialOverrides) apply(accounts []string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.passwords) == 0 {
+		return accounts
+	}
+	applied := make([]string, len(accounts))
+	for i, account := range accounts {
+		accountID := strings.TrimSpace(accountUserID(account))
+		if password, ok := c.passwords[accountID]; ok {
+			applied[i] = accountID + ":" + password
+		} else {
+			applied[i] = account
+		}
+	}
+	return applied
+}
+
+// accountIDs は上書きされているアカウントIDをID順に返す
+func (c *credentialOverrides) accountIDs() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ids := make([]string, 0, len(c.passwords))
+	for id := range c.passwords {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// UpdateAccountCredential は環境変数に設定されたアカウントのパスワードを更新する
+// 以降に開始するジョブとGetAllAccountsWithCredentialsは更新後のパスワードを使う
+func (s *DownloadService) UpdateAccountCredential(accountID, newPassword string) error {
+	accountID = strings.TrimSpace(accountID)
+	if accountID == "" || newPassword == "" {
+		return fmt.Errorf("%w: account ID and new password are required", ErrInvalidCredential)
+	}
+
+	found := false
+	for _, account := range envAccounts() {
+		if strings.TrimSpace(accountUserID(account)) == accountID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, accountID)
+	}
+
+	s.credentials.set(accountID, newPassword)
+	s.logMessagef(LogLevelInfo, "Updated credential for account %s", accountID)
+	return nil
+}
+
+// CredentialOverrideIDs はUpdateAccountCredentialでパスワードを更新したアカウントIDを返す
+func (s *DownloadService) CredentialOverrideIDs() []string {
+	return s.credentials.accountIDs()
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// getAccountsFile は環境変数からアカウント情報のファイルのパスを取得（未設定の場合は空）
+func getAccountsFile() string {
+	return strings.TrimSpace(os.Getenv("ETC_ACCOUNTS_FILE"))
+}
+
+// loadAccountsFile はETC_ACCOUNTS_FILEのアカウント情報（accountID:password形式）を読み込む
+// JSON配列（["user1:pass1","user2:pass2"]）または1行に1アカウント（空行と#で始まる行は無視）
+// 形式が不正な場合はどの行（要素）が不正かを返す（パスワードはエラーに含めない）
+func loadAccountsFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ETC_ACCOUNTS_FILE: %w", err)
+	}
+	// Windowsのメモ帳で保存したファイルのBOMを除く
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var accounts []string
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &accounts); err != nil {
+			return nil, fmt.Errorf("invalid JSON in ETC_ACCOUNTS_FILE %s: %w", path, err)
+		}
+		for i, account := range accounts {
+			if err := validateFileAccount(account); err != nil {
+				return nil, fmt.Errorf("invalid account at index %d in ETC_ACCOUNTS_FILE %s: %w", i, path, err)
+			}
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for line := 1; scanner.Scan(); line++ {
+			account := strings.TrimSpace(scanner.Text())
+			if account == "" || strings.HasPrefix(account, "#") {
+				continue
+			}
+			if err := validateFileAccount(account); err != nil {
+				return nil, fmt.Errorf("invalid account on line %d of ETC_ACCOUNTS_FILE %s: %w", line, path, err)
+			}
+			accounts = append(accounts, account)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read ETC_ACCOUNTS_FILE %s: %w", path, err)
+		}
+	}
+
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("no accounts in ETC_ACCOUNTS_FILE %s", path)
+	}
+	return accounts, nil
+}
+
+// validateFileAccount はファイルのアカウントがaccountID:password形式かを検証
+func validateFileAccount(account string) error {
+	accountID, password, found := strings.Cut(strings.TrimSpace(account), ":")
+	if !found || strings.TrimSpace(accountID) == "" || password == "" {
+		return errors.New("expected accountID:password")
+	}
+	return nil
+}
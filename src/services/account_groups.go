@@ -0,0 +1,76 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// ErrUnknownAccountGroup は指定されたアカウントグループがETC_ACCOUNT_GROUPSに設定されていない場合のエラー
+var ErrUnknownAccountGroup = errors.New("unknown account group")
+
+// getAccountGroups は環境変数からアカウントグループ（グループ名 -> アカウントID）を取得
+func getAccountGroups() map[string][]string {
+	return parseAccountGroups(os.Getenv("ETC_ACCOUNT_GROUPS"))
+}
+
+// parseAccountGroups はグループ名ごとのアカウントIDの指定を解析
+// JSON形式: {"groupA":["user1","user2"]} またはカンマ区切り: groupA=user1|user2,groupB=user3
+func parseAccountGroups(value string) map[string][]string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	groups := make(map[string][]string)
+	if strings.HasPrefix(value, "{") {
+		if err := json.Unmarshal([]byte(value), &groups); err != nil {
+			log.Printf("[Accounts] Invalid ETC_ACCOUNT_GROUPS JSON: %v", err)
+			return nil
+		}
+		return groups
+	}
+
+	for i, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, members, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			log.Printf("[Accounts] Ignoring invalid ETC_ACCOUNT_GROUPS entry #%d (expected group=account1|account2)", i+1)
+			continue
+		}
+		for _, accountID := range strings.Split(members, "|") {
+			if accountID = strings.TrimSpace(accountID); accountID != "" {
+				groups[name] = append(groups[name], accountID)
+			}
+		}
+	}
+	return groups
+}
+
+// GetGroupAccounts はグループに属する設定済みアカウントを認証情報付き（accountID:password形式）で返す
+// グループが設定されていない場合はErrUnknownAccountGroupを返す
+func (s *DownloadService) GetGroupAccounts(group string) ([]string, error) {
+	members, ok := getAccountGroups()[group]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAccountGroup, group)
+	}
+
+	inGroup := make(map[string]bool, len(members))
+	for _, accountID := range members {
+		inGroup[accountID] = true
+	}
+	var accounts []string
+	for _, account := range s.GetAllAccountsWithCredentials() {
+		if inGroup[strings.TrimSpace(accountUserID(account))] {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, nil
+}
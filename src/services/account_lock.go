@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// accountLocks はアカウントごとの排他制御
+// ETCサイトは短時間に繰り返しログインしたアカウントをロックするため、同じアカウントを
+// 複数のジョブ・ワーカーで同時に処理しない（異なるアカウントは並行して処理できる）
+type accountLocks struct {
+	mu    sync.Mutex
+	locks map[string]*accountLock
+}
+
+// accountLock は1アカウント分のロック（chに値が入っている間はロック中）
+type accountLock struct {
+	ch   chan struct{}
+	refs int // ロック中と待機中の数（0になったらmapから削除）
+}
+
+// ref はアカウントのロックを取得（なければ作成）して参照数を増やす
+func (l *accountLocks) ref(accountID string) *accountLock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.locks == nil {
+		l.locks = make(map[string]*accountLock)
+	}
+	lock, exists := l.locks[accountID]
+	if !exists {
+		lock = &accountLock{ch: make(chan struct{}, 1)}
+		l.locks[accountID] = lock
+	}
+	lock.refs++
+	return lock
+}
+
+// unref は参照数を減らし、使われなくなったロックを削除
+func (l *accountLocks) unref(accountID string, lock *accountLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, accountID)
+	}
+}
+
+// lockAccount はアカウントのロックを取得し、解放用の関数を返す
+// 他のジョブが処理中の場合は待機したことをログに出力する。ctxのキャンセルで待機を中断した場合はその原因を返す
+func (s *DownloadService) lockAccount(ctx context.Context, jobID, accountID string) (release func(), err error) {
+	lock := s.accountLocks.ref(accountID)
+	release = func() {
+		<-lock.ch
+		s.accountLocks.unref(accountID, lock)
+	}
+
+	select {
+	case lock.ch <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	s.logJobf(LogLevelInfo, jobID, accountID, "Waiting for account %s: it is being processed by another job", accountID)
+	waitStarted := time.Now()
+	select {
+	case lock.ch <- struct{}{}:
+		s.logJobf(LogLevelInfo, jobID, accountID, "Acquired account %s after waiting %v", accountID, time.Since(waitStarted).Round(time.Millisecond))
+		return release, nil
+	case <-ctx.Done():
+		s.accountLocks.unref(accountID, lock)
+		return nil, context.Cause(ctx)
+	}
+}
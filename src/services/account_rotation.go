@@ -0,0 +1,47 @@
+package services
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// getRotateAccounts は環境変数からジョブごとにアカウントの処理開始位置をずらすかを取得
+// ETC_ROTATE_ACCOUNTS 未設定または不正値の場合は有効（falseでリクエストの順序のまま処理する）
+func getRotateAccounts() bool {
+	rotateEnv := os.Getenv("ETC_ROTATE_ACCOUNTS")
+	if rotateEnv == "" {
+		return true
+	}
+
+	enabled, err := strconv.ParseBool(rotateEnv)
+	if err != nil {
+		log.Printf("[Accounts] Invalid ETC_ROTATE_ACCOUNTS value %q, using default: true", rotateEnv)
+		return true
+	}
+
+	return enabled
+}
+
+// rotateJobAccounts はアカウント数が同時処理数（MaxConcurrency）を超える場合に、
+// ジョブごとに1つずつ進む開始位置からアカウントを並べたコピーを返す
+// 同じアカウントが常に先に処理されてレート制限を受けることを避けるため（最初のジョブはリクエストの順序のまま）
+func (s *DownloadService) rotateJobAccounts(accounts []string) []string {
+	workers := s.MaxConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if !s.RotateAccounts || len(accounts) <= workers {
+		return accounts
+	}
+
+	offset := int((s.rotation.Add(1) - 1) % uint64(len(accounts)))
+	return rotateAccounts(accounts, offset)
+}
+
+// rotateAccounts はaccounts[offset]から始まり、末尾の次に先頭へ戻る順序のコピーを返す
+func rotateAccounts(accounts []string, offset int) []string {
+	rotated := make([]string, 0, len(accounts))
+	rotated = append(rotated, accounts[offset:]...)
+	return append(rotated, accounts[:offset]...)
+}
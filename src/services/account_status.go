@@ -0,0 +1,137 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// アカウントごとの最後の実行結果（GetAccountStatus）
+// メモリ上に保持し、DBが設定されている場合は migrations/007_add_account_run_status.sql のaccount_run_statusテーブルにも保存する
+
+const upsertAccountRunQuery = `INSERT INTO account_run_status (account_id, details)
+	VALUES (?, ?)
+	ON DUPLICATE KEY UPDATE details = VALUES(details)`
+
+const selectAccountRunQuery = `SELECT details FROM account_run_status WHERE account_id = ?`
+
+// AccountRunStatus はアカウントの最後の実行結果（認証情報は含めない）
+// 一度も実行していないアカウントはAccountID以外がゼロ値
+type AccountRunStatus struct {
+	AccountID string `json:"account_id"`
+	// LastJobID・LastRunAt・LastOutcome は最後に処理したジョブと結果（completed/failed/maintenance/authenticated）
+	LastJobID   string     `json:"last_job_id,omitempty"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	LastOutcome string     `json:"last_outcome,omitempty"`
+	// LastSuccessAt・LastRecordCount は最後にダウンロードに成功した日時と明細の件数
+	LastSuccessAt   *time.Time `json:"last_success_at,omitempty"`
+	LastRecordCount int        `json:"last_record_count,omitempty"`
+	// LastError・LastErrorAt は最後に失敗した理由と日時（その後に成功しても残す）
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// accountRunStates はアカウントID -> 最後の実行結果
+type accountRunStates struct {
+	mu   sync.Mutex
+	runs map[string]AccountRunStatus
+}
+
+// recordAccountRun はアカウントの処理結果を記録する（キャンセルで中止したアカウントは記録しない）
+// accountはパスワードを含むアカウント文字列で、エラーメッセージにパスワードが含まれていればマスクする
+func (s *DownloadService) recordAccountRun(jobID, account, outcome string, records int, runErr error) {
+	accountID := accountUserID(account)
+	s.accountRuns.mu.Lock()
+	defer s.accountRuns.mu.Unlock()
+
+	run, ok := s.accountRuns.runs[accountID]
+	if !ok {
+		// 再起動後の最初の実行では、DBに保存された前回までの成功・失敗を引き継ぐ
+		run, _ = s.loadAccountRun(accountID)
+	}
+	now := time.Now()
+	run.AccountID = accountID
+	run.LastJobID = jobID
+	run.LastRunAt = &now
+	run.LastOutcome = outcome
+	if runErr != nil {
+		run.LastError = runErr.Error()
+		if _, password, found := strings.Cut(account, ":"); found && password != "" {
+			run.LastError = strings.ReplaceAll(run.LastError, password, "*******")
+		}
+		run.LastErrorAt = &now
+	} else if outcome == accountStatusCompleted {
+		run.LastSuccessAt = &now
+		run.LastRecordCount = records
+	}
+
+	if s.accountRuns.runs == nil {
+		s.accountRuns.runs = make(map[string]AccountRunStatus)
+	}
+	s.accountRuns.runs[accountID] = run
+	s.saveAccountRun(jobID, run)
+}
+
+// GetAccountStatuses は指定したアカウントの最後の実行結果を指定順に返す（空の場合は設定済みの全アカウント）
+func (s *DownloadService) GetAccountStatuses(accountIDs []string) []AccountRunStatus {
+	if len(accountIDs) == 0 {
+		accountIDs = s.GetAllAccountIDs()
+	}
+
+	s.accountRuns.mu.Lock()
+	defer s.accountRuns.mu.Unlock()
+
+	statuses := make([]AccountRunStatus, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		accountID = strings.TrimSpace(accountID)
+		run, ok := s.accountRuns.runs[accountID]
+		if !ok {
+			run, ok = s.loadAccountRun(accountID)
+		}
+		if !ok {
+			run = AccountRunStatus{AccountID: accountID}
+		}
+		statuses = append(statuses, run)
+	}
+	return statuses
+}
+
+// saveAccountRun は最後の実行結果をDBに保存する（DBが設定されていない場合は何もしない）
+func (s *DownloadService) saveAccountRun(jobID string, run AccountRunStatus) {
+	if s.db == nil {
+		return
+	}
+
+	details, err := json.Marshal(run)
+	if err == nil {
+		_, err = s.db.Exec(upsertAccountRunQuery, run.AccountID, string(details))
+	}
+	if err != nil {
+		s.logJobf(LogLevelWarn, jobID, run.AccountID, "Failed to save last run status for account %s: %v", run.AccountID, err)
+	}
+}
+
+// loadAccountRun はDBから最後の実行結果を読み込む（記録がない場合やDBが設定されていない場合はfalse）
+func (s *DownloadService) loadAccountRun(accountID string) (AccountRunStatus, bool) {
+	if s.db == nil {
+		return AccountRunStatus{}, false
+	}
+
+	var details string
+	err := s.db.QueryRow(selectAccountRunQuery, accountID).Scan(&details)
+	if errors.Is(err, sql.ErrNoRows) {
+		return AccountRunStatus{}, false
+	}
+	var run AccountRunStatus
+	if err == nil {
+		err = json.Unmarshal([]byte(details), &run)
+	}
+	if err != nil {
+		s.logMessagef(LogLevelWarn, "Failed to load last run status for account %s: %v", accountID, err)
+		return AccountRunStatus{}, false
+	}
+	return run, true
+}
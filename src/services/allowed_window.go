@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	// ETC_TIMEZONEをタイムゾーンデータの無い環境（Windowsなど）でも読み込めるよう埋め込む
+	_ "time/tzdata"
+)
+
+// ErrOutsideAllowedWindow はETC_ALLOWED_WINDOWの時間帯の外でジョブを開始しようとした場合のエラー
+var ErrOutsideAllowedWindow = errors.New("outside the allowed download window")
+
+// TimeWindow は1日のうちの時間帯（Startを含みEndを含まない、Start > Endの場合は日付をまたぐ）
+type TimeWindow struct {
+	Start time.Duration // 0時からの経過時間
+	End   time.Duration
+}
+
+// ParseTimeWindow は"HH:MM-HH:MM"形式の時間帯を解析する（例: 01:00-05:00、22:00-05:00）
+func ParseTimeWindow(value string) (TimeWindow, error) {
+	startStr, endStr, found := strings.Cut(value, "-")
+	if !found {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q: expected HH:MM-HH:MM", value)
+	}
+	start, err := parseClock(startStr)
+	if err != nil {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q: %w", value, err)
+	}
+	end, err := parseClock(endStr)
+	if err != nil {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q: %w", value, err)
+	}
+	if start == end {
+		return TimeWindow{}, fmt.Errorf("invalid time window %q: start and end are the same", value)
+	}
+	return TimeWindow{Start: start, End: end}, nil
+}
+
+// parseClock は"HH:MM"を0時からの経過時間に変換する（24:00は不可）
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", strings.TrimSpace(value))
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains はtの時刻（tのタイムゾーン）が時間帯に含まれるかを返す
+func (w TimeWindow) Contains(t time.Time) bool {
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+	if w.Start < w.End {
+		return clock >= w.Start && clock < w.End
+	}
+	return clock >= w.Start || clock < w.End
+}
+
+// String は"HH:MM-HH:MM"形式で返す
+func (w TimeWindow) String() string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+	return clock(w.Start) + "-" + clock(w.End)
+}
+
+// checkAllowedWindow はnowがAllowedWindowの時間帯（Locationのタイムゾーン）の外であればErrOutsideAllowedWindowを返す
+// AllowedWindowが未設定の場合は常にnil
+func (s *DownloadService) checkAllowedWindow(now time.Time) error {
+	if s.AllowedWindow == nil {
+		return nil
+	}
+	loc := s.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	local := now.In(loc)
+	if s.AllowedWindow.Contains(local) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is outside %s (%s)", ErrOutsideAllowedWindow, local.Format("15:04"), s.AllowedWindow, loc)
+}
+
+// getAllowedWindow は環境変数ETC_ALLOWED_WINDOWからジョブを開始できる時間帯を取得
+// 未設定の場合はnil（制限なし）、不正値の場合も制限しない
+func (s *DownloadService) getAllowedWindow() *TimeWindow {
+	value := strings.TrimSpace(os.Getenv("ETC_ALLOWED_WINDOW"))
+	if value == "" {
+		return nil
+	}
+
+	window, err := ParseTimeWindow(value)
+	if err != nil {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_ALLOWED_WINDOW value %q, using default: no restriction (%v)", value, err)
+		return nil
+	}
+
+	return &window
+}
+
+// getLocation は環境変数ETC_TIMEZONE（例: Asia/Tokyo）からAllowedWindowを判定するタイムゾーンを取得
+// 未設定・不正値の場合はサーバーのローカルタイムゾーン
+func (s *DownloadService) getLocation() *time.Location {
+	value := strings.TrimSpace(os.Getenv("ETC_TIMEZONE"))
+	if value == "" {
+		return time.Local
+	}
+
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_TIMEZONE value %q, using default: %s", value, time.Local)
+		return time.Local
+	}
+
+	return loc
+}
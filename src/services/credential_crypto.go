@@ -0,0 +1,119 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// encryptedAccountPrefix は暗号化されたアカウント情報の接頭辞（enc:<base64(nonce+暗号文)>）
+const encryptedAccountPrefix = "enc:"
+
+// getCredentialKey は環境変数ETC_CRED_KEY（base64エンコードした16・24・32バイトのAES鍵）を取得
+// 未設定の場合はnilを返す
+func getCredentialKey() ([]byte, error) {
+	value := strings.TrimSpace(os.Getenv("ETC_CRED_KEY"))
+	if value == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ETC_CRED_KEY: not base64: %w", err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("invalid ETC_CRED_KEY: expected a 16, 24 or 32 byte key, got %d bytes", len(key))
+	}
+}
+
+// EncryptAccounts はアカウント情報（accountID:password形式）をETC_CRED_KEYの鍵でAES-GCM暗号化し、
+// ETC_CORP_ACCOUNTSやETC_ACCOUNTS_FILEにそのまま記載できるenc:形式で返す
+func EncryptAccounts(accounts []string) ([]string, error) {
+	key, err := getCredentialKey()
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, errors.New("ETC_CRED_KEY is not set")
+	}
+	aead, err := newCredentialAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := make([]string, 0, len(accounts))
+	for i, account := range accounts {
+		account = strings.TrimSpace(account)
+		if err := validateFileAccount(account); err != nil {
+			return nil, fmt.Errorf("invalid account at index %d: %w", i, err)
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		sealed := aead.Seal(nonce, nonce, []byte(account), nil)
+		encrypted = append(encrypted, encryptedAccountPrefix+base64.StdEncoding.EncodeToString(sealed))
+	}
+	return encrypted, nil
+}
+
+// decryptAccounts はenc:形式のアカウント情報をETC_CRED_KEYの鍵で復号する（平文のアカウントはそのまま）
+// 復号できない場合は暗号文をアカウントIDとして扱わないようエラーを返す
+func decryptAccounts(accounts []string) ([]string, error) {
+	if len(accounts) == 0 {
+		return accounts, nil
+	}
+
+	var aead cipher.AEAD
+	decrypted := make([]string, 0, len(accounts))
+	for i, account := range accounts {
+		value, ok := strings.CutPrefix(strings.TrimSpace(account), encryptedAccountPrefix)
+		if !ok {
+			decrypted = append(decrypted, account)
+			continue
+		}
+
+		if aead == nil {
+			key, err := getCredentialKey()
+			if err != nil {
+				return nil, err
+			}
+			if key == nil {
+				return nil, fmt.Errorf("account at index %d is encrypted but ETC_CRED_KEY is not set", i)
+			}
+			if aead, err = newCredentialAEAD(key); err != nil {
+				return nil, err
+			}
+		}
+
+		sealed, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(sealed) < aead.NonceSize() {
+			return nil, fmt.Errorf("failed to decrypt account at index %d: malformed encrypted value", i)
+		}
+		plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt account at index %d: wrong ETC_CRED_KEY or corrupted value", i)
+		}
+		if err := validateFileAccount(string(plain)); err != nil {
+			return nil, fmt.Errorf("invalid decrypted account at index %d: %w", i, err)
+		}
+		decrypted = append(decrypted, string(plain))
+	}
+	return decrypted, nil
+}
+
+// newCredentialAEAD はアカウント情報の暗号化に使うAES-GCMを作成
+func newCredentialAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ETC_CRED_KEY: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
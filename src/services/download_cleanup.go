@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// sessionFolderPattern はcreateSessionFolderが作成するセッションフォルダ名（日時_ジョブID_ランダム文字列）
+// ジョブIDを含まない以前の形式（日時のみ）も対象にする
+var sessionFolderPattern = regexp.MustCompile(`^(\d{8}_\d{6})(_[A-Za-z0-9_-]{1,64}_[0-9a-f]{8})?$`)
+
+// sessionFolderTime はセッションフォルダ名から作成日時を取得する（命名規則に合わない場合はfalse）
+func sessionFolderTime(name string) (time.Time, bool) {
+	match := sessionFolderPattern.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+	createdAt, err := time.ParseInLocation("20060102_150405", match[1], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return createdAt, true
+}
+
+// CleanupOldDownloads はDownloadDir直下のセッションフォルダのうち、作成からmaxAge以上経過したものを削除する
+// 誤削除を防ぐため、フォルダ名がセッションフォルダの命名規則に合うものだけを対象にする
+// 実行中のジョブのフォルダも対象になるため、ジョブを開始する前（サーバー起動時）に呼び出す
+func (s *DownloadService) CleanupOldDownloads(maxAge time.Duration) (removed int, err error) {
+	if maxAge <= 0 {
+		return 0, fmt.Errorf("invalid max age %v: must be positive", maxAge)
+	}
+
+	entries, err := os.ReadDir(s.DownloadDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read download directory %s: %w", s.DownloadDir, err)
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		createdAt, ok := sessionFolderTime(entry.Name())
+		if !ok || createdAt.After(cutoff) {
+			continue
+		}
+		if err := s.CleanupSession(filepath.Join(s.DownloadDir, entry.Name())); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		s.logMessagef(LogLevelInfo, "Removed %d session folders older than %v from %s", removed, maxAge, s.DownloadDir)
+	}
+	return removed, errors.Join(errs...)
+}
+
+// CleanupDownloadsOnStart はCleanupOnStartが設定されていれば、それより古いセッションフォルダを削除する
+func (s *DownloadService) CleanupDownloadsOnStart() (removed int, err error) {
+	if s.CleanupOnStart <= 0 {
+		return 0, nil
+	}
+	return s.CleanupOldDownloads(s.CleanupOnStart)
+}
+
+// getCleanupOnStart は環境変数から起動時に削除するセッションフォルダの経過時間を取得
+// ETC_CLEANUP_ON_START はtime.ParseDuration形式（例: 168h）、未設定・不正値の場合は0（削除しない）
+func (s *DownloadService) getCleanupOnStart() time.Duration {
+	ageEnv := os.Getenv("ETC_CLEANUP_ON_START")
+	if ageEnv == "" {
+		return 0
+	}
+
+	age, err := time.ParseDuration(ageEnv)
+	if err != nil || age < 0 {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_CLEANUP_ON_START value %q, using default: 0 (no cleanup)", ageEnv)
+		return 0
+	}
+
+	return age
+}
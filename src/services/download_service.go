@@ -1,17 +1,30 @@
 package services
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/models"
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
 	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // DownloadService はダウンロード処理を管理
@@ -20,10 +33,127 @@ type DownloadService struct {
 	logger         *log.Logger
 	jobs           map[string]*DownloadJob
 	jobMutex       sync.RWMutex
+	persistMu      sync.Mutex                       // ジョブのDB保存を直列化
+	pauses         map[string]*jobPause             // 実行中ジョブの一時停止制御（jobMutexで保護）
+	records        map[string][]*pb.ETCMeisaiRecord // ジョブごとの解析済み明細（jobMutexで保護）
+	runtime        runtimeCounters                  // ワーカーとブラウザの稼働状況（jobMutexで保護）
+	jobChanged     map[string]chan struct{}         // WaitForJobへのジョブの状態の変化の通知（jobMutexで保護）
 	scraperFactory ScraperFactory
-	logCallback    func(string) // ログコールバック関数
+	logCallback    func(string)          // ログコールバック関数
+	entryCallback  func(LogEntry)        // レベル付きログコールバック関数
+	metrics        *JobMetrics           // ジョブ数とダウンロード時間のメトリクス（無効時はnil）
+	jsonLogger     *log.Logger           // ETC_LOG_FORMAT=json の場合のJSONログ出力先（テキスト形式ではnil）
+	accountLocks   accountLocks          // 同じアカウントをジョブ間で同時に処理しないための排他制御
+	credentials    credentialOverrides   // UpdateAccountCredentialで更新したパスワード
+	idempotency    idempotencyKeys       // DownloadAsyncの冪等キー
+	accountRuns    accountRunStates      // アカウントごとの最後の実行結果
+	jobSlots       jobSlots              // 同時に実行するジョブ数の制限（MaxJobsRunning）
+	jobLogs        map[string]*LogBuffer // ジョブごとのログ（jobLogsMuで保護）
+	jobLogsMu      sync.Mutex
+	jobLogLines    int           // ジョブごとのログバッファの最大行数（ETC_LOG_BUFFER_SIZE）
+	rotation       atomic.Uint64 // RotateAccountsでずらした回数（次のジョブの開始位置）
+	resumeMu       sync.Mutex    // 中断したジョブの再開を直列化
+	proxies        proxyPool     // ProxyPoolの割り当て位置とプロキシごとの連続失敗回数
+
+	// シャットダウン制御（Shutdownでctxをキャンセルし、jobsWGで実行中ジョブの終了を待つ）
+	ctx          context.Context
+	cancel       context.CancelFunc
+	jobsWG       sync.WaitGroup
+	shuttingDown bool // jobMutexで保護
+
+	// MaxConcurrency は同時に処理するアカウント数の上限（ETC_MAX_CONCURRENCY、デフォルト1）
+	MaxConcurrency int
+	// RecoverMissingDownload はダウンロードファイルが見つからない場合にセッションフォルダから
+	// 直近のCSVを探すかどうか（ETC_RECOVER_MISSING_DOWNLOAD、デフォルトtrue）
+	RecoverMissingDownload bool
+	// AccountDelay はアカウント間のレート制限待機時間（ETC_ACCOUNT_DELAY_MS、デフォルト1000ms）
+	AccountDelay time.Duration
+	// JobTTL は終了済みジョブを保持する期間（ETC_JOB_TTL、デフォルト1h、0以下で無期限）
+	JobTTL time.Duration
+	// MaintenanceRetryDelay はETCサイトのメンテナンス中で失敗したアカウントを再実行するまでの待機時間
+	// （ETC_MAINTENANCE_RETRY_DELAY、デフォルト0で再実行しない）
+	MaintenanceRetryDelay time.Duration
+	// MaxJobs はメモリに保持するジョブ数の上限（ETC_MAX_JOBS、デフォルト0で無制限）
+	// 超えた場合は新しいジョブの追加時に終了済みジョブを古い順に削除する（実行中のジョブは削除しない）
+	MaxJobs int
+	// MaxJobsRunning は同時に実行するジョブ数の上限（ETC_MAX_JOBS_RUNNING、デフォルト0で無制限）
+	// 超えた分のジョブはpendingのまま受け付け順に待機し、実行中のジョブが終わると開始する
+	MaxJobsRunning int
+	// AllowedWindow はジョブを開始できる時間帯（ETC_ALLOWED_WINDOW、デフォルトnilで制限なし）
+	// 時間帯の外で開始したジョブはErrOutsideAllowedWindowで失敗する（実行中のジョブは時間帯を過ぎても続ける）
+	AllowedWindow *TimeWindow
+	// Location はAllowedWindowを判定するタイムゾーン（ETC_TIMEZONE、デフォルトはサーバーのローカルタイムゾーン）
+	Location *time.Location
+	// RetryPasses はジョブの最後に一時的な理由で失敗したアカウントのみを再実行する回数
+	// （ETC_JOB_RETRY_PASSES、デフォルト0で再実行しない。認証エラーとメンテナンスは再実行しない）
+	RetryPasses int
+	// RetryPassDelay は再実行までの待機時間（ETC_JOB_RETRY_PASS_DELAY、デフォルト30s、再実行のたびに2倍）
+	RetryPassDelay time.Duration
+	// RetryBaseDelay はログイン・ダウンロードのリトライ間隔の基準値（ETC_RETRY_BASE_DELAY_MS、デフォルト2000ms）
+	// リトライのたびに2倍になる（最大maxRetryDelay）
+	RetryBaseDelay time.Duration
+	// InitRetryCount はPlaywrightドライバの起動待ちでブラウザ初期化をリトライする回数（ETC_INIT_RETRY_COUNT、デフォルト2）
+	// ドライバ未インストールの場合はリトライしない
+	InitRetryCount int
+	// InitRetryDelay はブラウザ初期化のリトライ間隔（ETC_INIT_RETRY_DELAY_MS、デフォルト1000ms、一定間隔）
+	InitRetryDelay time.Duration
+	// DownloadDir はダウンロードファイルの保存先ベースディレクトリ（ETC_DOWNLOAD_DIR、デフォルト./downloads）
+	// ジョブごとのセッションフォルダはこの下に作成される
+	DownloadDir string
+	// CleanupDownloads は全アカウント成功したジョブのセッションフォルダを削除するか（ETC_CLEANUP_DOWNLOADS、デフォルトfalse）
+	// 失敗したアカウントがある場合は調査用に常に残す
+	CleanupDownloads bool
+	// CleanupOnStart は起動時（CleanupDownloadsOnStart）に削除するセッションフォルダの作成からの経過時間
+	// （ETC_CLEANUP_ON_START、デフォルト0で削除しない）
+	CleanupOnStart time.Duration
+	// Timeout はPlaywrightの操作タイムアウト（ETC_TIMEOUT_MS、デフォルト30000ms）
+	// リクエストのtimeout_msで上書きできる
+	Timeout time.Duration
+	// Incremental は開始日が指定されなかった場合に、アカウントごとに前回ダウンロードした
+	// 期間の終了日から取得するか（ETC_INCREMENTAL、デフォルトfalse、DB設定時のみ有効）
+	// 明細のDB保存は未実装のため、ETC_INCREMENTAL_WITHOUT_SAVEで明示的に許可した場合のみ有効
+	Incremental bool
+	// SortAccounts はアカウントをユーザーID順に処理するか（ETC_SORT_ACCOUNTS、デフォルトfalse）
+	SortAccounts bool
+	// RotateAccounts はアカウント数がMaxConcurrencyを超える場合に、処理を始めるアカウントを
+	// ジョブごとに1つずつずらすか（ETC_ROTATE_ACCOUNTS、デフォルトtrue）
+	// ユーザーID順に処理する場合（SortAccounts）はずらさない
+	RotateAccounts bool
+	// MaxRecordsPerAccount はアカウントごとに解析する明細の上限（ETC_MAX_RECORDS、デフォルト0=無制限）
+	// 超えた分は解析せず、AccountResult.Truncatedをtrueにする
+	MaxRecordsPerAccount int
+	// CSVEncoding はダウンロードしたCSVの文字コード（ETC_CSV_ENCODING、デフォルトCSVEncodingAuto=自動判定）
+	CSVEncoding CSVEncoding
+	// CSVColumnMap はダウンロードしたCSVの列名の別名（ETC_CSV_COLUMN_MAP、デフォルトnil=標準の列名のみ）
+	CSVColumnMap CSVColumnMap
+	// ProxyPool はアカウントに順番に割り当てるプロキシ（ETC_PROXY_POOL、デフォルトnilでETC_PROXY_URL）
+	// ETC_ACCOUNT_PROXIESで指定したアカウントには割り当てない。一時的なエラーで続けて
+	// maxProxyFailures回失敗したプロキシは、成功するまで（すべて失敗している場合を除き）飛ばす
+	ProxyPool []string
+	// CheckDB はジョブの開始前にDBへPingし、接続できなければジョブを失敗させるか（ETC_DB_CHECK、デフォルトtrue）
+	// DBが設定されていない場合は確認しない
+	CheckDB bool
+	// CallbackClient はジョブ完了通知の送信に使うHTTPクライアント（テストで差し替え可能）
+	CallbackClient HTTPDoer
+	// CallbackRetryDelay はジョブ完了通知の再送までの待機時間（デフォルト1s）
+	CallbackRetryDelay time.Duration
 }
 
+// defaultDownloadDir はETC_DOWNLOAD_DIR未設定時の保存先
+const defaultDownloadDir = "./downloads"
+
+// maxRetryDelay はリトライ間隔の上限
+const maxRetryDelay = 30 * time.Second
+
+// Playwrightの操作タイムアウトの既定値と許容範囲
+const (
+	defaultTimeout = 30 * time.Second
+	minTimeout     = 5 * time.Second
+	maxTimeout     = 300 * time.Second
+	// minDeadlineTimeout はJobOptions.Deadlineから求めたタイムアウトの下限
+	minDeadlineTimeout = time.Second
+)
+
 // DownloadJob はダウンロードジョブの状態
 type DownloadJob struct {
 	ID           string
@@ -33,30 +163,274 @@ type DownloadJob struct {
 	ErrorMessage string
 	StartedAt    time.Time
 	CompletedAt  *time.Time
+	// AccountResults はアカウントごとのダウンロード結果（完了したアカウントのみ）
+	AccountResults []AccountResult
+	// CurrentAccount は最後に処理を開始したアカウントID
+	CurrentAccount string
+	// PerAccount はアカウントIDごとの状態（pending/processing/completed/failed/maintenance）
+	PerAccount map[string]string
+	// FailedAccounts は失敗したアカウントとその理由
+	FailedAccounts []FailedAccount
+	// DryRun はダウンロードせず認証のみ確認するジョブの場合にtrue
+	DryRun bool
+	// RescheduledJobID はメンテナンス中だったアカウントを再実行するジョブのID（再実行しない場合は空）
+	RescheduledJobID string
+	// RescheduledAt は再実行するジョブの開始予定日時
+	RescheduledAt *time.Time
+	// Passes は実行したパス数（最初の実行で1、失敗したアカウントの再実行のたびに1増える）
+	Passes int
+	// TotalBytes・FileCount は完了したアカウントで保存したファイル（CSVと利用証明書のPDF）の合計サイズと数
+	TotalBytes int64
+	FileCount  int
+
+	// checkpoint は中断した場合に再開するための情報（ResumeJob参照）
+	checkpoint *jobCheckpoint
+}
+
+// JobOptions はジョブごとの実行オプション
+type JobOptions struct {
+	// DryRun がtrueの場合、各アカウントでInitialize・Login・Closeのみ行いダウンロードしない
+	DryRun bool
+	// Timeout はPlaywrightの操作タイムアウト（0の場合はDownloadService.Timeout）
+	Timeout time.Duration
+	// Headless はブラウザのHeadlessモード（nilの場合はETC_HEADLESS）
+	Headless *bool
+	// FromDateUnset はリクエストで開始日が指定されなかった場合にtrue
+	// Incrementalが有効な場合、アカウントごとに前回ダウンロードした日付から取得する
+	FromDateUnset bool
+	// SortAccounts がtrueの場合、アカウントをユーザーID順に処理する（DownloadService.SortAccountsが有効な場合も同様）
+	SortAccounts bool
+	// CallbackURL が指定された場合、ジョブ終了時に結果をJSONでPOSTする
+	CallbackURL string
+	// Deadline が指定された場合、Playwrightの操作タイムアウトをアカウントの処理開始時点の残り時間以下にする
+	Deadline time.Time
+	// CardNumbers が指定された場合、これらのETCカードの明細のみ保持する（空の場合はすべてのカード）
+	CardNumbers []string
+	// AbortOnCancel がtrueの場合、ジョブがキャンセルされると処理中のアカウントもスクレイパーを閉じて中止する
+	// （falseの場合は処理中のアカウントを最後まで処理してから止まる）
+	AbortOnCancel bool
+	// DownloadCertificates がtrueの場合、明細のダウンロード後に利用証明書のPDFもセッションフォルダに保存する
+	DownloadCertificates bool
+	// OutputFormat はジョブの結果として明細とCSVのどちらを残すか（ゼロ値はOutputFormatBoth）
+	OutputFormat OutputFormat
+
+	// maintenanceRetries はメンテナンスによる再実行の回数（再実行したジョブで1以上）
+	maintenanceRetries int
+	// resumed は再開する中断したジョブ（DBから読み込んだもの、新しいジョブではnil）
+	resumed *DownloadJob
+}
+
+// OutputFormat はジョブの結果として残すもの
+type OutputFormat int
+
+const (
+	// OutputFormatBoth は明細を解析し、CSVも残す
+	OutputFormatBoth OutputFormat = iota
+	// OutputFormatRecords は明細を解析し、解析に成功したCSVは削除する
+	OutputFormatRecords
+	// OutputFormatCSVPath は明細を解析せず、CSVのパスのみ残す（GetJobResultの明細は空になる）
+	OutputFormatCSVPath
+)
+
+// FailedAccount は失敗したアカウントの情報
+type FailedAccount struct {
+	AccountID string
+	Reason    string
+	// AuthError は認証情報が誤っている（サイトにログインを拒否された）場合にtrue
+	AuthError bool
+	// Timeout はタイムアウトで失敗した場合にtrue
+	Timeout bool
+	// Maintenance はETCサイトのメンテナンス中で失敗した場合にtrue
+	Maintenance bool
+}
+
+// jobStatusPartial は一部のアカウントのみ失敗したジョブの状態
+const jobStatusPartial = "partial"
+
+// アカウント単位の状態
+const (
+	accountStatusPending    = "pending"
+	accountStatusProcessing = "processing"
+	accountStatusCompleted  = "completed"
+	accountStatusFailed     = "failed"
+	// accountStatusAuthenticated はDryRunでログインに成功したアカウントの状態
+	accountStatusAuthenticated = "authenticated"
+	// accountStatusMaintenance はETCサイトのメンテナンス中で処理できなかったアカウントの状態
+	accountStatusMaintenance = "maintenance"
+	// accountStatusCancelled はAbortOnCancelのジョブがキャンセルされ、処理を中止したアカウントの状態
+	accountStatusCancelled = "cancelled"
+)
+
+// AccountResult はアカウント単位のダウンロード結果
+type AccountResult struct {
+	AccountID string
+	// ExpectedRecords はサイト上に表示された件数（ExpectedKnownがfalseの場合は不明）
+	ExpectedRecords int
+	ExpectedKnown   bool
+	// ActualRecords はダウンロードしたCSVから読み取った件数
+	ActualRecords int
+	// CountMismatch はサイト上の件数とCSVの件数が一致しない場合にtrue
+	CountMismatch bool
+	// Truncated はMaxRecordsPerAccountを超えたため明細の解析を打ち切った場合にtrue
+	// ActualRecordsは打ち切った後の件数になる
+	Truncated bool
+	// ParseFailed はCSVのダウンロードには成功したが明細の解析に失敗した場合にtrue
+	ParseFailed bool
+	// CSVPath はダウンロードしたCSVのパス（OutputFormatRecordsで削除した場合は空）
+	CSVPath string
+	// CertificatePaths はJobOptions.DownloadCertificatesで保存した利用証明書のPDFのパス
+	CertificatePaths []string
+	// CertificatesFailed は明細のダウンロードには成功したが利用証明書のダウンロードに失敗した場合にtrue
+	CertificatesFailed bool
+
+	// records はCSVから解析した明細（ジョブに記録する際に取り出す）
+	records []*pb.ETCMeisaiRecord
+	// bytes・files は保存したファイルの合計サイズと数（ジョブのTotalBytes・FileCountに加算する）
+	bytes int64
+	files int
+}
+
+// RuntimeMetrics はサービスの稼働状況のスナップショット
+type RuntimeMetrics struct {
+	QueuedJobs     int // 開始待ちのジョブ数
+	RunningJobs    int // 実行中のジョブ数
+	PausedJobs     int // 一時停止中のジョブ数
+	QueuedAccounts int // 実行中ジョブでワーカーの割り当て待ちのアカウント数
+	BusyWorkers    int // ダウンロード処理中のワーカー数
+	IdleWorkers    int // 待機中（一時停止・レート制限待ちを含む）のワーカー数
+	ActiveBrowsers int // 起動中のブラウザ数
+	// RateBudgetRemaining はグローバルなレート制限の残り枠（-1: レート制限なし）
+	RateBudgetRemaining int
+}
+
+// runtimeCounters はRuntimeMetrics用のカウンタ
+type runtimeCounters struct {
+	queuedAccounts int
+	workers        int
+	busyWorkers    int
+	activeBrowsers int
 }
 
 // DownloadServiceInterface はダウンロードサービスのインターフェース
 type DownloadServiceInterface interface {
 	GetAllAccountIDs() []string
 	GetAllAccountsWithCredentials() []string
-	ProcessAsync(jobID string, accounts []string, fromDate, toDate string)
+	GetGroupAccounts(group string) ([]string, error)
+	ProcessAsync(ctx context.Context, jobID string, accounts []string, fromDate, toDate string)
+	ProcessAsyncWithOptions(ctx context.Context, jobID string, accounts []string, fromDate, toDate string, opts JobOptions)
+	ProcessAsyncIdempotent(ctx context.Context, key, jobID string, accounts []string, fromDate, toDate string, opts JobOptions) (string, bool)
 	GetJobStatus(jobID string) (*DownloadJob, bool)
+	GetJobStatuses(jobIDs []string) map[string]*DownloadJob
+	WaitForJob(ctx context.Context, jobID, lastStatus string) (*DownloadJob, bool)
 	SetLogCallback(callback func(string))
+	SetLogEntryCallback(callback func(LogEntry))
+	StartJobReaper(interval time.Duration) (stop func())
+	PauseJob(jobID string) error
+	ResumeJob(jobID string) error
+	CancelJob(jobID string) error
+	TestAccount(ctx context.Context, account string) AccountTestResult
+	GetJobRecords(jobID string) ([]*pb.ETCMeisaiRecord, error)
+	GetJobRecordsSoFar(jobID string) ([]*pb.ETCMeisaiRecord, error)
+	GetJobLogs(jobID string, tail int) ([]LogEntry, error)
+	ExportJobCSV(jobID string, w io.Writer) error
+	GetJobFiles(jobID string) ([]FileInfo, error)
+	WriteJobFile(jobID, name string, w io.Writer) error
+	GetRuntimeMetrics() RuntimeMetrics
+	UpdateAccountCredential(accountID, newPassword string) error
+	CredentialOverrideIDs() []string
+	GetAccountStatuses(accountIDs []string) []AccountRunStatus
+}
+
+var (
+	// ErrJobNotFound は指定されたジョブが存在しない場合のエラー
+	ErrJobNotFound = errors.New("job not found")
+	// ErrInvalidJobState はジョブの状態が要求された操作に対応していない場合のエラー
+	ErrInvalidJobState = errors.New("invalid job state")
+	// ErrShuttingDown はシャットダウン中のため新しいジョブを開始できない場合のエラー
+	ErrShuttingDown = errors.New("download service is shutting down")
+	// ErrJobCancelled はCancelJobでジョブがキャンセルされた場合のエラー
+	ErrJobCancelled = errors.New("job cancelled")
+	// ErrDatabaseUnavailable はジョブの開始前にDBへ接続できなかった場合のエラー
+	ErrDatabaseUnavailable = errors.New("database unavailable")
+	// ErrInvalidFileName はジョブのファイル名がセッションフォルダ直下のファイルを指していない場合のエラー
+	ErrInvalidFileName = errors.New("invalid file name")
+	// ErrFileNotFound はジョブのセッションフォルダに指定されたファイルがない場合のエラー
+	ErrFileNotFound = errors.New("file not found")
+)
+
+// dbCheckTimeout はジョブ開始前のDBへのPingのタイムアウト
+const dbCheckTimeout = 3 * time.Second
+
+// jobPause は実行中ジョブの制御（一時停止シグナルとキャンセル）
+// 一時停止中はresumeChが作成され、再開時にcloseされる
+type jobPause struct {
+	resumeCh chan struct{}
+	// cancel はジョブのコンテキストをキャンセルする（原因はErrJobCancelled・ErrShuttingDownなど）
+	cancel context.CancelCauseFunc
 }
 
 // NewDownloadService creates a new download service
 func NewDownloadService(db *sql.DB, logger *log.Logger) *DownloadService {
-	return NewDownloadServiceWithFactory(db, logger, NewDefaultScraperFactory())
+	return NewDownloadServiceWithFactory(db, logger, NewScraperFactory())
 }
 
 // NewDownloadServiceWithFactory creates a new download service with a custom scraper factory
 func NewDownloadServiceWithFactory(db *sql.DB, logger *log.Logger, factory ScraperFactory) *DownloadService {
-	return &DownloadService{
+	s := &DownloadService{
 		db:             db,
 		logger:         logger,
 		jobs:           make(map[string]*DownloadJob),
+		pauses:         make(map[string]*jobPause),
+		records:        make(map[string][]*pb.ETCMeisaiRecord),
+		jobChanged:     make(map[string]chan struct{}),
+		jobLogs:        make(map[string]*LogBuffer),
+		jobLogLines:    getLogBufferSize(),
 		scraperFactory: factory,
+
+		MaxConcurrency:         GetMaxConcurrency(),
+		RecoverMissingDownload: getRecoverMissingDownload(),
+		DownloadDir:            getDownloadDir(),
+		CleanupDownloads:       getCleanupDownloads(),
+		Incremental:            getIncremental(),
+		SortAccounts:           getSortAccounts(),
+		RotateAccounts:         getRotateAccounts(),
+		MaxRecordsPerAccount:   getMaxRecordsPerAccount(),
+		CSVEncoding:            getCSVEncoding(),
+		CSVColumnMap:           getCSVColumnMap(),
+		ProxyPool:              getProxyPool(),
+		InitRetryCount:         getInitRetryCount(),
+		InitRetryDelay:         getInitRetryDelay(),
+		CheckDB:                getCheckDB(),
+		CallbackClient:         &http.Client{Timeout: callbackTimeout},
+		CallbackRetryDelay:     defaultCallbackRetryDelay,
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	if getMetricsEnabled() {
+		s.metrics = NewJobMetrics()
 	}
+	if logger != nil && getLogFormat() == logFormatJSON {
+		// JSONの各行をそのまま解析できるよう、日時などのプレフィックスを付けない
+		s.jsonLogger = log.New(logger.Writer(), "", 0)
+	}
+	s.AccountDelay = s.getAccountDelay()
+	s.JobTTL = s.getJobTTL()
+	s.MaxJobs = s.getMaxJobs()
+	s.MaxJobsRunning = s.getMaxJobsRunning()
+	s.MaintenanceRetryDelay = s.getMaintenanceRetryDelay()
+	s.CleanupOnStart = s.getCleanupOnStart()
+	s.RetryBaseDelay = s.getRetryBaseDelay()
+	s.RetryPasses = s.getRetryPasses()
+	s.AllowedWindow = s.getAllowedWindow()
+	s.Location = s.getLocation()
+	s.RetryPassDelay = s.getRetryPassDelay()
+	s.Timeout = s.getTimeout()
+	s.markInterruptedJobs()
+	// 起動時に形式の誤りや復号できないアカウントを知らせる（アカウントは参照のたびに読み込むため、起動後の修正も反映される）
+	if _, err := loadEnvAccounts(); err != nil {
+		s.logMessagef(LogLevelError, "Failed to load accounts: %v", err)
+	}
+
+	return s
 }
 
 // parseAccountsString はアカウント文字列をパース（JSON配列またはカンマ区切り文字列に対応）
@@ -74,22 +448,69 @@ func parseAccountsString(accountsStr string) []string {
 			accounts = jsonAccounts
 		} else {
 			// JSONパースエラーの場合はカンマ区切りとして扱う
-			accounts = strings.Split(accountsStr, ",")
+			accounts = splitAccountList(accountsStr)
 		}
 	} else {
 		// カンマ区切り文字列形式（従来形式: "user1:pass1,user2:pass2"）
-		accounts = strings.Split(accountsStr, ",")
+		accounts = splitAccountList(accountsStr)
+	}
+
+	return accounts
+}
+
+// splitAccountList はカンマ区切りのアカウント文字列を分割する
+// CSV形式の引用符に対応し、"user1:pa,ss1",user2:pass2 のように引用符内のカンマは区切りとみなさない
+func splitAccountList(accountsStr string) []string {
+	reader := csv.NewReader(strings.NewReader(accountsStr))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		// 引用符の対応が取れない場合などは従来通り単純に分割する
+		return strings.Split(accountsStr, ",")
 	}
 
+	var accounts []string
+	for _, record := range records {
+		accounts = append(accounts, record...)
+	}
 	return accounts
 }
 
 // GetAllAccountsWithCredentials は設定されているすべてのアカウント情報（ID:パスワード形式）を取得
+// UpdateAccountCredentialで更新したアカウントは更新後のパスワードを返す
 func (s *DownloadService) GetAllAccountsWithCredentials() []string {
+	return s.credentials.apply(envAccounts())
+}
+
+// envAccounts は環境変数に設定されているアカウント情報（ID:パスワード形式）を取得
+// 読み込めない・復号できない場合は他の環境変数にフォールバックせず、アカウントなしとする
+func envAccounts() []string {
+	accounts, err := loadEnvAccounts()
+	if err != nil {
+		log.Printf("[Accounts] %v", err)
+		return nil
+	}
+	return accounts
+}
+
+// loadEnvAccounts は環境変数に設定されているアカウント情報を読み込み、enc:形式のものを復号する
+func loadEnvAccounts() ([]string, error) {
 	// ETC_CORP_ACCOUNTS (推奨) - JSON配列またはカンマ区切り文字列に対応
 	corpAccounts := os.Getenv("ETC_CORP_ACCOUNTS")
 	if corpAccounts != "" {
-		return parseAccountsString(corpAccounts)
+		return decryptAccounts(parseAccountsString(corpAccounts))
+	}
+
+	// ETC_ACCOUNTS_FILE - 多数のアカウントを環境変数（プロセス一覧から見える）に書かずにファイルで指定
+	if path := getAccountsFile(); path != "" {
+		accounts, err := loadAccountsFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return decryptAccounts(accounts)
 	}
 
 	// 後方互換性のため ETC_CORPORATE_ACCOUNTS と ETC_PERSONAL_ACCOUNTS もサポート
@@ -105,7 +526,20 @@ func (s *DownloadService) GetAllAccountsWithCredentials() []string {
 		allAccounts = append(allAccounts, parseAccountsString(personalAccounts)...)
 	}
 
-	return allAccounts
+	return decryptAccounts(allAccounts)
+}
+
+// getAccountType はアカウントの取得元の環境変数から種別を判定
+// ETC_PERSONAL_ACCOUNTS に含まれるアカウントIDは個人、それ以外は法人（デフォルト）
+func getAccountType(accountID string) models.AccountType {
+	// 復号できない場合のエラーはenvAccountsで記録される
+	personalAccounts, _ := decryptAccounts(parseAccountsString(os.Getenv("ETC_PERSONAL_ACCOUNTS")))
+	for _, account := range personalAccounts {
+		if strings.TrimSpace(accountUserID(account)) == accountID {
+			return models.AccountTypePersonal
+		}
+	}
+	return models.AccountTypeCorporate
 }
 
 // GetAllAccountIDs は設定されているすべてのアカウントIDを取得
@@ -115,7 +549,8 @@ func (s *DownloadService) GetAllAccountIDs() []string {
 	// GetAllAccountsWithCredentials を使用して完全なアカウント情報を取得
 	accounts := s.GetAllAccountsWithCredentials()
 	for _, accountStr := range accounts {
-		parts := strings.Split(strings.TrimSpace(accountStr), ":")
+		// パスワードに":"が含まれる場合があるため最初の":"でのみ分割
+		parts := strings.SplitN(strings.TrimSpace(accountStr), ":", 2)
 		if len(parts) >= 1 {
 			accountIDs = append(accountIDs, parts[0])
 		}
@@ -124,172 +559,1465 @@ func (s *DownloadService) GetAllAccountIDs() []string {
 	return accountIDs
 }
 
+// ValidateAccountFormat はアカウント文字列がaccountID:password形式かを検証
+// アカウントIDはセッションフォルダに保存するファイル名の接頭辞になるため、パスの区切りや..を含む場合も不正とする
+func ValidateAccountFormat(account string) error {
+	parts := strings.SplitN(account, ":", 2)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid account format: %s (expected accountID:password)", maskAccountString(account))
+	}
+	if !scraper.IsSafePathComponent(parts[0]) {
+		return fmt.Errorf("invalid account ID %q: must not contain path separators or be \".\" or \"..\"", parts[0])
+	}
+	return nil
+}
+
+// AccountError はアカウントリスト中の不正なエントリ
+type AccountError struct {
+	Index   int    // リクエスト内の位置
+	Account string // パスワードをマスクしたアカウント文字列
+	Err     error
+}
+
+// Error returns the error message with the entry index
+func (e AccountError) Error() string {
+	return fmt.Sprintf("accounts[%d]: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying validation error
+func (e AccountError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateAccounts は各アカウントがaccountID:password形式（どちらも空でない）かを検証し、不正なエントリを返す
+// すべて正しい場合はnilを返す
+func ValidateAccounts(accounts []string) []AccountError {
+	var errs []AccountError
+	for i, account := range accounts {
+		if err := ValidateAccountFormat(account); err != nil {
+			errs = append(errs, AccountError{Index: i, Account: maskAccountString(account), Err: err})
+		}
+	}
+	return errs
+}
+
 // ProcessAsync は非同期でダウンロードを実行
-func (s *DownloadService) ProcessAsync(jobID string, accounts []string, fromDate, toDate string) {
+// ジョブはctxから派生したコンテキストで実行され、ctxのキャンセル・CancelJob・Shutdownで
+// 未処理のアカウントを処理せずにcancelledで終了する
+func (s *DownloadService) ProcessAsync(ctx context.Context, jobID string, accounts []string, fromDate, toDate string) {
+	s.ProcessAsyncWithOptions(ctx, jobID, accounts, fromDate, toDate, JobOptions{})
+}
+
+// ProcessAsyncWithOptions はオプションを指定して非同期でダウンロードを実行
+func (s *DownloadService) ProcessAsyncWithOptions(ctx context.Context, jobID string, accounts []string, fromDate, toDate string, opts JobOptions) {
+	if opts.resumed != nil {
+		// 再開したジョブはチェックポイントに保存した順序のまま処理する
+	} else if opts.SortAccounts || s.SortAccounts {
+		accounts = sortAccountsByUserID(accounts)
+	} else {
+		accounts = s.rotateJobAccounts(accounts)
+	}
+
+	perAccount := make(map[string]string, len(accounts))
+	for _, account := range accounts {
+		perAccount[accountUserID(account)] = accountStatusPending
+	}
+
+	// ダウンロード後の保存で失敗して時間を無駄にしないよう、DBに接続できなければ開始しない
+	dbErr := s.checkDB(ctx)
+
 	s.jobMutex.Lock()
 	job := &DownloadJob{
-		ID:        jobID,
-		Status:    "processing",
-		Progress:  0,
-		StartedAt: time.Now(),
+		ID:         jobID,
+		Status:     "processing",
+		Progress:   0,
+		StartedAt:  time.Now(),
+		PerAccount: perAccount,
+		DryRun:     opts.DryRun,
+		Passes:     1,
+		checkpoint: newJobCheckpoint(accounts, fromDate, toDate, opts),
+	}
+	// resumedAccounts は再開したジョブで前回までに完了したアカウント数
+	resumedAccounts := 0
+	if opts.resumed != nil {
+		job = resumedJob(opts.resumed, accounts)
+		resumedAccounts = len(job.checkpoint.Accounts) - len(accounts)
 	}
 	s.jobs[jobID] = job
+	evicted := s.evictOldJobsLocked()
+	rejectErr := dbErr
+	if s.shuttingDown {
+		// シャットダウン中は新しいジョブを開始しない
+		rejectErr = ErrShuttingDown
+	} else if rejectErr == nil {
+		// 許可された時間帯の外では開始しない（ETCサイトが混雑する時間帯を避ける）
+		rejectErr = s.checkAllowedWindow(time.Now())
+	}
+	if rejectErr != nil {
+		now := time.Now()
+		job.Status = "failed"
+		job.ErrorMessage = rejectErr.Error()
+		job.CompletedAt = &now
+		s.jobMutex.Unlock()
+		s.saveJob(jobID)
+		s.logEvictedJobs(jobID, evicted)
+		s.logJobf(LogLevelWarn, jobID, "", "Rejected download job %s: %v", jobID, rejectErr)
+		return
+	}
+	// 同時実行数の上限に達している場合は空くまでpendingで待機する
+	slotReady := s.jobSlots.reserve(s.MaxJobsRunning)
+	if slotReady != nil {
+		job.Status = jobStatusPending
+	}
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	// シャットダウン時は実行中のジョブもキャンセルする
+	stopShutdownCancel := context.AfterFunc(s.ctx, func() { cancel(ErrShuttingDown) })
+	s.pauses[jobID] = &jobPause{cancel: cancel}
+	// ShutdownのjobsWG.Waitと競合しないよう、jobMutexを保持したままAddする
+	s.jobsWG.Add(1)
 	s.jobMutex.Unlock()
+	s.saveJob(jobID)
+	s.logEvictedJobs(jobID, evicted)
+	s.metrics.JobStarted()
+	if slotReady != nil {
+		s.logJobf(LogLevelInfo, jobID, "", "Queued download job %s (%d jobs running at most)", jobID, s.MaxJobsRunning)
+	}
 
 	// ダウンロード処理をシミュレート
 	go func() {
+		defer s.jobsWG.Done()
+		defer cancel(nil)
+		defer stopShutdownCancel()
+		if opts.CallbackURL != "" {
+			// パニックで失敗した場合も含め、ジョブの状態が確定した後に通知する
+			defer s.notifyJobCallback(jobID, opts.CallbackURL)
+		}
 		defer func() {
 			if r := recover(); r != nil {
 				if s.logger != nil {
 					s.logger.Printf("Panic in download job %s: %v", jobID, r)
 				}
 				s.updateJobStatus(jobID, "failed", 0, fmt.Sprintf("Internal error: %v", r))
+				s.jobMutex.Lock()
+				delete(s.pauses, jobID)
+				s.jobMutex.Unlock()
 			}
 		}()
 
-		s.logMessage("Starting download job %s for %d accounts from %s to %s",
-			jobID, len(accounts), fromDate, toDate)
+		if slotReady != nil {
+			// 開始待ちの間にキャンセル・シャットダウンされた場合はどのアカウントも処理しない
+			if err := s.jobSlots.wait(jobCtx, slotReady); err != nil {
+				s.updateJobStatus(jobID, "cancelled", 0, err.Error())
+				s.jobMutex.Lock()
+				delete(s.pauses, jobID)
+				s.jobMutex.Unlock()
+				s.logJobf(LogLevelWarn, jobID, "", "Cancelled download job %s before it started: %v", jobID, err)
+				return
+			}
+			s.updateJobStatus(jobID, "processing", 0, "")
+		}
+		defer s.jobSlots.release()
+
+		if opts.DryRun {
+			s.logJobf(LogLevelInfo, jobID, "", "Starting dry run job %s for %d accounts (login only)", jobID, len(accounts))
+		} else {
+			s.logJobf(LogLevelInfo, jobID, "", "Starting download job %s for %d accounts from %s to %s",
+				jobID, len(accounts), fromDate, toDate)
+		}
 
 		// Create a shared session folder for all accounts in this job
-		sessionFolder := fmt.Sprintf("./downloads/%s", time.Now().Format("20060102_150405"))
+		// 保存先フォルダが作成できなければどのアカウントも処理できないためジョブを失敗させる
+		var (
+			sessionFolder string
+			err           error
+		)
+		if opts.resumed != nil && opts.resumed.checkpoint.SessionFolder != "" {
+			sessionFolder, err = s.reuseSessionFolder(opts.resumed.checkpoint.SessionFolder)
+		} else {
+			sessionFolder, err = s.createSessionFolder(jobID)
+		}
+		if err != nil {
+			errMsg := err.Error()
+			s.logJobf(LogLevelError, jobID, "", "Download job %s failed: %s", jobID, errMsg)
+			s.updateJobStatus(jobID, "failed", 0, errMsg)
+			s.jobMutex.Lock()
+			delete(s.pauses, jobID)
+			s.jobMutex.Unlock()
+			return
+		}
+		s.setSessionFolder(jobID, sessionFolder)
 
-		// 各アカウントを処理
+		// ワーカープールで各アカウントを処理
 		totalAccounts := len(accounts)
-		for i, account := range accounts {
-			// 進捗更新
-			progress := int(float64(i+1) / float64(totalAccounts) * 100)
-			s.updateJobProgress(jobID, progress)
-
-			// 実際のダウンロード処理（セッションフォルダを渡す）
-			if err := s.downloadAccountData(account, fromDate, toDate, sessionFolder); err != nil {
-				s.logMessage("Error downloading data for account %s: %v", account, err)
-				// エラーがあってもほかのアカウントの処理は続ける
+		workers := s.MaxConcurrency
+		if workers < 1 {
+			workers = 1
+		}
+		if workers > totalAccounts {
+			workers = totalAccounts
+		}
+
+		s.adjustRuntime(func(c *runtimeCounters) { c.workers += workers })
+		defer s.adjustRuntime(func(c *runtimeCounters) { c.workers -= workers })
+
+		progress := newJobProgress(totalAccounts + resumedAccounts)
+		// 再開したジョブは前回までに完了したアカウントの分から進める
+		progress.done = resumedAccounts
+		var summaries accountSummaries
+		// summarize はアカウントの試行回数・結果・所要時間をログに出力し、ジョブ終了時のログ用に保持する
+		summarize := func(accountID, outcome string, attempts *accountAttempts, startedAt time.Time) {
+			summary := accountSummary{AccountID: accountID, Outcome: outcome, Attempts: *attempts, Duration: time.Since(startedAt).Round(time.Millisecond)}
+			s.logJobf(LogLevelInfo, jobID, accountID, "Account %s finished as %s: %d attempts (initialize=%d login=%d download=%d), %d/%d retries used, took %v",
+				accountID, outcome, attempts.total(), attempts.Initialize, attempts.Login, attempts.Download, attempts.retries(), attempts.Budget, summary.Duration)
+			summaries.add(summary)
+		}
+		// runPass はpassAccountsをワーカープールで処理し、キャンセル・シャットダウンされずにすべて処理したかを返す
+		// pass は1から数えたパスの番号（2以降は前のパスで失敗したアカウントの再実行）
+		runPass := func(pass int, passAccounts []string) bool {
+			s.adjustRuntime(func(c *runtimeCounters) { c.queuedAccounts += len(passAccounts) })
+			var processed int32
+			accountCh := make(chan int)
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range accountCh {
+						// 一時停止中なら再開されるまで次のアカウントに進まない
+						s.waitIfPaused(jobCtx, jobID)
+						if jobCtx.Err() != nil {
+							// キャンセル・シャットダウン後は残りのアカウントを処理しない
+							s.adjustRuntime(func(c *runtimeCounters) { c.queuedAccounts-- })
+							continue
+						}
+
+						account := passAccounts[i]
+						// 同じアカウントを別のジョブが処理中なら終わるまで待つ
+						release, err := s.lockAccount(jobCtx, jobID, accountUserID(account))
+						if err != nil {
+							s.adjustRuntime(func(c *runtimeCounters) { c.queuedAccounts-- })
+							continue
+						}
+						s.adjustRuntime(func(c *runtimeCounters) {
+							c.queuedAccounts--
+							c.busyWorkers++
+						})
+						s.updateAccountStatus(jobID, account, accountStatusProcessing)
+						// 実際のダウンロード処理（アカウントごとにスクレイパーを作成し、セッションフォルダは共有）
+						accountStartedAt := time.Now()
+						accountFrom := s.accountFromDate(jobID, accountUserID(account), fromDate, toDate, opts)
+						onProgress := func(p scraper.DownloadProgress) {
+							s.logJobf(LogLevelInfo, jobID, accountUserID(account), "Account %s: %d rows so far (page %d/%d)",
+								accountUserID(account), p.Rows, p.Page, p.Pages)
+							if p.Pages > 0 {
+								s.updateJobProgress(jobID, progress.setAccount(accountUserID(account), accountDownloadShare*float64(p.Page)/float64(p.Pages)))
+							}
+						}
+						attempts := &accountAttempts{}
+						result, err := s.downloadAccountDataSafe(jobCtx, jobID, account, accountFrom, toDate, sessionFolder, opts, onProgress, attempts)
+						s.metrics.ObserveAccountDuration(time.Since(accountStartedAt))
+						release()
+						s.adjustRuntime(func(c *runtimeCounters) { c.busyWorkers-- })
+						if err != nil && opts.AbortOnCancel && jobCtx.Err() != nil {
+							// 中止したアカウントは失敗として記録せず、ジョブはキャンセル扱いになる
+							s.logJobf(LogLevelWarn, jobID, accountUserID(account), "Aborted download for account %s: %v", accountUserID(account), context.Cause(jobCtx))
+							s.updateAccountStatus(jobID, account, accountStatusCancelled)
+							summarize(accountUserID(account), accountStatusCancelled, attempts, accountStartedAt)
+							continue
+						}
+						if pass > 1 {
+							// 前のパスの失敗はこのパスの結果で置き換える
+							s.clearAccountFailure(jobID, accountUserID(account))
+						}
+						outcome := accountStatusCompleted
+						if err != nil {
+							s.logJobf(LogLevelError, jobID, accountUserID(account), "Error downloading data for account %s: %v", accountUserID(account), err)
+							s.recordAccountFailure(jobID, account, err)
+							outcome = accountStatusFailed
+							if scraper.IsMaintenanceError(err) {
+								outcome = accountStatusMaintenance
+							}
+							// エラーがあってもほかのアカウントの処理は続ける
+						} else if opts.DryRun {
+							s.updateAccountStatus(jobID, account, accountStatusAuthenticated)
+							outcome = accountStatusAuthenticated
+						} else {
+							s.recordAccountResult(jobID, result)
+							s.updateAccountStatus(jobID, account, accountStatusCompleted)
+							// 明細のDB保存は未実装のため、増分モードを許可した場合のみ、ダウンロードと解析の成功時に更新する
+							// （解析に失敗した明細を次回の増分ダウンロードで取得し直せるよう、失敗時は進めない）
+							if s.Incremental && !result.ParseFailed {
+								s.updateWatermark(jobID, accountUserID(account), toDate)
+							}
+						}
+						summarize(accountUserID(account), outcome, attempts, accountStartedAt)
+						records := 0
+						if result != nil {
+							records = result.ActualRecords
+						}
+						s.recordAccountRun(jobID, account, outcome, records, err)
+
+						// 進捗更新（並行実行でも正しくなるよう完了数をアトミックにカウント）
+						atomic.AddInt32(&processed, 1)
+						s.updateJobProgress(jobID, progress.finishAccount(accountUserID(account)))
+
+						// レート制限のため少し待機（最後のアカウントの後は待機しない）
+						if i < len(passAccounts)-1 {
+							select {
+							case <-time.After(s.AccountDelay):
+							case <-jobCtx.Done():
+							}
+						}
+					}
+				}()
+			}
+
+			sent := 0
+		feed:
+			for i := range passAccounts {
+				select {
+				case accountCh <- i:
+					sent++
+				case <-jobCtx.Done():
+					break feed
+				}
+			}
+			close(accountCh)
+			wg.Wait()
+			s.adjustRuntime(func(c *runtimeCounters) { c.queuedAccounts -= len(passAccounts) - sent })
+			return int(atomic.LoadInt32(&processed)) == len(passAccounts)
+		}
+
+		completed := runPass(1, accounts)
+		// 一時的な理由で失敗したアカウントのみ、間隔を空けてRetryPasses回まで再実行する
+		for pass := 2; completed && pass <= s.RetryPasses+1; pass++ {
+			retry := s.retryableAccounts(jobID, accounts)
+			if len(retry) == 0 {
+				break
 			}
+			delay := s.RetryPassDelay << (pass - 2)
+			s.logJobf(LogLevelInfo, jobID, "", "Retrying %d failed accounts of job %s in %v (pass %d of %d)",
+				len(retry), jobID, delay, pass, s.RetryPasses+1)
+			select {
+			case <-time.After(delay):
+			case <-jobCtx.Done():
+				completed = false
+				continue
+			}
+			s.startRetryPass(jobID, pass)
+			s.updateJobProgress(jobID, progress.retryAccounts(len(retry)))
+			completed = runPass(pass, retry)
+		}
 
-			// レート制限のため少し待機
-			time.Sleep(time.Second)
+		// キャンセル・シャットダウンで処理しきれなかったアカウントがあればキャンセル扱いにする
+		if !completed {
+			cause := context.Cause(jobCtx)
+			s.updateJobStatus(jobID, "cancelled", s.jobProgress(jobID), cause.Error())
+			s.jobMutex.Lock()
+			delete(s.pauses, jobID)
+			s.jobMutex.Unlock()
+			s.logJobf(LogLevelWarn, jobID, "", "Cancelled download job %s: %v%s", jobID, cause, summaries.logSuffix())
+			return
 		}
 
-		// 完了
+		// 完了（失敗したアカウント数に応じてcompleted/partial/failed）
 		now := time.Now()
+		failedAccounts := 0
+		finalStatus := "completed"
 		s.jobMutex.Lock()
 		if job, exists := s.jobs[jobID]; exists {
-			job.Status = "completed"
+			failedAccounts = len(job.FailedAccounts)
+			finalStatus = finalJobStatus(totalAccounts+resumedAccounts, failedAccounts)
+			if !isTerminalStatus(job.Status) {
+				s.metrics.JobFinished(finalStatus)
+			}
+			s.notifyJobChangedLocked(jobID)
+			job.Status = finalStatus
+			if failedAccounts > 0 {
+				job.ErrorMessage = fmt.Sprintf("%d of %d accounts failed", failedAccounts, totalAccounts+resumedAccounts)
+			}
 			job.Progress = 100
 			job.CompletedAt = &now
 		}
+		delete(s.pauses, jobID)
 		s.jobMutex.Unlock()
+		s.saveJob(jobID)
+
+		if failedAccounts > 0 {
+			s.logJobf(LogLevelWarn, jobID, "", "Finished download job %s as %s: %d of %d accounts failed%s",
+				jobID, finalStatus, failedAccounts, totalAccounts+resumedAccounts, summaries.logSuffix())
+		} else {
+			s.logJobf(LogLevelInfo, jobID, "", "Completed download job %s%s", jobID, summaries.logSuffix())
+		}
+
+		// 失敗したアカウントがある場合は調査用にセッションフォルダを残す
+		if s.CleanupDownloads {
+			if failedAccounts > 0 {
+				s.logJobf(LogLevelInfo, jobID, "", "Keeping session folder %s for job %s (%d failed accounts)", sessionFolder, jobID, failedAccounts)
+			} else if opts.OutputFormat == OutputFormatCSVPath {
+				// 呼び出し元はCSVのパスのみ受け取るため、CSVを削除しない
+				s.logJobf(LogLevelInfo, jobID, "", "Keeping session folder %s for job %s (output format CSV_PATH)", sessionFolder, jobID)
+			} else if err := s.CleanupSession(sessionFolder); err != nil {
+				s.logJobf(LogLevelWarn, jobID, "", "Failed to clean up session folder %s: %v", sessionFolder, err)
+			}
+		} else {
+			// ドライランなどでファイルが保存されなかった場合は空のフォルダを残さない（空でなければ削除されない）
+			_ = os.Remove(sessionFolder)
+		}
+
+		s.scheduleMaintenanceRetry(ctx, jobID, accounts, fromDate, toDate, opts)
+	}()
+}
+
+// checkDB はDBにPingし、接続できなければErrDatabaseUnavailableを返す
+// DBが設定されていない場合やCheckDBが無効な場合はnil
+func (s *DownloadService) checkDB(ctx context.Context) error {
+	if s.db == nil || !s.CheckDB {
+		return nil
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, dbCheckTimeout)
+	defer cancel()
+	if err := s.db.PingContext(pingCtx); err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseUnavailable, err)
+	}
+	return nil
+}
+
+// createSessionFolder はジョブで共有するセッションフォルダを作成する
+// 同じ秒に開始したジョブが衝突しないようフォルダ名にジョブIDと短いランダム文字列を含め、
+// それでも既に存在する場合は上書きを避けるためエラーを返す
+func (s *DownloadService) createSessionFolder(jobID string) (string, error) {
+	if err := os.MkdirAll(s.DownloadDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create download directory %s: %v", s.DownloadDir, err)
+	}
+
+	name := fmt.Sprintf("%s_%s_%s", time.Now().Format("20060102_150405"), sessionFolderJobID(jobID), uuid.NewString()[:8])
+	folder := filepath.Join(s.DownloadDir, name)
+	if err := os.Mkdir(folder, 0755); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("session folder %s already exists", folder)
+		}
+		return "", fmt.Errorf("failed to create session folder %s: %v", folder, err)
+	}
+	return folder, nil
+}
+
+// sessionFolderJobID はジョブIDをフォルダ名に使える文字（英数字・-・_）に置き換える
+func sessionFolderJobID(jobID string) string {
+	const maxLen = 64
+	name := strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, jobID)
+	if len(name) > maxLen {
+		name = name[:maxLen]
+	}
+	if name == "" {
+		name = "job"
+	}
+	return name
+}
+
+// CleanupSession はセッションフォルダを削除し、解放したバイト数をログに出力する
+// 誤削除を防ぐため、DownloadDir配下のフォルダ以外は削除しない
+func (s *DownloadService) CleanupSession(folder string) error {
+	base, err := filepath.Abs(s.DownloadDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve download directory: %w", err)
+	}
+	target, err := filepath.Abs(folder)
+	if err != nil {
+		return fmt.Errorf("failed to resolve session folder: %w", err)
+	}
+	if rel, err := filepath.Rel(base, target); err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("refusing to remove %s: not a session folder under %s", folder, s.DownloadDir)
+	}
+
+	var freed int64
+	err = filepath.WalkDir(target, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				freed += info.Size()
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		// ダウンロードしなかったジョブ（DryRunなど）はフォルダが作成されない
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to scan session folder %s: %w", folder, err)
+	}
+
+	if err := os.RemoveAll(target); err != nil {
+		return fmt.Errorf("failed to remove session folder %s: %w", folder, err)
+	}
+
+	s.logMessage("Removed session folder %s (freed %d bytes)", folder, freed)
+	return nil
+}
 
-		s.logMessage("Completed download job %s", jobID)
+// downloadAccountDataSafe はワーカー内のpanicをエラーに変換してdownloadAccountDataを実行
+func (s *DownloadService) downloadAccountDataSafe(ctx context.Context, jobID, accountID, fromDate, toDate, sessionFolder string, opts JobOptions, onProgress func(scraper.DownloadProgress), attempts *accountAttempts) (result *AccountResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("internal error: %v", r)
+		}
 	}()
+	return s.downloadAccountData(ctx, jobID, accountID, fromDate, toDate, sessionFolder, opts, onProgress, attempts)
 }
 
-// downloadAccountData は単一アカウントのデータをダウンロード
-func (s *DownloadService) downloadAccountData(accountID, fromDate, toDate, sessionFolder string) error {
+// downloadAccountData は単一アカウントのデータをダウンロード（DryRunの場合はログインのみ）
+// onProgressがnilでなければ検索したページごとに呼ばれる
+// opts.AbortOnCancelがtrueの場合、ctxがキャンセルされるとスクレイパーを閉じて処理を中止する
+// attemptsがnilでなければ各操作の実行回数とリトライできる回数を記録する
+func (s *DownloadService) downloadAccountData(ctx context.Context, jobID, accountID, fromDate, toDate, sessionFolder string, opts JobOptions, onProgress func(scraper.DownloadProgress), attempts *accountAttempts) (_ *AccountResult, err error) {
+	if attempts == nil {
+		attempts = &accountAttempts{}
+	}
+
 	// アカウント情報の解析（accountID:password形式）
-	parts := strings.Split(accountID, ":")
-	if len(parts) < 2 {
-		return fmt.Errorf("invalid account format: %s (expected accountID:password)", accountID)
+	if err := ValidateAccountFormat(accountID); err != nil {
+		return nil, err
 	}
 
+	// パスワードに":"が含まれる場合（user:pa:ss）も最初の":"以降をすべてパスワードとする
+	parts := strings.SplitN(accountID, ":", 2)
 	userID := parts[0]
 	password := parts[1]
 
+	timeout := s.Timeout
+	if opts.Timeout > 0 {
+		timeout = opts.Timeout
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	if !opts.Deadline.IsZero() {
+		if remaining := time.Until(opts.Deadline); remaining < timeout {
+			// 0以下はPlaywrightでタイムアウトなしになるため最小値を設ける
+			timeout = max(remaining, minDeadlineTimeout)
+		}
+	}
+	s.logJobf(LogLevelInfo, jobID, userID, "Using Playwright timeout %v for account %s", timeout, userID)
+
+	// Headlessモードはリクエストの指定を環境変数より優先する
+	headless := getHeadlessMode()
+	if opts.Headless != nil {
+		headless = *opts.Headless
+		s.logJobf(LogLevelInfo, jobID, userID, "Using headless=%v for account %s (request override)", headless, userID)
+	}
+
 	// スクレイパーの設定
 	config := &scraper.ScraperConfig{
 		UserID:        userID,
 		Password:      password,
-		DownloadPath:  "./downloads",
+		DownloadPath:  s.DownloadDir,
 		SessionFolder: sessionFolder, // Use shared session folder
-		Headless:      getHeadlessMode(),
-		Timeout:       30000,
+		Headless:      headless,
+		Timeout:       float64(timeout.Milliseconds()),
 		RetryCount:    3,
 	}
+	var accountProxy bool
+	config.ProxyURL, config.ProxyUsername, config.ProxyPassword, accountProxy = getAccountProxyConfig(userID)
+	if accountProxy {
+		s.logJobf(LogLevelInfo, jobID, userID, "Using account proxy %s for account %s", scraper.MaskProxyURL(config.ProxyURL), userID)
+	} else if len(s.ProxyPool) > 0 {
+		// 認証情報はURLに含める（ETC_PROXY_USERNAME/PASSWORDはETC_PROXY_URL用）
+		config.ProxyURL, config.ProxyUsername, config.ProxyPassword = s.pickPoolProxy(jobID, userID), "", ""
+		defer func() { s.recordPoolProxy(jobID, userID, config.ProxyURL, err) }()
+	}
+	config.CaptureOnError = getCaptureOnError()
+	config.AccountType = getAccountType(userID)
+	if config.AccountType == models.AccountTypePersonal {
+		s.logJobf(LogLevelInfo, jobID, userID, "Using personal login flow for account %s", userID)
+	}
+	config.UserAgent = getUserAgent()
+	config.Viewport = getViewport()
+	if selectors, err := getSelectors(); err != nil {
+		// 壊れたファイルでアカウントの処理を止めないよう、既定のセレクタで続ける
+		s.logJobf(LogLevelWarn, jobID, userID, "Using default selectors for account %s: %v", userID, err)
+	} else {
+		config.Selectors = selectors
+	}
+	config.OnProgress = onProgress
+	attempts.Budget = max(s.InitRetryCount, 0) + max(config.RetryCount, 0)
+	if !opts.DryRun {
+		attempts.Budget += max(config.RetryCount, 0)
+	}
 
 	// スクレイパー作成
 	etcScraper, err := s.scraperFactory.CreateScraper(config, s.logger)
 	if err != nil {
-		return fmt.Errorf("failed to create scraper: %w", err)
+		return nil, fmt.Errorf("failed to create scraper: %w", err)
 	}
 	defer etcScraper.Close()
 
-	// Playwright初期化
-	if err := etcScraper.Initialize(); err != nil {
-		return fmt.Errorf("failed to initialize scraper: %w", err)
+	// キャンセル時はブラウザを閉じ、実行中のPlaywright操作をエラーで終わらせる
+	// （Closeは2回呼ばれても問題ない）
+	abortCtx := context.Background()
+	if opts.AbortOnCancel {
+		abortCtx = ctx
+		stop := context.AfterFunc(ctx, func() {
+			s.logJobf(LogLevelWarn, jobID, userID, "Closing scraper for account %s: %v", userID, context.Cause(ctx))
+			etcScraper.Close()
+		})
+		defer stop()
 	}
 
-	// ログイン
-	if err := etcScraper.Login(); err != nil {
-		return fmt.Errorf("login failed for account %s: %w", userID, err)
+	// Playwright初期化（ドライバの起動待ちのみリトライ、未インストールは即失敗）
+	if err := s.initializeWithRetry(abortCtx, jobID, userID, etcScraper, &attempts.Initialize); err != nil {
+		return nil, fmt.Errorf("failed to initialize scraper: %w", err)
+	}
+	if abortCtx.Err() != nil {
+		// 初期化中にキャンセルされた場合、閉じた後に起動したブラウザが残っていることがある
+		etcScraper.Close()
+		return nil, fmt.Errorf("aborted: %w", context.Cause(abortCtx))
 	}
+	s.adjustRuntime(func(c *runtimeCounters) { c.activeBrowsers++ })
+	defer s.adjustRuntime(func(c *runtimeCounters) { c.activeBrowsers-- })
 
-	// データダウンロード
-	csvPath, err := etcScraper.DownloadMeisai(fromDate, toDate)
-	if err != nil {
-		return fmt.Errorf("download failed for account %s: %w", userID, err)
+	// ログイン（一時的なエラーのみリトライ、認証エラーは即失敗）
+	// ブラウザのコンテキストを再利用していてセッションが有効ならログインを省略する
+	// （確認できない場合や、認証情報を確認するドライランではログインする）
+	if s.sessionValid(jobID, userID, etcScraper, opts) {
+		s.logJobf(LogLevelInfo, jobID, userID, "Reusing logged-in session for account %s", userID)
+	} else {
+		err = s.withRetry(abortCtx, jobID, "Login", userID, config.RetryCount, &attempts.Login, etcScraper.Login)
+		if err != nil {
+			s.logCapture(jobID, userID, err)
+			return nil, fmt.Errorf("login failed for account %s: %w", userID, err)
+		}
 	}
 
-	s.logMessage("Successfully downloaded data for account %s: %s", userID, csvPath)
+	if opts.DryRun {
+		s.logJobf(LogLevelInfo, jobID, userID, "Dry run: login succeeded for account %s", userID)
+		return nil, nil
+	}
 
-	// TODO: CSVファイルをパースしてDBに保存
+	// データダウンロード
+	downloadStartedAt := time.Now()
+	var download scraper.DownloadResult
+	err = s.withRetry(abortCtx, jobID, "Download", userID, config.RetryCount, &attempts.Download, func() error {
+		var downloadErr error
+		download, downloadErr = etcScraper.DownloadMeisai(fromDate, toDate)
+		return downloadErr
+	})
+	if err != nil {
+		s.logCapture(jobID, userID, err)
+		return nil, fmt.Errorf("download failed for account %s: %w", userID, err)
+	}
+	csvPath := download.Path
+	// サイトが指定した期間を検索しなかった場合（保存期間外の日付を丸めた等）は明細が欠けている可能性がある
+	if !download.CoversRange(fromDate, toDate) {
+		s.logJobf(LogLevelWarn, jobID, userID, "Site searched %s to %s for account %s instead of the requested %s to %s",
+			download.ActualFrom.Format("2006-01-02"), download.ActualTo.Format("2006-01-02"), userID, fromDate, toDate)
+	}
 
-	return nil
-}
+	// ダウンロード成功が報告されてもファイルが存在しない場合がある（ブラウザが別の場所に保存した等）
+	csvInfo, err := os.Stat(csvPath)
+	if err != nil {
+		if !s.RecoverMissingDownload {
+			return nil, fmt.Errorf("download reported success but file not found at %s", csvPath)
+		}
+		recoveredPath, found := findRecentCSV(sessionFolder, userID, downloadStartedAt)
+		if !found {
+			return nil, fmt.Errorf("download reported success but file not found at %s", csvPath)
+		}
+		s.logJobf(LogLevelWarn, jobID, userID, "Download file not found at %s, recovered %s from session folder", csvPath, recoveredPath)
+		csvPath = recoveredPath
+		if csvInfo, err = os.Stat(csvPath); err != nil {
+			return nil, fmt.Errorf("failed to read downloaded file %s: %w", csvPath, err)
+		}
+	}
 
-// updateJobProgress はジョブの進捗を更新
-func (s *DownloadService) updateJobProgress(jobID string, progress int) {
-	s.jobMutex.Lock()
-	defer s.jobMutex.Unlock()
+	s.logJobf(LogLevelInfo, jobID, userID, "Successfully downloaded data for account %s: %s (%d bytes)", userID, csvPath, download.Bytes)
 
-	if job, exists := s.jobs[jobID]; exists {
-		job.Progress = progress
+	// サイト上の件数とCSVの件数を突き合わせる
+	actual, err := countCSVRecords(csvPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read downloaded file %s: %w", csvPath, err)
+	}
+	result := &AccountResult{
+		AccountID:     userID,
+		ActualRecords: actual,
+		bytes:         csvInfo.Size(),
+		files:         1,
+	}
+	result.ExpectedRecords, result.ExpectedKnown = etcScraper.ExpectedRecordCount()
+	if result.ExpectedKnown && result.ExpectedRecords != result.ActualRecords {
+		result.CountMismatch = true
+		s.logJobf(LogLevelWarn, jobID, userID, "Record count mismatch for account %s: site showed %d, CSV has %d",
+			userID, result.ExpectedRecords, result.ActualRecords)
 	}
-}
 
-// updateJobStatus はジョブのステータスを更新
-func (s *DownloadService) updateJobStatus(jobID string, status string, progress int, errorMsg string) {
-	s.jobMutex.Lock()
-	defer s.jobMutex.Unlock()
+	// 明細を解析してジョブの結果として保持（GetJobResultで取得できる）
+	if opts.OutputFormat == OutputFormatCSVPath {
+		s.logJobf(LogLevelInfo, jobID, userID, "Not parsing %s for account %s (output format CSV_PATH)", filepath.Base(csvPath), userID)
+	} else {
+		s.parseAccountRecords(jobID, userID, csvPath, opts, result)
+	}
+	result.CSVPath = csvPath
+	// 明細のみ返す場合はCSVを残さない（解析に失敗した場合は調査用に残す）
+	if opts.OutputFormat == OutputFormatRecords && !result.ParseFailed {
+		if err := os.Remove(csvPath); err != nil {
+			s.logJobf(LogLevelWarn, jobID, userID, "Failed to remove %s for account %s: %v", csvPath, userID, err)
+		} else {
+			result.CSVPath = ""
+			result.bytes -= csvInfo.Size()
+			result.files--
+		}
+	}
 
-	if job, exists := s.jobs[jobID]; exists {
-		job.Status = status
-		job.Progress = progress
-		if errorMsg != "" {
-			job.ErrorMessage = errorMsg
+	// 利用証明書（PDF）のダウンロード。失敗しても明細はそのまま結果として返す
+	if opts.DownloadCertificates {
+		result.CertificatePaths, err = etcScraper.DownloadCertificates(fromDate, toDate)
+		if err != nil {
+			result.CertificatesFailed = true
+			s.logCapture(jobID, userID, err)
+			s.logJobf(LogLevelWarn, jobID, userID, "Failed to download usage certificates for account %s: %v", userID, err)
+		} else {
+			s.logJobf(LogLevelInfo, jobID, userID, "Downloaded %d usage certificates for account %s", len(result.CertificatePaths), userID)
 		}
-		if status == "completed" || status == "failed" {
-			now := time.Now()
-			job.CompletedAt = &now
+		for _, path := range result.CertificatePaths {
+			if info, err := os.Stat(path); err == nil {
+				result.bytes += info.Size()
+				result.files++
+			}
 		}
 	}
+
+	// TODO: 明細をDBに保存
+
+	return result, nil
 }
 
-// GetJobStatus はジョブのステータスを取得
-func (s *DownloadService) GetJobStatus(jobID string) (*DownloadJob, bool) {
-	s.jobMutex.RLock()
-	defer s.jobMutex.RUnlock()
+// parseAccountRecords はダウンロードしたCSVの明細を解析してresultに保持する
+// 解析に失敗した場合はresult.ParseFailedをtrueにする（アカウントは失敗にしない）
+func (s *DownloadService) parseAccountRecords(jobID, userID, csvPath string, opts JobOptions, result *AccountResult) {
+	var (
+		encoding CSVEncoding
+		filtered int
+		err      error
+	)
+	result.records, result.Truncated, filtered, encoding, err = parseMeisaiFile(csvPath, s.CSVEncoding, s.MaxRecordsPerAccount, newCardNumberFilter(opts.CardNumbers), s.CSVColumnMap)
+	if s.CSVEncoding == CSVEncodingAuto {
+		s.logJobf(LogLevelInfo, jobID, userID, "Reading %s as %s (detected)", filepath.Base(csvPath), encoding)
+	} else {
+		s.logJobf(LogLevelInfo, jobID, userID, "Reading %s as %s (ETC_CSV_ENCODING)", filepath.Base(csvPath), encoding)
+	}
+	if err != nil {
+		result.ParseFailed = true
+		s.metrics.CSVParseFailed(csvParseErrorFields(err))
+		s.logJobf(LogLevelWarn, jobID, userID, "Failed to parse records for account %s: %v", userID, err)
+	} else {
+		s.metrics.CSVParsed(time.Now())
+	}
+	if filtered > 0 {
+		s.logJobf(LogLevelInfo, jobID, userID, "Filtered out %d records for account %s not matching card numbers (kept %d)",
+			filtered, userID, len(result.records))
+	}
+	if result.Truncated {
+		s.logJobf(LogLevelWarn, jobID, userID, "Truncated records for account %s: CSV has %d records, kept the first %d (ETC_MAX_RECORDS)",
+			userID, result.ActualRecords, len(result.records))
+		result.ActualRecords = len(result.records)
+	}
+	downloadedAt := timestamppb.Now()
+	for _, record := range result.records {
+		record.AccountId = userID
+		record.CsvFileName = filepath.Base(csvPath)
+		record.DownloadedAt = downloadedAt
+	}
+}
 
-	job, exists := s.jobs[jobID]
-	if !exists {
-		return nil, false
+// sessionValid はスクレイパーのセッションがログイン済みのまま有効かを確認する
+// ドライランの場合と、確認できなかった場合はfalse（ログインする）
+func (s *DownloadService) sessionValid(jobID, userID string, etcScraper scraper.ScraperInterface, opts JobOptions) bool {
+	if opts.DryRun {
+		return false
+	}
+	loggedIn, err := etcScraper.IsLoggedIn()
+	if err != nil {
+		s.logJobf(LogLevelWarn, jobID, userID, "Could not check session for account %s, logging in: %v", userID, err)
+		return false
 	}
+	return loggedIn
+}
 
-	// コピーを返す
-	jobCopy := *job
-	return &jobCopy, true
+// logCapture はエラー時にスクレイパーが保存したスクリーンショットとHTMLのパスをログに出力
+func (s *DownloadService) logCapture(jobID, userID string, err error) {
+	var captureErr *scraper.CaptureError
+	if errors.As(err, &captureErr) {
+		s.logJobf(LogLevelWarn, jobID, userID, "Saved page capture for account %s: screenshot=%s html=%s",
+			userID, captureErr.ScreenshotPath, captureErr.HTMLPath)
+	}
 }
 
-// GetHeadlessMode は環境変数からHeadlessモードの設定を取得
-// ETC_HEADLESS=false でブラウザを表示、未設定またはtrueでHeadlessモード（デフォルト）
-func GetHeadlessMode() bool {
-	headlessEnv := os.Getenv("ETC_HEADLESS")
-	if headlessEnv == "" {
-		log.Println("[Headless] ETC_HEADLESS not set, using default: true (Headless mode)")
-		return true // デフォルトはHeadlessモード
+// withRetry は一時的なエラーの場合に指数バックオフでリトライしながらfnを実行する
+// retryCountは初回実行後のリトライ回数、ctxがキャンセルされた場合はリトライを待たずに中止する
+// callsにはfnを実行した回数を加算する
+func (s *DownloadService) withRetry(ctx context.Context, jobID, operation, userID string, retryCount int, calls *int, fn func() error) error {
+	attempts := retryCount + 1
+	if attempts < 1 {
+		attempts = 1
 	}
 
-	// "false", "0", "no" の場合は非Headlessモード（ブラウザ表示）
-	headless, err := strconv.ParseBool(headlessEnv)
+	delay := s.RetryBaseDelay
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		*calls++
+		if err = fn(); err == nil {
+			return nil
+		}
+		if !scraper.IsTransientError(err) {
+			return err
+		}
+		if attempt == attempts {
+			break
+		}
+
+		s.logJobf(LogLevelWarn, jobID, userID, "%s attempt %d/%d failed for account %s: %v (retrying in %v)",
+			operation, attempt, attempts, userID, err, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return fmt.Errorf("%s aborted after %d attempts: %w (last error: %v)", operation, attempt, context.Cause(ctx), err)
+		}
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+
+	return fmt.Errorf("%s failed after %d attempts: %w", operation, attempts, err)
+}
+
+// initializeWithRetry はスクレイパーを初期化する
+// Playwrightドライバがまだ起動できない場合（scraper.ErrDriverNotReady）のみ、InitRetryDelayの間隔でInitRetryCount回までリトライする
+// ctxがキャンセルされた場合はリトライしない（callsにはInitializeを実行した回数を加算する）
+func (s *DownloadService) initializeWithRetry(ctx context.Context, jobID, userID string, etcScraper scraper.ScraperInterface, calls *int) error {
+	attempts := s.InitRetryCount + 1
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		*calls++
+		if err = etcScraper.Initialize(); err == nil {
+			return nil
+		}
+		if !errors.Is(err, scraper.ErrDriverNotReady) {
+			return err
+		}
+		if attempt == attempts {
+			break
+		}
+
+		s.logJobf(LogLevelWarn, jobID, userID, "Initialize attempt %d/%d failed for account %s: %v (retrying in %v)",
+			attempt, attempts, userID, err, s.InitRetryDelay)
+		// 途中まで起動したPlaywrightやブラウザを閉じてからやり直す
+		etcScraper.Close()
+		select {
+		case <-time.After(s.InitRetryDelay):
+		case <-ctx.Done():
+			return fmt.Errorf("Initialize aborted after %d attempts: %w (last error: %v)", attempt, context.Cause(ctx), err)
+		}
+	}
+
+	return fmt.Errorf("Initialize failed after %d attempts: %w", attempts, err)
+}
+
+// parseMeisaiFile はダウンロードした明細CSVファイルを解析する（maxRecordsが正の場合はその件数まで）
+// encがCSVEncodingAutoの場合は文字コードを判定し、解析に使った文字コードを返す
+// cardsに含まれないカードの明細は除き、除いた件数を返す
+func parseMeisaiFile(path string, enc CSVEncoding, maxRecords int, cards cardNumberFilter, columnMap CSVColumnMap) ([]*pb.ETCMeisaiRecord, bool, int, CSVEncoding, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, 0, enc, err
+	}
+	if enc == CSVEncodingAuto {
+		enc = detectCSVEncoding(raw)
+	}
+
+	records, truncated, filtered, err := parseMeisaiCSV(bytes.NewReader(raw), enc, maxRecords, cards, columnMap)
+	return records, truncated, filtered, enc, err
+}
+
+// countCSVRecords はCSVファイルのデータ行数（ヘッダー行と空行を除く）を数える
+func countCSVRecords(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	rows := 0
+	for {
+		_, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		rows++
+	}
+
+	// 先頭行はヘッダー
+	if rows > 0 {
+		rows--
+	}
+	return rows, nil
+}
+
+// fileModTimeTolerance はファイル更新時刻の精度の粗さを吸収する許容幅
+const fileModTimeTolerance = 2 * time.Second
+
+// findRecentCSV はセッションフォルダ内から指定アカウントの直近に作成されたCSVを探す
+// セッションフォルダは複数アカウントで共有されるため、アカウント名プレフィックスのファイルのみ対象
+func findRecentCSV(sessionFolder, userID string, since time.Time) (string, bool) {
+	// ファイルの更新時刻は粗い時計で記録されるため、since直後に作成したファイルでもsinceより前になることがある
+	since = since.Add(-fileModTimeTolerance)
+
+	matches, err := filepath.Glob(filepath.Join(sessionFolder, userID+"_*"))
+	if err != nil {
+		return "", false
+	}
+
+	var newestPath string
+	var newestTime time.Time
+	for _, path := range matches {
+		if !strings.EqualFold(filepath.Ext(path), ".csv") {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.ModTime().Before(since) {
+			continue
+		}
+		if newestPath == "" || info.ModTime().After(newestTime) {
+			newestPath = path
+			newestTime = info.ModTime()
+		}
+	}
+
+	return newestPath, newestPath != ""
+}
+
+// recordAccountResult はアカウント単位の結果をジョブに追加
+func (s *DownloadService) recordAccountResult(jobID string, result *AccountResult) {
+	if result == nil {
+		return
+	}
+
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	if job, exists := s.jobs[jobID]; exists {
+		s.records[jobID] = appendUniqueRecords(s.records[jobID], result.records)
+		stored := *result
+		stored.records = nil
+		job.AccountResults = append(job.AccountResults, stored)
+		job.TotalRecords += result.ActualRecords
+		job.TotalBytes += result.bytes
+		job.FileCount += result.files
+	}
+}
+
+// GetJobRecords は終了したジョブで解析した明細を返す
+// ジョブが存在しない（期限切れで削除された）場合はErrJobNotFound、終了していない場合はErrInvalidJobState
+func (s *DownloadService) GetJobRecords(jobID string) ([]*pb.ETCMeisaiRecord, error) {
+	s.jobMutex.RLock()
+	defer s.jobMutex.RUnlock()
+
+	job, exists := s.jobs[jobID]
+	if !exists {
+		return nil, ErrJobNotFound
+	}
+	if !isTerminalStatus(job.Status) {
+		return nil, fmt.Errorf("%w: cannot get result of job %s in status %s", ErrInvalidJobState, jobID, job.Status)
+	}
+
+	return s.records[jobID], nil
+}
+
+// GetJobRecordsSoFar はジョブでこれまでに解析した明細を返す（実行中のジョブでは完了したアカウントの分のみ）
+func (s *DownloadService) GetJobRecordsSoFar(jobID string) ([]*pb.ETCMeisaiRecord, error) {
+	s.jobMutex.RLock()
+	defer s.jobMutex.RUnlock()
+
+	if _, exists := s.jobs[jobID]; !exists {
+		return nil, ErrJobNotFound
+	}
+	return append([]*pb.ETCMeisaiRecord(nil), s.records[jobID]...), nil
+}
+
+// updateAccountStatus はアカウント単位の状態を更新（処理開始時はCurrentAccountも更新）
+func (s *DownloadService) updateAccountStatus(jobID, account, status string) {
+	defer s.saveJob(jobID) // ロック解放後に保存
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	if job, exists := s.jobs[jobID]; exists {
+		accountID := accountUserID(account)
+		job.PerAccount[accountID] = status
+		if status == accountStatusProcessing {
+			job.CurrentAccount = accountID
+		}
+	}
+}
+
+// recordAccountFailure は失敗したアカウントをジョブに記録
+func (s *DownloadService) recordAccountFailure(jobID, account string, err error) {
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	if job, exists := s.jobs[jobID]; exists {
+		accountID := accountUserID(account)
+		maintenance := scraper.IsMaintenanceError(err)
+		job.PerAccount[accountID] = accountStatusFailed
+		if maintenance {
+			job.PerAccount[accountID] = accountStatusMaintenance
+		}
+		job.FailedAccounts = append(job.FailedAccounts, FailedAccount{
+			AccountID:   accountID,
+			Reason:      err.Error(),
+			AuthError:   scraper.IsAuthError(err),
+			Timeout:     scraper.IsTimeoutError(err),
+			Maintenance: maintenance,
+		})
+	}
+}
+
+// accountUserID はアカウント文字列（accountID:password形式）からパスワードを除いたIDを返す
+func accountUserID(account string) string {
+	userID, _, _ := strings.Cut(account, ":")
+	return userID
+}
+
+// updateJobProgress はジョブの進捗を更新
+func (s *DownloadService) updateJobProgress(jobID string, progress int) {
+	defer s.saveJob(jobID) // ロック解放後に保存
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	// 並行するワーカーの更新が前後しても進捗は戻さない
+	if job, exists := s.jobs[jobID]; exists && progress > job.Progress {
+		job.Progress = progress
+	}
+}
+
+// updateJobStatus はジョブのステータスを更新
+func (s *DownloadService) updateJobStatus(jobID string, status string, progress int, errorMsg string) {
+	defer s.saveJob(jobID) // ロック解放後に保存
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	if job, exists := s.jobs[jobID]; exists {
+		if isTerminalStatus(status) && !isTerminalStatus(job.Status) {
+			s.metrics.JobFinished(status)
+		}
+		if status != job.Status {
+			s.notifyJobChangedLocked(jobID)
+		}
+		job.Status = status
+		job.Progress = progress
+		if errorMsg != "" {
+			job.ErrorMessage = errorMsg
+		}
+		if isTerminalStatus(status) {
+			now := time.Now()
+			job.CompletedAt = &now
+		}
+	}
+}
+
+// GetJobStatus はジョブのステータスを取得
+// メモリ上に無い場合（再起動前に登録されたジョブなど）はDBから読み込む
+func (s *DownloadService) GetJobStatus(jobID string) (*DownloadJob, bool) {
+	if job, exists := s.memoryJob(jobID); exists {
+		return job, true
+	}
+
+	job, err := s.LoadJob(jobID)
+	if err != nil {
+		if !errors.Is(err, ErrJobNotFound) {
+			s.logMessagef(LogLevelWarn, "%v", err)
+		}
+		return nil, false
+	}
+	return job, true
+}
+
+// memoryJob はメモリ上のジョブのコピーを返す
+func (s *DownloadService) memoryJob(jobID string) (*DownloadJob, bool) {
+	s.jobMutex.RLock()
+	defer s.jobMutex.RUnlock()
+
+	job, exists := s.jobs[jobID]
+	if !exists {
+		return nil, false
+	}
+	return copyJob(job), true
+}
+
+// GetJobStatuses は複数のジョブのステータスをジョブIDごとに取得する（存在しないジョブは含まない）
+// メモリ上のジョブは1回の読み取りロックで取得し、無いものだけDBから読み込む
+func (s *DownloadService) GetJobStatuses(jobIDs []string) map[string]*DownloadJob {
+	jobs := make(map[string]*DownloadJob, len(jobIDs))
+	var missing []string
+
+	s.jobMutex.RLock()
+	for _, jobID := range jobIDs {
+		if job, exists := s.jobs[jobID]; exists {
+			jobs[jobID] = copyJob(job)
+		} else {
+			missing = append(missing, jobID)
+		}
+	}
+	s.jobMutex.RUnlock()
+
+	for _, jobID := range missing {
+		if _, done := jobs[jobID]; done {
+			continue // 重複して指定されたID
+		}
+		job, err := s.LoadJob(jobID)
+		if err != nil {
+			if !errors.Is(err, ErrJobNotFound) {
+				s.logMessagef(LogLevelWarn, "%v", err)
+			}
+			continue
+		}
+		jobs[jobID] = job
+	}
+	return jobs
+}
+
+// copyJob はジョブのコピーを返す（呼び出し側でjobMutexを取得すること）
+func copyJob(job *DownloadJob) *DownloadJob {
+	jobCopy := *job
+	jobCopy.AccountResults = append([]AccountResult(nil), job.AccountResults...)
+	jobCopy.FailedAccounts = append([]FailedAccount(nil), job.FailedAccounts...)
+	if job.PerAccount != nil {
+		jobCopy.PerAccount = make(map[string]string, len(job.PerAccount))
+		for accountID, status := range job.PerAccount {
+			jobCopy.PerAccount[accountID] = status
+		}
+	}
+	return &jobCopy
+}
+
+// PauseJob は実行中のジョブを一時停止する
+// 処理中のアカウントは最後まで実行され、次のアカウントに進む前に停止する
+func (s *DownloadService) PauseJob(jobID string) error {
+	defer s.saveJob(jobID) // ロック解放後に保存
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	job, exists := s.jobs[jobID]
+	if !exists {
+		return ErrJobNotFound
+	}
+	pause, running := s.pauses[jobID]
+	if !running || job.Status != "processing" {
+		return fmt.Errorf("%w: cannot pause job %s in status %s", ErrInvalidJobState, jobID, job.Status)
+	}
+
+	pause.resumeCh = make(chan struct{})
+	job.Status = "paused"
+	s.notifyJobChangedLocked(jobID)
+	s.logMessage("Paused download job %s", jobID)
+	return nil
+}
+
+// ResumeJob は一時停止中のジョブを再開する
+// メモリにないジョブは、プロセスの再起動で中断した（interruptedの）ジョブとしてDBに保存された
+// チェックポイントから再開する（完了していないアカウントのみを元のセッションフォルダで処理する）
+func (s *DownloadService) ResumeJob(jobID string) error {
+	if _, exists := s.memoryJob(jobID); !exists {
+		return s.resumeInterruptedJob(jobID)
+	}
+	return s.resumePausedJob(jobID)
+}
+
+// resumePausedJob は一時停止中のジョブを再開する
+func (s *DownloadService) resumePausedJob(jobID string) error {
+	defer s.saveJob(jobID) // ロック解放後に保存
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	job, exists := s.jobs[jobID]
+	if !exists {
+		return ErrJobNotFound
+	}
+	pause, running := s.pauses[jobID]
+	if !running || job.Status != "paused" {
+		return fmt.Errorf("%w: cannot resume job %s in status %s", ErrInvalidJobState, jobID, job.Status)
+	}
+
+	close(pause.resumeCh)
+	pause.resumeCh = nil
+	job.Status = "processing"
+	s.notifyJobChangedLocked(jobID)
+	s.logMessage("Resumed download job %s", jobID)
+	return nil
+}
+
+// CancelJob は実行中または一時停止中のジョブをキャンセルする
+// 処理中のアカウントはそのアカウントの処理が終わるまで待ち、未処理のアカウントは処理せずにcancelledで終了する
+func (s *DownloadService) CancelJob(jobID string) error {
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	job, exists := s.jobs[jobID]
+	if !exists {
+		return ErrJobNotFound
+	}
+	pause, running := s.pauses[jobID]
+	if !running || isTerminalStatus(job.Status) {
+		return fmt.Errorf("%w: cannot cancel job %s in status %s", ErrInvalidJobState, jobID, job.Status)
+	}
+
+	pause.cancel(ErrJobCancelled)
+	s.logMessage("Cancelling download job %s", jobID)
+	return nil
+}
+
+// waitIfPaused はジョブが一時停止中であれば再開されるかctxがキャンセルされるまで待機する
+func (s *DownloadService) waitIfPaused(ctx context.Context, jobID string) {
+	s.jobMutex.RLock()
+	var resumeCh chan struct{}
+	if pause, ok := s.pauses[jobID]; ok {
+		resumeCh = pause.resumeCh
+	}
+	s.jobMutex.RUnlock()
+
+	if resumeCh != nil {
+		select {
+		case <-resumeCh:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// jobProgress はジョブの現在の進捗を返す
+func (s *DownloadService) jobProgress(jobID string) int {
+	s.jobMutex.RLock()
+	defer s.jobMutex.RUnlock()
+	if job, exists := s.jobs[jobID]; exists {
+		return job.Progress
+	}
+	return 0
+}
+
+// Shutdown は実行中のジョブにキャンセルを通知し、ctxの期限まで終了を待つ
+// 処理中のアカウントはそのアカウントの処理が終わるまで待ち、未処理のアカウントは処理しない
+// Shutdown後に開始されたジョブはErrShuttingDownで失敗する
+func (s *DownloadService) Shutdown(ctx context.Context) error {
+	s.jobMutex.Lock()
+	s.shuttingDown = true
+	s.jobMutex.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.jobsWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		// ブラウザプールなどスクレイパーが共有するリソースは、ジョブがすべて終わってから解放する
+		if closer, ok := s.scraperFactory.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				s.logMessagef(LogLevelWarn, "Failed to close scraper factory: %v", err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for running jobs: %w", ctx.Err())
+	}
+}
+
+// adjustRuntime は稼働状況のカウンタを更新
+func (s *DownloadService) adjustRuntime(update func(c *runtimeCounters)) {
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+	update(&s.runtime)
+}
+
+// Metrics はジョブ数とダウンロード時間のメトリクスを返す（ETC_METRICS_ENABLED=falseの場合はnil）
+// 返り値はhttp.Handlerとして既存の/metricsエンドポイントにマウントできる
+func (s *DownloadService) Metrics() *JobMetrics {
+	return s.metrics
+}
+
+// GetRuntimeMetrics は稼働状況のスナップショットを返す
+// ジョブとカウンタは同じロックで保護されているため、一貫した値が得られる
+func (s *DownloadService) GetRuntimeMetrics() RuntimeMetrics {
+	s.jobMutex.RLock()
+	defer s.jobMutex.RUnlock()
+
+	metrics := RuntimeMetrics{
+		QueuedAccounts:      s.runtime.queuedAccounts,
+		BusyWorkers:         s.runtime.busyWorkers,
+		IdleWorkers:         s.runtime.workers - s.runtime.busyWorkers,
+		ActiveBrowsers:      s.runtime.activeBrowsers,
+		RateBudgetRemaining: -1,
+	}
+	for _, job := range s.jobs {
+		switch job.Status {
+		case jobStatusPending:
+			metrics.QueuedJobs++
+		case "processing":
+			metrics.RunningJobs++
+		case "paused":
+			metrics.PausedJobs++
+		}
+	}
+	return metrics
+}
+
+// finalJobStatus は全アカウントの処理後のジョブの状態を返す
+// 全アカウント成功ならcompleted、一部失敗ならpartial、全アカウント失敗ならfailed
+func finalJobStatus(totalAccounts, failedAccounts int) string {
+	switch {
+	case failedAccounts == 0:
+		return "completed"
+	case failedAccounts < totalAccounts:
+		return jobStatusPartial
+	default:
+		return "failed"
+	}
+}
+
+// isTerminalStatus はジョブが終了状態（削除対象になり得る状態）かを判定
+func isTerminalStatus(status string) bool {
+	switch status {
+	case "completed", jobStatusPartial, "failed", "cancelled", jobStatusInterrupted:
+		return true
+	}
+	return false
+}
+
+// ReapExpiredJobs はJobTTLを過ぎた終了済みジョブを削除し、削除件数を返す
+func (s *DownloadService) ReapExpiredJobs() int {
+	if s.JobTTL <= 0 {
+		return 0
+	}
+
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	removed := 0
+	for jobID, job := range s.jobs {
+		if !isTerminalStatus(job.Status) || job.CompletedAt == nil {
+			continue
+		}
+		if time.Since(*job.CompletedAt) > s.JobTTL {
+			delete(s.jobs, jobID)
+			delete(s.records, jobID)
+			s.removeJobLogs(jobID)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// evictOldJobsLocked はジョブ数がMaxJobsを超えている分だけ終了済みジョブを古い順に削除し、削除件数を返す
+// 実行中のジョブしか残っていなければ上限を超えたままにする（jobMutexを保持して呼ぶこと）
+func (s *DownloadService) evictOldJobsLocked() int {
+	if s.MaxJobs <= 0 || len(s.jobs) <= s.MaxJobs {
+		return 0
+	}
+
+	var terminal []*DownloadJob
+	for _, job := range s.jobs {
+		if isTerminalStatus(job.Status) {
+			terminal = append(terminal, job)
+		}
+	}
+	sort.Slice(terminal, func(i, j int) bool {
+		return jobFinishedAt(terminal[i]).Before(jobFinishedAt(terminal[j]))
+	})
+
+	removed := 0
+	for _, job := range terminal {
+		if len(s.jobs) <= s.MaxJobs {
+			break
+		}
+		delete(s.jobs, job.ID)
+		delete(s.records, job.ID)
+		s.removeJobLogs(job.ID)
+		removed++
+	}
+	return removed
+}
+
+// logEvictedJobs はMaxJobsによって削除したジョブ数をログに記録する
+func (s *DownloadService) logEvictedJobs(jobID string, evicted int) {
+	if evicted > 0 {
+		s.logJobf(LogLevelInfo, jobID, "", "Evicted %d old jobs (max jobs: %d)", evicted, s.MaxJobs)
+	}
+}
+
+// jobFinishedAt はジョブの終了日時を返す（終了日時がなければ開始日時）
+func jobFinishedAt(job *DownloadJob) time.Time {
+	if job.CompletedAt != nil {
+		return *job.CompletedAt
+	}
+	return job.StartedAt
+}
+
+// StartJobReaper は終了済みジョブを定期的に削除するバックグラウンド処理を開始
+// 返り値のstop関数を呼ぶと停止する（複数回呼んでも安全）
+func (s *DownloadService) StartJobReaper(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if removed := s.ReapExpiredJobs(); removed > 0 {
+					s.logMessage("Removed %d expired jobs (TTL: %v)", removed, s.JobTTL)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// GetHeadlessMode は環境変数からHeadlessモードの設定を取得
+// ETC_HEADLESS=false でブラウザを表示、未設定またはtrueでHeadlessモード（デフォルト）
+func GetHeadlessMode() bool {
+	headlessEnv := os.Getenv("ETC_HEADLESS")
+	if headlessEnv == "" {
+		log.Println("[Headless] ETC_HEADLESS not set, using default: true (Headless mode)")
+		return true // デフォルトはHeadlessモード
+	}
+
+	// "false", "0", "no" の場合は非Headlessモード（ブラウザ表示）
+	headless, err := strconv.ParseBool(headlessEnv)
 	if err != nil {
 		// パースエラーの場合もデフォルトのHeadlessモード
 		log.Printf("[Headless] Invalid ETC_HEADLESS value %q, using default: true (Headless mode)", headlessEnv)
@@ -305,6 +2033,499 @@ func GetHeadlessMode() bool {
 	return headless
 }
 
+// GetMaxConcurrency は環境変数から同時処理アカウント数を取得
+// ETC_MAX_CONCURRENCY 未設定または不正値の場合は1（従来どおり逐次処理）
+func GetMaxConcurrency() int {
+	concurrencyEnv := os.Getenv("ETC_MAX_CONCURRENCY")
+	if concurrencyEnv == "" {
+		return 1
+	}
+
+	concurrency, err := strconv.Atoi(concurrencyEnv)
+	if err != nil || concurrency < 1 {
+		log.Printf("[Concurrency] Invalid ETC_MAX_CONCURRENCY value %q, using default: 1", concurrencyEnv)
+		return 1
+	}
+
+	return concurrency
+}
+
+// getAccountDelay は環境変数からアカウント間の待機時間を取得
+// ETC_ACCOUNT_DELAY_MS が不正値の場合は警告を記録してデフォルト（1000ms）を使用
+func (s *DownloadService) getAccountDelay() time.Duration {
+	const defaultDelay = time.Second
+
+	delayEnv := os.Getenv("ETC_ACCOUNT_DELAY_MS")
+	if delayEnv == "" {
+		return defaultDelay
+	}
+
+	delayMs, err := strconv.Atoi(delayEnv)
+	if err != nil || delayMs < 0 {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_ACCOUNT_DELAY_MS value %q, using default: %v", delayEnv, defaultDelay)
+		return defaultDelay
+	}
+
+	return time.Duration(delayMs) * time.Millisecond
+}
+
+// ValidateTimeout はPlaywrightの操作タイムアウトが許容範囲（5秒〜300秒）内か検証
+func ValidateTimeout(timeout time.Duration) error {
+	if timeout < minTimeout || timeout > maxTimeout {
+		return fmt.Errorf("timeout must be between %v and %v, got %v", minTimeout, maxTimeout, timeout)
+	}
+	return nil
+}
+
+// getTimeout は環境変数からPlaywrightの操作タイムアウトを取得
+// ETC_TIMEOUT_MS: ミリ秒単位（デフォルト30000、範囲外や不正な値はデフォルト）
+func (s *DownloadService) getTimeout() time.Duration {
+	timeoutEnv := os.Getenv("ETC_TIMEOUT_MS")
+	if timeoutEnv == "" {
+		return defaultTimeout
+	}
+
+	timeoutMs, err := strconv.Atoi(timeoutEnv)
+	if err == nil {
+		err = ValidateTimeout(time.Duration(timeoutMs) * time.Millisecond)
+	}
+	if err != nil {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_TIMEOUT_MS value %q (%v), using default: %v", timeoutEnv, err, defaultTimeout)
+		return defaultTimeout
+	}
+
+	return time.Duration(timeoutMs) * time.Millisecond
+}
+
+// getRetryBaseDelay は環境変数からリトライ間隔の基準値を取得
+// ETC_RETRY_BASE_DELAY_MS: ミリ秒単位（デフォルト2000、負の値や不正な値はデフォルト）
+func (s *DownloadService) getRetryBaseDelay() time.Duration {
+	const defaultDelay = 2 * time.Second
+
+	delayEnv := os.Getenv("ETC_RETRY_BASE_DELAY_MS")
+	if delayEnv == "" {
+		return defaultDelay
+	}
+
+	ms, err := strconv.Atoi(delayEnv)
+	if err != nil || ms < 0 {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_RETRY_BASE_DELAY_MS value %q, using default: %v", delayEnv, defaultDelay)
+		return defaultDelay
+	}
+
+	return time.Duration(ms) * time.Millisecond
+}
+
+// getJobTTL は環境変数から終了済みジョブの保持期間を取得
+// ETC_JOB_TTL はtime.ParseDuration形式（例: 30m, 2h）、不正値の場合はデフォルト（1h）
+func (s *DownloadService) getJobTTL() time.Duration {
+	const defaultTTL = time.Hour
+
+	ttlEnv := os.Getenv("ETC_JOB_TTL")
+	if ttlEnv == "" {
+		return defaultTTL
+	}
+
+	ttl, err := time.ParseDuration(ttlEnv)
+	if err != nil {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_JOB_TTL value %q, using default: %v", ttlEnv, defaultTTL)
+		return defaultTTL
+	}
+
+	return ttl
+}
+
+// getMaintenanceRetryDelay は環境変数からメンテナンス中だったアカウントを再実行するまでの待機時間を取得
+// ETC_MAINTENANCE_RETRY_DELAY はtime.ParseDuration形式（例: 30m, 1h）、未設定・不正値の場合は0（再実行しない）
+func (s *DownloadService) getMaintenanceRetryDelay() time.Duration {
+	delayEnv := os.Getenv("ETC_MAINTENANCE_RETRY_DELAY")
+	if delayEnv == "" {
+		return 0
+	}
+
+	delay, err := time.ParseDuration(delayEnv)
+	if err != nil || delay < 0 {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_MAINTENANCE_RETRY_DELAY value %q, using default: 0 (no retry)", delayEnv)
+		return 0
+	}
+
+	return delay
+}
+
+// getMaxJobs は環境変数からメモリに保持するジョブ数の上限を取得
+// ETC_MAX_JOBS は0以上の整数（0で無制限）、不正値の場合はデフォルト（0）
+func (s *DownloadService) getMaxJobs() int {
+	maxEnv := os.Getenv("ETC_MAX_JOBS")
+	if maxEnv == "" {
+		return 0
+	}
+
+	maxJobs, err := strconv.Atoi(maxEnv)
+	if err != nil || maxJobs < 0 {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_MAX_JOBS value %q, using default: 0 (unlimited)", maxEnv)
+		return 0
+	}
+
+	return maxJobs
+}
+
+// getDownloadDir は環境変数からダウンロード保存先のベースディレクトリを取得
+func getDownloadDir() string {
+	if dir := strings.TrimSpace(os.Getenv("ETC_DOWNLOAD_DIR")); dir != "" {
+		return dir
+	}
+	return defaultDownloadDir
+}
+
+// getRecoverMissingDownload は環境変数からダウンロードファイル欠落時の復旧有無を取得
+// ETC_RECOVER_MISSING_DOWNLOAD=false で復旧を無効化（デフォルトは有効）
+func getRecoverMissingDownload() bool {
+	recoverEnv := os.Getenv("ETC_RECOVER_MISSING_DOWNLOAD")
+	if recoverEnv == "" {
+		return true
+	}
+
+	recoverMissing, err := strconv.ParseBool(recoverEnv)
+	if err != nil {
+		log.Printf("[Download] Invalid ETC_RECOVER_MISSING_DOWNLOAD value %q, using default: true", recoverEnv)
+		return true
+	}
+
+	return recoverMissing
+}
+
+// getCheckDB は環境変数からジョブ開始前のDB接続確認の有無を取得
+// ETC_DB_CHECK=false で確認しない（デフォルトは確認する）
+func getCheckDB() bool {
+	checkEnv := os.Getenv("ETC_DB_CHECK")
+	if checkEnv == "" {
+		return true
+	}
+
+	check, err := strconv.ParseBool(checkEnv)
+	if err != nil {
+		log.Printf("[DB] Invalid ETC_DB_CHECK value %q, using default: true", checkEnv)
+		return true
+	}
+
+	return check
+}
+
+// getMetricsEnabled は環境変数からメトリクス収集の有無を取得（ETC_METRICS_ENABLED、デフォルト有効）
+func getMetricsEnabled() bool {
+	metricsEnv := os.Getenv("ETC_METRICS_ENABLED")
+	if metricsEnv == "" {
+		return true
+	}
+
+	enabled, err := strconv.ParseBool(metricsEnv)
+	if err != nil {
+		log.Printf("[Metrics] Invalid ETC_METRICS_ENABLED value %q, using default: true", metricsEnv)
+		return true
+	}
+
+	return enabled
+}
+
+// ログ出力形式（ETC_LOG_FORMAT）
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// getLogFormat は環境変数からログ出力形式を取得
+// ETC_LOG_FORMAT 未設定または不正値の場合はtext
+func getLogFormat() string {
+	formatEnv := strings.ToLower(strings.TrimSpace(os.Getenv("ETC_LOG_FORMAT")))
+	switch formatEnv {
+	case "", logFormatText:
+		return logFormatText
+	case logFormatJSON:
+		return logFormatJSON
+	}
+
+	log.Printf("[Log] Invalid ETC_LOG_FORMAT value %q, using default: %s", formatEnv, logFormatText)
+	return logFormatText
+}
+
+// getIncremental は環境変数から増分ダウンロードの有無を取得
+// ETC_INCREMENTAL 未設定または不正値の場合は無効
+// 明細はDBに保存されず、前回の終了日より前の明細はジョブの結果（ETC_JOB_TTLまで）からしか取得できないため、
+// ETC_INCREMENTAL_WITHOUT_SAVE でそれを許可していない場合も無効
+func getIncremental() bool {
+	incrementalEnv := os.Getenv("ETC_INCREMENTAL")
+	if incrementalEnv == "" {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(incrementalEnv)
+	if err != nil {
+		log.Printf("[Incremental] Invalid ETC_INCREMENTAL value %q, using default: false", incrementalEnv)
+		return false
+	}
+	if !enabled {
+		return false
+	}
+
+	withoutSave, _ := strconv.ParseBool(os.Getenv("ETC_INCREMENTAL_WITHOUT_SAVE"))
+	if !withoutSave {
+		log.Printf("[Incremental] ETC_INCREMENTAL is ignored: records are not saved to the database, " +
+			"so records skipped by incremental downloads are lost unless every job result is fetched. " +
+			"Set ETC_INCREMENTAL_WITHOUT_SAVE=true to enable it anyway")
+		return false
+	}
+	log.Printf("[Incremental] Incremental downloads enabled without saving records: " +
+		"fetch every job result (GetJobResult or ExportJobCSV) before it expires (ETC_JOB_TTL), " +
+		"later jobs do not download the same period again")
+	return true
+}
+
+// getMaxRecordsPerAccount は環境変数からアカウントごとの明細の上限を取得
+// ETC_MAX_RECORDS 未設定または0の場合は無制限、不正値の場合も無制限
+func getMaxRecordsPerAccount() int {
+	maxEnv := os.Getenv("ETC_MAX_RECORDS")
+	if maxEnv == "" {
+		return 0
+	}
+
+	maxRecords, err := strconv.Atoi(maxEnv)
+	if err != nil || maxRecords < 0 {
+		log.Printf("[Records] Invalid ETC_MAX_RECORDS value %q, using default: 0 (unlimited)", maxEnv)
+		return 0
+	}
+
+	return maxRecords
+}
+
+// defaultInitRetryCount・defaultInitRetryDelay はブラウザ初期化のリトライの既定値
+const (
+	defaultInitRetryCount = 2
+	defaultInitRetryDelay = time.Second
+)
+
+// getInitRetryCount は環境変数からブラウザ初期化のリトライ回数を取得
+// ETC_INIT_RETRY_COUNT: 0でリトライなし、負の値や不正な値はデフォルト（2）
+func getInitRetryCount() int {
+	countEnv := os.Getenv("ETC_INIT_RETRY_COUNT")
+	if countEnv == "" {
+		return defaultInitRetryCount
+	}
+
+	count, err := strconv.Atoi(countEnv)
+	if err != nil || count < 0 {
+		log.Printf("[Browser] Invalid ETC_INIT_RETRY_COUNT value %q, using default: %d", countEnv, defaultInitRetryCount)
+		return defaultInitRetryCount
+	}
+
+	return count
+}
+
+// getInitRetryDelay は環境変数からブラウザ初期化のリトライ間隔を取得
+// ETC_INIT_RETRY_DELAY_MS: ミリ秒単位（デフォルト1000、負の値や不正な値はデフォルト）
+func getInitRetryDelay() time.Duration {
+	delayEnv := os.Getenv("ETC_INIT_RETRY_DELAY_MS")
+	if delayEnv == "" {
+		return defaultInitRetryDelay
+	}
+
+	ms, err := strconv.Atoi(delayEnv)
+	if err != nil || ms < 0 {
+		log.Printf("[Browser] Invalid ETC_INIT_RETRY_DELAY_MS value %q, using default: %v", delayEnv, defaultInitRetryDelay)
+		return defaultInitRetryDelay
+	}
+
+	return time.Duration(ms) * time.Millisecond
+}
+
+// getCSVEncoding は環境変数からダウンロードしたCSVの文字コードを取得
+// ETC_CSV_ENCODING 未設定または不正値の場合は自動判定
+func getCSVEncoding() CSVEncoding {
+	encodingEnv := os.Getenv("ETC_CSV_ENCODING")
+	if encodingEnv == "" {
+		return CSVEncodingAuto
+	}
+
+	enc, err := ParseCSVEncoding(encodingEnv)
+	if err != nil {
+		log.Printf("[CSV] Invalid ETC_CSV_ENCODING value %q, using default: auto", encodingEnv)
+		return CSVEncodingAuto
+	}
+
+	return enc
+}
+
+// getCSVColumnMap は環境変数からCSVの列名の別名を取得
+// ETC_CSV_COLUMN_MAP 未設定または不正値の場合は標準の列名のみ
+func getCSVColumnMap() CSVColumnMap {
+	value := os.Getenv("ETC_CSV_COLUMN_MAP")
+	if value == "" {
+		return nil
+	}
+
+	columnMap, err := ParseCSVColumnMap(value)
+	if err != nil {
+		log.Printf("[CSV] Invalid ETC_CSV_COLUMN_MAP value %q, using default: standard column names only (%v)", value, err)
+		return nil
+	}
+
+	return columnMap
+}
+
+// getSortAccounts は環境変数からアカウントをユーザーID順に処理するかを取得
+// ETC_SORT_ACCOUNTS 未設定または不正値の場合は無効
+func getSortAccounts() bool {
+	sortEnv := os.Getenv("ETC_SORT_ACCOUNTS")
+	if sortEnv == "" {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(sortEnv)
+	if err != nil {
+		log.Printf("[Accounts] Invalid ETC_SORT_ACCOUNTS value %q, using default: false", sortEnv)
+		return false
+	}
+
+	return enabled
+}
+
+// sortAccountsByUserID はアカウントをユーザーID順に並べたコピーを返す
+// 同じユーザーIDのアカウントは元の順序を保つ
+func sortAccountsByUserID(accounts []string) []string {
+	sorted := append([]string(nil), accounts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return accountUserID(sorted[i]) < accountUserID(sorted[j])
+	})
+	return sorted
+}
+
+// getCaptureOnError は環境変数からログイン・ダウンロード失敗時の画面キャプチャの有無を取得
+// ETC_CAPTURE_ON_ERROR 未設定または不正値の場合は保存しない
+func getCaptureOnError() bool {
+	captureEnv := os.Getenv("ETC_CAPTURE_ON_ERROR")
+	if captureEnv == "" {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(captureEnv)
+	if err != nil {
+		log.Printf("[Capture] Invalid ETC_CAPTURE_ON_ERROR value %q, using default: false", captureEnv)
+		return false
+	}
+
+	return enabled
+}
+
+// getUserAgent は環境変数からブラウザのユーザーエージェントを取得
+// ETC_USER_AGENT 未設定の場合は空（スクレイパーの既定のデスクトップUAを使用）
+func getUserAgent() string {
+	return strings.TrimSpace(os.Getenv("ETC_USER_AGENT"))
+}
+
+// getViewport は環境変数からブラウザのビューポートを取得（例: 1366x768）
+// ETC_VIEWPORT 未設定または不正値の場合はゼロ値（スクレイパーの既定の1920x1080を使用）
+func getViewport() scraper.Size {
+	viewportEnv := os.Getenv("ETC_VIEWPORT")
+	if viewportEnv == "" {
+		return scraper.Size{}
+	}
+
+	widthStr, heightStr, ok := strings.Cut(strings.ToLower(strings.TrimSpace(viewportEnv)), "x")
+	width, widthErr := strconv.Atoi(strings.TrimSpace(widthStr))
+	height, heightErr := strconv.Atoi(strings.TrimSpace(heightStr))
+	if !ok || widthErr != nil || heightErr != nil || width <= 0 || height <= 0 {
+		log.Printf("[Browser] Invalid ETC_VIEWPORT value %q, using default: %dx%d",
+			viewportEnv, scraper.DefaultViewportWidth, scraper.DefaultViewportHeight)
+		return scraper.Size{}
+	}
+
+	return scraper.Size{Width: width, Height: height}
+}
+
+// getSelectors は環境変数で指定したJSONファイルからETCサイトのセレクタを読み込む
+// ETC_SELECTORS_FILE 未設定の場合はゼロ値（スクレイパーの既定のセレクタを使用）
+// アカウントの処理ごとに読み込むため、サイトの変更にはファイルの差し替えだけで対応できる
+func getSelectors() (scraper.Selectors, error) {
+	path := strings.TrimSpace(os.Getenv("ETC_SELECTORS_FILE"))
+	if path == "" {
+		return scraper.Selectors{}, nil
+	}
+	return scraper.LoadSelectors(path)
+}
+
+// getCleanupDownloads は環境変数から成功したジョブのセッションフォルダ削除の有無を取得
+// 安全のためデフォルトは削除しない
+func getCleanupDownloads() bool {
+	cleanupEnv := os.Getenv("ETC_CLEANUP_DOWNLOADS")
+	if cleanupEnv == "" {
+		return false
+	}
+
+	cleanup, err := strconv.ParseBool(cleanupEnv)
+	if err != nil {
+		log.Printf("[Download] Invalid ETC_CLEANUP_DOWNLOADS value %q, using default: false", cleanupEnv)
+		return false
+	}
+
+	return cleanup
+}
+
+// getProxyConfig は環境変数からプロキシ設定を取得（ETC_PROXY_URL未設定時はプロキシなし）
+func getProxyConfig() (proxyURL, username, password string) {
+	proxyURL = strings.TrimSpace(os.Getenv("ETC_PROXY_URL"))
+	if proxyURL == "" {
+		return "", "", ""
+	}
+	return proxyURL, os.Getenv("ETC_PROXY_USERNAME"), os.Getenv("ETC_PROXY_PASSWORD")
+}
+
+// getAccountProxyConfig はアカウントのプロキシ設定を取得
+// ETC_ACCOUNT_PROXIES にアカウントの指定があればそれを使い（4つ目の返り値がtrue）、なければETC_PROXY_URL
+func getAccountProxyConfig(accountID string) (proxyURL, username, password string, perAccount bool) {
+	if proxyURL, ok := parseAccountProxies(os.Getenv("ETC_ACCOUNT_PROXIES"))[accountID]; ok {
+		// 認証情報はURLに含める（ETC_PROXY_USERNAME/PASSWORDは共通プロキシ用）
+		return proxyURL, "", "", true
+	}
+	proxyURL, username, password = getProxyConfig()
+	return proxyURL, username, password, false
+}
+
+// parseAccountProxies はアカウントIDごとのプロキシ指定を解析
+// JSON形式: {"user1":"socks5://proxy1:1080"} またはカンマ区切り: user1=socks5://proxy1:1080,user2=http://proxy2:8080
+func parseAccountProxies(value string) map[string]string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	proxies := make(map[string]string)
+	if strings.HasPrefix(value, "{") {
+		if err := json.Unmarshal([]byte(value), &proxies); err != nil {
+			log.Printf("[Proxy] Invalid ETC_ACCOUNT_PROXIES JSON: %v", err)
+			return nil
+		}
+		return proxies
+	}
+
+	for i, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		accountID, proxyURL := strings.TrimSpace(parts[0]), ""
+		if len(parts) == 2 {
+			proxyURL = strings.TrimSpace(parts[1])
+		}
+		if accountID == "" || proxyURL == "" {
+			// プロキシURLに認証情報が含まれ得るため内容は出力しない
+			log.Printf("[Proxy] Ignoring invalid ETC_ACCOUNT_PROXIES entry #%d (expected accountID=proxyURL)", i+1)
+			continue
+		}
+		proxies[accountID] = proxyURL
+	}
+	return proxies
+}
+
 // getHeadlessMode は後方互換性のため維持（非推奨）
 func getHeadlessMode() bool {
 	return GetHeadlessMode()
@@ -315,13 +2536,68 @@ func (s *DownloadService) SetLogCallback(callback func(string)) {
 	s.logCallback = callback
 }
 
-// logMessage はログメッセージを記録
+// SetLogEntryCallback はレベルと時刻付きのログコールバック関数を設定
+func (s *DownloadService) SetLogEntryCallback(callback func(LogEntry)) {
+	s.entryCallback = callback
+}
+
+// logMessage はInfoレベルでログメッセージを記録（後方互換用）
 func (s *DownloadService) logMessage(format string, args ...interface{}) {
+	s.logMessagef(LogLevelInfo, format, args...)
+}
+
+// logMessagef は指定レベルでログメッセージを記録
+func (s *DownloadService) logMessagef(level LogLevel, format string, args ...interface{}) {
+	s.logJobf(level, "", "", format, args...)
+}
+
+// logJobf はジョブIDとアカウントIDを付けてログメッセージを記録
+// ETC_LOG_FORMAT=json の場合はloggerにJSONで出力する（ログバッファには元のメッセージを保持）
+// ジョブIDがある場合はジョブごとのログバッファにも記録する
+func (s *DownloadService) logJobf(level LogLevel, jobID, account, format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	if s.logger != nil {
-		s.logger.Println(msg)
+	entry := LogEntry{Timestamp: time.Now(), Level: level, Message: msg}
+	if jobID != "" {
+		s.jobLogBuffer(jobID).AddEntry(entry)
+	}
+	if s.jsonLogger != nil {
+		s.writeJSONLog(level, jobID, account, msg)
+	} else if s.logger != nil {
+		if level == LogLevelInfo {
+			s.logger.Println(msg)
+		} else {
+			s.logger.Printf("[%s] %s", level, msg)
+		}
 	}
 	if s.logCallback != nil {
 		s.logCallback(msg)
 	}
+	if s.entryCallback != nil {
+		s.entryCallback(entry)
+	}
+}
+
+// jsonLogLine はETC_LOG_FORMAT=json の場合の1行分のログ
+type jsonLogLine struct {
+	Ts      string `json:"ts"`
+	Level   string `json:"level"`
+	JobID   string `json:"job_id,omitempty"`
+	Account string `json:"account,omitempty"`
+	Msg     string `json:"msg"`
+}
+
+// writeJSONLog はログをJSONオブジェクト1行として出力
+func (s *DownloadService) writeJSONLog(level LogLevel, jobID, account, msg string) {
+	line, err := json.Marshal(jsonLogLine{
+		Ts:      time.Now().Format(time.RFC3339Nano),
+		Level:   strings.ToLower(level.String()),
+		JobID:   jobID,
+		Account: account,
+		Msg:     msg,
+	})
+	if err != nil {
+		s.jsonLogger.Printf("[%s] %s", level, msg)
+		return
+	}
+	s.jsonLogger.Println(string(line))
 }
\ No newline at end of file
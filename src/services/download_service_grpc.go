@@ -1,16 +1,24 @@
 package services
 
 import (
+	"bufio"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/buildinfo"
 	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -19,111 +27,296 @@ type DownloadServiceGRPC struct {
 	pb.UnimplementedDownloadServiceServer
 	downloadService DownloadServiceInterface
 	logBuffer       *LogBuffer
+
+	// IDGenerator はDownloadSync・DownloadAsyncで作成するジョブのIDを生成する（デフォルトuuid.NewString）
+	// テストで固定のIDを使う場合や、テナントのプレフィックスを付ける場合などに差し替える
+	IDGenerator func() string
 }
 
-// LogBuffer はログを保持するリングバッファ
-type LogBuffer struct {
-	lines    []string
-	maxLines int
-	mu       sync.RWMutex
+// NewDownloadServiceGRPC creates a new gRPC download service
+func NewDownloadServiceGRPC(db *sql.DB, logger *log.Logger) *DownloadServiceGRPC {
+	return NewDownloadServiceGRPCWithService(NewDownloadService(db, logger))
 }
 
-// NewLogBuffer creates a new log buffer
-func NewLogBuffer(maxLines int) *LogBuffer {
-	return &LogBuffer{
-		lines:    make([]string, 0, maxLines),
-		maxLines: maxLines,
+// NewDownloadServiceGRPCWithService creates a new gRPC download service that wraps the given
+// download service and collects its logs into a log buffer
+func NewDownloadServiceGRPCWithService(downloadService DownloadServiceInterface) *DownloadServiceGRPC {
+	grpcService := &DownloadServiceGRPC{
+		downloadService: downloadService,
+		logBuffer:       NewLogBuffer(getLogBufferSize()),
+		IDGenerator:     uuid.NewString,
 	}
+
+	// ログコールバックを設定（レベルと時刻を保持したままバッファに追加）
+	grpcService.downloadService.SetLogEntryCallback(grpcService.logBuffer.AddEntry)
+
+	return grpcService
 }
 
-// Add adds a log line to the buffer
-func (lb *LogBuffer) Add(line string) {
-	lb.mu.Lock()
-	defer lb.mu.Unlock()
+// NewDownloadServiceGRPCWithMock creates a new gRPC download service with a custom download service
+func NewDownloadServiceGRPCWithMock(downloadService DownloadServiceInterface) *DownloadServiceGRPC {
+	return &DownloadServiceGRPC{
+		downloadService: downloadService,
+		IDGenerator:     uuid.NewString,
+	}
+}
 
-	lb.lines = append(lb.lines, line)
-	if len(lb.lines) > lb.maxLines {
-		lb.lines = lb.lines[1:]
+// newJobID はIDGeneratorでジョブIDを生成する（未設定の場合はUUID）
+func (s *DownloadServiceGRPC) newJobID() string {
+	if s.IDGenerator == nil {
+		return uuid.NewString()
 	}
+	return s.IDGenerator()
 }
 
-// GetTail returns the last N lines
-func (lb *LogBuffer) GetTail(n int) []string {
-	lb.mu.RLock()
-	defer lb.mu.RUnlock()
+// syncPollInterval は同期ダウンロードでジョブの終了を確認する間隔
+const syncPollInterval = 100 * time.Millisecond
+
+// syncResponseMargin は同期ダウンロードで途中までの結果を返すため、呼び出し元のdeadlineより前に待機を打ち切る余裕
+const syncResponseMargin = time.Second
 
-	if n <= 0 || n > len(lb.lines) {
-		n = len(lb.lines)
+// DownloadSync は同期ダウンロードを実行
+// ジョブとして実行して終了まで待ち、明細と失敗の種別（error_code）を返す
+func (s *DownloadServiceGRPC) DownloadSync(ctx context.Context, req *pb.DownloadRequest) (*pb.DownloadResponse, error) {
+	// パラメータのデフォルト値設定
+	fromDate, toDate, err := s.setDefaultDates(req.FromDate, req.ToDate)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	timeout, err := requestTimeout(req.TimeoutMs)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.MaxInlineRecords < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max_inline_records must not be negative")
+	}
+	outputFormat, err := requestOutputFormat(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	start := len(lb.lines) - n
-	if start < 0 {
-		start = 0
+	// 処理を始める前にアカウント形式を検証
+	if errs := ValidateAccounts(req.Accounts); len(errs) > 0 {
+		return nil, invalidAccountsError(errs)
 	}
 
-	result := make([]string, n)
-	copy(result, lb.lines[start:])
-	return result
-}
+	accounts, err := s.requestAccounts(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		// 設定済みのアカウントのうち形式が正しいものを使用
+		accounts, _ = partitionAccounts(s.downloadService.GetAllAccountsWithCredentials())
+		if len(accounts) == 0 {
+			return &pb.DownloadResponse{
+				Success:      false,
+				Records:      []*pb.ETCMeisaiRecord{},
+				Error:        "No accounts configured",
+				ErrorMessage: "No accounts configured",
+				ErrorCode:    pb.ErrorCode_ERROR_CODE_NO_ACCOUNTS,
+			}, nil
+		}
+	}
 
-// GetAll returns all lines
-func (lb *LogBuffer) GetAll() []string {
-	lb.mu.RLock()
-	defer lb.mu.RUnlock()
+	jobID := s.newJobID()
+	opts := JobOptions{
+		DryRun:        req.DryRun,
+		Timeout:       timeout,
+		Headless:      req.Headless,
+		FromDateUnset: req.FromDate == "",
+		CardNumbers:   req.CardNumbers,
+		// クライアントが切断した場合は処理中のアカウントも中止してブラウザを解放する
+		AbortOnCancel:        true,
+		DownloadCertificates: req.DownloadCertificates,
+		OutputFormat:         outputFormat,
+	}
+	// 呼び出し元にdeadlineがある場合は、その少し前までに途中の結果を返せるようにする
+	waitCtx := ctx
+	if deadline, ok := ctx.Deadline(); ok {
+		opts.Deadline = syncWaitDeadline(deadline)
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithDeadline(ctx, opts.Deadline)
+		defer cancel()
+	}
+	// RPCがキャンセル・タイムアウトした場合はジョブも中止する
+	s.downloadService.ProcessAsyncWithOptions(ctx, jobID, accounts, fromDate, toDate, opts)
 
-	result := make([]string, len(lb.lines))
-	copy(result, lb.lines)
-	return result
-}
+	job, err := s.waitForJob(waitCtx, jobID)
+	if err != nil {
+		if ctx.Err() != nil || !errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+			return nil, err
+		}
+		response, err := s.truncatedSyncResponse(jobID)
+		if err != nil {
+			return nil, err
+		}
+		response.FromDate, response.ToDate = fromDate, toDate
+		return limitInlineRecords(response, req.MaxInlineRecords), nil
+	}
+	records, err := s.downloadService.GetJobRecords(jobID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
 
-// NewDownloadServiceGRPC creates a new gRPC download service
-func NewDownloadServiceGRPC(db *sql.DB, logger *log.Logger) *DownloadServiceGRPC {
-	grpcService := &DownloadServiceGRPC{
-		downloadService: NewDownloadService(db, logger),
-		logBuffer:       NewLogBuffer(1000), // 最大1000行保持
+	response := &pb.DownloadResponse{
+		Success:     true,
+		RecordCount: int32(len(records)),
+		Records:     records,
+		JobId:       jobID,
+		FromDate:    fromDate,
+		ToDate:      toDate,
+	}
+	if outputFormat == OutputFormatCSVPath {
+		// 明細を解析していないため、CSVから数えた件数を返す
+		response.RecordCount = int32(job.TotalRecords)
+	}
+	for _, r := range job.AccountResults {
+		if r.CSVPath != "" {
+			response.CsvPaths = append(response.CsvPaths, r.CSVPath)
+		}
+	}
+	if len(response.CsvPaths) > 0 {
+		response.CsvPath = response.CsvPaths[0]
+	}
+	if code, message := syncErrorCode(job); code != pb.ErrorCode_ERROR_CODE_UNSPECIFIED {
+		response.Success = false
+		response.ErrorCode = code
+		response.Error = message
+		response.ErrorMessage = message
 	}
 
-	// ログコールバックを設定
-	grpcService.downloadService.SetLogCallback(func(msg string) {
-		grpcService.logBuffer.Add(msg)
-	})
+	return limitInlineRecords(response, req.MaxInlineRecords), nil
+}
 
-	return grpcService
+// requestOutputFormat はDownloadRequestのoutput_formatをOutputFormatに変換する（未指定はOutputFormatBoth）
+func requestOutputFormat(req *pb.DownloadRequest) (OutputFormat, error) {
+	switch req.OutputFormat {
+	case pb.OutputFormat_OUTPUT_FORMAT_UNSPECIFIED, pb.OutputFormat_OUTPUT_FORMAT_BOTH:
+		return OutputFormatBoth, nil
+	case pb.OutputFormat_OUTPUT_FORMAT_RECORDS:
+		return OutputFormatRecords, nil
+	case pb.OutputFormat_OUTPUT_FORMAT_CSV_PATH:
+		if len(req.CardNumbers) > 0 {
+			// カードの絞り込みは明細の解析時に行うため、CSVには全カードの明細が含まれる
+			return 0, fmt.Errorf("card_numbers cannot be used with output_format OUTPUT_FORMAT_CSV_PATH")
+		}
+		return OutputFormatCSVPath, nil
+	default:
+		return 0, fmt.Errorf("unknown output_format %d", req.OutputFormat)
+	}
 }
 
-// NewDownloadServiceGRPCWithMock creates a new gRPC download service with a custom download service
-func NewDownloadServiceGRPCWithMock(downloadService DownloadServiceInterface) *DownloadServiceGRPC {
-	return &DownloadServiceGRPC{
-		downloadService: downloadService,
+// limitInlineRecords は明細がmaxInlineRecordsを超える場合、gRPCのメッセージサイズの上限を超えないよう
+// 明細を除いてtruncatedとする（件数とjob_idは残し、GetJobResult・ExportJobCSVで取得できる）
+func limitInlineRecords(response *pb.DownloadResponse, maxInlineRecords int32) *pb.DownloadResponse {
+	if maxInlineRecords > 0 && response.RecordCount > maxInlineRecords {
+		response.Records = []*pb.ETCMeisaiRecord{}
+		response.Truncated = true
 	}
+	return response
 }
 
-// DownloadSync は同期ダウンロードを実行
-func (s *DownloadServiceGRPC) DownloadSync(ctx context.Context, req *pb.DownloadRequest) (*pb.DownloadResponse, error) {
-	// パラメータのデフォルト値設定
-	fromDate, toDate := s.setDefaultDates(req.FromDate, req.ToDate)
+// syncWaitDeadline は呼び出し元のdeadlineからジョブの終了を待つ期限を求める
+// 残り時間が短い場合は余裕を残り時間の半分にする
+func syncWaitDeadline(deadline time.Time) time.Time {
+	margin := syncResponseMargin
+	if remaining := time.Until(deadline); remaining < 2*margin {
+		margin = remaining / 2
+	}
+	return deadline.Add(-margin)
+}
 
-	// TODO: 実際のダウンロード処理を実装
-	// ここで fromDate と toDate を使用してダウンロード処理を行う
-	_ = fromDate
-	_ = toDate
+// truncatedSyncResponse はdeadlineまでに終わらなかったジョブを中止し、完了したアカウントの明細のみを返す
+func (s *DownloadServiceGRPC) truncatedSyncResponse(jobID string) (*pb.DownloadResponse, error) {
+	if err := s.downloadService.CancelJob(jobID); err != nil && !errors.Is(err, ErrInvalidJobState) {
+		return nil, jobControlError(err)
+	}
+	records, err := s.downloadService.GetJobRecordsSoFar(jobID)
+	if err != nil {
+		return nil, jobControlError(err)
+	}
+	message := fmt.Sprintf("deadline exceeded before job %s finished; returning %d records of completed accounts", jobID, len(records))
+	return &pb.DownloadResponse{
+		Success:      false,
+		RecordCount:  int32(len(records)),
+		Records:      records,
+		Error:        message,
+		ErrorMessage: message,
+		ErrorCode:    pb.ErrorCode_ERROR_CODE_DEADLINE_EXCEEDED,
+		Truncated:    true,
+		JobId:        jobID,
+	}, nil
+}
 
-	response := &pb.DownloadResponse{
-		Success:     true,
-		RecordCount: 0,
-		CsvPath:     "",
-		Records:     []*pb.ETCMeisaiRecord{},
+// waitForJob はジョブが終了するまで待つ（ctxが終了した場合はそのエラーを返す）
+func (s *DownloadServiceGRPC) waitForJob(ctx context.Context, jobID string) (*DownloadJob, error) {
+	ticker := time.NewTicker(syncPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if job, ok := s.downloadService.GetJobStatus(jobID); ok && job.CompletedAt != nil {
+			return job, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
 	}
+}
 
-	return response, nil
+// syncErrorCode は終了したジョブから失敗の種別とメッセージを判定する（成功時はERROR_CODE_UNSPECIFIED）
+// 複数のアカウントが失敗した場合は認証エラー、メンテナンス、タイムアウト、その他の順に優先する
+func syncErrorCode(job *DownloadJob) (pb.ErrorCode, string) {
+	if len(job.FailedAccounts) > 0 {
+		failed, code := job.FailedAccounts[0], pb.ErrorCode_ERROR_CODE_DOWNLOAD_FAILED
+		for _, f := range job.FailedAccounts {
+			if f.AuthError {
+				failed, code = f, pb.ErrorCode_ERROR_CODE_AUTH_FAILED
+				break
+			}
+			if f.Maintenance && code != pb.ErrorCode_ERROR_CODE_MAINTENANCE {
+				failed, code = f, pb.ErrorCode_ERROR_CODE_MAINTENANCE
+			}
+			if f.Timeout && code == pb.ErrorCode_ERROR_CODE_DOWNLOAD_FAILED {
+				failed, code = f, pb.ErrorCode_ERROR_CODE_TIMEOUT
+			}
+		}
+		return code, fmt.Sprintf("%s (%s: %s)", job.ErrorMessage, failed.AccountID, failed.Reason)
+	}
+	if job.Status != "completed" {
+		// キャンセル・中断など
+		return pb.ErrorCode_ERROR_CODE_DOWNLOAD_FAILED, fmt.Sprintf("job %s: %s", job.Status, job.ErrorMessage)
+	}
+	for _, r := range job.AccountResults {
+		if r.ParseFailed {
+			return pb.ErrorCode_ERROR_CODE_PARSE_ERROR, fmt.Sprintf("failed to parse records for account %s", r.AccountID)
+		}
+	}
+	return pb.ErrorCode_ERROR_CODE_UNSPECIFIED, ""
 }
 
 // DownloadAsync は非同期でダウンロードを開始
 func (s *DownloadServiceGRPC) DownloadAsync(ctx context.Context, req *pb.DownloadRequest) (*pb.DownloadJobResponse, error) {
 	// パラメータのデフォルト値設定
-	fromDate, toDate := s.setDefaultDates(req.FromDate, req.ToDate)
+	fromDate, toDate, err := s.setDefaultDates(req.FromDate, req.ToDate)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	timeout, err := requestTimeout(req.TimeoutMs)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.CallbackUrl != "" {
+		if err := ValidateCallbackURL(req.CallbackUrl); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
 
-	accounts := req.Accounts
+	accounts, err := s.requestAccounts(req)
+	if err != nil {
+		return nil, err
+	}
 	if len(accounts) == 0 {
 		// デフォルトで全アカウントを使用（ID:パスワード形式）
 		// GetAllAccountsWithCredentials() を使用して完全な認証情報を取得
@@ -137,59 +330,497 @@ func (s *DownloadServiceGRPC) DownloadAsync(ctx context.Context, req *pb.Downloa
 		}
 	}
 
+	// アカウント形式の検証（strict_accountsの場合は1件でも不正ならジョブを開始しない）
+	if req.StrictAccounts {
+		if errs := ValidateAccounts(accounts); len(errs) > 0 {
+			return nil, invalidAccountsError(errs)
+		}
+	}
+	validAccounts, warnings := partitionAccounts(accounts)
+	if len(warnings) > 0 {
+		for _, w := range warnings {
+			s.logEntry(LogLevelWarn, "Skipping account: "+w)
+		}
+		if len(validAccounts) == 0 {
+			return &pb.DownloadJobResponse{
+				JobId:    "",
+				Status:   "failed",
+				Message:  "No valid accounts",
+				Warnings: warnings,
+			}, nil
+		}
+	}
+
 	// ジョブIDを生成
-	jobID := uuid.New().String()
+	jobID := s.newJobID()
 
 	// 非同期でダウンロード開始
-	s.downloadService.ProcessAsync(jobID, accounts, fromDate, toDate)
+	opts := JobOptions{
+		DryRun:               req.DryRun,
+		Timeout:              timeout,
+		Headless:             req.Headless,
+		FromDateUnset:        req.FromDate == "",
+		CallbackURL:          req.CallbackUrl,
+		CardNumbers:          req.CardNumbers,
+		DownloadCertificates: req.DownloadCertificates,
+	}
+	// ジョブはRPCの終了後も続けるため、リクエストのキャンセルを引き継がないコンテキストで実行する
+	// （キャンセルはCancelJobで行う）
+	if req.IdempotencyKey != "" {
+		existingID, started := s.downloadService.ProcessAsyncIdempotent(context.WithoutCancel(ctx), req.IdempotencyKey, jobID, validAccounts, fromDate, toDate, opts)
+		if !started {
+			// 同じキーのジョブが実行中または終了直後なら、そのジョブを返す
+			response := &pb.DownloadJobResponse{
+				JobId:    existingID,
+				Status:   "pending",
+				Message:  "Existing job returned for idempotency key",
+				Warnings: warnings,
+			}
+			if job, ok := s.downloadService.GetJobStatus(existingID); ok {
+				response.Status = job.Status
+				// 期間は今回のリクエストではなく、既存のジョブの開始時に記録したものを返す
+				if job.checkpoint != nil {
+					response.FromDate = job.checkpoint.FromDate
+					response.ToDate = job.checkpoint.ToDate
+					response.Message += " for " + formatDateRange(job.checkpoint.FromDate, job.checkpoint.ToDate, job.checkpoint.FromDateUnset, false)
+				}
+			}
+			return response, nil
+		}
+	} else {
+		s.downloadService.ProcessAsyncWithOptions(context.WithoutCancel(ctx), jobID, validAccounts, fromDate, toDate, opts)
+	}
+
+	message := "Download job started"
+	if opts.DryRun {
+		message = "Dry run job started"
+	}
 
 	return &pb.DownloadJobResponse{
-		JobId:   jobID,
-		Status:  "pending",
-		Message: "Download job started",
+		JobId:    jobID,
+		Status:   "pending",
+		Message:  message + " for " + dateRangeMessage(req, fromDate, toDate),
+		Warnings: warnings,
+		FromDate: fromDate,
+		ToDate:   toDate,
 	}, nil
 }
 
+// dateRangeMessage はジョブで使う期間を「from to to」の形式で返す
+// 省略したためsetDefaultDatesで決めた日付は、サーバーで決めたことがわかるよう末尾に示す
+func dateRangeMessage(req *pb.DownloadRequest, fromDate, toDate string) string {
+	return formatDateRange(fromDate, toDate, req.FromDate == "", req.ToDate == "")
+}
+
+// formatDateRange は期間を「from to to (default from_date, to_date)」の形式で返す
+// 既存のジョブではto_dateを省略したかは記録していないため、from_dateのみ示す
+func formatDateRange(fromDate, toDate string, fromDefault, toDefault bool) string {
+	var defaulted []string
+	if fromDefault {
+		defaulted = append(defaulted, "from_date")
+	}
+	if toDefault {
+		defaulted = append(defaulted, "to_date")
+	}
+	message := fromDate + " to " + toDate
+	if len(defaulted) > 0 {
+		message += " (default " + strings.Join(defaulted, ", ") + ")"
+	}
+	return message
+}
+
+// requestAccounts はリクエストで指定されたアカウントを返す
+// groupが指定された場合はグループに属する設定済みアカウントに展開する（accountsとの同時指定と未設定のグループはInvalidArgument）
+func (s *DownloadServiceGRPC) requestAccounts(req *pb.DownloadRequest) ([]string, error) {
+	if req.Group == "" {
+		return req.Accounts, nil
+	}
+	if len(req.Accounts) > 0 {
+		return nil, status.Error(codes.InvalidArgument, "accounts and group cannot be specified together")
+	}
+	accounts, err := s.downloadService.GetGroupAccounts(req.Group)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(accounts) == 0 {
+		// 全アカウントに広げて実行しないよう、設定済みのアカウントが無いグループはエラーにする
+		return nil, status.Errorf(codes.FailedPrecondition, "no configured accounts in group %s", req.Group)
+	}
+	return accounts, nil
+}
+
+// partitionAccounts は有効なアカウントと不正なアカウントの警告メッセージに振り分ける
+func partitionAccounts(accounts []string) ([]string, []string) {
+	errs := ValidateAccounts(accounts)
+	invalid := make(map[int]bool, len(errs))
+	warnings := make([]string, 0, len(errs))
+	for _, e := range errs {
+		invalid[e.Index] = true
+		warnings = append(warnings, e.Error())
+	}
+	if len(warnings) == 0 {
+		warnings = nil
+	}
+
+	valid := make([]string, 0, len(accounts))
+	for i, account := range accounts {
+		if !invalid[i] {
+			valid = append(valid, account)
+		}
+	}
+	return valid, warnings
+}
+
+// requestTimeout はリクエストのtimeout_msを検証して変換する（未指定は0）
+func requestTimeout(timeoutMs int32) (time.Duration, error) {
+	if timeoutMs == 0 {
+		return 0, nil
+	}
+	timeout := time.Duration(timeoutMs) * time.Millisecond
+	if err := ValidateTimeout(timeout); err != nil {
+		return 0, fmt.Errorf("invalid timeout_ms: %w", err)
+	}
+	return timeout, nil
+}
+
+// invalidAccountsError は不正なアカウントを列挙したInvalidArgumentエラーを返す（パスワードはマスク済み）
+func invalidAccountsError(errs []AccountError) error {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	return status.Errorf(codes.InvalidArgument, "invalid accounts: %s", strings.Join(messages, "; "))
+}
+
 // GetJobStatus はジョブのステータスを取得
 func (s *DownloadServiceGRPC) GetJobStatus(ctx context.Context, req *pb.GetJobStatusRequest) (*pb.JobStatus, error) {
 	job, exists := s.downloadService.GetJobStatus(req.JobId)
 	if !exists {
-		return nil, nil
+		return nil, status.Errorf(codes.NotFound, "job not found: %s", req.JobId)
 	}
 
-	status := &pb.JobStatus{
-		JobId:        job.ID,
-		Status:       job.Status,
-		Progress:     int32(job.Progress),
-		TotalRecords: int32(job.TotalRecords),
-		ErrorMessage: job.ErrorMessage,
-		StartedAt:    timestamppb.New(job.StartedAt),
+	return jobToProto(job), nil
+}
+
+// WaitForJob はジョブのstatusが変わるか終了するまで待ってステータスを返す
+// 呼び出し元にdeadlineがある場合は、DeadlineExceededにならないようその少し前に待機をやめて現在のステータスを返す
+func (s *DownloadServiceGRPC) WaitForJob(ctx context.Context, req *pb.WaitForJobRequest) (*pb.JobStatus, error) {
+	waitCtx := ctx
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithDeadline(ctx, syncWaitDeadline(deadline))
+		defer cancel()
 	}
 
-	if job.CompletedAt != nil {
-		status.CompletedAt = timestamppb.New(*job.CompletedAt)
+	job, exists := s.downloadService.WaitForJob(waitCtx, req.JobId, req.LastStatus)
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "job not found: %s", req.JobId)
+	}
+	return jobToProto(job), nil
+}
+
+// GetJobStatuses は複数のジョブのステータスを一括取得
+// 存在しないジョブがあってもエラーにせず、found=falseのエントリとして返す
+func (s *DownloadServiceGRPC) GetJobStatuses(ctx context.Context, req *pb.GetJobStatusesRequest) (*pb.GetJobStatusesResponse, error) {
+	jobs := s.downloadService.GetJobStatuses(req.JobIds)
+
+	entries := make([]*pb.JobStatusEntry, len(req.JobIds))
+	for i, jobID := range req.JobIds {
+		entry := &pb.JobStatusEntry{JobId: jobID}
+		if job, exists := jobs[jobID]; exists {
+			entry.Found = true
+			entry.Status = jobToProto(job)
+		}
+		entries[i] = entry
+	}
+	return &pb.GetJobStatusesResponse{Entries: entries}, nil
+}
+
+// PauseJob は実行中のジョブを一時停止
+func (s *DownloadServiceGRPC) PauseJob(ctx context.Context, req *pb.PauseJobRequest) (*pb.JobStatus, error) {
+	if err := s.downloadService.PauseJob(req.JobId); err != nil {
+		return nil, jobControlError(err)
+	}
+	job, _ := s.downloadService.GetJobStatus(req.JobId)
+	return jobToProto(job), nil
+}
+
+// ResumeJob は一時停止中のジョブ、またはプロセスの再起動で中断した（interruptedの）ジョブを再開
+// 中断したジョブは完了していないアカウントのみを処理し、同じジョブIDのprocessingの状態を返す
+func (s *DownloadServiceGRPC) ResumeJob(ctx context.Context, req *pb.ResumeJobRequest) (*pb.JobStatus, error) {
+	if err := s.downloadService.ResumeJob(req.JobId); err != nil {
+		return nil, jobControlError(err)
+	}
+	job, _ := s.downloadService.GetJobStatus(req.JobId)
+	return jobToProto(job), nil
+}
+
+// CancelJob は実行中または一時停止中のジョブをキャンセル
+// キャンセルは処理中のアカウントが終わった後に反映されるため、直後のステータスはprocessing/pausedのまま
+func (s *DownloadServiceGRPC) CancelJob(ctx context.Context, req *pb.CancelJobRequest) (*pb.JobStatus, error) {
+	if err := s.downloadService.CancelJob(req.JobId); err != nil {
+		return nil, jobControlError(err)
+	}
+	job, _ := s.downloadService.GetJobStatus(req.JobId)
+	return jobToProto(job), nil
+}
+
+// 明細取得の1ページの件数
+const (
+	defaultResultPageSize = 100
+	maxResultPageSize     = 1000
+)
+
+// GetJobResult は終了したジョブでダウンロードした明細を取得（page_tokenは次の開始位置）
+func (s *DownloadServiceGRPC) GetJobResult(ctx context.Context, req *pb.GetJobResultRequest) (*pb.GetJobResultResponse, error) {
+	records, err := s.downloadService.GetJobRecords(req.JobId)
+	if err != nil {
+		return nil, jobControlError(err)
+	}
+
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultResultPageSize
+	}
+	if pageSize > maxResultPageSize {
+		pageSize = maxResultPageSize
+	}
+
+	offset := 0
+	if req.PageToken != "" {
+		offset, err = strconv.Atoi(req.PageToken)
+		if err != nil || offset < 0 || offset > len(records) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token: %q", req.PageToken)
+		}
+	}
+
+	end := offset + pageSize
+	if end > len(records) {
+		end = len(records)
+	}
+
+	resp := &pb.GetJobResultResponse{
+		Records:      records[offset:end],
+		TotalRecords: int32(len(records)),
+	}
+	if end < len(records) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+// exportChunkSize はExportJobCSVで1メッセージに含めるCSVの最大バイト数
+const exportChunkSize = 32 * 1024
+
+// ExportJobCSV は終了したジョブの明細をCSVとして分割送信する
+func (s *DownloadServiceGRPC) ExportJobCSV(req *pb.ExportJobCSVRequest, stream pb.DownloadService_ExportJobCSVServer) error {
+	// 送信を始める前にジョブの状態を確認し、エラーをステータスコードで返す
+	if _, err := s.downloadService.GetJobRecords(req.JobId); err != nil {
+		return jobControlError(err)
+	}
+
+	w := bufio.NewWriterSize(exportChunkWriter{stream: stream}, exportChunkSize)
+	if err := s.downloadService.ExportJobCSV(req.JobId, w); err != nil {
+		return jobControlError(err)
+	}
+	if err := w.Flush(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// exportChunkWriter は書き込まれたバイト列をExportJobCSVChunkとして送信する
+type exportChunkWriter struct {
+	stream pb.DownloadService_ExportJobCSVServer
+}
+
+func (w exportChunkWriter) Write(p []byte) (int, error) {
+	// Sendの後にbufioがバッファを再利用するためコピーして送信する
+	data := append([]byte(nil), p...)
+	if err := w.stream.Send(&pb.ExportJobCSVChunk{Data: data}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// GetJobFiles は終了したジョブのセッションフォルダのファイル一覧を取得
+func (s *DownloadServiceGRPC) GetJobFiles(ctx context.Context, req *pb.GetJobFilesRequest) (*pb.GetJobFilesResponse, error) {
+	files, err := s.downloadService.GetJobFiles(req.JobId)
+	if err != nil {
+		return nil, jobControlError(err)
 	}
 
-	return status, nil
+	resp := &pb.GetJobFilesResponse{Files: make([]*pb.JobFile, 0, len(files))}
+	for _, f := range files {
+		resp.Files = append(resp.Files, &pb.JobFile{
+			Name:      f.Name,
+			Size:      f.Size,
+			AccountId: f.AccountID,
+		})
+	}
+	return resp, nil
+}
+
+// DownloadFile は終了したジョブのセッションフォルダのファイルを分割送信する
+func (s *DownloadServiceGRPC) DownloadFile(req *pb.DownloadFileRequest, stream pb.DownloadService_DownloadFileServer) error {
+	// WriteJobFileはファイルを開いてから書き込むため、エラーはバッファを送信する前に返る
+	w := bufio.NewWriterSize(fileChunkWriter{stream: stream}, exportChunkSize)
+	if err := s.downloadService.WriteJobFile(req.JobId, req.Name, w); err != nil {
+		return jobControlError(err)
+	}
+	if err := w.Flush(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// fileChunkWriter は書き込まれたバイト列をDownloadFileChunkとして送信する
+type fileChunkWriter struct {
+	stream pb.DownloadService_DownloadFileServer
+}
+
+func (w fileChunkWriter) Write(p []byte) (int, error) {
+	// Sendの後にbufioがバッファを再利用するためコピーして送信する
+	data := append([]byte(nil), p...)
+	if err := w.stream.Send(&pb.DownloadFileChunk{Data: data}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// jobControlError はジョブ操作のエラーをgRPCステータスに変換
+func jobControlError(err error) error {
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrInvalidJobState):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, ErrAccountNotFound):
+		// 中断したジョブのアカウントが設定から削除された場合（ResumeJob）
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, ErrInvalidFileName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrFileNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
 }
 
 // GetAllAccountIDs は設定されている全アカウントIDを取得
 func (s *DownloadServiceGRPC) GetAllAccountIDs(ctx context.Context, req *pb.GetAllAccountIDsRequest) (*pb.GetAllAccountIDsResponse, error) {
 	accountIDs := s.downloadService.GetAllAccountIDs()
+	if req.Group != "" {
+		accounts, err := s.downloadService.GetGroupAccounts(req.Group)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		accountIDs = make([]string, 0, len(accounts))
+		for _, account := range accounts {
+			accountIDs = append(accountIDs, strings.TrimSpace(accountUserID(account)))
+		}
+	}
 	return &pb.GetAllAccountIDsResponse{
 		AccountIds: accountIDs,
 	}, nil
 }
 
+// GetAccountStatus はアカウントごとの最後の実行結果を返す（パスワードは含めない）
+func (s *DownloadServiceGRPC) GetAccountStatus(ctx context.Context, req *pb.GetAccountStatusRequest) (*pb.GetAccountStatusResponse, error) {
+	runs := s.downloadService.GetAccountStatuses(req.AccountIds)
+	resp := &pb.GetAccountStatusResponse{Accounts: make([]*pb.AccountStatus, 0, len(runs))}
+	for _, run := range runs {
+		account := &pb.AccountStatus{
+			AccountId:       run.AccountID,
+			LastJobId:       run.LastJobID,
+			LastOutcome:     run.LastOutcome,
+			LastRecordCount: int32(run.LastRecordCount),
+			LastError:       run.LastError,
+		}
+		if run.LastRunAt != nil {
+			account.LastRunAt = timestamppb.New(*run.LastRunAt)
+		}
+		if run.LastSuccessAt != nil {
+			account.LastSuccessAt = timestamppb.New(*run.LastSuccessAt)
+		}
+		if run.LastErrorAt != nil {
+			account.LastErrorAt = timestamppb.New(*run.LastErrorAt)
+		}
+		resp.Accounts = append(resp.Accounts, account)
+	}
+	return resp, nil
+}
+
+// TestAccount は単一アカウントの初期化とログインのみを行い、結果を返す
+// 認証情報はレスポンスにもログにも含めない
+func (s *DownloadServiceGRPC) TestAccount(ctx context.Context, req *pb.TestAccountRequest) (*pb.TestAccountResponse, error) {
+	var account string
+	if req.AccountIndex != nil {
+		accounts := s.downloadService.GetAllAccountsWithCredentials()
+		index := int(req.GetAccountIndex())
+		if index < 0 || index >= len(accounts) {
+			return nil, status.Errorf(codes.InvalidArgument, "account_index %d out of range (%d accounts configured)", index, len(accounts))
+		}
+		account = accounts[index]
+	} else {
+		if req.AccountId == "" || req.Password == "" {
+			return nil, status.Error(codes.InvalidArgument, "account_id and password (or account_index) are required")
+		}
+		account = req.AccountId + ":" + req.Password
+	}
+
+	result := s.downloadService.TestAccount(ctx, account)
+	return &pb.TestAccountResponse{
+		Ok:        result.OK,
+		LatencyMs: result.Latency.Milliseconds(),
+		Error:     result.Error,
+	}, nil
+}
+
+// UpdateCredential は環境変数に設定されたアカウントのパスワードを更新する
+// パスワードはレスポンスにもログにも含めない
+func (s *DownloadServiceGRPC) UpdateCredential(ctx context.Context, req *pb.UpdateCredentialRequest) (*pb.UpdateCredentialResponse, error) {
+	if err := s.downloadService.UpdateAccountCredential(req.AccountId, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidCredential):
+			return nil, status.Error(codes.InvalidArgument, "account_id and new_password are required")
+		case errors.Is(err, ErrAccountNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	return &pb.UpdateCredentialResponse{
+		AccountId: strings.TrimSpace(req.AccountId),
+	}, nil
+}
+
+// GetVersion はビルド情報を取得
+func (s *DownloadServiceGRPC) GetVersion(ctx context.Context, req *pb.GetVersionRequest) (*pb.GetVersionResponse, error) {
+	info := buildinfo.Get()
+	return &pb.GetVersionResponse{
+		Version:                 info.Version,
+		Commit:                  info.Commit,
+		BuildTime:               info.BuildTime,
+		GoVersion:               info.GoVersion,
+		PlaywrightDriverVersion: scraper.PlaywrightDriverVersion(),
+	}, nil
+}
+
 // GetEnvironmentVariables は環境変数を取得（デバッグ用）
 func (s *DownloadServiceGRPC) GetEnvironmentVariables(ctx context.Context, req *pb.GetEnvironmentVariablesRequest) (*pb.GetEnvironmentVariablesResponse, error) {
 	return &pb.GetEnvironmentVariablesResponse{
-		EtcCorpAccounts:       maskAccountString(os.Getenv("ETC_CORP_ACCOUNTS")),
-		EtcHeadless:           os.Getenv("ETC_HEADLESS"),
-		GrpcPort:              os.Getenv("GRPC_PORT"),
-		HttpPort:              os.Getenv("HTTP_PORT"),
-		EtcCorporateAccounts:  maskAccountString(os.Getenv("ETC_CORPORATE_ACCOUNTS")),
-		EtcPersonalAccounts:   maskAccountString(os.Getenv("ETC_PERSONAL_ACCOUNTS")),
+		EtcCorpAccounts:      maskAccountString(os.Getenv("ETC_CORP_ACCOUNTS")),
+		EtcHeadless:          os.Getenv("ETC_HEADLESS"),
+		GrpcPort:             os.Getenv("GRPC_PORT"),
+		HttpPort:             os.Getenv("HTTP_PORT"),
+		EtcCorporateAccounts: maskAccountString(os.Getenv("ETC_CORPORATE_ACCOUNTS")),
+		EtcPersonalAccounts:  maskAccountString(os.Getenv("ETC_PERSONAL_ACCOUNTS")),
+		EtcDownloadDir:       os.Getenv("ETC_DOWNLOAD_DIR"),
+		EtcMaxConcurrency:    os.Getenv("ETC_MAX_CONCURRENCY"),
+		EtcTimeoutMs:         os.Getenv("ETC_TIMEOUT_MS"),
+		CredentialOverrides:  maskCredentialOverrides(s.downloadService.CredentialOverrideIDs()),
+		EtcAccountsFile:      os.Getenv("ETC_ACCOUNTS_FILE"),
 	}, nil
 }
 
@@ -200,57 +831,353 @@ func (s *DownloadServiceGRPC) GetServerLogs(ctx context.Context, req *pb.GetServ
 		tailLines = 100 // デフォルト100行
 	}
 
-	var logLines []string
-	if s.logBuffer != nil {
-		logLines = s.logBuffer.GetTail(tailLines)
-	} else {
-		logLines = []string{"Log buffer not initialized"}
+	if s.logBuffer == nil {
+		return &pb.GetServerLogsResponse{
+			LogLines:   []string{"Log buffer not initialized"},
+			TotalLines: 1,
+		}, nil
+	}
+
+	if req.Offset < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "offset must not be negative: %d", req.Offset)
+	}
+
+	offset := int(req.Offset)
+	entries, hasMore := s.logBuffer.GetRangeEntries(offset, tailLines, logLevelFromProto(req.MinLevel))
+	logLines := make([]string, len(entries))
+	pbEntries := make([]*pb.LogEntry, len(entries))
+	for i, entry := range entries {
+		logLines[i] = entry.Message
+		pbEntries[i] = &pb.LogEntry{
+			Timestamp: timestamppb.New(entry.Timestamp),
+			Level:     logLevelToProto(entry.Level),
+			Message:   entry.Message,
+		}
 	}
 
 	return &pb.GetServerLogsResponse{
 		LogLines:   logLines,
 		TotalLines: int32(len(logLines)),
+		Entries:    pbEntries,
+		NextOffset: int32(offset + len(entries)),
+		HasMore:    hasMore,
 	}, nil
 }
 
+// GetJobLogs はジョブごとのログを取得
+func (s *DownloadServiceGRPC) GetJobLogs(ctx context.Context, req *pb.GetJobLogsRequest) (*pb.GetJobLogsResponse, error) {
+	tailLines := int(req.TailLines)
+	if tailLines <= 0 {
+		tailLines = 100 // デフォルト100行
+	}
+
+	entries, err := s.downloadService.GetJobLogs(req.JobId, tailLines)
+	if err != nil {
+		return nil, jobControlError(err)
+	}
+
+	logLines := make([]string, len(entries))
+	pbEntries := make([]*pb.LogEntry, len(entries))
+	for i, entry := range entries {
+		logLines[i] = entry.Message
+		pbEntries[i] = &pb.LogEntry{
+			Timestamp: timestamppb.New(entry.Timestamp),
+			Level:     logLevelToProto(entry.Level),
+			Message:   entry.Message,
+		}
+	}
+
+	return &pb.GetJobLogsResponse{
+		JobId:    req.JobId,
+		LogLines: logLines,
+		Entries:  pbEntries,
+	}, nil
+}
+
+// logLevelFromProto はgRPCのログレベルを変換（未指定は全レベル対象のDebug）
+func logLevelFromProto(level pb.LogLevel) LogLevel {
+	switch level {
+	case pb.LogLevel_LOG_LEVEL_INFO:
+		return LogLevelInfo
+	case pb.LogLevel_LOG_LEVEL_WARN:
+		return LogLevelWarn
+	case pb.LogLevel_LOG_LEVEL_ERROR:
+		return LogLevelError
+	default:
+		return LogLevelDebug
+	}
+}
+
+// logLevelToProto はログレベルをgRPCのログレベルに変換
+func logLevelToProto(level LogLevel) pb.LogLevel {
+	switch level {
+	case LogLevelDebug:
+		return pb.LogLevel_LOG_LEVEL_DEBUG
+	case LogLevelInfo:
+		return pb.LogLevel_LOG_LEVEL_INFO
+	case LogLevelWarn:
+		return pb.LogLevel_LOG_LEVEL_WARN
+	case LogLevelError:
+		return pb.LogLevel_LOG_LEVEL_ERROR
+	default:
+		return pb.LogLevel_LOG_LEVEL_UNSPECIFIED
+	}
+}
+
+// Metrics はジョブ数とダウンロード時間のメトリクスを返す（メトリクス無効時やモックの場合はnil）
+func (s *DownloadServiceGRPC) Metrics() *JobMetrics {
+	if ds, ok := s.downloadService.(interface{ Metrics() *JobMetrics }); ok {
+		return ds.Metrics()
+	}
+	return nil
+}
+
+// Shutdown は実行中のジョブをキャンセルし、ctxの期限まで終了を待つ（モックなど非対応の場合は何もしない）
+func (s *DownloadServiceGRPC) Shutdown(ctx context.Context) error {
+	if ds, ok := s.downloadService.(interface{ Shutdown(context.Context) error }); ok {
+		return ds.Shutdown(ctx)
+	}
+	return nil
+}
+
+// GetRuntimeMetrics は稼働状況のスナップショットを取得
+func (s *DownloadServiceGRPC) GetRuntimeMetrics(ctx context.Context, req *pb.GetRuntimeMetricsRequest) (*pb.RuntimeMetrics, error) {
+	m := s.downloadService.GetRuntimeMetrics()
+	resp := &pb.RuntimeMetrics{
+		QueuedJobs:          int32(m.QueuedJobs),
+		RunningJobs:         int32(m.RunningJobs),
+		PausedJobs:          int32(m.PausedJobs),
+		QueuedAccounts:      int32(m.QueuedAccounts),
+		BusyWorkers:         int32(m.BusyWorkers),
+		IdleWorkers:         int32(m.IdleWorkers),
+		ActiveBrowsers:      int32(m.ActiveBrowsers),
+		RateBudgetRemaining: int32(m.RateBudgetRemaining),
+	}
+	if s.logBuffer != nil {
+		resp.LogBufferLines = int32(s.logBuffer.Len())
+		resp.LogBufferCapacity = int32(s.logBuffer.Capacity())
+		resp.LogDroppedLines = s.logBuffer.DroppedLines()
+	}
+	return resp, nil
+}
+
+// StreamServerLogs はサーバーログをストリーミング
+// 最初に末尾tail_lines行を送信し、その後は新しいログを受信するたびに送信する
+func (s *DownloadServiceGRPC) StreamServerLogs(req *pb.StreamServerLogsRequest, stream pb.DownloadService_StreamServerLogsServer) error {
+	if s.logBuffer == nil {
+		return status.Error(codes.Unavailable, "log buffer not initialized")
+	}
+
+	tailLines := int(req.TailLines)
+	if tailLines <= 0 {
+		tailLines = 100 // デフォルト100行
+	}
+
+	tail, lines, unsubscribe := s.logBuffer.subscribeWithTail(tailLines)
+	defer unsubscribe()
+
+	for _, line := range tail {
+		if err := stream.Send(&pb.StreamServerLogsResponse{Line: line}); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(&pb.StreamServerLogsResponse{Line: line}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// CleanupDownloadsOnStart はETC_CLEANUP_ON_STARTより古いセッションフォルダを削除する
+// （サーバー起動時に呼び出す、モックなど非対応の場合は何もしない）
+func (s *DownloadServiceGRPC) CleanupDownloadsOnStart() (removed int, err error) {
+	if ds, ok := s.downloadService.(interface{ CleanupDownloadsOnStart() (int, error) }); ok {
+		return ds.CleanupDownloadsOnStart()
+	}
+	return 0, nil
+}
+
+// StartJobReaper は終了済みジョブの定期削除を開始（サーバー起動時に呼び出す）
+func (s *DownloadServiceGRPC) StartJobReaper(interval time.Duration) (stop func()) {
+	return s.downloadService.StartJobReaper(interval)
+}
+
 // LogMessage はログメッセージをバッファに追加（外部から呼び出し可能）
 func (s *DownloadServiceGRPC) LogMessage(message string) {
+	s.logEntry(LogLevelInfo, message)
+}
+
+// logEntry は指定レベルでログメッセージをバッファに追加
+func (s *DownloadServiceGRPC) logEntry(level LogLevel, message string) {
 	if s.logBuffer != nil {
-		s.logBuffer.Add(message)
+		s.logBuffer.AddEntry(LogEntry{Timestamp: time.Now(), Level: level, Message: message})
 	}
 }
 
+// jobToProto はDownloadJobをgRPCのJobStatusに変換
+func jobToProto(job *DownloadJob) *pb.JobStatus {
+	status := &pb.JobStatus{
+		JobId:            job.ID,
+		Status:           job.Status,
+		Progress:         int32(job.Progress),
+		TotalRecords:     int32(job.TotalRecords),
+		ErrorMessage:     job.ErrorMessage,
+		StartedAt:        timestamppb.New(job.StartedAt),
+		CurrentAccount:   job.CurrentAccount,
+		PerAccount:       job.PerAccount,
+		DryRun:           job.DryRun,
+		RescheduledJobId: job.RescheduledJobID,
+		Passes:           int32(job.Passes),
+		TotalBytes:       job.TotalBytes,
+		FileCount:        int32(job.FileCount),
+	}
+
+	if job.CompletedAt != nil {
+		status.CompletedAt = timestamppb.New(*job.CompletedAt)
+	}
+	if job.RescheduledAt != nil {
+		status.RescheduledAt = timestamppb.New(*job.RescheduledAt)
+	}
+
+	for _, f := range job.FailedAccounts {
+		status.FailedAccounts = append(status.FailedAccounts, &pb.FailedAccount{
+			AccountId:   f.AccountID,
+			Reason:      f.Reason,
+			AuthError:   f.AuthError,
+			Maintenance: f.Maintenance,
+			Timeout:     f.Timeout,
+		})
+	}
+
+	for _, r := range job.AccountResults {
+		status.AccountResults = append(status.AccountResults, &pb.AccountResult{
+			AccountId:          r.AccountID,
+			ExpectedRecords:    int32(r.ExpectedRecords),
+			ExpectedKnown:      r.ExpectedKnown,
+			ActualRecords:      int32(r.ActualRecords),
+			CountMismatch:      r.CountMismatch,
+			Truncated:          r.Truncated,
+			CertificatePaths:   r.CertificatePaths,
+			CertificatesFailed: r.CertificatesFailed,
+			CsvPath:            r.CSVPath,
+		})
+	}
+
+	return status
+}
+
 // maskAccountString はアカウント文字列をマスク（パスワード部分を隠す）
 func maskAccountString(accountStr string) string {
 	if accountStr == "" {
 		return ""
 	}
 
-	accounts := strings.Split(accountStr, ",")
-	maskedAccounts := make([]string, len(accounts))
+	// JSON配列形式は要素ごとにマスクしてJSON配列のまま返す
+	// （カンマで分割するとパスワード内のカンマや引用符で要素が崩れる）
+	if strings.HasPrefix(strings.TrimSpace(accountStr), "[") {
+		var jsonAccounts []string
+		if err := json.Unmarshal([]byte(accountStr), &jsonAccounts); err == nil {
+			masked, err := json.Marshal(maskAccounts(jsonAccounts))
+			if err == nil {
+				return string(masked)
+			}
+		}
+	}
+
+	// カンマ区切り形式（JSONとして解析できない場合も含む）はparseAccountsStringと同じく引用符を考慮して分割する
+	return strings.Join(maskAccounts(splitAccountList(accountStr)), ",")
+}
+
+// maskCredentialOverrides はパスワードを更新したアカウントを userid:******* の形式で返す
+func maskCredentialOverrides(accountIDs []string) []string {
+	masked := make([]string, len(accountIDs))
+	for i, accountID := range accountIDs {
+		masked[i] = accountID + ":*******"
+	}
+	return masked
+}
 
+// maskAccounts は各アカウントのパスワードをマスクする（パスワード内のコロンも含めて隠す）
+func maskAccounts(accounts []string) []string {
+	maskedAccounts := make([]string, len(accounts))
 	for i, account := range accounts {
-		parts := strings.Split(account, ":")
-		if len(parts) >= 2 {
+		parts := strings.SplitN(account, ":", 2)
+		if len(parts) == 2 {
 			// userid:******* の形式にマスク
 			maskedAccounts[i] = parts[0] + ":*******"
 		} else {
 			maskedAccounts[i] = account
 		}
 	}
+	return maskedAccounts
+}
+
+// dateLayout はスクレイパーに渡す日付の正規形式
+const dateLayout = "2006-01-02"
+
+// flexibleDateLayouts はリクエストで受け付ける日付形式（先頭から順に試す）
+var flexibleDateLayouts = []string{dateLayout, "2006/01/02", "20060102"}
 
-	return strings.Join(maskedAccounts, ",")
+// parseFlexibleDate は複数の日付形式（2006-01-02, 2006/01/02, 20060102）を解析
+func parseFlexibleDate(s string) (time.Time, error) {
+	for _, layout := range flexibleDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD, YYYY/MM/DD or YYYYMMDD)", s)
 }
 
-// setDefaultDates はデフォルトの日付を設定
-func (s *DownloadServiceGRPC) setDefaultDates(fromDate, toDate string) (string, string) {
+// setDefaultDates はデフォルトの日付を設定し、日付を2006-01-02形式に正規化
+func (s *DownloadServiceGRPC) setDefaultDates(fromDate, toDate string) (string, string, error) {
 	now := time.Now()
 	if toDate == "" {
-		toDate = now.Format("2006-01-02")
+		toDate = now.Format(dateLayout)
+	} else {
+		t, err := parseFlexibleDate(toDate)
+		if err != nil {
+			return "", "", fmt.Errorf("to_date: %w", err)
+		}
+		toDate = t.Format(dateLayout)
 	}
 	if fromDate == "" {
 		lastMonth := now.AddDate(0, -1, 0)
-		fromDate = lastMonth.Format("2006-01-02")
+		fromDate = lastMonth.Format(dateLayout)
+	} else {
+		t, err := parseFlexibleDate(fromDate)
+		if err != nil {
+			return "", "", fmt.Errorf("from_date: %w", err)
+		}
+		fromDate = t.Format(dateLayout)
+	}
+	return fromDate, toDate, nil
+}
+
+// defaultLogBufferSize はETC_LOG_BUFFER_SIZE未設定時のログバッファの最大行数
+const defaultLogBufferSize = 1000
+
+// getLogBufferSize は環境変数からログバッファの最大行数を取得
+// ETC_LOG_BUFFER_SIZE 未設定または不正値（正の整数以外）の場合は1000
+func getLogBufferSize() int {
+	sizeEnv := os.Getenv("ETC_LOG_BUFFER_SIZE")
+	if sizeEnv == "" {
+		return defaultLogBufferSize
 	}
-	return fromDate, toDate
-}
\ No newline at end of file
+
+	size, err := strconv.Atoi(sizeEnv)
+	if err != nil || size < 1 {
+		log.Printf("[LogBuffer] Invalid ETC_LOG_BUFFER_SIZE value %q, using default: %d", sizeEnv, defaultLogBufferSize)
+		return defaultLogBufferSize
+	}
+
+	return size
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxIdempotencyKeys は保持する冪等キーの上限（超えた場合は古いキーから削除）
+const maxIdempotencyKeys = 10000
+
+// idempotencyKeys はDownloadAsyncの冪等キーと開始したジョブIDの対応
+// クライアントのリトライで同じジョブが重複して開始されないようにする
+type idempotencyKeys struct {
+	mu      sync.Mutex // キーの確認からジョブの登録までを直列化する
+	entries map[string]idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	jobID     string
+	createdAt time.Time
+}
+
+// ProcessAsyncIdempotent は冪等キーを指定して非同期でダウンロードを実行し、ジョブIDを返す
+// 同じキーのジョブが実行中またはJobTTL以内に終了していれば新しいジョブを開始せず、そのジョブIDとfalseを返す
+func (s *DownloadService) ProcessAsyncIdempotent(ctx context.Context, key, jobID string, accounts []string, fromDate, toDate string, opts JobOptions) (string, bool) {
+	s.idempotency.mu.Lock()
+	defer s.idempotency.mu.Unlock()
+
+	if s.idempotency.entries == nil {
+		s.idempotency.entries = make(map[string]idempotencyEntry)
+	}
+	if entry, ok := s.idempotency.entries[key]; ok && s.jobIsRetained(entry.jobID) {
+		s.logJobf(LogLevelInfo, entry.jobID, "", "Returning existing job %s for idempotency key", entry.jobID)
+		return entry.jobID, false
+	}
+
+	s.pruneIdempotencyKeysLocked()
+	s.idempotency.entries[key] = idempotencyEntry{jobID: jobID, createdAt: time.Now()}
+	s.ProcessAsyncWithOptions(ctx, jobID, accounts, fromDate, toDate, opts)
+	return jobID, true
+}
+
+// jobIsRetained はジョブが実行中、またはJobTTL以内に終了していればtrueを返す
+// MaxJobsやJobTTLで削除されたジョブはfalse
+func (s *DownloadService) jobIsRetained(jobID string) bool {
+	s.jobMutex.RLock()
+	defer s.jobMutex.RUnlock()
+
+	job, exists := s.jobs[jobID]
+	if !exists {
+		return false
+	}
+	if !isTerminalStatus(job.Status) || job.CompletedAt == nil || s.JobTTL <= 0 {
+		return true
+	}
+	return time.Since(*job.CompletedAt) <= s.JobTTL
+}
+
+// pruneIdempotencyKeysLocked は保持されなくなったジョブのキーを削除し、
+// それでも上限に達していれば古いキーから削除する（idempotency.muを保持して呼ぶこと）
+func (s *DownloadService) pruneIdempotencyKeysLocked() {
+	for key, entry := range s.idempotency.entries {
+		if !s.jobIsRetained(entry.jobID) {
+			delete(s.idempotency.entries, key)
+		}
+	}
+	for len(s.idempotency.entries) >= maxIdempotencyKeys {
+		oldestKey := ""
+		var oldest time.Time
+		for key, entry := range s.idempotency.entries {
+			if oldestKey == "" || entry.createdAt.Before(oldest) {
+				oldestKey, oldest = key, entry.createdAt
+			}
+		}
+		delete(s.idempotency.entries, oldestKey)
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ジョブ完了通知（コールバック）の送信設定
+const (
+	// callbackTimeout は1回の送信のタイムアウト
+	callbackTimeout = 5 * time.Second
+	// callbackAttempts は送信の最大試行回数（初回を含む）
+	callbackAttempts = 3
+	// defaultCallbackRetryDelay は再送までの待機時間の既定値
+	defaultCallbackRetryDelay = time.Second
+)
+
+// HTTPDoer はコールバックの送信に使うHTTPクライアント（*http.Clientが満たす）
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// JobCallbackPayload はジョブ終了時にコールバックURLへPOSTするJSON
+type JobCallbackPayload struct {
+	JobID          string                     `json:"job_id"`
+	Status         string                     `json:"status"`
+	RecordCount    int                        `json:"record_count"`
+	FailedAccounts []JobCallbackFailedAccount `json:"failed_accounts"`
+}
+
+// JobCallbackFailedAccount は失敗したアカウントの情報
+type JobCallbackFailedAccount struct {
+	AccountID string `json:"account_id"`
+	Reason    string `json:"reason"`
+}
+
+// ValidateCallbackURL はコールバックURLがhttp(s)の絶対URLか検証する
+func ValidateCallbackURL(callbackURL string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil {
+		return fmt.Errorf("invalid callback_url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid callback_url: must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// notifyJobCallback はジョブの終了状態をコールバックURLへ送信する
+// 送信に失敗してもログに出力するだけで、ジョブの状態は変更しない
+func (s *DownloadService) notifyJobCallback(jobID, callbackURL string) {
+	payload, ok := s.jobCallbackPayload(jobID)
+	if !ok {
+		return
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		s.logJobf(LogLevelError, jobID, "", "Failed to encode callback for job %s: %v", jobID, err)
+		return
+	}
+
+	target := callbackLogURL(callbackURL)
+	for attempt := 1; attempt <= callbackAttempts; attempt++ {
+		err = s.postJobCallback(callbackURL, body)
+		if err == nil {
+			s.logJobf(LogLevelInfo, jobID, "", "Delivered callback for job %s to %s", jobID, target)
+			return
+		}
+		if attempt < callbackAttempts {
+			s.logJobf(LogLevelWarn, jobID, "", "Callback for job %s to %s failed (attempt %d/%d): %v",
+				jobID, target, attempt, callbackAttempts, err)
+			time.Sleep(s.CallbackRetryDelay)
+		}
+	}
+	s.logJobf(LogLevelError, jobID, "", "Giving up on callback for job %s to %s after %d attempts: %v",
+		jobID, target, callbackAttempts, err)
+}
+
+// jobCallbackPayload はジョブの現在の状態から送信内容を作成する
+func (s *DownloadService) jobCallbackPayload(jobID string) (JobCallbackPayload, bool) {
+	s.jobMutex.RLock()
+	defer s.jobMutex.RUnlock()
+
+	job, exists := s.jobs[jobID]
+	if !exists {
+		return JobCallbackPayload{}, false
+	}
+	payload := JobCallbackPayload{
+		JobID:          job.ID,
+		Status:         job.Status,
+		RecordCount:    job.TotalRecords,
+		FailedAccounts: make([]JobCallbackFailedAccount, 0, len(job.FailedAccounts)),
+	}
+	for _, f := range job.FailedAccounts {
+		payload.FailedAccounts = append(payload.FailedAccounts, JobCallbackFailedAccount{
+			AccountID: f.AccountID,
+			Reason:    f.Reason,
+		})
+	}
+	return payload, true
+}
+
+// postJobCallback は1回分の送信を行い、2xx以外の応答をエラーとして返す
+func (s *DownloadService) postJobCallback(callbackURL string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), callbackTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := s.CallbackClient
+	if client == nil {
+		return errors.New("no callback client configured")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// callbackLogURL はログ出力用に認証情報とクエリを除いたURLを返す
+func callbackLogURL(callbackURL string) string {
+	u, err := url.Parse(callbackURL)
+	if err != nil {
+		return "<invalid>"
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+)
+
+// jobExportHeader はExportJobCSVで出力するCSVのヘッダー行
+var jobExportHeader = []string{
+	"account_id",
+	"usage_date",
+	"entry_ic",
+	"exit_ic",
+	"vehicle_number",
+	"etc_card_number",
+	"amount",
+	"csv_file_name",
+}
+
+// ExportJobCSV は終了したジョブの全アカウントの明細をUTF-8のCSV（ヘッダー行付き）でwに書き出す
+// 明細がない場合はヘッダー行のみ書き出す。エラーはGetJobRecordsと同じ
+func (s *DownloadService) ExportJobCSV(jobID string, w io.Writer) error {
+	records, err := s.GetJobRecords(jobID)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(jobExportHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, r := range records {
+		if err := cw.Write(jobExportRow(r)); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// jobExportRow は明細をCSVの1行に変換する（利用日時はJST）
+func jobExportRow(r *pb.ETCMeisaiRecord) []string {
+	usage := ""
+	if r.GetUsageDate() != nil {
+		usage = r.GetUsageDate().AsTime().In(jst).Format("2006/01/02 15:04")
+	}
+	return []string{
+		r.GetAccountId(),
+		usage,
+		r.GetEntryIc(),
+		r.GetExitIc(),
+		r.GetVehicleNumber(),
+		r.GetEtcCardNumber(),
+		strconv.Itoa(int(r.GetAmount())),
+		r.GetCsvFileName(),
+	}
+}
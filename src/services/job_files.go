@@ -0,0 +1,139 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileInfo はジョブのセッションフォルダに保存されたファイル
+type FileInfo struct {
+	// Name はセッションフォルダ内のファイル名（WriteJobFileに指定する）
+	Name string
+	Size int64
+	// AccountID はファイルを保存したアカウント（判別できない場合は空）
+	AccountID string
+}
+
+// GetJobFiles は終了したジョブのセッションフォルダ直下のファイルを名前順に返す
+// ジョブが存在しない場合はErrJobNotFound、終了していない・セッションフォルダがない場合はErrInvalidJobState、
+// セッションフォルダが削除された（ETC_CLEANUP_DOWNLOADSなど）場合はErrFileNotFound
+func (s *DownloadService) GetJobFiles(jobID string) ([]FileInfo, error) {
+	job, folder, err := s.jobSessionFolder(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: session folder of job %s was removed", ErrFileNotFound, jobID)
+		}
+		return nil, fmt.Errorf("failed to read session folder %s: %w", folder, err)
+	}
+
+	owners := jobFileOwners(job)
+	files := make([]FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // 一覧の取得後に削除された
+		}
+		files = append(files, FileInfo{
+			Name:      entry.Name(),
+			Size:      info.Size(),
+			AccountID: jobFileOwner(owners, job.checkpoint.Accounts, entry.Name()),
+		})
+	}
+	return files, nil
+}
+
+// WriteJobFile は終了したジョブのセッションフォルダ直下のファイルnameの内容をwに書き出す
+// nameがファイル名でない（パスの区切りや..を含む）場合はErrInvalidFileName、
+// ファイルがない場合はErrFileNotFound。それ以外のエラーはGetJobFilesと同じ
+func (s *DownloadService) WriteJobFile(jobID, name string, w io.Writer) error {
+	if !isJobFileName(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidFileName, name)
+	}
+	_, folder, err := s.jobSessionFolder(jobID)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(folder, name)
+	// シンボリックリンクを辿ってセッションフォルダの外を読まないよう、通常のファイルのみ開く
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return fmt.Errorf("%w: %s in job %s", ErrFileNotFound, name, jobID)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// jobSessionFolder は終了したジョブとそのセッションフォルダを返す
+func (s *DownloadService) jobSessionFolder(jobID string) (*DownloadJob, string, error) {
+	job, exists := s.GetJobStatus(jobID)
+	if !exists {
+		return nil, "", ErrJobNotFound
+	}
+	if !isTerminalStatus(job.Status) {
+		return nil, "", fmt.Errorf("%w: job %s is %s", ErrInvalidJobState, jobID, job.Status)
+	}
+	if job.checkpoint == nil || job.checkpoint.SessionFolder == "" {
+		return nil, "", fmt.Errorf("%w: job %s has no session folder", ErrInvalidJobState, jobID)
+	}
+	return job, job.checkpoint.SessionFolder, nil
+}
+
+// isJobFileName はnameがパスを含まないファイル名か（パストラバーサルの防止）
+func isJobFileName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	return !strings.ContainsAny(name, "/\\\x00") && filepath.Base(name) == name
+}
+
+// jobFileOwners はアカウントの結果に記録されたファイル名 -> アカウントIDを返す
+func jobFileOwners(job *DownloadJob) map[string]string {
+	owners := make(map[string]string)
+	for _, result := range job.AccountResults {
+		if result.CSVPath != "" {
+			owners[filepath.Base(result.CSVPath)] = result.AccountID
+		}
+		for _, path := range result.CertificatePaths {
+			owners[filepath.Base(path)] = result.AccountID
+		}
+	}
+	return owners
+}
+
+// jobFileOwner はファイルを保存したアカウントを返す
+// アカウントの結果に記録されていないファイル（失敗したアカウントのファイルなど）は、
+// 保存時に付ける「アカウントID_」の接頭辞から判別する（最も長く一致するアカウント）
+func jobFileOwner(owners map[string]string, accounts []string, name string) string {
+	if owner, ok := owners[name]; ok {
+		return owner
+	}
+	owner := ""
+	for _, accountID := range accounts {
+		if strings.HasPrefix(name, accountID+"_") && len(accountID) > len(owner) {
+			owner = accountID
+		}
+	}
+	return owner
+}
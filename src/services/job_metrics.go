@@ -0,0 +1,183 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// accountDurationBuckets はアカウント単位のダウンロード時間のヒストグラム境界（秒）
+var accountDurationBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600}
+
+// JobMetrics はジョブ数とダウンロード時間のメトリクス（Prometheusテキスト形式で出力可能）
+// nilのJobMetricsに対する操作はすべて何もしない（メトリクス無効時）
+type JobMetrics struct {
+	mu            sync.Mutex
+	jobsStarted   uint64
+	jobsCompleted uint64
+	jobsPartial   uint64
+	jobsFailed    uint64
+	runningJobs   int64
+	bucketCounts  []uint64 // accountDurationBucketsの各境界以下の件数（累積ではない）
+	durationSum   float64
+	durationCount uint64
+	// csvParseErrors は解析に失敗した明細CSVの数、csvFieldErrors は列の種別ごとの失敗数
+	csvParseErrors uint64
+	csvFieldErrors map[string]uint64
+	// lastCSVParsed は最後に明細CSVの解析に成功した時刻（未成功の場合はゼロ値）
+	lastCSVParsed time.Time
+}
+
+// NewJobMetrics creates a new metrics collector
+func NewJobMetrics() *JobMetrics {
+	return &JobMetrics{
+		bucketCounts:   make([]uint64, len(accountDurationBuckets)),
+		csvFieldErrors: make(map[string]uint64),
+	}
+}
+
+// JobStarted はジョブの開始を記録
+func (m *JobMetrics) JobStarted() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobsStarted++
+	m.runningJobs++
+}
+
+// JobFinished はジョブの終了を記録（statusはcompleted/partial/failed、それ以外は実行中数のみ減らす）
+func (m *JobMetrics) JobFinished(status string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch status {
+	case "completed":
+		m.jobsCompleted++
+	case jobStatusPartial:
+		m.jobsPartial++
+	case "failed":
+		m.jobsFailed++
+	}
+	if m.runningJobs > 0 {
+		m.runningJobs--
+	}
+}
+
+// ObserveAccountDuration はアカウント1件のダウンロード時間を記録
+func (m *JobMetrics) ObserveAccountDuration(d time.Duration) {
+	if m == nil {
+		return
+	}
+	seconds := d.Seconds()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, upper := range accountDurationBuckets {
+		if seconds <= upper {
+			m.bucketCounts[i]++
+			break
+		}
+	}
+	m.durationSum += seconds
+	m.durationCount++
+}
+
+// CSVParseFailed は明細CSVの解析エラーを記録（fieldsは解析できなかった列の種別）
+// ETCサイトのCSVの形式が変わったことを検知するため
+func (m *JobMetrics) CSVParseFailed(fields []string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.csvParseErrors++
+	for _, field := range fields {
+		m.csvFieldErrors[field]++
+	}
+}
+
+// CSVParsed は明細CSVの解析に成功した時刻を記録
+func (m *JobMetrics) CSVParsed(at time.Time) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if at.After(m.lastCSVParsed) {
+		m.lastCSVParsed = at
+	}
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (m *JobMetrics) WritePrometheus(w io.Writer) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var err error
+	printf := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	printf("# HELP etc_meisai_jobs_started_total Number of download jobs started.\n")
+	printf("# TYPE etc_meisai_jobs_started_total counter\n")
+	printf("etc_meisai_jobs_started_total %d\n", m.jobsStarted)
+	printf("# HELP etc_meisai_jobs_completed_total Number of download jobs completed.\n")
+	printf("# TYPE etc_meisai_jobs_completed_total counter\n")
+	printf("etc_meisai_jobs_completed_total %d\n", m.jobsCompleted)
+	printf("# HELP etc_meisai_jobs_partial_total Number of download jobs finished with some accounts failed.\n")
+	printf("# TYPE etc_meisai_jobs_partial_total counter\n")
+	printf("etc_meisai_jobs_partial_total %d\n", m.jobsPartial)
+	printf("# HELP etc_meisai_jobs_failed_total Number of download jobs failed.\n")
+	printf("# TYPE etc_meisai_jobs_failed_total counter\n")
+	printf("etc_meisai_jobs_failed_total %d\n", m.jobsFailed)
+	printf("# HELP etc_meisai_jobs_running Number of download jobs currently running.\n")
+	printf("# TYPE etc_meisai_jobs_running gauge\n")
+	printf("etc_meisai_jobs_running %d\n", m.runningJobs)
+
+	printf("# HELP etc_meisai_account_download_duration_seconds Time spent downloading a single account.\n")
+	printf("# TYPE etc_meisai_account_download_duration_seconds histogram\n")
+	var cumulative uint64
+	for i, upper := range accountDurationBuckets {
+		cumulative += m.bucketCounts[i]
+		printf("etc_meisai_account_download_duration_seconds_bucket{le=\"%s\"} %d\n",
+			strconv.FormatFloat(upper, 'g', -1, 64), cumulative)
+	}
+	printf("etc_meisai_account_download_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.durationCount)
+	printf("etc_meisai_account_download_duration_seconds_sum %s\n", strconv.FormatFloat(m.durationSum, 'g', -1, 64))
+	printf("etc_meisai_account_download_duration_seconds_count %d\n", m.durationCount)
+
+	printf("# HELP etc_meisai_csv_parse_errors_total Number of downloaded CSVs that failed to parse.\n")
+	printf("# TYPE etc_meisai_csv_parse_errors_total counter\n")
+	printf("etc_meisai_csv_parse_errors_total %d\n", m.csvParseErrors)
+	printf("# HELP etc_meisai_csv_parse_column_errors_total Number of CSV parse errors by the column that was missing or invalid.\n")
+	printf("# TYPE etc_meisai_csv_parse_column_errors_total counter\n")
+	for _, field := range csvParseFields {
+		printf("etc_meisai_csv_parse_column_errors_total{column=\"%s\"} %d\n", field, m.csvFieldErrors[field])
+	}
+	printf("# HELP etc_meisai_csv_last_parse_success_timestamp_seconds Unix time of the last CSV parsed successfully (0 if none).\n")
+	printf("# TYPE etc_meisai_csv_last_parse_success_timestamp_seconds gauge\n")
+	lastParsed := 0.0
+	if !m.lastCSVParsed.IsZero() {
+		lastParsed = float64(m.lastCSVParsed.UnixNano()) / 1e9
+	}
+	printf("etc_meisai_csv_last_parse_success_timestamp_seconds %s\n", strconv.FormatFloat(lastParsed, 'f', 3, 64))
+
+	return err
+}
+
+// ServeHTTP serves the metrics so an existing /metrics endpoint can mount this handler
+func (m *JobMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WritePrometheus(w)
+}
@@ -0,0 +1,65 @@
+package services
+
+import "sync"
+
+// accountDownloadShare はアカウント内の進捗のうちダウンロード（検索ページ）が占める割合
+// 残りはCSVの解析・保存に充て、アカウントの完了時に100%にする
+const accountDownloadShare = 0.9
+
+// jobProgress はジョブの進捗を集計する
+// 完了したアカウントに加えて処理中のアカウント内の進捗も反映し、アカウント単位より細かく進める
+type jobProgress struct {
+	mu       sync.Mutex
+	total    int
+	done     int
+	accounts map[string]float64 // 処理中のアカウントID -> アカウント内の進捗（0〜1）
+}
+
+func newJobProgress(total int) *jobProgress {
+	return &jobProgress{total: total, accounts: make(map[string]float64)}
+}
+
+// setAccount は処理中のアカウント内の進捗を更新し、ジョブ全体の進捗(%)を返す
+// リトライでページ数が最初から数え直されても進捗は戻さない
+func (p *jobProgress) setAccount(accountID string, fraction float64) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if fraction > p.accounts[accountID] {
+		p.accounts[accountID] = fraction
+	}
+	return p.percentLocked()
+}
+
+// finishAccount はアカウントの処理完了（成功・失敗を問わない）を記録し、ジョブ全体の進捗(%)を返す
+func (p *jobProgress) finishAccount(accountID string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.accounts, accountID)
+	p.done++
+	return p.percentLocked()
+}
+
+// retryAccounts は再実行するn件のアカウントを未完了に戻し、ジョブ全体の進捗(%)を返す
+func (p *jobProgress) retryAccounts(n int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done -= n
+	if p.done < 0 {
+		p.done = 0
+	}
+	return p.percentLocked()
+}
+
+func (p *jobProgress) percentLocked() int {
+	if p.total == 0 {
+		return 0
+	}
+	sum := float64(p.done)
+	for _, fraction := range p.accounts {
+		sum += fraction
+	}
+	return int(sum / float64(p.total) * 100)
+}
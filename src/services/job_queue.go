@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// jobStatusPending は同時実行数の上限（ETC_MAX_JOBS_RUNNING）により開始待ちのジョブの状態
+const jobStatusPending = "pending"
+
+// jobSlots は同時に実行するジョブ数の制限
+// 空きがない場合は開始待ちのジョブを受け付け順に並べ、実行中のジョブが終わると先頭のジョブに枠を渡す
+type jobSlots struct {
+	mu      sync.Mutex
+	running int
+	waiters []chan struct{} // 開始待ちのジョブ（枠を渡すとcloseする）
+}
+
+// reserve は空きがあれば枠を確保してnilを返し、なければ待機列の末尾に加えて
+// 枠が渡されるとcloseされるチャネルを返す（limitが0以下の場合は制限しない）
+func (q *jobSlots) reserve(limit int) chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if limit <= 0 || (q.running < limit && len(q.waiters) == 0) {
+		q.running++
+		return nil
+	}
+	ready := make(chan struct{})
+	q.waiters = append(q.waiters, ready)
+	return ready
+}
+
+// wait はreserveで待機列に加えたジョブに枠が渡されるまで待つ
+// ctxのキャンセルで待機を中断した場合は待機列から外し、その原因を返す
+func (q *jobSlots) wait(ctx context.Context, ready chan struct{}) error {
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	for i, waiter := range q.waiters {
+		if waiter == ready {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			q.mu.Unlock()
+			return context.Cause(ctx)
+		}
+	}
+	q.mu.Unlock()
+	// キャンセルと同時に枠が渡されていた場合は次のジョブに譲る
+	q.release()
+	return context.Cause(ctx)
+}
+
+// release はジョブの終了時に枠を返す（開始待ちのジョブがあれば先頭のジョブに渡す）
+func (q *jobSlots) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.waiters) > 0 {
+		ready := q.waiters[0]
+		q.waiters = q.waiters[1:]
+		close(ready)
+		return
+	}
+	q.running--
+}
+
+// getMaxJobsRunning は環境変数から同時に実行するジョブ数の上限を取得
+// ETC_MAX_JOBS_RUNNING は0以上の整数（0で無制限）、不正値の場合はデフォルト（0）
+func (s *DownloadService) getMaxJobsRunning() int {
+	maxEnv := os.Getenv("ETC_MAX_JOBS_RUNNING")
+	if maxEnv == "" {
+		return 0
+	}
+
+	maxJobs, err := strconv.Atoi(maxEnv)
+	if err != nil || maxJobs < 0 {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_MAX_JOBS_RUNNING value %q, using default: 0 (unlimited)", maxEnv)
+		return 0
+	}
+
+	return maxJobs
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// jobCheckpoint は中断したジョブを再開するための情報（detailsカラムに保存）
+// アカウントごとの完了状況はDownloadJob.PerAccountとして、アカウントの処理が終わるたびに保存される
+// パスワードは保存せず、再開時に設定されているアカウント情報から取得する
+type jobCheckpoint struct {
+	// Accounts はジョブのアカウントID（処理した順）
+	Accounts []string `json:"accounts"`
+	FromDate string   `json:"from_date"`
+	ToDate   string   `json:"to_date"`
+	// SessionFolder はジョブのセッションフォルダ（作成前は空）
+	SessionFolder string `json:"session_folder,omitempty"`
+
+	// 再開したジョブに引き継ぐJobOptions（DryRunはDownloadJob.DryRunから引き継ぐ）
+	FromDateUnset        bool          `json:"from_date_unset,omitempty"`
+	Timeout              time.Duration `json:"timeout,omitempty"`
+	Headless             *bool         `json:"headless,omitempty"`
+	CallbackURL          string        `json:"callback_url,omitempty"`
+	CardNumbers          []string      `json:"card_numbers,omitempty"`
+	AbortOnCancel        bool          `json:"abort_on_cancel,omitempty"`
+	DownloadCertificates bool          `json:"download_certificates,omitempty"`
+	OutputFormat         OutputFormat  `json:"output_format,omitempty"`
+}
+
+// newJobCheckpoint はジョブの開始時の情報からチェックポイントを作成する
+func newJobCheckpoint(accounts []string, fromDate, toDate string, opts JobOptions) *jobCheckpoint {
+	accountIDs := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		accountIDs = append(accountIDs, accountUserID(account))
+	}
+	return &jobCheckpoint{
+		Accounts:             accountIDs,
+		FromDate:             fromDate,
+		ToDate:               toDate,
+		FromDateUnset:        opts.FromDateUnset,
+		Timeout:              opts.Timeout,
+		Headless:             opts.Headless,
+		CallbackURL:          opts.CallbackURL,
+		CardNumbers:          opts.CardNumbers,
+		AbortOnCancel:        opts.AbortOnCancel,
+		DownloadCertificates: opts.DownloadCertificates,
+		OutputFormat:         opts.OutputFormat,
+	}
+}
+
+// jobOptions は再開したジョブのJobOptionsを返す（Deadlineは引き継がない）
+func (c *jobCheckpoint) jobOptions(dryRun bool) JobOptions {
+	return JobOptions{
+		DryRun:               dryRun,
+		Timeout:              c.Timeout,
+		Headless:             c.Headless,
+		FromDateUnset:        c.FromDateUnset,
+		CallbackURL:          c.CallbackURL,
+		CardNumbers:          c.CardNumbers,
+		AbortOnCancel:        c.AbortOnCancel,
+		DownloadCertificates: c.DownloadCertificates,
+		OutputFormat:         c.OutputFormat,
+	}
+}
+
+// setSessionFolder はジョブのセッションフォルダをチェックポイントに記録して保存する
+func (s *DownloadService) setSessionFolder(jobID, folder string) {
+	s.jobMutex.Lock()
+	if job, exists := s.jobs[jobID]; exists && job.checkpoint != nil {
+		// スナップショット（copyJob）と共有しているため、書き換えずに置き換える
+		checkpoint := *job.checkpoint
+		checkpoint.SessionFolder = folder
+		job.checkpoint = &checkpoint
+	}
+	s.jobMutex.Unlock()
+	s.saveJob(jobID)
+}
+
+// reuseSessionFolder は再開したジョブで元のセッションフォルダを使う（削除されていた場合は作り直す）
+func (s *DownloadService) reuseSessionFolder(folder string) (string, error) {
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return "", fmt.Errorf("failed to reuse session folder %s: %v", folder, err)
+	}
+	return folder, nil
+}
+
+// resumeInterruptedJob はプロセスの再起動で中断した（interruptedの）ジョブを、DBに保存された
+// チェックポイントから同じジョブIDで再開する
+// 完了したアカウントの結果はそのまま残し、それ以外のアカウントのみを元のセッションフォルダで処理する
+// 完了したアカウントの明細はメモリにのみ保持していたため、GetJobRecordsは再開後に処理したアカウントの明細のみを返す
+func (s *DownloadService) resumeInterruptedJob(jobID string) error {
+	// 同じジョブを同時に再開しない
+	s.resumeMu.Lock()
+	defer s.resumeMu.Unlock()
+	if _, running := s.memoryJob(jobID); running {
+		return s.resumePausedJob(jobID)
+	}
+
+	job, err := s.LoadJob(jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status != jobStatusInterrupted {
+		return fmt.Errorf("%w: cannot resume job %s in status %s", ErrInvalidJobState, jobID, job.Status)
+	}
+	if job.checkpoint == nil {
+		return fmt.Errorf("%w: job %s has no checkpoint to resume from", ErrInvalidJobState, jobID)
+	}
+
+	credentials := make(map[string]string)
+	for _, account := range s.GetAllAccountsWithCredentials() {
+		credentials[strings.TrimSpace(accountUserID(account))] = account
+	}
+	var remaining, missing []string
+	for _, accountID := range job.checkpoint.Accounts {
+		switch job.PerAccount[accountID] {
+		case accountStatusCompleted, accountStatusAuthenticated:
+			continue
+		}
+		account, ok := credentials[accountID]
+		if !ok {
+			missing = append(missing, accountID)
+			continue
+		}
+		remaining = append(remaining, account)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: cannot resume job %s: %s", ErrAccountNotFound, jobID, strings.Join(missing, ", "))
+	}
+
+	s.logJobf(LogLevelInfo, jobID, "", "Resuming interrupted job %s: %d of %d accounts left in %s",
+		jobID, len(remaining), len(job.checkpoint.Accounts), job.checkpoint.SessionFolder)
+	opts := job.checkpoint.jobOptions(job.DryRun)
+	opts.resumed = job
+	s.ProcessAsyncWithOptions(context.Background(), jobID, remaining, job.checkpoint.FromDate, job.checkpoint.ToDate, opts)
+	return nil
+}
+
+// resumedJob は再開するジョブを処理中に戻す（accountsは再び処理するアカウント）
+// 再び処理するアカウントの状態と失敗は取り除き、完了したアカウントの結果と件数は残す
+func resumedJob(job *DownloadJob, accounts []string) *DownloadJob {
+	resumed := copyJob(job)
+	resumed.Status = "processing"
+	resumed.ErrorMessage = ""
+	resumed.CompletedAt = nil
+	if resumed.PerAccount == nil {
+		resumed.PerAccount = make(map[string]string, len(accounts))
+	}
+	rerun := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		rerun[accountUserID(account)] = true
+		resumed.PerAccount[accountUserID(account)] = accountStatusPending
+	}
+	kept := resumed.FailedAccounts[:0]
+	for _, f := range resumed.FailedAccounts {
+		if !rerun[f.AccountID] {
+			kept = append(kept, f)
+		}
+	}
+	resumed.FailedAccounts = kept
+	return resumed
+}
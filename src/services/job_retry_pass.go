@@ -0,0 +1,99 @@
+package services
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// defaultRetryPassDelay は失敗したアカウントを再実行するまでの待機時間のデフォルト
+const defaultRetryPassDelay = 30 * time.Second
+
+// getRetryPasses は環境変数から失敗したアカウントを再実行する回数を取得
+// ETC_JOB_RETRY_PASSES は0以上の整数、未設定・不正値の場合は0（再実行しない）
+func (s *DownloadService) getRetryPasses() int {
+	passesEnv := os.Getenv("ETC_JOB_RETRY_PASSES")
+	if passesEnv == "" {
+		return 0
+	}
+
+	passes, err := strconv.Atoi(passesEnv)
+	if err != nil || passes < 0 {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_JOB_RETRY_PASSES value %q, using default: 0 (no retry)", passesEnv)
+		return 0
+	}
+
+	return passes
+}
+
+// getRetryPassDelay は環境変数から失敗したアカウントを再実行するまでの待機時間を取得
+// ETC_JOB_RETRY_PASS_DELAY はtime.ParseDuration形式（例: 30s, 2m）、不正値の場合はデフォルト（30s）
+func (s *DownloadService) getRetryPassDelay() time.Duration {
+	delayEnv := os.Getenv("ETC_JOB_RETRY_PASS_DELAY")
+	if delayEnv == "" {
+		return defaultRetryPassDelay
+	}
+
+	delay, err := time.ParseDuration(delayEnv)
+	if err != nil || delay < 0 {
+		s.logMessagef(LogLevelWarn, "Invalid ETC_JOB_RETRY_PASS_DELAY value %q, using default: %v", delayEnv, defaultRetryPassDelay)
+		return defaultRetryPassDelay
+	}
+
+	return delay
+}
+
+// retryableAccounts はジョブで失敗したアカウントのうち再実行するものをaccountsの順に返す
+// 認証エラー（再実行しても失敗し、アカウントがロックされるおそれがある）と
+// メンテナンス（ETC_MAINTENANCE_RETRY_DELAYで別のジョブとして再実行する）は除く
+func (s *DownloadService) retryableAccounts(jobID string, accounts []string) []string {
+	s.jobMutex.RLock()
+	defer s.jobMutex.RUnlock()
+
+	job, exists := s.jobs[jobID]
+	if !exists {
+		return nil
+	}
+	failed := make(map[string]bool, len(job.FailedAccounts))
+	for _, f := range job.FailedAccounts {
+		if !f.AuthError && !f.Maintenance {
+			failed[f.AccountID] = true
+		}
+	}
+
+	var retry []string
+	for _, account := range accounts {
+		if failed[accountUserID(account)] {
+			retry = append(retry, account)
+		}
+	}
+	return retry
+}
+
+// startRetryPass はジョブのパス数を更新する
+func (s *DownloadService) startRetryPass(jobID string, pass int) {
+	s.jobMutex.Lock()
+	if job, exists := s.jobs[jobID]; exists {
+		job.Passes = pass
+	}
+	s.jobMutex.Unlock()
+	s.saveJob(jobID)
+}
+
+// clearAccountFailure は再実行したアカウントの前のパスでの失敗を取り除く
+func (s *DownloadService) clearAccountFailure(jobID, accountID string) {
+	s.jobMutex.Lock()
+	defer s.jobMutex.Unlock()
+
+	job, exists := s.jobs[jobID]
+	if !exists {
+		return
+	}
+	kept := job.FailedAccounts[:0]
+	for _, f := range job.FailedAccounts {
+		if f.AccountID != accountID {
+			kept = append(kept, f)
+		}
+	}
+	job.FailedAccounts = kept
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ジョブ永続化（migrations/005_add_download_jobs.sql のdownload_jobsテーブル）
+// DBが設定されていない場合（db == nil）はすべて何もしない
+// MySQLドライバを使う場合、DSNにparseTime=trueを指定すること
+
+// jobStatusInterrupted は処理中にプロセスが再起動したジョブの状態
+const jobStatusInterrupted = "interrupted"
+
+const upsertJobQuery = `INSERT INTO download_jobs
+	(id, status, progress, total_records, error_message, dry_run, details, started_at, completed_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+	status = VALUES(status), progress = VALUES(progress), total_records = VALUES(total_records),
+	error_message = VALUES(error_message), details = VALUES(details), completed_at = VALUES(completed_at)`
+
+const selectJobQuery = `SELECT id, status, progress, total_records, error_message, dry_run, details, started_at, completed_at
+	FROM download_jobs WHERE id = ?`
+
+const markInterruptedQuery = `UPDATE download_jobs SET status = ?, completed_at = ?
+	WHERE status IN ('pending', 'processing', 'paused')`
+
+// jobDetails はジョブのアカウント単位の状態（detailsカラムにJSONで保存）
+type jobDetails struct {
+	CurrentAccount string            `json:"current_account,omitempty"`
+	PerAccount     map[string]string `json:"per_account,omitempty"`
+	AccountResults []AccountResult   `json:"account_results,omitempty"`
+	FailedAccounts []FailedAccount   `json:"failed_accounts,omitempty"`
+	// RescheduledJobID・RescheduledAt はメンテナンスによる再実行（DownloadJob参照）
+	RescheduledJobID string     `json:"rescheduled_job_id,omitempty"`
+	RescheduledAt    *time.Time `json:"rescheduled_at,omitempty"`
+	Passes           int        `json:"passes,omitempty"`
+	TotalBytes       int64      `json:"total_bytes,omitempty"`
+	FileCount        int        `json:"file_count,omitempty"`
+	// Checkpoint は中断したジョブを再開するための情報（ResumeJob参照）
+	Checkpoint *jobCheckpoint `json:"checkpoint,omitempty"`
+}
+
+// saveJob はジョブの現在の状態をDBに保存する（jobMutexを保持せずに呼ぶこと）
+func (s *DownloadService) saveJob(jobID string) {
+	if s.db == nil {
+		return
+	}
+
+	// 古いスナップショットが新しいものを上書きしないよう、スナップショット取得から書き込みまでを直列化
+	s.persistMu.Lock()
+	defer s.persistMu.Unlock()
+
+	job, exists := s.memoryJob(jobID)
+	if !exists {
+		return
+	}
+
+	details, err := json.Marshal(jobDetails{
+		CurrentAccount:   job.CurrentAccount,
+		PerAccount:       job.PerAccount,
+		AccountResults:   job.AccountResults,
+		FailedAccounts:   job.FailedAccounts,
+		RescheduledJobID: job.RescheduledJobID,
+		RescheduledAt:    job.RescheduledAt,
+		Passes:           job.Passes,
+		TotalBytes:       job.TotalBytes,
+		FileCount:        job.FileCount,
+		Checkpoint:       job.checkpoint,
+	})
+	if err != nil {
+		s.logMessagef(LogLevelWarn, "Failed to encode job %s for persistence: %v", jobID, err)
+		return
+	}
+
+	var completedAt sql.NullTime
+	if job.CompletedAt != nil {
+		completedAt = sql.NullTime{Time: *job.CompletedAt, Valid: true}
+	}
+
+	if _, err := s.db.Exec(upsertJobQuery,
+		job.ID, job.Status, job.Progress, job.TotalRecords, job.ErrorMessage, job.DryRun,
+		string(details), job.StartedAt, completedAt); err != nil {
+		s.logMessagef(LogLevelWarn, "Failed to persist job %s: %v", jobID, err)
+	}
+}
+
+// LoadJob はDBに保存されたジョブを読み込む（再起動前に登録されたジョブの参照用）
+// 見つからない場合はErrJobNotFoundを返す
+func (s *DownloadService) LoadJob(jobID string) (*DownloadJob, error) {
+	if s.db == nil {
+		return nil, ErrJobNotFound
+	}
+
+	var (
+		job         DownloadJob
+		details     string
+		completedAt sql.NullTime
+	)
+	err := s.db.QueryRow(selectJobQuery, jobID).Scan(
+		&job.ID, &job.Status, &job.Progress, &job.TotalRecords, &job.ErrorMessage, &job.DryRun,
+		&details, &job.StartedAt, &completedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load job %s: %w", jobID, err)
+	}
+
+	if completedAt.Valid {
+		job.CompletedAt = &completedAt.Time
+	}
+	if details != "" {
+		var d jobDetails
+		if err := json.Unmarshal([]byte(details), &d); err != nil {
+			return nil, fmt.Errorf("failed to decode job %s: %w", jobID, err)
+		}
+		job.CurrentAccount = d.CurrentAccount
+		job.PerAccount = d.PerAccount
+		job.AccountResults = d.AccountResults
+		job.FailedAccounts = d.FailedAccounts
+		job.RescheduledJobID = d.RescheduledJobID
+		job.RescheduledAt = d.RescheduledAt
+		job.Passes = d.Passes
+		job.TotalBytes = d.TotalBytes
+		job.FileCount = d.FileCount
+		job.checkpoint = d.Checkpoint
+	}
+
+	return &job, nil
+}
+
+// markInterruptedJobs は前回のプロセスで処理中のまま残ったジョブをinterruptedにする（起動時に呼ぶ）
+func (s *DownloadService) markInterruptedJobs() {
+	if s.db == nil {
+		return
+	}
+
+	result, err := s.db.Exec(markInterruptedQuery, jobStatusInterrupted, time.Now())
+	if err != nil {
+		s.logMessagef(LogLevelWarn, "Failed to mark interrupted jobs: %v", err)
+		return
+	}
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		s.logMessagef(LogLevelWarn, "Marked %d jobs left in progress by a previous run as %s", n, jobStatusInterrupted)
+	}
+}
@@ -0,0 +1,40 @@
+package services
+
+import "context"
+
+// notifyJobChangedLocked はジョブの状態の変化をWaitForJobで待っている呼び出し元に通知する（jobMutexを保持して呼ぶこと）
+func (s *DownloadService) notifyJobChangedLocked(jobID string) {
+	if ch, ok := s.jobChanged[jobID]; ok {
+		close(ch)
+		delete(s.jobChanged, jobID)
+	}
+}
+
+// WaitForJob はジョブの状態が変わるか終了するまで待ち、その時点のジョブを返す
+// lastStatusを指定した場合、現在の状態と異なればすぐに返す（前回の取得以降の変化を見逃さないため）
+// 終了済みのジョブやメモリ上に無いジョブはすぐに返し、ctxが終了した場合も待機をやめて現在のジョブを返す
+func (s *DownloadService) WaitForJob(ctx context.Context, jobID, lastStatus string) (*DownloadJob, bool) {
+	s.jobMutex.Lock()
+	job, exists := s.jobs[jobID]
+	if !exists {
+		s.jobMutex.Unlock()
+		return s.GetJobStatus(jobID)
+	}
+	if isTerminalStatus(job.Status) || (lastStatus != "" && job.Status != lastStatus) {
+		jobCopy := copyJob(job)
+		s.jobMutex.Unlock()
+		return jobCopy, true
+	}
+	changed, ok := s.jobChanged[jobID]
+	if !ok {
+		changed = make(chan struct{})
+		s.jobChanged[jobID] = changed
+	}
+	s.jobMutex.Unlock()
+
+	select {
+	case <-changed:
+	case <-ctx.Done():
+	}
+	return s.GetJobStatus(jobID)
+}
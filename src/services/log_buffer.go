@@ -0,0 +1,333 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel はログの重要度
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// String returns the level name
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LogLevel(%d)", int(l))
+	}
+}
+
+// ParseLogLevel はログレベル名（debug/info/warn/error、大文字小文字を区別しない）を解析
+func ParseLogLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LogLevelDebug, nil
+	case "info":
+		return LogLevelInfo, nil
+	case "warn", "warning":
+		return LogLevelWarn, nil
+	case "error":
+		return LogLevelError, nil
+	}
+	return LogLevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+// LogEntry はログバッファに保持する1行分のログ
+type LogEntry struct {
+	Timestamp time.Time
+	Level     LogLevel
+	Message   string
+}
+
+// LogBuffer はログを保持するリングバッファ
+type LogBuffer struct {
+	entries     []LogEntry // 満杯になるまでは追加順、満杯後はstartが最古の位置
+	start       int
+	maxLines    int
+	mu          sync.RWMutex
+	subscribers map[*logSubscriber]struct{}
+	dropped     uint64 // 購読者の受信が追いつかず破棄した行数
+}
+
+// logSubscriberBufferSize は購読者ごとのチャネルのバッファサイズ
+const logSubscriberBufferSize = 256
+
+// logSubscriber はログの購読者
+type logSubscriber struct {
+	ch chan string
+}
+
+// NewLogBuffer creates a new log buffer
+func NewLogBuffer(maxLines int) *LogBuffer {
+	return &LogBuffer{
+		entries:     make([]LogEntry, 0, maxLines),
+		maxLines:    maxLines,
+		subscribers: make(map[*logSubscriber]struct{}),
+	}
+}
+
+// Add adds an Info level log line to the buffer
+func (lb *LogBuffer) Add(line string) {
+	lb.AddEntry(LogEntry{Timestamp: time.Now(), Level: LogLevelInfo, Message: line})
+}
+
+// AddEntry adds a log entry to the buffer and fans its message out to subscribers
+func (lb *LogBuffer) AddEntry(entry LogEntry) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	switch {
+	case lb.maxLines <= 0:
+		// 容量0の場合は保持せず購読者への配信のみ行う
+	case len(lb.entries) < lb.maxLines:
+		lb.entries = append(lb.entries, entry)
+	default:
+		// 満杯の場合は最古の行を上書き
+		lb.entries[lb.start] = entry
+		lb.start = (lb.start + 1) % len(lb.entries)
+	}
+
+	for sub := range lb.subscribers {
+		select {
+		case sub.ch <- entry.Message:
+		default:
+			// 受信が遅い購読者のために書き込みをブロックしない
+			lb.dropped++
+		}
+	}
+}
+
+// Subscribe registers a subscriber that receives every line added after this call.
+// The returned function unsubscribes and closes the channel; it is safe to call more than once.
+func (lb *LogBuffer) Subscribe() (<-chan string, func()) {
+	_, ch, unsubscribe := lb.subscribeWithTail(0)
+	return ch, unsubscribe
+}
+
+// subscribeWithTail は末尾n行の取得と購読登録を同一ロック内で行い、取りこぼしや重複を防ぐ
+func (lb *LogBuffer) subscribeWithTail(n int) ([]string, <-chan string, func()) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	var tail []string
+	if n > 0 {
+		tail = messages(lb.tailEntries(n, LogLevelDebug))
+	}
+
+	sub := &logSubscriber{ch: make(chan string, logSubscriberBufferSize)}
+	lb.subscribers[sub] = struct{}{}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			lb.mu.Lock()
+			defer lb.mu.Unlock()
+			delete(lb.subscribers, sub)
+			close(sub.ch)
+		})
+	}
+	return tail, sub.ch, unsubscribe
+}
+
+// DroppedLines returns how many lines were dropped because a subscriber was too slow
+func (lb *LogBuffer) DroppedLines() uint64 {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+	return lb.dropped
+}
+
+// SubscriberCount returns the number of active subscribers
+func (lb *LogBuffer) SubscriberCount() int {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+	return len(lb.subscribers)
+}
+
+// Len returns the number of lines currently held in the buffer
+func (lb *LogBuffer) Len() int {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+	return len(lb.entries)
+}
+
+// Capacity returns the maximum number of lines the buffer holds
+func (lb *LogBuffer) Capacity() int {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+	return lb.maxLines
+}
+
+// Resize changes the maximum number of lines, keeping the most recent lines when shrinking.
+// Values less than 1 are ignored.
+func (lb *LogBuffer) Resize(n int) {
+	if n < 1 {
+		return
+	}
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	entries := make([]LogEntry, 0, n)
+	lb.entries = append(entries, lb.tailEntries(n, LogLevelDebug)...)
+	lb.start = 0
+	lb.maxLines = n
+}
+
+// GetTail returns the last N lines
+func (lb *LogBuffer) GetTail(n int) []string {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	return messages(lb.tailEntries(n, LogLevelDebug))
+}
+
+// GetTailEntries returns the last N entries whose level is at least minLevel
+func (lb *LogBuffer) GetTailEntries(n int, minLevel LogLevel) []LogEntry {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	return lb.tailEntries(n, minLevel)
+}
+
+// GetAll returns all lines
+func (lb *LogBuffer) GetAll() []string {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	return messages(lb.tailEntries(0, LogLevelDebug))
+}
+
+// GetRange returns up to count lines ending offset lines before the newest one, oldest first.
+// The bool reports whether older lines remain, so offset+len(lines) pages further back.
+func (lb *LogBuffer) GetRange(offset, count int) ([]string, bool) {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	entries, hasMore := lb.rangeEntries(offset, count, LogLevelDebug)
+	return messages(entries), hasMore
+}
+
+// GetRangeEntries returns the entries at least minLevel selected like GetRange
+// (offset and count apply to the entries that pass the level filter)
+func (lb *LogBuffer) GetRangeEntries(offset, count int, minLevel LogLevel) ([]LogEntry, bool) {
+	lb.mu.RLock()
+	defer lb.mu.RUnlock()
+
+	return lb.rangeEntries(offset, count, minLevel)
+}
+
+// at は古い方からi番目のエントリを返す（呼び出し側でロックを取得すること）
+func (lb *LogBuffer) at(i int) LogEntry {
+	return lb.entries[(lb.start+i)%len(lb.entries)]
+}
+
+// tailEntries はminLevel以上の末尾n件を古い順で返す（n<=0は全件、呼び出し側でロックを取得すること）
+func (lb *LogBuffer) tailEntries(n int, minLevel LogLevel) []LogEntry {
+	result, _ := lb.rangeEntries(0, n, minLevel)
+	return result
+}
+
+// rangeEntries はminLevel以上のエントリのうち、新しい方からoffset件を飛ばした後のcount件を古い順で返す
+// count<=0は残り全件。2つ目の返り値はさらに古いエントリが残っている場合にtrue（呼び出し側でロックを取得すること）
+func (lb *LogBuffer) rangeEntries(offset, count int, minLevel LogLevel) ([]LogEntry, bool) {
+	if offset < 0 {
+		offset = 0
+	}
+
+	var result []LogEntry
+	skipped := 0
+	i := len(lb.entries) - 1
+	for ; i >= 0; i-- {
+		if count > 0 && len(result) == count {
+			break
+		}
+		entry := lb.at(i)
+		if entry.Level < minLevel {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		result = append(result, entry)
+	}
+
+	hasMore := false
+	for ; i >= 0; i-- {
+		if lb.at(i).Level >= minLevel {
+			hasMore = true
+			break
+		}
+	}
+
+	// 新しい順に集めたので古い順に並べ直す
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	if result == nil {
+		result = []LogEntry{}
+	}
+	return result, hasMore
+}
+
+// messages はエントリからメッセージのみを取り出す
+func messages(entries []LogEntry) []string {
+	result := make([]string, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.Message
+	}
+	return result
+}
+
+// jobLogBuffer はジョブのログバッファを返す（なければ作成する）
+func (s *DownloadService) jobLogBuffer(jobID string) *LogBuffer {
+	s.jobLogsMu.Lock()
+	defer s.jobLogsMu.Unlock()
+
+	buf, exists := s.jobLogs[jobID]
+	if !exists {
+		buf = NewLogBuffer(s.jobLogLines)
+		s.jobLogs[jobID] = buf
+	}
+	return buf
+}
+
+// removeJobLogs はジョブのログバッファを削除
+func (s *DownloadService) removeJobLogs(jobID string) {
+	s.jobLogsMu.Lock()
+	defer s.jobLogsMu.Unlock()
+	delete(s.jobLogs, jobID)
+}
+
+// GetJobLogs はジョブのログの末尾tail件を古い順で返す（tail<=0は全件）
+// 並行して実行中の他のジョブやサーバー全体のログは含まない
+func (s *DownloadService) GetJobLogs(jobID string, tail int) ([]LogEntry, error) {
+	if _, exists := s.GetJobStatus(jobID); !exists {
+		return nil, ErrJobNotFound
+	}
+
+	s.jobLogsMu.Lock()
+	buf, exists := s.jobLogs[jobID]
+	s.jobLogsMu.Unlock()
+	if !exists {
+		// 再起動前のジョブなどログが残っていない場合
+		return []LogEntry{}, nil
+	}
+	return buf.GetTailEntries(tail, LogLevelDebug), nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxMaintenanceRetries はメンテナンスによる再実行の上限回数（メンテナンスが長引いた場合に再実行を繰り返さない）
+const maxMaintenanceRetries = 3
+
+// scheduleMaintenanceRetry はETCサイトのメンテナンス中で処理できなかったアカウントを
+// MaintenanceRetryDelay後に新しいジョブで再実行する（MaintenanceRetryDelayが0の場合は何もしない）
+// 再実行するジョブのIDと開始予定日時は元のジョブのRescheduledJobID・RescheduledAtに記録する
+func (s *DownloadService) scheduleMaintenanceRetry(ctx context.Context, jobID string, accounts []string, fromDate, toDate string, opts JobOptions) {
+	if s.MaintenanceRetryDelay <= 0 {
+		return
+	}
+
+	retryJobID := uuid.NewString()
+	retryAt := time.Now().Add(s.MaintenanceRetryDelay)
+	var retryAccounts []string
+	s.jobMutex.Lock()
+	if job, exists := s.jobs[jobID]; exists {
+		for _, account := range accounts {
+			if job.PerAccount[accountUserID(account)] == accountStatusMaintenance {
+				retryAccounts = append(retryAccounts, account)
+			}
+		}
+		if len(retryAccounts) > 0 && opts.maintenanceRetries < maxMaintenanceRetries {
+			job.RescheduledJobID = retryJobID
+			job.RescheduledAt = &retryAt
+		}
+	}
+	s.jobMutex.Unlock()
+	if len(retryAccounts) == 0 {
+		return
+	}
+	if opts.maintenanceRetries >= maxMaintenanceRetries {
+		s.logJobf(LogLevelWarn, jobID, "", "Not rescheduling job %s: %d accounts still under maintenance after %d retries",
+			jobID, len(retryAccounts), opts.maintenanceRetries)
+		return
+	}
+	s.saveJob(jobID)
+	s.logJobf(LogLevelWarn, jobID, "", "ETC site under maintenance: rescheduled %d accounts of job %s as job %s at %s",
+		len(retryAccounts), jobID, retryJobID, retryAt.Format(time.RFC3339))
+
+	retryOpts := opts
+	retryOpts.Deadline = time.Time{}
+	retryOpts.maintenanceRetries++
+	retryOpts.resumed = nil
+	go func() {
+		timer := time.NewTimer(s.MaintenanceRetryDelay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			s.ProcessAsyncWithOptions(ctx, retryJobID, retryAccounts, fromDate, toDate, retryOpts)
+		case <-s.ctx.Done():
+			// シャットダウン後は再実行しない
+		case <-ctx.Done():
+		}
+	}()
+}
@@ -0,0 +1,451 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/width"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CSVEncoding は明細CSVの文字コード
+type CSVEncoding int
+
+const (
+	// CSVEncodingAuto はBOMと内容から文字コードを自動判定
+	CSVEncodingAuto CSVEncoding = iota
+	// CSVEncodingUTF8 はUTF-8（BOM付きも可）
+	CSVEncodingUTF8
+	// CSVEncodingShiftJIS はShift-JIS
+	CSVEncodingShiftJIS
+)
+
+// String returns the encoding name
+func (e CSVEncoding) String() string {
+	switch e {
+	case CSVEncodingUTF8:
+		return "UTF-8"
+	case CSVEncodingShiftJIS:
+		return "Shift-JIS"
+	default:
+		return "auto"
+	}
+}
+
+// ParseCSVEncoding は文字コード名（auto, utf-8, shift_jis など、大文字小文字は区別しない）を解析する
+func ParseCSVEncoding(name string) (CSVEncoding, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "auto":
+		return CSVEncodingAuto, nil
+	case "utf-8", "utf8":
+		return CSVEncodingUTF8, nil
+	case "shift_jis", "shift-jis", "shiftjis", "sjis":
+		return CSVEncodingShiftJIS, nil
+	default:
+		return CSVEncodingAuto, fmt.Errorf("unknown CSV encoding: %s", name)
+	}
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// jst は明細の日時のタイムゾーン
+var jst = time.FixedZone("JST", 9*60*60)
+
+// 明細CSVのヘッダー名（全角英数・括弧は半角に正規化して比較）
+const (
+	colEntryDate = "利用年月日(自)"
+	colEntryTime = "時分(自)"
+	colExitDate  = "利用年月日(至)"
+	colExitTime  = "時分(至)"
+	colEntryIC   = "利用IC(自)"
+	colExitIC    = "利用IC(至)"
+	colAmount    = "通行料金"
+	colVehicleNo = "車両番号"
+	colETCCardNo = "ETCカード番号"
+)
+
+// meisaiColumns は明細の解析に使う列名（ETC_CSV_COLUMN_MAPのキーに指定できる列名）
+var meisaiColumns = []string{
+	colEntryDate, colEntryTime, colExitDate, colExitTime,
+	colEntryIC, colExitIC, colAmount, colVehicleNo, colETCCardNo,
+}
+
+// 解析に失敗した列の種別（メトリクスのcolumnラベル）
+const (
+	csvFieldUsageDate = "usage_date"
+	csvFieldAmount    = "amount"
+)
+
+// csvParseFields はメトリクスに出力する列の種別（一度も失敗していなくても0を出力する）
+var csvParseFields = []string{csvFieldUsageDate, csvFieldAmount}
+
+// csvParseError は明細CSVの解析エラー
+// fieldsは解析できなかった（ヘッダーに無い・値が不正な）列の種別、読み込みや文字コードのエラーでは空
+type csvParseError struct {
+	fields []string
+	err    error
+}
+
+func (e *csvParseError) Error() string { return e.err.Error() }
+func (e *csvParseError) Unwrap() error { return e.err }
+
+// csvParseErrorFields は解析エラーの列の種別を返す（列に依らないエラーはnil）
+func csvParseErrorFields(err error) []string {
+	var parseErr *csvParseError
+	if errors.As(err, &parseErr) {
+		return parseErr.fields
+	}
+	return nil
+}
+
+// CSVColumnMap は明細CSVの列名 -> 同じ列として扱う別名のヘッダー名
+// アカウント種別などによって列名が異なるCSVを解析するために使う（例: "通行料金" -> ["料金"]）
+type CSVColumnMap map[string][]string
+
+// ParseCSVColumnMap は列名の別名の指定を解析する
+// JSON形式: {"通行料金":["料金"]} またはカンマ区切り: 通行料金=料金|通行料,車両番号=車番
+// 列名・別名の全角英数・括弧は半角に正規化し、解析に使わない列名はエラーとする
+func ParseCSVColumnMap(value string) (CSVColumnMap, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	raw := make(map[string][]string)
+	if strings.HasPrefix(value, "{") {
+		if err := json.Unmarshal([]byte(value), &raw); err != nil {
+			return nil, fmt.Errorf("invalid CSV column map JSON: %w", err)
+		}
+	} else {
+		for i, entry := range strings.Split(value, ",") {
+			if strings.TrimSpace(entry) == "" {
+				continue
+			}
+			name, aliases, ok := strings.Cut(entry, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid CSV column map entry #%d %q: expected column=alias1|alias2", i+1, strings.TrimSpace(entry))
+			}
+			raw[name] = append(raw[name], strings.Split(aliases, "|")...)
+		}
+	}
+
+	columnMap := make(CSVColumnMap, len(raw))
+	for name, aliases := range raw {
+		name = normalizeHeader(name)
+		if !slices.Contains(meisaiColumns, name) {
+			return nil, fmt.Errorf("unknown CSV column %q (expected one of %s)", name, strings.Join(meisaiColumns, ", "))
+		}
+		for _, alias := range aliases {
+			if alias = normalizeHeader(alias); alias != "" {
+				columnMap[name] = append(columnMap[name], alias)
+			}
+		}
+	}
+	return columnMap, nil
+}
+
+// normalizeHeader はヘッダー名の前後の空白を除き、全角英数・括弧を半角にする
+func normalizeHeader(name string) string {
+	return width.Fold.String(strings.TrimSpace(name))
+}
+
+// resolveColumns はヘッダーから列名 -> 列番号を求める
+// 標準の列名が無い列はcolumnMapの別名で探し、どちらにも無い列は含めない（未知の列は無視する）
+func resolveColumns(header []string, columnMap CSVColumnMap) map[string]int {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[normalizeHeader(name)] = i
+	}
+	for name, aliases := range columnMap {
+		if _, ok := columns[name]; ok {
+			continue
+		}
+		for _, alias := range aliases {
+			if i, ok := columns[alias]; ok {
+				columns[name] = i
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// missingColumns は明細の解析に必要な列のうちヘッダーに無いものと、その種別（csvParseError.fields）を返す
+// 利用年月日は出口（至）と入口（自）のどちらかがあればよい
+func missingColumns(columns map[string]int) (missing, fields []string) {
+	_, hasExitDate := columns[colExitDate]
+	_, hasEntryDate := columns[colEntryDate]
+	if !hasExitDate && !hasEntryDate {
+		missing = append(missing, colExitDate+" or "+colEntryDate)
+		fields = append(fields, csvFieldUsageDate)
+	}
+	if _, ok := columns[colAmount]; !ok {
+		missing = append(missing, colAmount)
+		fields = append(fields, csvFieldAmount)
+	}
+	return missing, fields
+}
+
+// ParseMeisaiCSV はETC明細CSVを解析する
+// 文字コード（UTF-8/BOM付きUTF-8/Shift-JIS）と改行コード（CRLF/LF/CR）は自動判定する
+func ParseMeisaiCSV(r io.Reader) ([]*pb.ETCMeisaiRecord, error) {
+	return ParseMeisaiCSVWithEncoding(r, CSVEncodingAuto)
+}
+
+// ParseMeisaiCSVWithEncoding は文字コードを指定してETC明細CSVを解析する
+func ParseMeisaiCSVWithEncoding(r io.Reader, enc CSVEncoding) ([]*pb.ETCMeisaiRecord, error) {
+	return ParseMeisaiCSVWithColumnMap(r, enc, nil)
+}
+
+// ParseMeisaiCSVWithColumnMap は文字コードと列名の別名を指定してETC明細CSVを解析する
+func ParseMeisaiCSVWithColumnMap(r io.Reader, enc CSVEncoding, columnMap CSVColumnMap) ([]*pb.ETCMeisaiRecord, error) {
+	records, _, _, err := parseMeisaiCSV(r, enc, 0, nil, columnMap)
+	return records, err
+}
+
+// cardNumberFilter は解析する明細をETCカード番号で絞り込む（空の場合はすべてのカード）
+// カード番号は全角・半角、空白・ハイフンの違いを無視して比較する
+type cardNumberFilter map[string]struct{}
+
+// newCardNumberFilter は指定されたカード番号のフィルタを作成する（空の番号は無視）
+func newCardNumberFilter(cardNumbers []string) cardNumberFilter {
+	filter := make(cardNumberFilter, len(cardNumbers))
+	for _, cardNumber := range cardNumbers {
+		if normalized := normalizeCardNumber(cardNumber); normalized != "" {
+			filter[normalized] = struct{}{}
+		}
+	}
+	return filter
+}
+
+// matches はカード番号がフィルタに含まれるかを返す（フィルタが空の場合は常にtrue）
+func (f cardNumberFilter) matches(cardNumber string) bool {
+	if len(f) == 0 {
+		return true
+	}
+	_, ok := f[normalizeCardNumber(cardNumber)]
+	return ok
+}
+
+// normalizeCardNumber はカード番号を半角にし、空白とハイフンを除く
+func normalizeCardNumber(cardNumber string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(width.Fold.String(strings.TrimSpace(cardNumber)))
+}
+
+// parseMeisaiCSV はETC明細CSVを解析する
+// maxRecordsが正の場合はその件数で解析を打ち切り、打ち切った場合はtruncatedにtrueを返す
+// cardsに含まれないカードの明細は読み飛ばし、その件数をfilteredに返す
+// 列はヘッダー名（見つからない場合はcolumnMapの別名）で探すため、列の順序や余分な列は問わない
+func parseMeisaiCSV(r io.Reader, enc CSVEncoding, maxRecords int, cards cardNumberFilter, columnMap CSVColumnMap) (records []*pb.ETCMeisaiRecord, truncated bool, filtered int, err error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, false, 0, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	text, err := decodeMeisaiCSV(raw, enc)
+	if err != nil {
+		return nil, false, 0, err
+	}
+
+	reader := csv.NewReader(strings.NewReader(normalizeLineEndings(text)))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []*pb.ETCMeisaiRecord{}, false, 0, nil
+	}
+	if err != nil {
+		return nil, false, 0, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := resolveColumns(header, columnMap)
+	if missing, fields := missingColumns(columns); len(missing) > 0 {
+		return nil, false, 0, &csvParseError{
+			fields: fields,
+			err: fmt.Errorf("CSV header is missing required columns: %s (got %s; map renamed columns with ETC_CSV_COLUMN_MAP)",
+				strings.Join(missing, ", "), strings.Join(header, ",")),
+		}
+	}
+
+	field := func(row []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	records = []*pb.ETCMeisaiRecord{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, 0, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankRow(row) {
+			continue
+		}
+		if !cards.matches(field(row, colETCCardNo)) {
+			filtered++
+			continue
+		}
+		if maxRecords > 0 && len(records) >= maxRecords {
+			return records, true, filtered, nil
+		}
+
+		// 利用日時は出口（至）を優先し、無ければ入口（自）を使う
+		date, clock := field(row, colExitDate), field(row, colExitTime)
+		if date == "" {
+			date, clock = field(row, colEntryDate), field(row, colEntryTime)
+		}
+		usageDate, err := parseMeisaiDateTime(date, clock)
+		if err != nil {
+			return nil, false, 0, &csvParseError{fields: []string{csvFieldUsageDate}, err: fmt.Errorf("line %d: %w", line, err)}
+		}
+
+		amount, err := parseMeisaiAmount(field(row, colAmount))
+		if err != nil {
+			return nil, false, 0, &csvParseError{fields: []string{csvFieldAmount}, err: fmt.Errorf("line %d: %w", line, err)}
+		}
+
+		records = append(records, &pb.ETCMeisaiRecord{
+			UsageDate:     timestamppb.New(usageDate),
+			EntryIc:       field(row, colEntryIC),
+			ExitIc:        field(row, colExitIC),
+			VehicleNumber: field(row, colVehicleNo),
+			EtcCardNumber: field(row, colETCCardNo),
+			Amount:        int32(amount),
+		})
+	}
+
+	return records, false, filtered, nil
+}
+
+// RecordDedupKey は明細の同一性を判定するキー（SHA-256の16進文字列）を返す
+// 利用日時（JSTの分単位）・入口IC・出口IC・通行料金・ETCカード番号から計算するため、
+// 同じ利用を別のCSVやアカウントからダウンロードしても同じキーになる
+func RecordDedupKey(r *pb.ETCMeisaiRecord) string {
+	usage := ""
+	if r.GetUsageDate() != nil {
+		usage = r.GetUsageDate().AsTime().In(jst).Format("2006-01-02 15:04")
+	}
+	// 区切り文字を含むIC名でも他のフィールドと混ざらないよう、制御文字で区切る
+	fields := []string{
+		usage,
+		r.GetEntryIc(),
+		r.GetExitIc(),
+		strconv.Itoa(int(r.GetAmount())),
+		r.GetEtcCardNumber(),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+// appendUniqueRecords はmergedにない明細のみをrecordsから追加する（RecordDedupKeyで判定）
+// 同じ利用を複数のアカウント（同じETCカードを登録したアカウントなど）や期間の重なるCSVから
+// ダウンロードした場合に、ジョブの明細に重複して含めない。先に追加した明細を残す
+func appendUniqueRecords(merged, records []*pb.ETCMeisaiRecord) []*pb.ETCMeisaiRecord {
+	seen := make(map[string]struct{}, len(merged)+len(records))
+	for _, r := range merged {
+		seen[RecordDedupKey(r)] = struct{}{}
+	}
+	for _, r := range records {
+		key := RecordDedupKey(r)
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// decodeMeisaiCSV はCSVのバイト列をUTF-8文字列に変換する
+func decodeMeisaiCSV(raw []byte, enc CSVEncoding) (string, error) {
+	if enc == CSVEncodingAuto {
+		enc = detectCSVEncoding(raw)
+	}
+
+	switch enc {
+	case CSVEncodingUTF8:
+		return string(bytes.TrimPrefix(raw, utf8BOM)), nil
+	case CSVEncodingShiftJIS:
+		decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(raw)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode Shift-JIS CSV: %w", err)
+		}
+		return string(decoded), nil
+	default:
+		return "", fmt.Errorf("unsupported CSV encoding: %v", enc)
+	}
+}
+
+// detectCSVEncoding はBOMとUTF-8としての妥当性から文字コードを判定する
+// 日本語を含むShift-JISのバイト列はほぼ確実に不正なUTF-8になるため、妥当なUTF-8はUTF-8とみなす
+func detectCSVEncoding(raw []byte) CSVEncoding {
+	if bytes.HasPrefix(raw, utf8BOM) || utf8.Valid(raw) {
+		return CSVEncodingUTF8
+	}
+	return CSVEncodingShiftJIS
+}
+
+// normalizeLineEndings はCRLFとCRをLFに統一する
+func normalizeLineEndings(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\r", "\n")
+}
+
+// isBlankRow は全フィールドが空の行かを判定
+func isBlankRow(row []string) bool {
+	for _, f := range row {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseMeisaiDateTime は明細の日付（2006/01/02 または 06/01/02）と時分（15:04）を解析
+func parseMeisaiDateTime(date, clock string) (time.Time, error) {
+	value := date
+	layouts := []string{"2006/01/02", "06/01/02"}
+	if clock != "" {
+		value = date + " " + clock
+		layouts = []string{"2006/01/02 15:04", "06/01/02 15:04"}
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, jst); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid usage date %q", value)
+}
+
+// parseMeisaiAmount は料金（"1,200" のような桁区切りを含む場合あり）を解析
+func parseMeisaiAmount(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	amount, err := strconv.Atoi(strings.ReplaceAll(s, ",", ""))
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+// maxProxyFailures はプロキシプールのプロキシを飛ばすまでの連続失敗回数
+const maxProxyFailures = 3
+
+// getProxyPool は環境変数からアカウントに順番に割り当てるプロキシの一覧を取得
+// ETC_PROXY_POOL はカンマ区切りのプロキシURL（認証情報はURLに含める）、未設定の場合はnil
+// 不正なエントリは読み飛ばす
+func getProxyPool() []string {
+	value := strings.TrimSpace(os.Getenv("ETC_PROXY_POOL"))
+	if value == "" {
+		return nil
+	}
+
+	var pool []string
+	for i, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if u, err := url.Parse(entry); err != nil || u.Scheme == "" || u.Host == "" {
+			// プロキシURLに認証情報が含まれ得るため内容は出力しない
+			log.Printf("[Proxy] Ignoring invalid ETC_PROXY_POOL entry #%d (expected scheme://host:port)", i+1)
+			continue
+		}
+		pool = append(pool, entry)
+	}
+	return pool
+}
+
+// proxyPool はProxyPoolのプロキシを順番に割り当て、プロキシごとの連続失敗回数を数える
+type proxyPool struct {
+	mu       sync.Mutex
+	next     int            // 次に割り当てるプロキシの位置
+	failures map[string]int // プロキシURL -> 連続失敗回数
+}
+
+// pick はpoolから連続失敗回数がmaxProxyFailures未満の次のプロキシを返す
+// skippedは飛ばしたプロキシ、すべてのプロキシが上限に達している場合はexhaustedをtrueにして次のプロキシを返す
+func (p *proxyPool) pick(pool []string) (proxyURL string, skipped []string, exhausted bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	start := p.next % len(pool)
+	for i := range pool {
+		candidate := pool[(start+i)%len(pool)]
+		if p.failures[candidate] < maxProxyFailures {
+			p.next = start + i + 1
+			return candidate, skipped, false
+		}
+		skipped = append(skipped, candidate)
+	}
+	p.next = start + 1
+	return pool[start], skipped, true
+}
+
+// record はプロキシを使ったアカウントの結果を記録し、連続失敗回数を返す
+// 成功した場合は0に戻し、ネットワーク・タイムアウトなど一時的なエラーのみ失敗として数える
+// （認証エラーやメンテナンスはプロキシの問題ではないため数えない）
+func (p *proxyPool) record(proxyURL string, err error) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil {
+		delete(p.failures, proxyURL)
+		return 0
+	}
+	if isProxyFailure(err) {
+		if p.failures == nil {
+			p.failures = make(map[string]int)
+		}
+		p.failures[proxyURL]++
+	}
+	return p.failures[proxyURL]
+}
+
+// isProxyFailure はアカウントのエラーがプロキシに起因する可能性があるか（ネットワーク・タイムアウトなど一時的なエラー）を返す
+// "login failed for account ..."などのラップしたメッセージで判定しないよう、最も内側のエラーで判定する
+func isProxyFailure(err error) bool {
+	if scraper.IsAuthError(err) || scraper.IsMaintenanceError(err) {
+		return false
+	}
+	for inner := errors.Unwrap(err); inner != nil; inner = errors.Unwrap(err) {
+		err = inner
+	}
+	return scraper.IsTransientError(err)
+}
+
+// pickPoolProxy はアカウントにProxyPoolのプロキシを割り当て、選んだ理由をログに出力する
+func (s *DownloadService) pickPoolProxy(jobID, userID string) string {
+	proxyURL, skipped, exhausted := s.proxies.pick(s.ProxyPool)
+	for _, p := range skipped {
+		s.logJobf(LogLevelInfo, jobID, userID, "Skipping pooled proxy %s for account %s: failed %d times in a row",
+			scraper.MaskProxyURL(p), userID, maxProxyFailures)
+	}
+	if exhausted {
+		s.logJobf(LogLevelWarn, jobID, userID, "All %d pooled proxies failed %d times in a row, using %s for account %s anyway",
+			len(s.ProxyPool), maxProxyFailures, scraper.MaskProxyURL(proxyURL), userID)
+		return proxyURL
+	}
+	s.logJobf(LogLevelInfo, jobID, userID, "Using pooled proxy %s for account %s", scraper.MaskProxyURL(proxyURL), userID)
+	return proxyURL
+}
+
+// recordPoolProxy はアカウントの結果をプロキシの連続失敗回数に反映する
+func (s *DownloadService) recordPoolProxy(jobID, userID, proxyURL string, err error) {
+	failures := s.proxies.record(proxyURL, err)
+	if err == nil || !isProxyFailure(err) {
+		return
+	}
+	if failures == maxProxyFailures {
+		s.logJobf(LogLevelWarn, jobID, userID, "Pooled proxy %s failed %d times in a row (account %s), skipping it for later accounts",
+			scraper.MaskProxyURL(proxyURL), failures, userID)
+		return
+	}
+	s.logJobf(LogLevelWarn, jobID, userID, "Pooled proxy %s failed for account %s (%d in a row)",
+		scraper.MaskProxyURL(proxyURL), userID, failures)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// accountAttempts はアカウントの処理で各操作を実行した回数（リトライを含む）
+type accountAttempts struct {
+	Initialize int
+	Login      int
+	Download   int
+	// Budget は許可されたリトライ回数の合計（初回の実行は含まない）
+	Budget int
+}
+
+// total は実行した回数の合計
+func (a accountAttempts) total() int {
+	return a.Initialize + a.Login + a.Download
+}
+
+// retries は初回の実行を除いたリトライ回数
+func (a accountAttempts) retries() int {
+	retries := 0
+	for _, n := range []int{a.Initialize, a.Login, a.Download} {
+		if n > 1 {
+			retries += n - 1
+		}
+	}
+	return retries
+}
+
+// accountSummary はジョブ終了時のログに出力するアカウントごとの結果
+type accountSummary struct {
+	AccountID string
+	Outcome   string
+	Attempts  accountAttempts
+	Duration  time.Duration
+}
+
+func (a accountSummary) String() string {
+	return fmt.Sprintf("%s %s (%d attempts, %d/%d retries, %v)",
+		a.AccountID, a.Outcome, a.Attempts.total(), a.Attempts.retries(), a.Attempts.Budget, a.Duration)
+}
+
+// accountSummaries はワーカーが並行して追加するアカウントごとの結果
+type accountSummaries struct {
+	mu    sync.Mutex
+	items []accountSummary
+}
+
+func (s *accountSummaries) add(summary accountSummary) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = append(s.items, summary)
+}
+
+// String はアカウントID順に結果を連結する（処理したアカウントがなければ空）
+func (s *accountSummaries) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := append([]accountSummary(nil), s.items...)
+	sort.Slice(items, func(i, j int) bool { return items[i].AccountID < items[j].AccountID })
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = item.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// logSuffix はジョブ終了時のログに付けるアカウントごとの結果（処理したアカウントがなければ空）
+func (s *accountSummaries) logSuffix() string {
+	if text := s.String(); text != "" {
+		return " [" + text + "]"
+	}
+	return ""
+}
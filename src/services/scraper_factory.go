@@ -2,6 +2,8 @@ package services
 
 import (
 	"log"
+	"os"
+	"strconv"
 
 	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
 )
@@ -22,4 +24,57 @@ func (f *DefaultScraperFactory) CreateScraper(config *scraper.ScraperConfig, log
 // NewDefaultScraperFactory creates a new default scraper factory
 func NewDefaultScraperFactory() ScraperFactory {
 	return &DefaultScraperFactory{}
+}
+
+// PooledScraperFactory creates ETCScraper instances that share pooled browsers.
+// Every scraper gets a fresh browser context, so cookies and storage are not
+// shared between accounts.
+type PooledScraperFactory struct {
+	pool *scraper.BrowserPool
+}
+
+// NewPooledScraperFactory creates a factory that launches at most size browsers
+// per launch configuration
+func NewPooledScraperFactory(size int) *PooledScraperFactory {
+	return NewPooledScraperFactoryWithPlaywright(&scraper.DefaultPlaywrightFactory{}, size)
+}
+
+// NewPooledScraperFactoryWithPlaywright creates a pooled factory on top of a custom Playwright factory
+func NewPooledScraperFactoryWithPlaywright(factory scraper.PlaywrightFactory, size int) *PooledScraperFactory {
+	return &PooledScraperFactory{pool: scraper.NewBrowserPool(factory, size)}
+}
+
+// CreateScraper creates a new ETCScraper instance backed by the browser pool
+func (f *PooledScraperFactory) CreateScraper(config *scraper.ScraperConfig, logger *log.Logger) (scraper.ScraperInterface, error) {
+	return scraper.NewETCScraperWithFactory(config, logger, f.pool)
+}
+
+// Close closes the pooled browsers (DownloadService.Shutdown calls it once jobs have finished)
+func (f *PooledScraperFactory) Close() error {
+	return f.pool.Close()
+}
+
+// NewScraperFactory creates a pooled factory when ETC_BROWSER_POOL_SIZE is positive,
+// and the default factory (one browser per account) otherwise
+func NewScraperFactory() ScraperFactory {
+	if size := getBrowserPoolSize(); size > 0 {
+		return NewPooledScraperFactory(size)
+	}
+	return NewDefaultScraperFactory()
+}
+
+// getBrowserPoolSize reads ETC_BROWSER_POOL_SIZE (default 0 = pooling disabled)
+func getBrowserPoolSize() int {
+	sizeEnv := os.Getenv("ETC_BROWSER_POOL_SIZE")
+	if sizeEnv == "" {
+		return 0
+	}
+
+	size, err := strconv.Atoi(sizeEnv)
+	if err != nil || size < 0 {
+		log.Printf("[Browser] Invalid ETC_BROWSER_POOL_SIZE value %q, using default: 0 (pooling disabled)", sizeEnv)
+		return 0
+	}
+
+	return size
 }
\ No newline at end of file
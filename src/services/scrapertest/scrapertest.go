@@ -0,0 +1,273 @@
+// Package scrapertest はDownloadServiceのテスト用に、ブラウザを起動しない
+// services.ScraperFactory の実装（FakeScraperFactory・FakeScraper）を提供する
+//
+//	factory := &scrapertest.FakeScraperFactory{
+//		CSV: "利用年月日（自）,通行料金\n2024/01/05,1200\n",
+//		Accounts: map[string]scrapertest.Behavior{
+//			"user2": {LoginErr: &scraper.AuthError{Reason: "invalid password"}},
+//		},
+//	}
+//	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+package scrapertest
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+// DefaultCSV はCSVを指定しなかった場合にダウンロードされる、明細0件のCSV
+const DefaultCSV = "利用年月日（自）,通行料金\n"
+
+// ErrClosed はダウンロード中にCloseされた場合のDownloadMeisaiのエラー（実際のブラウザと同じ）
+var ErrClosed = errors.New("target page, context or browser has been closed")
+
+// Behavior はアカウントごとの動作
+// ゼロ値のフィールドはFakeScraperFactoryの同名のフィールドを使う
+type Behavior struct {
+	// InitErr・LoginErr・DownloadErr は各操作が返すエラー
+	InitErr     error
+	LoginErr    error
+	DownloadErr error
+	// InitFailures・LoginFailures・DownloadFailures はエラーを返す回数（0の場合は毎回）
+	// 一時的なエラー（タイムアウトなど）の後に成功するリトライを再現できる
+	InitFailures     int
+	LoginFailures    int
+	DownloadFailures int
+	// LoggedIn・LoggedInErr はIsLoggedInが返す値（再利用したセッションが有効な場合を再現する）
+	LoggedIn    bool
+	LoggedInErr error
+	// CertificateErr はDownloadCertificatesが返すエラー
+	CertificateErr error
+	// CSV はダウンロードされるCSVの内容
+	CSV string
+	// Latency はDownloadMeisaiにかかる時間（Closeで中断される）
+	Latency time.Duration
+	// ExpectedRecords はサイトに表示される件数（ExpectedKnownがfalseの場合は不明）
+	ExpectedRecords int
+	ExpectedKnown   bool
+}
+
+// FakeScraperFactory はFakeScraperを作成し、アカウントごとの呼び出し回数を記録する
+// フィールドは全アカウント共通の動作で、Accountsでアカウントごとに上書きできる
+// フィールドはDownloadServiceに渡す前に設定し、実行中に変更しないこと
+type FakeScraperFactory struct {
+	Behavior
+	// Accounts はアカウントID -> そのアカウントの動作
+	Accounts map[string]Behavior
+	// CreateErr はCreateScraperが返すエラー
+	CreateErr error
+
+	mu       sync.Mutex
+	scrapers []*FakeScraper
+	calls    map[string]*Calls
+}
+
+// Calls はアカウントごとの各操作の呼び出し回数
+type Calls struct {
+	Initialize   int
+	Login        int
+	Download     int
+	Certificates int
+	Close        int
+}
+
+var _ services.ScraperFactory = (*FakeScraperFactory)(nil)
+
+// CreateScraper はFakeScraperを作成する
+func (f *FakeScraperFactory) CreateScraper(config *scraper.ScraperConfig, logger *log.Logger) (scraper.ScraperInterface, error) {
+	if f.CreateErr != nil {
+		return nil, f.CreateErr
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := &FakeScraper{factory: f, config: config, closed: make(chan struct{})}
+	f.scrapers = append(f.scrapers, s)
+	return s, nil
+}
+
+// Scrapers は作成したFakeScraperを作成順に返す
+func (f *FakeScraperFactory) Scrapers() []*FakeScraper {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*FakeScraper(nil), f.scrapers...)
+}
+
+// Calls はアカウントの各操作の呼び出し回数を返す
+func (f *FakeScraperFactory) Calls(accountID string) Calls {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if calls, ok := f.calls[accountID]; ok {
+		return *calls
+	}
+	return Calls{}
+}
+
+// behavior はアカウントの動作を返す（未設定のフィールドは共通の動作）
+func (f *FakeScraperFactory) behavior(accountID string) Behavior {
+	b := f.Accounts[accountID]
+	def := f.Behavior
+	if b.InitErr == nil {
+		b.InitErr, b.InitFailures = def.InitErr, def.InitFailures
+	}
+	if b.LoginErr == nil {
+		b.LoginErr, b.LoginFailures = def.LoginErr, def.LoginFailures
+	}
+	if b.DownloadErr == nil {
+		b.DownloadErr, b.DownloadFailures = def.DownloadErr, def.DownloadFailures
+	}
+	if b.CertificateErr == nil {
+		b.CertificateErr = def.CertificateErr
+	}
+	if !b.LoggedIn && b.LoggedInErr == nil {
+		b.LoggedIn, b.LoggedInErr = def.LoggedIn, def.LoggedInErr
+	}
+	if b.CSV == "" {
+		b.CSV = def.CSV
+	}
+	if b.CSV == "" {
+		b.CSV = DefaultCSV
+	}
+	if b.Latency == 0 {
+		b.Latency = def.Latency
+	}
+	if !b.ExpectedKnown {
+		b.ExpectedRecords, b.ExpectedKnown = def.ExpectedRecords, def.ExpectedKnown
+	}
+	return b
+}
+
+// count はアカウントの操作の呼び出し回数を1増やし、増やした後の回数を返す
+func (f *FakeScraperFactory) count(accountID string, field func(*Calls) *int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = make(map[string]*Calls)
+	}
+	calls, ok := f.calls[accountID]
+	if !ok {
+		calls = &Calls{}
+		f.calls[accountID] = calls
+	}
+	n := field(calls)
+	*n++
+	return *n
+}
+
+// failing は操作が何回目の呼び出しでエラーを返すか判定する
+func failing(err error, failures, call int) error {
+	if err == nil || (failures > 0 && call > failures) {
+		return nil
+	}
+	return err
+}
+
+// FakeScraper はFakeScraperFactoryの設定に従って動作するスクレイパー
+type FakeScraper struct {
+	factory   *FakeScraperFactory
+	config    *scraper.ScraperConfig
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// Config はDownloadServiceから渡された設定を返す
+func (s *FakeScraper) Config() *scraper.ScraperConfig {
+	return s.config
+}
+
+// Initialize はアカウントのInitErrを返す
+func (s *FakeScraper) Initialize() error {
+	call := s.factory.count(s.config.UserID, func(c *Calls) *int { return &c.Initialize })
+	b := s.factory.behavior(s.config.UserID)
+	return failing(b.InitErr, b.InitFailures, call)
+}
+
+// Login はアカウントのLoginErrを返す
+func (s *FakeScraper) Login() error {
+	call := s.factory.count(s.config.UserID, func(c *Calls) *int { return &c.Login })
+	b := s.factory.behavior(s.config.UserID)
+	return failing(b.LoginErr, b.LoginFailures, call)
+}
+
+// IsLoggedIn はアカウントのLoggedIn・LoggedInErrを返す
+func (s *FakeScraper) IsLoggedIn() (bool, error) {
+	b := s.factory.behavior(s.config.UserID)
+	return b.LoggedIn, b.LoggedInErr
+}
+
+// DownloadMeisai はLatencyだけ待ってからCSVをセッションフォルダの<アカウントID>_meisai.csvに保存する
+func (s *FakeScraper) DownloadMeisai(fromDate, toDate string) (scraper.DownloadResult, error) {
+	call := s.factory.count(s.config.UserID, func(c *Calls) *int { return &c.Download })
+	b := s.factory.behavior(s.config.UserID)
+
+	if b.Latency > 0 {
+		select {
+		case <-time.After(b.Latency):
+		case <-s.closed:
+			return scraper.DownloadResult{}, ErrClosed
+		}
+	}
+	if err := failing(b.DownloadErr, b.DownloadFailures, call); err != nil {
+		return scraper.DownloadResult{}, err
+	}
+
+	folder := s.config.SessionFolder
+	if folder == "" {
+		folder = s.config.DownloadPath
+	}
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return scraper.DownloadResult{}, err
+	}
+	path := filepath.Join(folder, s.config.UserID+"_meisai.csv")
+	if err := os.WriteFile(path, []byte(b.CSV), 0644); err != nil {
+		return scraper.DownloadResult{}, err
+	}
+
+	result := scraper.DownloadResult{Path: path, Bytes: int64(len(b.CSV)), Pages: 1, RowCountEstimate: -1}
+	if b.ExpectedKnown {
+		result.RowCountEstimate = b.ExpectedRecords
+	}
+	return result, nil
+}
+
+// DownloadCertificates は利用証明書のPDFをセッションフォルダの<アカウントID>_certificate.pdfに保存する
+func (s *FakeScraper) DownloadCertificates(fromDate, toDate string) ([]string, error) {
+	s.factory.count(s.config.UserID, func(c *Calls) *int { return &c.Certificates })
+	b := s.factory.behavior(s.config.UserID)
+	if b.CertificateErr != nil {
+		return nil, b.CertificateErr
+	}
+
+	folder := s.config.SessionFolder
+	if folder == "" {
+		folder = s.config.DownloadPath
+	}
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(folder, s.config.UserID+"_certificate.pdf")
+	if err := os.WriteFile(path, []byte("%PDF-1.4\n"), 0644); err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+// ExpectedRecordCount はアカウントのExpectedRecordsを返す
+func (s *FakeScraper) ExpectedRecordCount() (int, bool) {
+	b := s.factory.behavior(s.config.UserID)
+	return b.ExpectedRecords, b.ExpectedKnown
+}
+
+// Close は実行中のDownloadMeisaiを中断する
+func (s *FakeScraper) Close() error {
+	s.factory.count(s.config.UserID, func(c *Calls) *int { return &c.Close })
+	s.closeOnce.Do(func() { close(s.closed) })
+	return nil
+}
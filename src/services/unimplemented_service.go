@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+)
+
+// ErrNotImplemented はUnimplementedDownloadServiceのメソッドが返すエラー
+var ErrNotImplemented = errors.New("not implemented")
+
+// UnimplementedDownloadService はDownloadServiceInterfaceの全メソッドを何もしない実装で提供する
+// テスト用のモックに埋め込み、必要なメソッドだけを上書きする（pb.UnimplementedDownloadServiceServerと同じ使い方）
+// エラーを返すメソッドはErrNotImplemented、それ以外はゼロ値・見つからない（false）を返す
+type UnimplementedDownloadService struct{}
+
+var _ DownloadServiceInterface = UnimplementedDownloadService{}
+
+func (UnimplementedDownloadService) GetAllAccountIDs() []string { return nil }
+
+func (UnimplementedDownloadService) GetAllAccountsWithCredentials() []string { return nil }
+
+func (UnimplementedDownloadService) GetGroupAccounts(group string) ([]string, error) {
+	return nil, ErrNotImplemented
+}
+
+func (UnimplementedDownloadService) ProcessAsync(ctx context.Context, jobID string, accounts []string, fromDate, toDate string) {
+}
+
+func (UnimplementedDownloadService) ProcessAsyncWithOptions(ctx context.Context, jobID string, accounts []string, fromDate, toDate string, opts JobOptions) {
+}
+
+func (UnimplementedDownloadService) ProcessAsyncIdempotent(ctx context.Context, key, jobID string, accounts []string, fromDate, toDate string, opts JobOptions) (string, bool) {
+	return "", false
+}
+
+func (UnimplementedDownloadService) GetJobStatus(jobID string) (*DownloadJob, bool) {
+	return nil, false
+}
+
+func (UnimplementedDownloadService) GetJobStatuses(jobIDs []string) map[string]*DownloadJob {
+	return map[string]*DownloadJob{}
+}
+
+func (UnimplementedDownloadService) WaitForJob(ctx context.Context, jobID, lastStatus string) (*DownloadJob, bool) {
+	return nil, false
+}
+
+func (UnimplementedDownloadService) SetLogCallback(callback func(string)) {}
+
+func (UnimplementedDownloadService) SetLogEntryCallback(callback func(LogEntry)) {}
+
+func (UnimplementedDownloadService) StartJobReaper(interval time.Duration) (stop func()) {
+	return func() {}
+}
+
+func (UnimplementedDownloadService) PauseJob(jobID string) error { return ErrNotImplemented }
+
+func (UnimplementedDownloadService) ResumeJob(jobID string) error { return ErrNotImplemented }
+
+func (UnimplementedDownloadService) CancelJob(jobID string) error { return ErrNotImplemented }
+
+func (UnimplementedDownloadService) TestAccount(ctx context.Context, account string) AccountTestResult {
+	return AccountTestResult{Error: ErrNotImplemented.Error()}
+}
+
+func (UnimplementedDownloadService) GetJobRecords(jobID string) ([]*pb.ETCMeisaiRecord, error) {
+	return nil, ErrNotImplemented
+}
+
+func (UnimplementedDownloadService) GetJobRecordsSoFar(jobID string) ([]*pb.ETCMeisaiRecord, error) {
+	return nil, ErrNotImplemented
+}
+
+func (UnimplementedDownloadService) GetJobLogs(jobID string, tail int) ([]LogEntry, error) {
+	return nil, ErrNotImplemented
+}
+
+func (UnimplementedDownloadService) ExportJobCSV(jobID string, w io.Writer) error {
+	return ErrNotImplemented
+}
+
+func (UnimplementedDownloadService) GetJobFiles(jobID string) ([]FileInfo, error) {
+	return nil, ErrNotImplemented
+}
+
+func (UnimplementedDownloadService) WriteJobFile(jobID, name string, w io.Writer) error {
+	return ErrNotImplemented
+}
+
+func (UnimplementedDownloadService) GetRuntimeMetrics() RuntimeMetrics { return RuntimeMetrics{} }
+
+func (UnimplementedDownloadService) UpdateAccountCredential(accountID, newPassword string) error {
+	return ErrNotImplemented
+}
+
+func (UnimplementedDownloadService) CredentialOverrideIDs() []string { return nil }
+
+func (UnimplementedDownloadService) GetAccountStatuses(accountIDs []string) []AccountRunStatus {
+	return nil
+}
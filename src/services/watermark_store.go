@@ -0,0 +1,74 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// アカウントごとのダウンロード済み日付（migrations/006_add_download_watermarks.sql のdownload_watermarksテーブル）
+// DBが設定されていない場合（db == nil）は記録も参照もしない
+
+// 古い期間を再取得しても後退しないよう、新しい日付の場合のみ更新する
+const upsertWatermarkQuery = `INSERT INTO download_watermarks (account_id, last_download_date)
+	VALUES (?, ?)
+	ON DUPLICATE KEY UPDATE last_download_date = GREATEST(last_download_date, VALUES(last_download_date))`
+
+const selectWatermarkQuery = `SELECT last_download_date FROM download_watermarks WHERE account_id = ?`
+
+// GetLastDownloadDate はアカウントの最後にダウンロードに成功した期間の終了日を返す
+// 記録がない場合やDBが設定されていない場合はfalse
+func (s *DownloadService) GetLastDownloadDate(accountID string) (time.Time, bool) {
+	if s.db == nil {
+		return time.Time{}, false
+	}
+
+	var last time.Time
+	err := s.db.QueryRow(selectWatermarkQuery, accountID).Scan(&last)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false
+	}
+	if err != nil {
+		s.logMessagef(LogLevelWarn, "Failed to load last download date for account %s: %v", accountID, err)
+		return time.Time{}, false
+	}
+	return last, true
+}
+
+// updateWatermark はアカウントの最後にダウンロードに成功した期間の終了日を記録
+func (s *DownloadService) updateWatermark(jobID, accountID, toDate string) {
+	if s.db == nil {
+		return
+	}
+
+	date, err := time.Parse(dateLayout, toDate)
+	if err != nil {
+		s.logJobf(LogLevelWarn, jobID, accountID, "Failed to record last download date for account %s: %v", accountID, err)
+		return
+	}
+	if _, err := s.db.Exec(upsertWatermarkQuery, accountID, date); err != nil {
+		s.logJobf(LogLevelWarn, jobID, accountID, "Failed to record last download date for account %s: %v", accountID, err)
+	}
+}
+
+// accountFromDate はアカウントのダウンロード開始日を返す
+// 増分モードでfrom_dateが指定されていない場合は、前回ダウンロードした期間の終了日から取得する
+// 前回の終了日がtoDateより後（過去の期間を取得し直す場合）は、期間が逆転しないようfromDateを使う
+func (s *DownloadService) accountFromDate(jobID, accountID, fromDate, toDate string, opts JobOptions) string {
+	if !s.Incremental || !opts.FromDateUnset {
+		return fromDate
+	}
+
+	last, ok := s.GetLastDownloadDate(accountID)
+	if !ok {
+		return fromDate
+	}
+	incrementalFrom := last.Format(dateLayout)
+	if incrementalFrom > toDate {
+		s.logJobf(LogLevelInfo, jobID, accountID, "Last downloaded date %s of account %s is after to_date %s, downloading from %s",
+			incrementalFrom, accountID, toDate, fromDate)
+		return fromDate
+	}
+	s.logJobf(LogLevelInfo, jobID, accountID, "Incremental download for account %s from %s (last downloaded)", accountID, incrementalFrom)
+	return incrementalFrom
+}
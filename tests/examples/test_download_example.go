@@ -58,10 +58,11 @@ func main() {
 
 	// データダウンロード
 	logger.Printf("Downloading meisai from %s to %s...", fromDate, toDate)
-	csvPath, err := etcScraper.DownloadMeisai(fromDate, toDate)
+	result, err := etcScraper.DownloadMeisai(fromDate, toDate)
 	if err != nil {
 		logger.Fatalf("Download failed: %v", err)
 	}
+	csvPath := result.Path
 
 	logger.Printf("Successfully downloaded: %s", csvPath)
 	fmt.Println("\nTest completed successfully!")
@@ -13,9 +13,16 @@ type MockETCScraper struct {
 	InitializeError    error
 	LoginCalled        bool
 	LoginError         error
+	LoggedIn           bool
+	LoggedInError      error
 	DownloadCalled     bool
 	DownloadError      error
 	DownloadResult     string
+	ExpectedCount      int
+	ExpectedCountKnown bool
+	CertificatesCalled bool
+	CertificatesError  error
+	CertificatePaths   []string
 	CloseCalled        bool
 	CloseError         error
 	FromDate           string
@@ -41,17 +48,36 @@ func (m *MockETCScraper) Login() error {
 	return m.LoginError
 }
 
+// IsLoggedIn mocks the IsLoggedIn method
+func (m *MockETCScraper) IsLoggedIn() (bool, error) {
+	return m.LoggedIn, m.LoggedInError
+}
+
 // DownloadMeisai mocks the DownloadMeisai method
-func (m *MockETCScraper) DownloadMeisai(fromDate, toDate string) (string, error) {
+func (m *MockETCScraper) DownloadMeisai(fromDate, toDate string) (scraper.DownloadResult, error) {
 	m.DownloadCalled = true
 	m.FromDate = fromDate
 	m.ToDate = toDate
 
 	if m.DownloadError != nil {
-		return "", m.DownloadError
+		return scraper.DownloadResult{}, m.DownloadError
 	}
 
-	return m.DownloadResult, nil
+	return scraper.DownloadResult{Path: m.DownloadResult, RowCountEstimate: -1}, nil
+}
+
+// ExpectedRecordCount mocks the ExpectedRecordCount method
+func (m *MockETCScraper) ExpectedRecordCount() (int, bool) {
+	return m.ExpectedCount, m.ExpectedCountKnown
+}
+
+// DownloadCertificates mocks the DownloadCertificates method
+func (m *MockETCScraper) DownloadCertificates(fromDate, toDate string) ([]string, error) {
+	m.CertificatesCalled = true
+	if m.CertificatesError != nil {
+		return nil, m.CertificatesError
+	}
+	return m.CertificatePaths, nil
 }
 
 // Close mocks the Close method
@@ -98,7 +124,10 @@ func (f *MockScraperFactory) CreateScraper(config *scraper.ScraperConfig, logger
 type ConfigurableETCScraper struct {
 	InitializeFunc func() error
 	LoginFunc      func() error
-	DownloadFunc   func(fromDate, toDate string) (string, error)
+	LoggedInFunc   func() (bool, error)
+	DownloadFunc   func(fromDate, toDate string) (scraper.DownloadResult, error)
+	ExpectedFunc   func() (int, bool)
+	CertFunc       func(fromDate, toDate string) ([]string, error)
 	CloseFunc      func() error
 }
 
@@ -107,8 +136,8 @@ func NewConfigurableETCScraper() *ConfigurableETCScraper {
 	return &ConfigurableETCScraper{
 		InitializeFunc: func() error { return nil },
 		LoginFunc:      func() error { return nil },
-		DownloadFunc:   func(fromDate, toDate string) (string, error) {
-			return fmt.Sprintf("/downloads/mock_%s_%s.csv", fromDate, toDate), nil
+		DownloadFunc:   func(fromDate, toDate string) (scraper.DownloadResult, error) {
+			return scraper.DownloadResult{Path: fmt.Sprintf("/downloads/mock_%s_%s.csv", fromDate, toDate), RowCountEstimate: -1}, nil
 		},
 		CloseFunc:      func() error { return nil },
 	}
@@ -130,12 +159,36 @@ func (c *ConfigurableETCScraper) Login() error {
 	return nil
 }
 
+// IsLoggedIn calls the configured function
+func (c *ConfigurableETCScraper) IsLoggedIn() (bool, error) {
+	if c.LoggedInFunc != nil {
+		return c.LoggedInFunc()
+	}
+	return false, nil
+}
+
 // DownloadMeisai calls the configured function
-func (c *ConfigurableETCScraper) DownloadMeisai(fromDate, toDate string) (string, error) {
+func (c *ConfigurableETCScraper) DownloadMeisai(fromDate, toDate string) (scraper.DownloadResult, error) {
 	if c.DownloadFunc != nil {
 		return c.DownloadFunc(fromDate, toDate)
 	}
-	return "", nil
+	return scraper.DownloadResult{}, nil
+}
+
+// ExpectedRecordCount calls the configured function
+func (c *ConfigurableETCScraper) ExpectedRecordCount() (int, bool) {
+	if c.ExpectedFunc != nil {
+		return c.ExpectedFunc()
+	}
+	return 0, false
+}
+
+// DownloadCertificates calls the configured function
+func (c *ConfigurableETCScraper) DownloadCertificates(fromDate, toDate string) ([]string, error) {
+	if c.CertFunc != nil {
+		return c.CertFunc(fromDate, toDate)
+	}
+	return nil, nil
 }
 
 // Close calls the configured function
@@ -0,0 +1,142 @@
+package grpc_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	etcgrpc "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const downloadServiceName = "etc_meisai.download.v1.DownloadService"
+
+// pingDriver is a database/sql driver whose Ping result is controlled by the test
+type pingDriver struct{ err error }
+
+func (d *pingDriver) Open(string) (driver.Conn, error) { return &pingConn{d}, nil }
+
+type pingConn struct{ d *pingDriver }
+
+func (c *pingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *pingConn) Close() error                        { return nil }
+func (c *pingConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+func (c *pingConn) Ping(context.Context) error          { return c.d.err }
+
+var failingPing = &pingDriver{err: errors.New("connection refused")}
+
+func init() {
+	sql.Register("failing-ping", failingPing)
+}
+
+type bufListener struct{ lis *bufconn.Listener }
+
+func (b *bufListener) Listen(network, address string) (net.Listener, error) { return b.lis, nil }
+
+// startServer starts the server on an in-memory listener and returns a health client
+func startServer(t *testing.T, db *sql.DB) healthpb.HealthClient {
+	t.Helper()
+	return startServerWithLogger(t, db, log.New(os.Stderr, "", 0))
+}
+
+// startServerWithLogger is startServer with the server logging to logger
+func startServerWithLogger(t *testing.T, db *sql.DB, logger *log.Logger) healthpb.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := etcgrpc.NewServerWithListener(db, logger, &bufListener{lis})
+	go server.Start("0")
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+// installFakeDriver points PLAYWRIGHT_DRIVER_PATH at a directory that looks like an installed driver
+func installFakeDriver(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "package"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "package", "cli.js"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PLAYWRIGHT_DRIVER_PATH", dir)
+}
+
+func checkStatus(t *testing.T, client healthpb.HealthClient, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q): %v", service, err)
+	}
+	return resp.Status
+}
+
+func TestHealth_ServingWhenDependenciesAvailable(t *testing.T) {
+	installFakeDriver(t)
+	client := startServer(t, nil)
+
+	if got := checkStatus(t, client, ""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("overall status = %v, want SERVING", got)
+	}
+	if got := checkStatus(t, client, downloadServiceName); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("download service status = %v, want SERVING", got)
+	}
+}
+
+func TestHealth_NotServingWhenDBPingFails(t *testing.T) {
+	installFakeDriver(t)
+	db, err := sql.Open("failing-ping", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	client := startServer(t, db)
+
+	if got := checkStatus(t, client, ""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("overall status = %v, want NOT_SERVING", got)
+	}
+	if got := checkStatus(t, client, downloadServiceName); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("download service status = %v, want NOT_SERVING", got)
+	}
+}
+
+func TestHealth_DownloadServiceNotServingWithoutBrowser(t *testing.T) {
+	t.Setenv("PLAYWRIGHT_DRIVER_PATH", filepath.Join(t.TempDir(), "missing"))
+	client := startServer(t, nil)
+
+	if got := checkStatus(t, client, ""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("overall status = %v, want SERVING", got)
+	}
+	if got := checkStatus(t, client, downloadServiceName); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("download service status = %v, want NOT_SERVING", got)
+	}
+}
+
+func TestHealth_UnknownServiceIsNotFound(t *testing.T) {
+	installFakeDriver(t)
+	client := startServer(t, nil)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "no.such.Service"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package grpc_test
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	etcgrpc "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newGateway serves the HTTP gateway of a new server from an httptest server
+func newGateway(t *testing.T, logger *log.Logger) *httptest.Server {
+	t.Helper()
+	server := etcgrpc.NewServerWithListener(nil, logger, &bufListener{bufconn.Listen(1 << 20)})
+	t.Cleanup(server.Stop)
+	handler, err := server.HTTPHandler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateway := httptest.NewServer(handler)
+	t.Cleanup(gateway.Close)
+	return gateway
+}
+
+func TestHTTPGateway_ExposesJobAndLogRPCs(t *testing.T) {
+	var buf syncBuffer
+	installFakeDriver(t)
+	gateway := newGateway(t, log.New(&buf, "", 0))
+
+	resp, err := http.Get(gateway.URL + "/etc_meisai_scraper/v1/logs?tail_lines=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logs struct {
+		TotalLines int `json:"totalLines"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&logs)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the server logs as JSON, got %d %v", resp.StatusCode, err)
+	}
+	if got := buf.String(); !strings.Contains(got, "rpc method=/etc_meisai.download.v1.DownloadService/GetServerLogs code=OK") ||
+		!strings.Contains(got, "peer=127.0.0.1:") {
+		t.Errorf("expected the HTTP call to be logged like an RPC, got %q", got)
+	}
+
+	tests := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodGet, "/etc_meisai_scraper/v1/download/jobs/missing", "", http.StatusNotFound},
+		{http.MethodPost, "/etc_meisai_scraper/v1/download/async", `{"accounts":["no-password"],"strict_accounts":true}`, http.StatusBadRequest},
+		// only DownloadAsync, GetJobStatus and GetServerLogs are exposed
+		{http.MethodGet, "/etc_meisai_scraper/v1/version", "", http.StatusNotImplemented},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, gateway.URL+tt.path, strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.want, resp.StatusCode)
+		}
+	}
+}
+
+func TestHTTPGateway_AppliesRateLimit(t *testing.T) {
+	installFakeDriver(t)
+	t.Setenv("ETC_RPC_RATE", "GetServerLogs=0.001:1")
+	gateway := newGateway(t, log.New(&syncBuffer{}, "", 0))
+
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		resp, err := http.Get(gateway.URL + "/etc_meisai_scraper/v1/logs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("call %d: expected %d, got %d", i+1, want, resp.StatusCode)
+		}
+	}
+}
+
+// tcpListener listens on a free loopback port whatever port is requested
+type tcpListener struct{ addr chan string }
+
+func (l *tcpListener) Listen(network, address string) (net.Listener, error) {
+	lis, err := net.Listen(network, "127.0.0.1:0")
+	if err == nil {
+		l.addr <- lis.Addr().String()
+	}
+	return lis, err
+}
+
+func TestStartHTTP_ServesUntilStop(t *testing.T) {
+	installFakeDriver(t)
+	listener := &tcpListener{addr: make(chan string, 1)}
+	server := etcgrpc.NewServerWithListener(nil, log.New(&syncBuffer{}, "", 0), listener)
+	done := make(chan error, 1)
+	go func() { done <- server.StartHTTP("0") }()
+
+	addr := <-listener.addr
+	resp, err := http.Get("http://" + addr + "/etc_meisai_scraper/v1/logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	server.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected StartHTTP to return nil after Stop, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("StartHTTP did not return after Stop")
+	}
+}
@@ -0,0 +1,143 @@
+package grpc_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	etcgrpc "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/grpc"
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryUnaryInterceptor_ConvertsPanicToInternal(t *testing.T) {
+	var buf bytes.Buffer
+	interceptor := etcgrpc.RecoveryUnaryInterceptor(log.New(&buf, "", 0))
+	info := &grpc.UnaryServerInfo{FullMethod: "/etc_meisai.download.v1.DownloadService/GetJobStatus"}
+
+	resp, err := interceptor(context.Background(), &pb.GetJobStatusRequest{}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("nil map")
+	})
+	if resp != nil || status.Code(err) != codes.Internal {
+		t.Errorf("expected Internal, got %v %v", resp, err)
+	}
+	if !strings.Contains(buf.String(), "panic in /etc_meisai.download.v1.DownloadService/GetJobStatus: nil map") {
+		t.Errorf("expected the panic to be logged, got %q", buf.String())
+	}
+}
+
+// fakeServerStream is a grpc.ServerStream with only a context
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context     { return s.ctx }
+func (s *fakeServerStream) SetHeader(metadata.MD) error  { return nil }
+func (s *fakeServerStream) SendHeader(metadata.MD) error { return nil }
+
+func TestStreamInterceptors_RecoverAndLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+	recovery := etcgrpc.RecoveryStreamInterceptor(logger)
+	logging := etcgrpc.LoggingStreamInterceptor(logger)
+	info := &grpc.StreamServerInfo{FullMethod: "/etc_meisai.download.v1.DownloadService/StreamServerLogs"}
+	stream := &fakeServerStream{ctx: context.Background()}
+
+	err := logging(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return recovery(srv, ss, info, func(interface{}, grpc.ServerStream) error { panic("boom") })
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expected Internal, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "rpc method=/etc_meisai.download.v1.DownloadService/StreamServerLogs code=Internal") {
+		t.Errorf("expected the stream to be logged, got %q", buf.String())
+	}
+}
+
+func TestLoggingUnaryInterceptor_RedactsPasswords(t *testing.T) {
+	var buf bytes.Buffer
+	interceptor := etcgrpc.LoggingUnaryInterceptor(log.New(&buf, "", 0))
+
+	tests := []struct {
+		method string
+		req    interface{}
+		keep   []string
+	}{
+		{"/etc_meisai.download.v1.DownloadService/DownloadSync",
+			&pb.DownloadRequest{Accounts: []string{"user1:s3cret-1", "user2:s3cret:2"}, FromDate: "2024-01-01"},
+			[]string{"user1:*******", "user2:*******", "2024-01-01"}},
+		{"/etc_meisai.download.v1.DownloadService/TestAccount",
+			&pb.TestAccountRequest{AccountId: "user1", Password: "s3cret-3"},
+			[]string{"user1"}},
+		{"/etc_meisai.download.v1.DownloadService/UpdateCredential",
+			&pb.UpdateCredentialRequest{AccountId: "user1", NewPassword: "s3cret-4"},
+			[]string{"user1"}},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+		_, err := interceptor(context.Background(), tt.req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "missing")
+		})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("%s: expected the handler's error to be returned, got %v", tt.method, err)
+		}
+		line := buf.String()
+		if !strings.Contains(line, "rpc method="+tt.method+" code=NotFound duration=") || !strings.Contains(line, "peer=unknown") {
+			t.Errorf("%s: unexpected log line %q", tt.method, line)
+		}
+		if strings.Contains(line, "s3cret") {
+			t.Errorf("%s: password exposed in log: %q", tt.method, line)
+		}
+		for _, want := range tt.keep {
+			if !strings.Contains(line, want) {
+				t.Errorf("%s: expected %q in log, got %q", tt.method, want, line)
+			}
+		}
+	}
+
+	// the handler still receives the original request
+	req := &pb.TestAccountRequest{AccountId: "user1", Password: "s3cret-5"}
+	interceptor(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: "/x"}, func(ctx context.Context, r interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	if req.Password != "s3cret-5" {
+		t.Errorf("expected the request to be left untouched, got %q", req.Password)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the server's goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestNewServer_LogsEachRPC(t *testing.T) {
+	var buf syncBuffer
+	installFakeDriver(t)
+	client := startServerWithLogger(t, nil, log.New(&buf, "", 0))
+
+	checkStatus(t, client, "")
+	if got := buf.String(); !strings.Contains(got, "rpc method=/grpc.health.v1.Health/Check code=OK") {
+		t.Errorf("expected the health check to be logged, got %q", got)
+	}
+}
@@ -0,0 +1,64 @@
+package grpc_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	etcgrpc "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestNewServer_WritesLogsToETCLogOutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	if err := os.WriteFile(path, []byte("previous run\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ETC_LOG_OUTPUT", path)
+
+	server := etcgrpc.NewServerWithListener(nil, nil, &bufListener{bufconn.Listen(1 << 20)})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server.Start("0")
+	}()
+	waitForLog(t, path, "Starting gRPC server on port 0")
+	server.Stop()
+	<-done
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "previous run\n") {
+		t.Errorf("expected the log file to be appended to, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), "[GRPC-SERVER]") || !strings.Contains(string(data), "Stopping gRPC server") {
+		t.Errorf("expected the server logs in the file, got:\n%s", data)
+	}
+}
+
+func TestNewServer_UnwritableLogOutputFallsBackToStdout(t *testing.T) {
+	t.Setenv("ETC_LOG_OUTPUT", filepath.Join(t.TempDir(), "missing", "server.log"))
+
+	server := etcgrpc.NewServerWithListener(nil, nil, &bufListener{bufconn.Listen(1 << 20)})
+	if server == nil {
+		t.Fatal("expected a server")
+	}
+	server.Stop()
+}
+
+// waitForLog polls the file until it contains substr
+func waitForLog(t *testing.T, path, substr string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, _ := os.ReadFile(path); strings.Contains(string(data), substr) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s does not contain %q", path, substr)
+}
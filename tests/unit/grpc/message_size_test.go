@@ -0,0 +1,76 @@
+package grpc_test
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	etcgrpc "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startServerWithDialOptions is startServer with extra client dial options
+func startServerWithDialOptions(t *testing.T, opts ...grpc.DialOption) healthpb.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := etcgrpc.NewServerWithListener(nil, log.New(os.Stderr, "", 0), &bufListener{lis})
+	go server.Start("0")
+	t.Cleanup(server.Stop)
+
+	opts = append(opts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestNewServer_MaxMsgSizeFromEnv(t *testing.T) {
+	installFakeDriver(t)
+	// a 2MB request fits the default 4MB limit but not 1MB
+	large := &healthpb.HealthCheckRequest{Service: strings.Repeat("x", 2<<20)}
+
+	t.Run("default", func(t *testing.T) {
+		client := startServerWithDialOptions(t)
+		if _, err := client.Check(context.Background(), large); status.Code(err) != codes.NotFound {
+			t.Errorf("expected the request to reach the health service, got %v", err)
+		}
+	})
+
+	t.Run("lowered", func(t *testing.T) {
+		t.Setenv("ETC_GRPC_MAX_MSG_MB", "1")
+		client := startServerWithDialOptions(t)
+		if _, err := client.Check(context.Background(), large); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("expected ResourceExhausted from the server, got %v", err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv("ETC_GRPC_MAX_MSG_MB", "0")
+		if got := etcgrpc.MaxMsgSize(); got != 4<<20 {
+			t.Errorf("expected the 4MB default, got %d", got)
+		}
+	})
+}
+
+func TestClientDialOptions_UseSameLimit(t *testing.T) {
+	installFakeDriver(t)
+	t.Setenv("ETC_GRPC_MAX_MSG_MB", "1")
+	client := startServerWithDialOptions(t, etcgrpc.ClientDialOptions()...)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: strings.Repeat("x", 2<<20)})
+	if status.Code(err) != codes.ResourceExhausted || !strings.Contains(err.Error(), "trying to send message larger than max") {
+		t.Errorf("expected the client to refuse sending the message, got %v", err)
+	}
+}
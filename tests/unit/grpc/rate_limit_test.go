@@ -0,0 +1,69 @@
+package grpc_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	etcgrpc "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const downloadAsyncMethod = "/etc_meisai.download.v1.DownloadService/DownloadAsync"
+
+func TestRateLimiter_BurstThenRefill(t *testing.T) {
+	limiter := etcgrpc.NewRateLimiter(map[string]etcgrpc.RPCRate{"DownloadAsync": {PerSecond: 20, Burst: 2}})
+
+	if !limiter.Allow(downloadAsyncMethod) || !limiter.Allow(downloadAsyncMethod) {
+		t.Fatal("expected the burst to be allowed")
+	}
+	if limiter.Allow(downloadAsyncMethod) {
+		t.Error("expected the call after the burst to be rejected")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if !limiter.Allow(downloadAsyncMethod) {
+		t.Error("expected a token to be refilled")
+	}
+	for i := 0; i < 100; i++ {
+		if !limiter.Allow("/etc_meisai.download.v1.DownloadService/GetJobStatus") {
+			t.Fatal("expected methods without a limit to be unlimited")
+		}
+	}
+}
+
+func TestRateLimiter_UnaryInterceptorReturnsResourceExhausted(t *testing.T) {
+	limiter := etcgrpc.NewRateLimiter(map[string]etcgrpc.RPCRate{"DownloadAsync": {PerSecond: 0.001, Burst: 1}})
+	interceptor := limiter.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: downloadAsyncMethod}
+	calls := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return nil, nil
+	}
+
+	if _, err := interceptor(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("expected the first call to pass, got %v", err)
+	}
+	_, err := interceptor(context.Background(), nil, info, handler)
+	if status.Code(err) != codes.ResourceExhausted || calls != 1 {
+		t.Errorf("expected ResourceExhausted without calling the handler, got %v after %d calls", err, calls)
+	}
+}
+
+func TestNewServer_RateLimitFromEnv(t *testing.T) {
+	installFakeDriver(t)
+	// an invalid entry is ignored and the valid one still applies
+	t.Setenv("ETC_RPC_RATE", "Check=0.001:1,bogus")
+	client := startServerWithLogger(t, nil, log.New(os.Stderr, "", 0))
+
+	checkStatus(t, client, "")
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package grpc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	etcgrpc "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testPKI is a throwaway CA with a server and a client certificate written as PEM files
+type testPKI struct {
+	dir        string
+	caPool     *x509.CertPool
+	clientCert tls.Certificate
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	p := &testPKI{dir: t.TempDir(), caPool: x509.NewCertPool()}
+
+	caKey := newKey(t)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+	p.caPool.AddCert(caCert)
+	p.writePEM(t, "ca.pem", "CERTIFICATE", caDER)
+
+	issue := func(serial int64, name string, usage x509.ExtKeyUsage) ([]byte, *ecdsa.PrivateKey) {
+		key := newKey(t)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der, key
+	}
+
+	serverDER, serverKey := issue(2, "localhost", x509.ExtKeyUsageServerAuth)
+	p.writePEM(t, "server.pem", "CERTIFICATE", serverDER)
+	p.writeKey(t, "server-key.pem", serverKey)
+
+	clientDER, clientKey := issue(3, "client", x509.ExtKeyUsageClientAuth)
+	p.clientCert = tls.Certificate{Certificate: [][]byte{clientDER}, PrivateKey: clientKey}
+	return p
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func (p *testPKI) path(name string) string { return filepath.Join(p.dir, name) }
+
+func (p *testPKI) writePEM(t *testing.T, name, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(p.path(name), pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (p *testPKI) writeKey(t *testing.T, name string, key *ecdsa.PrivateKey) {
+	t.Helper()
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.writePEM(t, name, "EC PRIVATE KEY", der)
+}
+
+// startTLSServer starts the server on an in-memory listener and returns a function
+// that runs a health check with the given client transport credentials
+func startTLSServer(t *testing.T) func(creds credentials.TransportCredentials) error {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := etcgrpc.NewServerWithListener(nil, log.New(os.Stderr, "", 0), &bufListener{lis})
+	go server.Start("0")
+	t.Cleanup(server.Stop)
+
+	return func(creds credentials.TransportCredentials) error {
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+			grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+}
+
+func TestServer_TLS(t *testing.T) {
+	pki := newTestPKI(t)
+	t.Setenv("ETC_TLS_CERT", pki.path("server.pem"))
+	t.Setenv("ETC_TLS_KEY", pki.path("server-key.pem"))
+	check := startTLSServer(t)
+
+	if err := check(credentials.NewTLS(&tls.Config{RootCAs: pki.caPool, ServerName: "localhost"})); err != nil {
+		t.Errorf("expected a TLS client to connect, got %v", err)
+	}
+	if err := check(insecure.NewCredentials()); err == nil {
+		t.Error("expected a plaintext client to be rejected")
+	}
+}
+
+func TestServer_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	t.Setenv("ETC_TLS_CERT", pki.path("server.pem"))
+	t.Setenv("ETC_TLS_KEY", pki.path("server-key.pem"))
+	t.Setenv("ETC_TLS_CLIENT_CA", pki.path("ca.pem"))
+	check := startTLSServer(t)
+
+	withCert := &tls.Config{RootCAs: pki.caPool, ServerName: "localhost", Certificates: []tls.Certificate{pki.clientCert}}
+	if err := check(credentials.NewTLS(withCert)); err != nil {
+		t.Errorf("expected a client with a certificate to connect, got %v", err)
+	}
+	withoutCert := &tls.Config{RootCAs: pki.caPool, ServerName: "localhost"}
+	if err := check(credentials.NewTLS(withoutCert)); err == nil {
+		t.Error("expected a client without a certificate to be rejected")
+	}
+}
+
+func TestServer_InvalidTLSConfigurationFailsStart(t *testing.T) {
+	pki := newTestPKI(t)
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"key missing", map[string]string{"ETC_TLS_CERT": pki.path("server.pem")}, "both ETC_TLS_CERT and ETC_TLS_KEY"},
+		{"client CA without cert", map[string]string{"ETC_TLS_CLIENT_CA": pki.path("ca.pem")}, "ETC_TLS_CLIENT_CA requires"},
+		{"unreadable cert", map[string]string{"ETC_TLS_CERT": pki.path("missing.pem"), "ETC_TLS_KEY": pki.path("server-key.pem")}, "failed to load server certificate"},
+		{"empty client CA", map[string]string{"ETC_TLS_CERT": pki.path("server.pem"), "ETC_TLS_KEY": pki.path("server-key.pem"), "ETC_TLS_CLIENT_CA": pki.path("server-key.pem")}, "no certificates found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			server := etcgrpc.NewServerWithListener(nil, log.New(os.Stderr, "", 0), &bufListener{bufconn.Listen(1 << 20)})
+			defer server.Stop()
+
+			// a broken TLS setup must not fall back to plaintext
+			err := server.Start("0")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected Start to fail with %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -14,12 +15,16 @@ import (
 )
 
 // CompleteMockDownloadService provides complete mock implementation
+// Methods the handlers do not use come from the embedded UnimplementedDownloadService
 type CompleteMockDownloadService struct {
+	services.UnimplementedDownloadService
 	accountIDs       []string
 	jobs             map[string]*services.DownloadJob
 	processAsyncFunc func(jobID string, accounts []string, fromDate, toDate string)
 }
 
+var _ services.DownloadServiceInterface = (*CompleteMockDownloadService)(nil)
+
 func NewCompleteMockDownloadService() *CompleteMockDownloadService {
 	return &CompleteMockDownloadService{
 		accountIDs: []string{"test1", "test2"},
@@ -31,7 +36,7 @@ func (m *CompleteMockDownloadService) GetAllAccountIDs() []string {
 	return m.accountIDs
 }
 
-func (m *CompleteMockDownloadService) ProcessAsync(jobID string, accounts []string, fromDate, toDate string) {
+func (m *CompleteMockDownloadService) ProcessAsync(ctx context.Context, jobID string, accounts []string, fromDate, toDate string) {
 	if m.processAsyncFunc != nil {
 		m.processAsyncFunc(jobID, accounts, fromDate, toDate)
 	}
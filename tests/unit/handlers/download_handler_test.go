@@ -2,6 +2,7 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -12,15 +13,19 @@ import (
 )
 
 // MockDownloadService implements DownloadServiceInterface for testing
+// Methods the tests do not use come from the embedded UnimplementedDownloadService
 type MockDownloadService struct {
+	services.UnimplementedDownloadService
 	accountIDs []string
 }
 
+var _ services.DownloadServiceInterface = (*MockDownloadService)(nil)
+
 func (m *MockDownloadService) GetAllAccountIDs() []string {
 	return m.accountIDs
 }
 
-func (m *MockDownloadService) ProcessAsync(jobID string, accounts []string, fromDate, toDate string) {
+func (m *MockDownloadService) ProcessAsync(ctx context.Context, jobID string, accounts []string, fromDate, toDate string) {
 	// Mock implementation
 }
 
@@ -0,0 +1,81 @@
+package scraper_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+// contextRecorder captures the options passed to NewContext and then fails,
+// so Initialize stops right after the context creation.
+type contextRecorder struct {
+	options scraper.BrowserNewContextOptions
+}
+
+func (r *contextRecorder) Run() (scraper.PlaywrightInterface, error) { return r, nil }
+func (r *contextRecorder) Install() error                            { return nil }
+func (r *contextRecorder) Stop() error                               { return nil }
+func (r *contextRecorder) GetChromium() scraper.BrowserTypeInterface { return r }
+func (r *contextRecorder) Close() error                              { return nil }
+
+func (r *contextRecorder) Launch(scraper.BrowserTypeLaunchOptions) (scraper.BrowserInterface, error) {
+	return r, nil
+}
+
+func (r *contextRecorder) NewContext(options scraper.BrowserNewContextOptions) (scraper.BrowserContextInterface, error) {
+	r.options = options
+	return nil, errors.New("context creation stopped by test")
+}
+
+func contextOptionsFor(t *testing.T, config *scraper.ScraperConfig) scraper.BrowserNewContextOptions {
+	t.Helper()
+	recorder := &contextRecorder{}
+	s, err := scraper.NewETCScraperWithFactory(config, log.New(&bytes.Buffer{}, "", 0), recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Initialize(); err == nil {
+		t.Fatal("expected Initialize to fail at context creation")
+	}
+	return recorder.options
+}
+
+func TestInitialize_DefaultDesktopIdentity(t *testing.T) {
+	options := contextOptionsFor(t, &scraper.ScraperConfig{UserID: "user1"})
+
+	if options.UserAgent == nil || *options.UserAgent != scraper.DefaultUserAgent {
+		t.Errorf("expected default user agent, got %v", options.UserAgent)
+	}
+	if options.Viewport == nil || options.Viewport.Width != scraper.DefaultViewportWidth || options.Viewport.Height != scraper.DefaultViewportHeight {
+		t.Errorf("expected default viewport, got %+v", options.Viewport)
+	}
+}
+
+func TestInitialize_CustomUserAgentAndViewport(t *testing.T) {
+	options := contextOptionsFor(t, &scraper.ScraperConfig{
+		UserID:    "user1",
+		UserAgent: "CustomAgent/1.0",
+		Viewport:  scraper.Size{Width: 1366, Height: 768},
+	})
+
+	if options.UserAgent == nil || *options.UserAgent != "CustomAgent/1.0" {
+		t.Errorf("expected custom user agent, got %v", options.UserAgent)
+	}
+	if options.Viewport == nil || *options.Viewport != (scraper.Size{Width: 1366, Height: 768}) {
+		t.Errorf("expected custom viewport, got %+v", options.Viewport)
+	}
+}
+
+func TestInitialize_IncompleteViewportUsesDefault(t *testing.T) {
+	options := contextOptionsFor(t, &scraper.ScraperConfig{
+		UserID:   "user1",
+		Viewport: scraper.Size{Width: 800},
+	})
+
+	if options.Viewport == nil || options.Viewport.Width != scraper.DefaultViewportWidth || options.Viewport.Height != scraper.DefaultViewportHeight {
+		t.Errorf("expected default viewport when height is missing, got %+v", options.Viewport)
+	}
+}
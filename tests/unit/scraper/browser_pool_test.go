@@ -0,0 +1,182 @@
+package scraper_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+// countingPlaywright records launched browsers and created contexts
+type countingPlaywright struct {
+	mu       sync.Mutex
+	runs     int
+	stopped  bool
+	browsers []*countingBrowser
+	// contextErr makes NewContext fail on the next browser it is set for
+	contextErr error
+}
+
+func (p *countingPlaywright) Install() error { return nil }
+
+func (p *countingPlaywright) Run() (scraper.PlaywrightInterface, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.runs++
+	return p, nil
+}
+
+func (p *countingPlaywright) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = true
+	return nil
+}
+
+func (p *countingPlaywright) GetChromium() scraper.BrowserTypeInterface { return p }
+
+func (p *countingPlaywright) Launch(options scraper.BrowserTypeLaunchOptions) (scraper.BrowserInterface, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b := &countingBrowser{pw: p, headless: *options.Headless, contextErr: p.contextErr}
+	p.contextErr = nil
+	p.browsers = append(p.browsers, b)
+	return b, nil
+}
+
+func (p *countingPlaywright) launched() []*countingBrowser {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*countingBrowser(nil), p.browsers...)
+}
+
+type countingBrowser struct {
+	pw         *countingPlaywright
+	headless   bool
+	contextErr error
+	contexts   []*countingContext
+	closed     bool
+}
+
+func (b *countingBrowser) NewContext(scraper.BrowserNewContextOptions) (scraper.BrowserContextInterface, error) {
+	b.pw.mu.Lock()
+	defer b.pw.mu.Unlock()
+	if b.contextErr != nil {
+		return nil, b.contextErr
+	}
+	c := &countingContext{}
+	b.contexts = append(b.contexts, c)
+	return c, nil
+}
+
+func (b *countingBrowser) Close() error {
+	b.pw.mu.Lock()
+	defer b.pw.mu.Unlock()
+	b.closed = true
+	return nil
+}
+
+type countingContext struct {
+	closed bool
+}
+
+func (c *countingContext) NewPage() (scraper.PageInterface, error) {
+	return &failingPage{browser: &failingPageBrowser{}}, nil
+}
+func (c *countingContext) SetDefaultTimeout(float64) {}
+func (c *countingContext) On(string, interface{})    {}
+func (c *countingContext) Close() error              { c.closed = true; return nil }
+
+func newPooledScraper(t *testing.T, pool *scraper.BrowserPool, userID string, headless bool) *scraper.ETCScraper {
+	t.Helper()
+	s, err := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{UserID: userID, Headless: headless},
+		log.New(&bytes.Buffer{}, "", 0), pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	return s
+}
+
+func TestBrowserPool_ReusesBrowserWithFreshContexts(t *testing.T) {
+	pw := &countingPlaywright{}
+	pool := scraper.NewBrowserPool(pw, 1)
+
+	for _, user := range []string{"user1", "user2", "user3"} {
+		newPooledScraper(t, pool, user, true).Close()
+	}
+
+	browsers := pw.launched()
+	if pw.runs != 1 || len(browsers) != 1 {
+		t.Fatalf("expected one Playwright run and one browser, got %d runs and %d browsers", pw.runs, len(browsers))
+	}
+	if len(browsers[0].contexts) != 3 {
+		t.Fatalf("expected a fresh context per account, got %d", len(browsers[0].contexts))
+	}
+	for i, c := range browsers[0].contexts {
+		if !c.closed {
+			t.Errorf("expected context %d to be closed after its account", i)
+		}
+	}
+	if browsers[0].closed || pw.stopped {
+		t.Error("expected the browser to stay open while the pool is open")
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !browsers[0].closed || !pw.stopped {
+		t.Error("expected Close to close the browser and stop Playwright")
+	}
+	if _, err := pool.Run(); err == nil {
+		t.Error("expected Run to fail after Close")
+	}
+}
+
+func TestBrowserPool_SizeAndLaunchOptions(t *testing.T) {
+	pw := &countingPlaywright{}
+	pool := scraper.NewBrowserPool(pw, 2)
+	defer pool.Close()
+
+	// 同時に3アカウントを処理しても起動するブラウザは最大2つ
+	first := newPooledScraper(t, pool, "user1", true)
+	second := newPooledScraper(t, pool, "user2", true)
+	third := newPooledScraper(t, pool, "user3", true)
+	if got := len(pw.launched()); got != 2 {
+		t.Errorf("expected 2 browsers for 3 concurrent scrapers, got %d", got)
+	}
+	first.Close()
+	second.Close()
+	third.Close()
+
+	// 起動オプションが異なる場合は別のブラウザを使う
+	newPooledScraper(t, pool, "user4", false).Close()
+	browsers := pw.launched()
+	if len(browsers) != 3 || browsers[2].headless {
+		t.Errorf("expected a separate visible browser, got %d browsers", len(browsers))
+	}
+}
+
+func TestBrowserPool_DiscardsBrokenBrowser(t *testing.T) {
+	pw := &countingPlaywright{contextErr: errors.New("browser has been closed")}
+	pool := scraper.NewBrowserPool(pw, 1)
+	defer pool.Close()
+
+	s, _ := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{UserID: "user1", Headless: true},
+		log.New(&bytes.Buffer{}, "", 0), pool)
+	if err := s.Initialize(); err == nil {
+		t.Fatal("expected Initialize to fail")
+	}
+	s.Close()
+
+	newPooledScraper(t, pool, "user2", true).Close()
+	browsers := pw.launched()
+	if len(browsers) != 2 || !browsers[0].closed {
+		t.Errorf("expected the broken browser to be closed and replaced, got %d browsers", len(browsers))
+	}
+}
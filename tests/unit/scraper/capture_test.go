@@ -0,0 +1,125 @@
+package scraper_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+var errGotoFailed = errors.New("net::ERR_CONNECTION_RESET")
+
+// failingPageBrowser is a minimal browser whose page fails to navigate,
+// so Login fails on its first step.
+type failingPageBrowser struct {
+	screenshotErr error
+	html          interface{}
+}
+
+func (b *failingPageBrowser) Run() (scraper.PlaywrightInterface, error) { return b, nil }
+func (b *failingPageBrowser) Install() error                            { return nil }
+func (b *failingPageBrowser) Stop() error                               { return nil }
+func (b *failingPageBrowser) GetChromium() scraper.BrowserTypeInterface { return b }
+func (b *failingPageBrowser) Close() error                              { return nil }
+func (b *failingPageBrowser) SetDefaultTimeout(float64)                 {}
+func (b *failingPageBrowser) On(string, interface{})                    {}
+
+func (b *failingPageBrowser) Launch(scraper.BrowserTypeLaunchOptions) (scraper.BrowserInterface, error) {
+	return b, nil
+}
+
+func (b *failingPageBrowser) NewContext(scraper.BrowserNewContextOptions) (scraper.BrowserContextInterface, error) {
+	return b, nil
+}
+
+func (b *failingPageBrowser) NewPage() (scraper.PageInterface, error) {
+	return &failingPage{browser: b}, nil
+}
+
+type failingPage struct {
+	browser *failingPageBrowser
+}
+
+func (p *failingPage) Goto(string, scraper.PageGotoOptions) (scraper.Response, error) {
+	return nil, errGotoFailed
+}
+func (p *failingPage) Locator(string) scraper.LocatorInterface                    { return nil }
+func (p *failingPage) WaitForLoadState(scraper.PageWaitForLoadStateOptions) error { return nil }
+func (p *failingPage) Close() error                                               { return nil }
+func (p *failingPage) On(string, interface{})                                     {}
+func (p *failingPage) Evaluate(string, ...interface{}) (interface{}, error) {
+	return p.browser.html, nil
+}
+func (p *failingPage) Screenshot(options scraper.PageScreenshotOptions) ([]byte, error) {
+	if p.browser.screenshotErr != nil {
+		return nil, p.browser.screenshotErr
+	}
+	return []byte("png"), os.WriteFile(options.Path, []byte("png"), 0644)
+}
+
+func loginWithCapture(t *testing.T, browser *failingPageBrowser, capture bool) (string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	s, err := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{
+		UserID:         "user1",
+		SessionFolder:  dir,
+		CaptureOnError: capture,
+	}, log.New(&bytes.Buffer{}, "", 0), browser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	return dir, s.Login()
+}
+
+func TestLogin_CapturesPageOnError(t *testing.T) {
+	dir, err := loginWithCapture(t, &failingPageBrowser{html: "<html>error page</html>"}, true)
+
+	var captureErr *scraper.CaptureError
+	if !errors.As(err, &captureErr) {
+		t.Fatalf("expected CaptureError, got %v", err)
+	}
+	if !errors.Is(err, errGotoFailed) || !scraper.IsTransientError(err) {
+		t.Errorf("expected original error to be preserved, got %v", err)
+	}
+	if filepath.Dir(captureErr.ScreenshotPath) != dir || filepath.Dir(captureErr.HTMLPath) != dir {
+		t.Errorf("expected captures in session folder %s, got %+v", dir, captureErr)
+	}
+	if html, _ := os.ReadFile(captureErr.HTMLPath); string(html) != "<html>error page</html>" {
+		t.Errorf("unexpected HTML dump %q", html)
+	}
+	if _, statErr := os.Stat(captureErr.ScreenshotPath); statErr != nil {
+		t.Errorf("expected screenshot file: %v", statErr)
+	}
+}
+
+func TestLogin_CaptureFailureKeepsOriginalError(t *testing.T) {
+	_, err := loginWithCapture(t, &failingPageBrowser{screenshotErr: errors.New("page crashed"), html: 42}, true)
+
+	var captureErr *scraper.CaptureError
+	if errors.As(err, &captureErr) {
+		t.Errorf("expected no CaptureError when nothing was saved, got %+v", captureErr)
+	}
+	if !errors.Is(err, errGotoFailed) {
+		t.Errorf("expected original error, got %v", err)
+	}
+}
+
+func TestLogin_NoCaptureByDefault(t *testing.T) {
+	dir, err := loginWithCapture(t, &failingPageBrowser{html: "<html></html>"}, false)
+
+	var captureErr *scraper.CaptureError
+	if errors.As(err, &captureErr) {
+		t.Errorf("expected no capture when CaptureOnError is false, got %+v", captureErr)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected no files in session folder, got %d", len(entries))
+	}
+}
@@ -0,0 +1,57 @@
+package scraper_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDownloadCertificates_OnePDFPerMonthWithResults(t *testing.T) {
+	site := &statementSite{
+		selected: map[string]string{},
+		rows: map[string][]string{
+			"202401": {"24/01/10,08:00,24/01/10,09:00,東京,横浜,1000\r\n"},
+			"202403": {"24/03/05,08:00,24/03/05,09:00,横浜,東京,1000\r\n"},
+		},
+	}
+	s, _, err := downloadStatement(t, site, "2024-01-15", "2024-03-10")
+	if err != nil {
+		t.Fatalf("DownloadMeisai failed: %v", err)
+	}
+
+	// the same page serves both downloads without saving either file twice
+	site.searched = nil
+	paths, err := s.DownloadCertificates("2024-01-15", "2024-03-10")
+	if err != nil {
+		t.Fatalf("DownloadCertificates failed: %v", err)
+	}
+	if got := strings.Join(site.searched, ","); got != "202401,202402,202403" {
+		t.Errorf("expected one search per month, got %s", got)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("expected a certificate for each month with results, got %q", paths)
+	}
+	for i, month := range []string{"202401", "202403"} {
+		if want := "user1_certificate_" + month + ".pdf"; filepath.Base(paths[i]) != want {
+			t.Errorf("expected %s, got %s", want, paths[i])
+		}
+		data, err := os.ReadFile(paths[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "%PDF "+month {
+			t.Errorf("expected the certificate of %s, got %q", month, data)
+		}
+	}
+}
+
+func TestDownloadCertificates_NoResults(t *testing.T) {
+	site := &statementSite{selected: map[string]string{}, rows: map[string][]string{}}
+	s, _, _ := downloadStatement(t, site, "2024-05-01", "2024-05-31")
+
+	paths, err := s.DownloadCertificates("2024-05-01", "2024-05-31")
+	if err != nil || len(paths) != 0 {
+		t.Errorf("expected no certificates and no error, got %q %v", paths, err)
+	}
+}
@@ -0,0 +1,75 @@
+package scraper_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+func TestDownloadMeisai_ReturnsDownloadMetadata(t *testing.T) {
+	site := &statementSite{
+		selected: map[string]string{},
+		rows: map[string][]string{
+			"202401": {"24/01/10,08:00,24/01/10,09:00,東京,横浜,1000\r\n"},
+			"202403": {"24/03/05,08:00,24/03/05,09:00,横浜,東京,1000\r\n", "24/03/06,18:00,24/03/06,19:00,東京,川崎,800\r\n"},
+		},
+	}
+	s, err := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{
+		UserID:        "user1",
+		SessionFolder: t.TempDir(),
+		TestMode:      true,
+	}, log.New(&bytes.Buffer{}, "", 0), site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	result, err := s.DownloadMeisai("2024-01-15", "2024-03-10")
+	if err != nil {
+		t.Fatalf("DownloadMeisai failed: %v", err)
+	}
+	info, err := os.Stat(result.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Bytes != info.Size() || result.Pages != 3 || result.RowCountEstimate != 3 {
+		t.Errorf("expected %d bytes over 3 pages with 3 rows, got %+v", info.Size(), result)
+	}
+	wantFrom := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	wantTo := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	if !result.ActualFrom.Equal(wantFrom) || !result.ActualTo.Equal(wantTo) {
+		t.Errorf("expected the searched range %v - %v, got %v - %v", wantFrom, wantTo, result.ActualFrom, result.ActualTo)
+	}
+	if !result.CoversRange("2024-01-15", "2024-03-10") {
+		t.Error("expected the result to cover the requested range")
+	}
+}
+
+func TestDownloadResult_CoversRange(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name   string
+		result scraper.DownloadResult
+		want   bool
+	}{
+		{"exact", scraper.DownloadResult{ActualFrom: day(1, 1), ActualTo: day(1, 31)}, true},
+		{"wider", scraper.DownloadResult{ActualFrom: day(1, 1), ActualTo: day(2, 10)}, true},
+		{"start clamped", scraper.DownloadResult{ActualFrom: day(1, 15), ActualTo: day(1, 31)}, false},
+		{"end clamped", scraper.DownloadResult{ActualFrom: day(1, 1), ActualTo: day(1, 20)}, false},
+		{"unknown", scraper.DownloadResult{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.CoversRange("2024/01/01", "2024/01/31"); got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package scraper_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+// driverFactory fails to start Playwright or to launch the browser
+type driverFactory struct {
+	runErr    error
+	launchErr error
+}
+
+func (f *driverFactory) Install() error { return nil }
+func (f *driverFactory) Run() (scraper.PlaywrightInterface, error) {
+	if f.runErr != nil {
+		return nil, f.runErr
+	}
+	return f, nil
+}
+func (f *driverFactory) Stop() error                               { return nil }
+func (f *driverFactory) GetChromium() scraper.BrowserTypeInterface { return f }
+func (f *driverFactory) Launch(scraper.BrowserTypeLaunchOptions) (scraper.BrowserInterface, error) {
+	return nil, f.launchErr
+}
+
+func TestInitialize_ClassifiesDriverErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		factory *driverFactory
+		want    error
+	}{
+		{"driver missing", &driverFactory{runErr: errors.New("please install the driver (v1.52.0) first")}, scraper.ErrDriverNotInstalled},
+		{"driver not started", &driverFactory{runErr: errors.New("could not get driver version: exit status 1")}, scraper.ErrDriverNotReady},
+		{"browser missing", &driverFactory{launchErr: errors.New("Executable doesn't exist at /ms-playwright/chromium-1169/chrome")}, scraper.ErrDriverNotInstalled},
+		{"browser not started", &driverFactory{launchErr: errors.New("Target page, context or browser has been closed")}, scraper.ErrDriverNotReady},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{UserID: "user1"}, log.New(&bytes.Buffer{}, "", 0), tt.factory)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = s.Initialize()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+			if tt.want == scraper.ErrDriverNotInstalled && errors.Is(err, scraper.ErrDriverNotReady) {
+				t.Errorf("a missing driver must not be retryable: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"playwright timeout", fmt.Errorf("failed to click login link: %w", playwright.ErrTimeout), true},
+		{"context deadline", fmt.Errorf("wait: %w", context.DeadlineExceeded), true},
+		{"navigation failure", errors.New("failed to navigate to top page: net::ERR_CONNECTION_RESET"), true},
+		{"download timeout", errors.New("download timeout after 60 seconds"), true},
+		{"login rejected", errors.New("login failed: ログインIDまたはパスワードが誤っています"), false},
+		{"login rejected mentioning timeout", errors.New("login failed: session timeout"), false},
+		{"missing CSV link", errors.New("CSV download link not found with any selector"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scraper.IsTransientError(tt.err); got != tt.want {
+				t.Errorf("IsTransientError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTimeoutError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"playwright timeout", fmt.Errorf("failed to click login link: %w", playwright.ErrTimeout), true},
+		{"context deadline", fmt.Errorf("wait: %w", context.DeadlineExceeded), true},
+		{"download timeout", errors.New("download timeout after 60 seconds"), true},
+		{"navigation failure", errors.New("failed to navigate to top page: net::ERR_CONNECTION_RESET"), false},
+		{"login rejected mentioning timeout", errors.New("login failed: session timeout"), false},
+		{"auth error", &scraper.AuthError{Reason: "timeout"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scraper.IsTimeoutError(tt.err); got != tt.want {
+				t.Errorf("IsTimeoutError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
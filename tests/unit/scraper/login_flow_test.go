@@ -0,0 +1,161 @@
+package scraper_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/models"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+// selectorRecorder is a site where every element exists; it records the
+// selectors the scraper used and the values it filled in
+type selectorRecorder struct {
+	selectors []string
+	filled    map[string]string
+	// pageText is returned by Evaluate as the page's title and body text
+	pageText string
+	// missing holds selectors that match no element
+	missing map[string]bool
+}
+
+func (r *selectorRecorder) Run() (scraper.PlaywrightInterface, error) { return r, nil }
+func (r *selectorRecorder) Install() error                            { return nil }
+func (r *selectorRecorder) Stop() error                               { return nil }
+func (r *selectorRecorder) GetChromium() scraper.BrowserTypeInterface { return r }
+func (r *selectorRecorder) Close() error                              { return nil }
+func (r *selectorRecorder) SetDefaultTimeout(float64)                 {}
+func (r *selectorRecorder) On(string, interface{})                    {}
+
+func (r *selectorRecorder) Launch(scraper.BrowserTypeLaunchOptions) (scraper.BrowserInterface, error) {
+	return r, nil
+}
+
+func (r *selectorRecorder) NewContext(scraper.BrowserNewContextOptions) (scraper.BrowserContextInterface, error) {
+	return r, nil
+}
+
+func (r *selectorRecorder) NewPage() (scraper.PageInterface, error) { return &recordingPage{r}, nil }
+
+type recordingPage struct {
+	recorder *selectorRecorder
+}
+
+func (p *recordingPage) Goto(string, scraper.PageGotoOptions) (scraper.Response, error) {
+	return nil, nil
+}
+func (p *recordingPage) Locator(selector string) scraper.LocatorInterface {
+	p.recorder.selectors = append(p.recorder.selectors, selector)
+	return &recordingLocator{recorder: p.recorder, selector: selector}
+}
+func (p *recordingPage) WaitForLoadState(scraper.PageWaitForLoadStateOptions) error { return nil }
+func (p *recordingPage) Close() error                                               { return nil }
+func (p *recordingPage) On(string, interface{})                                     {}
+func (p *recordingPage) Evaluate(string, ...interface{}) (interface{}, error) {
+	return p.recorder.pageText, nil
+}
+func (p *recordingPage) Screenshot(scraper.PageScreenshotOptions) ([]byte, error) {
+	return nil, nil
+}
+
+type recordingLocator struct {
+	recorder *selectorRecorder
+	selector string
+}
+
+func (l *recordingLocator) First() scraper.LocatorInterface         { return l }
+func (l *recordingLocator) Click(scraper.LocatorClickOptions) error { return nil }
+func (l *recordingLocator) Check(scraper.LocatorCheckOptions) error { return nil }
+func (l *recordingLocator) SelectOption([]string) error             { return nil }
+func (l *recordingLocator) Count() (int, error) {
+	if l.recorder.missing[l.selector] {
+		return 0, nil
+	}
+	return 1, nil
+}
+func (l *recordingLocator) TextContent(scraper.LocatorTextContentOptions) (string, error) {
+	return "", nil
+}
+func (l *recordingLocator) IsChecked(scraper.LocatorIsCheckedOptions) (bool, error) {
+	return true, nil
+}
+func (l *recordingLocator) Fill(value string) error {
+	l.recorder.filled[l.selector] = value
+	return nil
+}
+
+func loginAs(t *testing.T, accountType models.AccountType) *selectorRecorder {
+	t.Helper()
+	recorder := &selectorRecorder{filled: map[string]string{}}
+	s, err := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{
+		UserID:      "user1",
+		Password:    "pass1",
+		TestMode:    true,
+		AccountType: accountType,
+	}, log.New(&bytes.Buffer{}, "", 0), recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.Login(); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	return recorder
+}
+
+func TestLogin_CorporateFlowByDefault(t *testing.T) {
+	recorder := loginAs(t, "")
+
+	if !strings.Contains(recorder.selectors[0], "funccode=1013000000") {
+		t.Errorf("expected corporate login link, got %s", recorder.selectors[0])
+	}
+	if recorder.filled["input[name='risLoginId']"] != "user1" || recorder.filled["input[name='risPassword']"] != "pass1" {
+		t.Errorf("expected corporate login form, got %v", recorder.filled)
+	}
+}
+
+func TestLogin_PersonalFlow(t *testing.T) {
+	recorder := loginAs(t, models.AccountTypePersonal)
+
+	if strings.Contains(recorder.selectors[0], "funccode=1013000000") {
+		t.Errorf("expected personal login link, got %s", recorder.selectors[0])
+	}
+	if _, ok := recorder.filled["input[name='risLoginId']"]; ok {
+		t.Errorf("personal login must not use the corporate form, got %v", recorder.filled)
+	}
+	if len(recorder.filled) != 2 {
+		t.Errorf("expected user ID and password to be filled, got %v", recorder.filled)
+	}
+}
+
+func TestLogin_MaintenancePage(t *testing.T) {
+	recorder := &selectorRecorder{filled: map[string]string{}, pageText: "ETC利用照会サービス\nただいまシステムメンテナンス中です"}
+	s, err := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{
+		UserID:   "user1",
+		Password: "pass1",
+		TestMode: true,
+	}, log.New(&bytes.Buffer{}, "", 0), recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	err = s.Login()
+	if !scraper.IsMaintenanceError(err) {
+		t.Fatalf("expected a MaintenanceError, got %v", err)
+	}
+	if scraper.IsTransientError(err) || scraper.IsAuthError(err) {
+		t.Errorf("maintenance must be neither transient nor an auth error: %v", err)
+	}
+	if len(recorder.filled) != 0 {
+		t.Errorf("expected no credentials to be entered on the maintenance page, got %v", recorder.filled)
+	}
+}
@@ -0,0 +1,63 @@
+package scraper_test
+
+import (
+	"bytes"
+	"log"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+func TestIsSafePathComponent(t *testing.T) {
+	for name, want := range map[string]bool{
+		"user1":       true,
+		"user.name_1": true,
+		"":            false,
+		".":           false,
+		"..":          false,
+		"../user1":    false,
+		`..\user1`:    false,
+		"a/b":         false,
+		"user\x001":   false,
+	} {
+		if got := scraper.IsSafePathComponent(name); got != want {
+			t.Errorf("IsSafePathComponent(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestHandleDownload_KeepsFilesInDownloadPath(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		userID, suggested, want string
+	}{
+		{"user1", "meisai.csv", "user1_meisai.csv"},
+		{"../../user1", "meisai.csv", "user1_meisai.csv"},
+		{"user1", "../../meisai.csv", "user1_meisai.csv"},
+		{"user1", `..\..\meisai.csv`, "user1_meisai.csv"},
+		{"..", "..", "___"},
+	}
+	for _, tt := range tests {
+		s, err := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{
+			UserID:       tt.userID,
+			DownloadPath: dir,
+			TestMode:     true,
+		}, log.New(&bytes.Buffer{}, "", 0), &statementSite{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		done := make(chan string, 1)
+		s.HandleDownload(&statementDownload{name: tt.suggested, content: "x"}, done)
+		select {
+		case path := <-done:
+			if path != filepath.Join(dir, tt.want) {
+				t.Errorf("%q %q: expected %s, got %s", tt.userID, tt.suggested, filepath.Join(dir, tt.want), path)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%q %q: download was not saved", tt.userID, tt.suggested)
+		}
+	}
+}
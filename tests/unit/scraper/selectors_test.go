@@ -0,0 +1,96 @@
+package scraper_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+func writeSelectorsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "selectors.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSelectors_OverridesOnlyGivenSelectors(t *testing.T) {
+	path := writeSelectorsFile(t, `{
+		"corporate_login": {"user_id": "input[name='newLoginId']"},
+		"search_button": "button#search",
+		"csv_links": ["a.csv"]
+	}`)
+
+	selectors, err := scraper.LoadSelectors(path)
+	if err != nil {
+		t.Fatalf("LoadSelectors failed: %v", err)
+	}
+	defaults := scraper.DefaultSelectors()
+	if selectors.CorporateLogin.UserID != "input[name='newLoginId']" || selectors.SearchButton != "button#search" {
+		t.Errorf("expected overrides to be applied, got %+v", selectors)
+	}
+	if len(selectors.CSVLinks) != 1 || selectors.CSVLinks[0] != "a.csv" {
+		t.Errorf("expected CSV links to be replaced, got %v", selectors.CSVLinks)
+	}
+	if selectors.CorporateLogin.Password != defaults.CorporateLogin.Password || selectors.FromYear != defaults.FromYear {
+		t.Errorf("expected selectors missing from the file to keep their defaults, got %+v", selectors)
+	}
+}
+
+func TestLoadSelectors_RejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown key", `{"serch_button": "button#search"}`, "unknown field"},
+		{"malformed", `{"search_button": `, "invalid selectors file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := scraper.LoadSelectors(writeSelectorsFile(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := scraper.LoadSelectors(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestLogin_UsesConfiguredSelectors(t *testing.T) {
+	recorder := &selectorRecorder{filled: map[string]string{}}
+	s, err := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{
+		UserID:   "user1",
+		Password: "pass1",
+		TestMode: true,
+		Selectors: scraper.Selectors{
+			CorporateLogin: scraper.LoginSelectors{Link: "a#login", UserID: "input#id"},
+		},
+	}, log.New(&bytes.Buffer{}, "", 0), recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.Login(); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	if recorder.selectors[0] != "a#login" {
+		t.Errorf("expected the configured login link, got %s", recorder.selectors[0])
+	}
+	if recorder.filled["input#id"] != "user1" || recorder.filled["input[name='risPassword']"] != "pass1" {
+		t.Errorf("expected the configured user ID field and the default password field, got %v", recorder.filled)
+	}
+}
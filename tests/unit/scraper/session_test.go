@@ -0,0 +1,55 @@
+package scraper_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+func TestIsLoggedIn(t *testing.T) {
+	loggedIn := scraper.DefaultSelectors().LoggedIn
+	recorder := &selectorRecorder{filled: map[string]string{}, missing: map[string]bool{}}
+	s, err := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{
+		UserID:   "user1",
+		Password: "pass1",
+		TestMode: true,
+	}, log.New(&bytes.Buffer{}, "", 0), recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.IsLoggedIn(); err == nil {
+		t.Error("expected an inconclusive check before Initialize")
+	}
+	if err := s.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	recorder.selectors = nil
+	if ok, err := s.IsLoggedIn(); ok || err != nil {
+		t.Errorf("expected a fresh scraper to be logged out, got %t %v", ok, err)
+	}
+	if len(recorder.selectors) != 0 {
+		t.Errorf("expected no page lookups before Login, got %q", recorder.selectors)
+	}
+
+	if err := s.Login(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.IsLoggedIn(); !ok || err != nil {
+		t.Errorf("expected the session to be valid after Login, got %t %v", ok, err)
+	}
+
+	// the logout link disappears once the site ends the session
+	recorder.missing[loggedIn] = true
+	if ok, err := s.IsLoggedIn(); ok || err != nil {
+		t.Errorf("expected an expired session, got %t %v", ok, err)
+	}
+	recorder.missing[loggedIn] = false
+	if ok, _ := s.IsLoggedIn(); ok {
+		t.Error("expected the expired session to stay logged out until the next Login")
+	}
+}
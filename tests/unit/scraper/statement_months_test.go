@@ -0,0 +1,264 @@
+package scraper_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+)
+
+const statementHeader = "利用年月日（自）,利用時刻（自）,利用年月日（至）,利用時刻（至）,利用ＩＣ（自）,利用ＩＣ（至）,通行料金\r\n"
+
+// statementSite is a fake ETC site whose search results depend on the month
+// selected in the search form
+type statementSite struct {
+	mu       sync.Mutex
+	rows     map[string][]string // YYYYMM -> CSV rows
+	selected map[string]string
+	searched []string
+	onDL     func(scraper.Download)
+}
+
+func (b *statementSite) Run() (scraper.PlaywrightInterface, error) { return b, nil }
+func (b *statementSite) Install() error                            { return nil }
+func (b *statementSite) Stop() error                               { return nil }
+func (b *statementSite) GetChromium() scraper.BrowserTypeInterface { return b }
+func (b *statementSite) Close() error                              { return nil }
+func (b *statementSite) SetDefaultTimeout(float64)                 {}
+
+func (b *statementSite) Launch(scraper.BrowserTypeLaunchOptions) (scraper.BrowserInterface, error) {
+	return b, nil
+}
+
+func (b *statementSite) NewContext(scraper.BrowserNewContextOptions) (scraper.BrowserContextInterface, error) {
+	return b, nil
+}
+
+func (b *statementSite) NewPage() (scraper.PageInterface, error) { return &statementPage{site: b}, nil }
+
+func (b *statementSite) On(event string, handler interface{}) {
+	if fn, ok := handler.(func(scraper.Download)); ok && event == "download" {
+		b.onDL = fn
+	}
+}
+
+func (b *statementSite) month() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.selected["fromYYYY"] + b.selected["fromMM"]
+}
+
+type statementPage struct {
+	site *statementSite
+}
+
+func (p *statementPage) Goto(string, scraper.PageGotoOptions) (scraper.Response, error) {
+	return nil, nil
+}
+func (p *statementPage) Locator(selector string) scraper.LocatorInterface {
+	return &statementLocator{site: p.site, selector: selector}
+}
+func (p *statementPage) WaitForLoadState(scraper.PageWaitForLoadStateOptions) error { return nil }
+func (p *statementPage) Close() error                                               { return nil }
+func (p *statementPage) On(event string, handler interface{})                       { p.site.On(event, handler) }
+func (p *statementPage) Screenshot(scraper.PageScreenshotOptions) ([]byte, error) {
+	return nil, nil
+}
+
+// Evaluate returns the values selected in the search form for the selectors
+// passed as its argument, like the script that reads the searched range
+func (p *statementPage) Evaluate(_ string, args ...interface{}) (interface{}, error) {
+	if len(args) == 0 {
+		return "", nil
+	}
+	selectors, _ := args[0].([]string)
+	p.site.mu.Lock()
+	defer p.site.mu.Unlock()
+	values := make([]interface{}, len(selectors))
+	for i, selector := range selectors {
+		values[i] = p.site.selected[selectName(selector)]
+	}
+	return values, nil
+}
+
+// selectName returns the name in a select[name='...'] selector
+func selectName(selector string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(selector, "select[name='"), "']")
+}
+
+type statementLocator struct {
+	site     *statementSite
+	selector string
+}
+
+func (l *statementLocator) Count() (int, error) {
+	if l.selector == "input[name='hakkoMeisai']" {
+		return len(l.site.rows[l.site.month()]), nil
+	}
+	return 1, nil
+}
+func (l *statementLocator) First() scraper.LocatorInterface { return l }
+func (l *statementLocator) Fill(string) error               { return nil }
+func (l *statementLocator) TextContent(scraper.LocatorTextContentOptions) (string, error) {
+	return "", nil
+}
+func (l *statementLocator) Check(scraper.LocatorCheckOptions) error { return nil }
+func (l *statementLocator) IsChecked(scraper.LocatorIsCheckedOptions) (bool, error) {
+	return true, nil
+}
+
+func (l *statementLocator) SelectOption(values []string) error {
+	name := selectName(l.selector)
+	l.site.mu.Lock()
+	defer l.site.mu.Unlock()
+	l.site.selected[name] = values[0]
+	return nil
+}
+
+func (l *statementLocator) Click(scraper.LocatorClickOptions) error {
+	switch {
+	case l.selector == "input[name='focusTarget']":
+		l.site.searched = append(l.site.searched, l.site.month())
+	case strings.Contains(l.selector, "明細ＣＳＶ"):
+		csv := statementHeader + strings.Join(l.site.rows[l.site.month()], "")
+		l.site.onDL(&statementDownload{content: csv})
+	case strings.Contains(l.selector, "利用証明書"):
+		l.site.onDL(&statementDownload{name: "riyoshomei.pdf", content: "%PDF " + l.site.month()})
+	}
+	return nil
+}
+
+type statementDownload struct {
+	name    string // empty = meisai.csv
+	content string
+}
+
+func (d *statementDownload) SuggestedFilename() string {
+	if d.name == "" {
+		return "meisai.csv"
+	}
+	return d.name
+}
+func (d *statementDownload) SaveAs(path string) error {
+	return os.WriteFile(path, []byte(d.content), 0644)
+}
+
+func downloadStatement(t *testing.T, site *statementSite, fromDate, toDate string) (*scraper.ETCScraper, string, error) {
+	t.Helper()
+	s, err := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{
+		UserID:        "user1",
+		SessionFolder: t.TempDir(),
+		TestMode:      true,
+	}, log.New(&bytes.Buffer{}, "", 0), site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	result, err := s.DownloadMeisai(fromDate, toDate)
+	return s, result.Path, err
+}
+
+func TestDownloadMeisai_ConcatenatesMonthsWithSingleHeader(t *testing.T) {
+	site := &statementSite{
+		selected: map[string]string{},
+		rows: map[string][]string{
+			"202401": {"24/01/10,08:00,24/01/10,09:00,東京,横浜,1000\r\n"},
+			"202403": {"24/03/05,08:00,24/03/05,09:00,横浜,東京,1000\r\n", "24/03/06,18:00,24/03/06,19:00,東京,川崎,800\r\n"},
+		},
+	}
+
+	s, path, err := downloadStatement(t, site, "2024-01-15", "2024-03-10")
+	if err != nil {
+		t.Fatalf("DownloadMeisai failed: %v", err)
+	}
+
+	if got := strings.Join(site.searched, ","); got != "202401,202402,202403" {
+		t.Errorf("expected one search per month, got %s", got)
+	}
+	if site.selected["toYYYY"]+site.selected["toMM"]+site.selected["toDD"] != "20240310" {
+		t.Errorf("expected the last month to end at the requested date, got %v", site.selected)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := statementHeader + site.rows["202401"][0] + site.rows["202403"][0] + site.rows["202403"][1]
+	if string(data) != want {
+		t.Errorf("unexpected combined CSV:\n%s", data)
+	}
+	if count, known := s.ExpectedRecordCount(); !known || count != 3 {
+		t.Errorf("expected the site count summed over months (3), got %d (known=%t)", count, known)
+	}
+}
+
+func TestDownloadMeisai_SingleMonthReturnsDownloadedFile(t *testing.T) {
+	site := &statementSite{
+		selected: map[string]string{},
+		rows:     map[string][]string{"202405": {"24/05/01,08:00,24/05/01,09:00,東京,横浜,1000\r\n"}},
+	}
+
+	_, path, err := downloadStatement(t, site, "2024-05-01", "2024-05-31")
+	if err != nil {
+		t.Fatalf("DownloadMeisai failed: %v", err)
+	}
+	if !strings.HasSuffix(path, "user1_meisai.csv") {
+		t.Errorf("expected the downloaded file to be returned as is, got %s", path)
+	}
+	if len(site.searched) != 1 || site.selected["fromDD"] != "01" || site.selected["toDD"] != "31" {
+		t.Errorf("expected one search for the requested dates, got %v %v", site.searched, site.selected)
+	}
+}
+
+func TestDownloadMeisai_NoResultsInAnyMonth(t *testing.T) {
+	site := &statementSite{selected: map[string]string{}, rows: map[string][]string{}}
+
+	_, _, err := downloadStatement(t, site, "2024-01-01", "2024-02-29")
+	if err == nil || !strings.Contains(err.Error(), "for any month") {
+		t.Errorf("expected an error when no month has results, got %v", err)
+	}
+}
+
+func TestDownloadMeisai_ReportsProgressPerMonth(t *testing.T) {
+	site := &statementSite{
+		selected: map[string]string{},
+		rows: map[string][]string{
+			"202401": {"24/01/10,08:00,24/01/10,09:00,東京,横浜,1000\r\n"},
+			"202403": {"24/03/05,08:00,24/03/05,09:00,横浜,東京,1000\r\n", "24/03/06,18:00,24/03/06,19:00,東京,川崎,800\r\n"},
+		},
+	}
+	var got []scraper.DownloadProgress
+	s, err := scraper.NewETCScraperWithFactory(&scraper.ScraperConfig{
+		UserID:        "user1",
+		SessionFolder: t.TempDir(),
+		TestMode:      true,
+		OnProgress:    func(p scraper.DownloadProgress) { got = append(got, p) },
+	}, log.New(&bytes.Buffer{}, "", 0), site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if _, err := s.DownloadMeisai("2024-01-15", "2024-03-10"); err != nil {
+		t.Fatalf("DownloadMeisai failed: %v", err)
+	}
+	want := []scraper.DownloadProgress{{Page: 1, Pages: 3, Rows: 1}, {Page: 2, Pages: 3, Rows: 1}, {Page: 3, Pages: 3, Rows: 3}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("progress %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTestAccount_Success(t *testing.T) {
+	factory := &fakeScraperFactory{}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	resp, err := grpcSvc.TestAccount(context.Background(), &pb.TestAccountRequest{AccountId: "user1", Password: "s3cret-pw"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Ok || resp.Error != "" || resp.LatencyMs < 0 {
+		t.Errorf("expected successful login, got %+v", resp)
+	}
+	if factory.loginAttempts("user1") != 1 || factory.downloadAttempts("user1") != 0 {
+		t.Errorf("expected a single login and no download, got %d logins and %d downloads",
+			factory.loginAttempts("user1"), factory.downloadAttempts("user1"))
+	}
+	if _, exists := svc.GetJobStatus(""); exists {
+		t.Error("expected no job to be created")
+	}
+}
+
+func TestTestAccount_FailureDoesNotLeakPassword(t *testing.T) {
+	var buf bytes.Buffer
+	factory := &fakeScraperFactory{LoginErrors: map[string]error{
+		"user1": errors.New("login rejected for user1/s3cret-pw"),
+	}}
+	svc := services.NewDownloadServiceWithFactory(nil, log.New(&buf, "", 0), factory)
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	resp, err := grpcSvc.TestAccount(context.Background(), &pb.TestAccountRequest{AccountId: "user1", Password: "s3cret-pw"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Ok || !strings.Contains(resp.Error, "login rejected") {
+		t.Errorf("expected login failure, got %+v", resp)
+	}
+	if strings.Contains(resp.String(), "s3cret-pw") {
+		t.Errorf("password echoed in response: %s", resp)
+	}
+	if strings.Contains(buf.String(), "s3cret-pw") {
+		t.Errorf("password written to logs:\n%s", buf.String())
+	}
+}
+
+func TestTestAccount_ConfiguredAccountIndex(t *testing.T) {
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:pass1,user2:pass2")
+	factory := &fakeScraperFactory{LoginErrors: map[string]error{"user1": errors.New("invalid password")}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	resp, err := grpcSvc.TestAccount(context.Background(), &pb.TestAccountRequest{AccountIndex: proto.Int32(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Ok || factory.loginAttempts("user2") != 1 || factory.loginAttempts("user1") != 0 {
+		t.Errorf("expected only the second configured account to be tested, got %+v", resp)
+	}
+
+	for _, req := range []*pb.TestAccountRequest{
+		{AccountIndex: proto.Int32(2)},
+		{AccountIndex: proto.Int32(-1)},
+		{AccountId: "user1"},
+		{},
+	} {
+		_, err := grpcSvc.TestAccount(context.Background(), req)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument for %v, got %v", req, err)
+		}
+	}
+}
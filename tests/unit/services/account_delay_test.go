@@ -0,0 +1,44 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestNewDownloadService_AccountDelayFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", time.Second},
+		{"250", 250 * time.Millisecond},
+		{"0", 0},
+		{"-5", time.Second},
+		{"fast", time.Second},
+	}
+	for _, tt := range tests {
+		t.Setenv("ETC_ACCOUNT_DELAY_MS", tt.value)
+		svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+		if svc.AccountDelay != tt.want {
+			t.Errorf("ETC_ACCOUNT_DELAY_MS=%q: got %v, want %v", tt.value, svc.AccountDelay, tt.want)
+		}
+	}
+}
+
+func TestProcessAsync_SkipsDelayAfterLastAccount(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	svc.AccountDelay = 300 * time.Millisecond
+
+	start := time.Now()
+	svc.ProcessAsync(context.Background(), "delay-job", []string{"a:1", "b:2"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "delay-job", 5*time.Second)
+	elapsed := time.Since(start)
+
+	// Two accounts should only wait once
+	if elapsed < 300*time.Millisecond || elapsed >= 600*time.Millisecond {
+		t.Errorf("expected a single delay between accounts, took %v", elapsed)
+	}
+}
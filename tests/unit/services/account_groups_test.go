@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDownloadAsync_GroupExpandsToAccounts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:pass1,user2:pass2,user3:pass3")
+	t.Setenv("ETC_ACCOUNT_GROUPS", "east=user1|user3,west=user2")
+
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{Group: "east"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := waitForJob(t, svc, resp.JobId, 5*time.Second)
+	if len(job.PerAccount) != 2 || job.PerAccount["user1"] == "" || job.PerAccount["user3"] == "" {
+		t.Errorf("expected the east group's accounts, got %v", job.PerAccount)
+	}
+	for _, cfg := range factory.configs {
+		if cfg.UserID == "user3" && cfg.Password != "pass3" {
+			t.Errorf("expected the configured password, got %q", cfg.Password)
+		}
+	}
+}
+
+func TestGetAllAccountIDs_GroupFilter(t *testing.T) {
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:pass1,user2:pass2,user3:pass3")
+	t.Setenv("ETC_ACCOUNT_GROUPS", `{"east":["user1","user3"],"west":["user2"]}`)
+	grpcSvc := services.NewDownloadServiceGRPCWithService(services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{}))
+
+	resp, err := grpcSvc.GetAllAccountIDs(context.Background(), &pb.GetAllAccountIDsRequest{Group: "east"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(resp.AccountIds, ","); got != "user1,user3" {
+		t.Errorf("expected user1,user3, got %s", got)
+	}
+
+	resp, err = grpcSvc.GetAllAccountIDs(context.Background(), &pb.GetAllAccountIDsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.AccountIds) != 3 {
+		t.Errorf("expected all accounts without a group, got %v", resp.AccountIds)
+	}
+}
+
+func TestAccountGroups_Errors(t *testing.T) {
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:pass1")
+	t.Setenv("ETC_ACCOUNT_GROUPS", "east=user1,empty=user9")
+	grpcSvc := services.NewDownloadServiceGRPCWithService(services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{}))
+
+	tests := []struct {
+		name string
+		req  *pb.DownloadRequest
+		code codes.Code
+	}{
+		{"unknown group", &pb.DownloadRequest{Group: "north"}, codes.InvalidArgument},
+		{"group and accounts", &pb.DownloadRequest{Group: "east", Accounts: []string{"user1:pass1"}}, codes.InvalidArgument},
+		{"no configured accounts", &pb.DownloadRequest{Group: "empty"}, codes.FailedPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := grpcSvc.DownloadAsync(context.Background(), tt.req); status.Code(err) != tt.code {
+				t.Errorf("DownloadAsync: expected %s, got %v", tt.code, err)
+			}
+			if _, err := grpcSvc.DownloadSync(context.Background(), tt.req); status.Code(err) != tt.code {
+				t.Errorf("DownloadSync: expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+
+	if _, err := grpcSvc.GetAllAccountIDs(context.Background(), &pb.GetAllAccountIDsRequest{Group: "north"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetAllAccountIDs: expected InvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_SameAccountIsNotScrapedConcurrently(t *testing.T) {
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.MaxConcurrency = 2
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "job-a", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+
+	// user1はjob-aが処理中のため待機し、user2は並行して処理される
+	svc.ProcessAsync(context.Background(), "job-b", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 2 && logs.contains("Waiting for account user1") })
+	time.Sleep(50 * time.Millisecond)
+	if n := factory.createdScrapers(); n != 2 {
+		t.Fatalf("expected user1 to wait for job-a, but %d scrapers were created", n)
+	}
+
+	close(gate)
+	waitForJob(t, svc, "job-a", 5*time.Second)
+	job := waitForJob(t, svc, "job-b", 5*time.Second)
+	if job.Status != "completed" {
+		t.Errorf("expected job-b to complete, got %s", job.Status)
+	}
+	if !logs.contains("Acquired account user1 after waiting") {
+		t.Error("expected the lock wait to be logged")
+	}
+}
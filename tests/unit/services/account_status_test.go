@@ -0,0 +1,83 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestGetAccountStatus_LastRunPerAccount(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:pass1,user2:s3cret-pw,user3:pass3")
+
+	factory := &fakeScraperFactory{
+		CSV:         threeRowCSV,
+		LoginErrors: map[string]error{"user2": errors.New("login rejected for s3cret-pw")},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1", "user2:s3cret-pw"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-1", 5*time.Second)
+
+	resp, err := grpcSvc.GetAccountStatus(context.Background(), &pb.GetAccountStatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Accounts) != 3 {
+		t.Fatalf("expected every configured account, got %v", resp.Accounts)
+	}
+	user1, user2, user3 := resp.Accounts[0], resp.Accounts[1], resp.Accounts[2]
+	if user1.AccountId != "user1" || user1.LastOutcome != "completed" || user1.LastJobId != "job-1" ||
+		user1.LastSuccessAt == nil || user1.LastRecordCount != 3 || user1.LastError != "" {
+		t.Errorf("unexpected status for user1: %v", user1)
+	}
+	if user2.LastOutcome != "failed" || user2.LastSuccessAt != nil || user2.LastErrorAt == nil || !strings.Contains(user2.LastError, "login rejected") {
+		t.Errorf("unexpected status for user2: %v", user2)
+	}
+	if user3.AccountId != "user3" || user3.LastRunAt != nil || user3.LastOutcome != "" {
+		t.Errorf("expected an empty status for the account that never ran, got %v", user3)
+	}
+	if strings.Contains(resp.String(), "s3cret-pw") || strings.Contains(resp.String(), "pass1") {
+		t.Errorf("credentials exposed in response: %s", resp)
+	}
+
+	// a later failure keeps the last success
+	factory.LoginErrors["user1"] = errors.New("login failed: timeout")
+	svc.ProcessAsync(context.Background(), "job-2", []string{"user1:pass1"}, "2024-02-01", "2024-02-29")
+	waitForJob(t, svc, "job-2", 5*time.Second)
+
+	resp, err = grpcSvc.GetAccountStatus(context.Background(), &pb.GetAccountStatusRequest{AccountIds: []string{"user1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Accounts) != 1 {
+		t.Fatalf("expected only the requested account, got %v", resp.Accounts)
+	}
+	if got := resp.Accounts[0]; got.LastOutcome != "failed" || got.LastJobId != "job-2" ||
+		!got.LastSuccessAt.AsTime().Equal(user1.LastSuccessAt.AsTime()) || got.LastRecordCount != 3 || got.LastError == "" {
+		t.Errorf("expected the failure on top of the last success, got %v", got)
+	}
+}
+
+func TestGetAccountStatus_SurvivesRestart(t *testing.T) {
+	t.Chdir(t.TempDir())
+	db := openJobsDB(t)
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(db, nil, factory)
+
+	svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-1", 5*time.Second)
+
+	restarted := services.NewDownloadServiceWithFactory(db, nil, factory)
+	statuses := restarted.GetAccountStatuses([]string{"user1"})
+	if len(statuses) != 1 || statuses[0].LastOutcome != "completed" || statuses[0].LastRecordCount != 3 || statuses[0].LastSuccessAt == nil {
+		t.Errorf("expected the last run to be loaded from the database, got %+v", statuses)
+	}
+}
@@ -0,0 +1,30 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/models"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_AccountTypeFromEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_CORP_ACCOUNTS", "")
+	t.Setenv("ETC_CORPORATE_ACCOUNTS", "corp1:pass1")
+	t.Setenv("ETC_PERSONAL_ACCOUNTS", `["personal1:pass2"]`)
+
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.ProcessAsync(context.Background(), "job-type", svc.GetAllAccountsWithCredentials(), "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-type", 5*time.Second)
+
+	types := map[string]models.AccountType{}
+	for _, cfg := range factory.configs {
+		types[cfg.UserID] = cfg.AccountType
+	}
+	if types["corp1"] != models.AccountTypeCorporate || types["personal1"] != models.AccountTypePersonal {
+		t.Errorf("unexpected account types: %v", types)
+	}
+}
@@ -0,0 +1,97 @@
+package services_test
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func writeAccountsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "accounts")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGetAllAccountsWithCredentials_AccountsFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"one per line", "# corporate accounts\nuser1:pass1\n\r\nuser2:pa,ss:2\r\n", []string{"user1:pass1", "user2:pa,ss:2"}},
+		{"json array", `["user1:pass1", "user2:pass2"]`, []string{"user1:pass1", "user2:pass2"}},
+		{"bom", "\ufeffuser1:pass1\n", []string{"user1:pass1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ETC_CORP_ACCOUNTS", "")
+			t.Setenv("ETC_CORPORATE_ACCOUNTS", "legacy:pass")
+			t.Setenv("ETC_ACCOUNTS_FILE", writeAccountsFile(t, tt.content))
+			svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+			if got := svc.GetAllAccountsWithCredentials(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAllAccountsWithCredentials_CorpAccountsTakePrecedenceOverFile(t *testing.T) {
+	t.Setenv("ETC_CORP_ACCOUNTS", "env:pass")
+	t.Setenv("ETC_ACCOUNTS_FILE", writeAccountsFile(t, "file:pass\n"))
+
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if got := svc.GetAllAccountsWithCredentials(); !reflect.DeepEqual(got, []string{"env:pass"}) {
+		t.Errorf("expected ETC_CORP_ACCOUNTS to win, got %q", got)
+	}
+}
+
+func TestGetAllAccountsWithCredentials_InvalidAccountsFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"missing password", "user1:pass1\nuser2\n", "invalid account on line 2"},
+		{"bad json", `["user1:pass1",`, "invalid JSON"},
+		{"json entry without password", `["user1:pass1", "user2:"]`, "invalid account at index 1"},
+		{"empty", "# nothing yet\n", "no accounts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ETC_CORP_ACCOUNTS", "")
+			t.Setenv("ETC_CORPORATE_ACCOUNTS", "legacy:pass")
+			t.Setenv("ETC_ACCOUNTS_FILE", writeAccountsFile(t, tt.content))
+			var logs strings.Builder
+			svc := services.NewDownloadServiceWithFactory(nil, log.New(&logs, "", 0), &fakeScraperFactory{})
+
+			// a broken file must not silently fall back to the legacy variables
+			if got := svc.GetAllAccountsWithCredentials(); len(got) != 0 {
+				t.Errorf("expected no accounts, got %q", got)
+			}
+			if !strings.Contains(logs.String(), tt.want) {
+				t.Errorf("expected the startup log to contain %q, got %q", tt.want, logs.String())
+			}
+			if strings.Contains(logs.String(), "pass1") {
+				t.Errorf("expected passwords to stay out of the log, got %q", logs.String())
+			}
+		})
+	}
+
+	t.Run("unreadable", func(t *testing.T) {
+		t.Setenv("ETC_CORP_ACCOUNTS", "")
+		t.Setenv("ETC_ACCOUNTS_FILE", filepath.Join(t.TempDir(), "missing"))
+		var logs strings.Builder
+		services.NewDownloadServiceWithFactory(nil, log.New(&logs, "", 0), &fakeScraperFactory{})
+		if !strings.Contains(logs.String(), "failed to read ETC_ACCOUNTS_FILE") {
+			t.Errorf("expected a read error at startup, got %q", logs.String())
+		}
+	})
+}
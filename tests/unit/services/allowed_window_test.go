@@ -0,0 +1,101 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestParseTimeWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC) }
+	tests := []struct {
+		value string
+		in    []time.Time
+		out   []time.Time
+	}{
+		{"01:00-05:00", []time.Time{at(1, 0), at(4, 59)}, []time.Time{at(0, 59), at(5, 0), at(13, 0)}},
+		{"22:00-05:30", []time.Time{at(22, 0), at(0, 0), at(5, 29)}, []time.Time{at(5, 30), at(21, 59), at(12, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			window, err := services.ParseTimeWindow(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if window.String() != tt.value {
+				t.Errorf("expected %q, got %q", tt.value, window.String())
+			}
+			for _, ts := range tt.in {
+				if !window.Contains(ts) {
+					t.Errorf("expected %s to be inside", ts.Format("15:04"))
+				}
+			}
+			for _, ts := range tt.out {
+				if window.Contains(ts) {
+					t.Errorf("expected %s to be outside", ts.Format("15:04"))
+				}
+			}
+		})
+	}
+
+	for _, value := range []string{"01:00", "1am-5am", "01:00-24:00", "03:00-03:00", "25:00-05:00"} {
+		if _, err := services.ParseTimeWindow(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+// windowAround returns a window that starts offset from now (in loc) and lasts an hour
+func windowAround(loc *time.Location, offset time.Duration) *services.TimeWindow {
+	now := time.Now().In(loc)
+	clock := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute
+	start := (clock + offset + 24*time.Hour) % (24 * time.Hour)
+	return &services.TimeWindow{Start: start, End: (start + time.Hour) % (24 * time.Hour)}
+}
+
+func TestProcessAsync_RefusesToStartOutsideAllowedWindow(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.Location = time.UTC
+	logs := recordLogs(svc)
+
+	svc.AllowedWindow = windowAround(time.UTC, 2*time.Hour)
+	svc.ProcessAsync(context.Background(), "outside", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "outside", time.Second)
+	if job.Status != "failed" || !strings.Contains(job.ErrorMessage, services.ErrOutsideAllowedWindow.Error()) || !strings.Contains(job.ErrorMessage, "(UTC)") {
+		t.Errorf("expected the job to be refused with the window in the reason, got %s %q", job.Status, job.ErrorMessage)
+	}
+	if !logs.contains("Rejected download job outside") {
+		t.Errorf("expected the rejection to be logged, got %q", logs.lines)
+	}
+	if len(factory.configs) != 0 {
+		t.Errorf("expected no scraper to be created, got %d", len(factory.configs))
+	}
+
+	svc.AllowedWindow = windowAround(time.UTC, -30*time.Minute)
+	svc.ProcessAsync(context.Background(), "inside", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	if job := waitForJob(t, svc, "inside", 5*time.Second); job.Status != "completed" {
+		t.Errorf("expected the job inside the window to run, got %s %q", job.Status, job.ErrorMessage)
+	}
+}
+
+func TestAllowedWindow_FromEnv(t *testing.T) {
+	t.Setenv("ETC_ALLOWED_WINDOW", "01:00-05:00")
+	t.Setenv("ETC_TIMEZONE", "Asia/Tokyo")
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if svc.AllowedWindow == nil || svc.AllowedWindow.String() != "01:00-05:00" || svc.Location.String() != "Asia/Tokyo" {
+		t.Fatalf("unexpected window %v in %v", svc.AllowedWindow, svc.Location)
+	}
+
+	t.Setenv("ETC_ALLOWED_WINDOW", "nightly")
+	t.Setenv("ETC_TIMEZONE", "Mars/Olympus")
+	svc = services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if svc.AllowedWindow != nil || svc.Location != time.Local {
+		t.Errorf("expected invalid values to fall back to no restriction in local time, got %v in %v", svc.AllowedWindow, svc.Location)
+	}
+}
@@ -0,0 +1,43 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_UserAgentAndViewportFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		viewport  string
+		wantUA    string
+		wantSize  scraper.Size
+	}{
+		{name: "unset", wantSize: scraper.Size{}},
+		{name: "custom", userAgent: "CustomAgent/1.0", viewport: "1366x768", wantUA: "CustomAgent/1.0", wantSize: scraper.Size{Width: 1366, Height: 768}},
+		{name: "invalid viewport", viewport: "wide", wantSize: scraper.Size{}},
+		{name: "zero height", viewport: "1366x0", wantSize: scraper.Size{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			t.Setenv("ETC_USER_AGENT", tt.userAgent)
+			t.Setenv("ETC_VIEWPORT", tt.viewport)
+
+			factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+			svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+			svc.ProcessAsync(context.Background(), "job-identity", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+			waitForJob(t, svc, "job-identity", 5*time.Second)
+
+			cfg := factory.configs[0]
+			if cfg.UserAgent != tt.wantUA || cfg.Viewport != tt.wantSize {
+				t.Errorf("expected UA %q and viewport %+v, got %q and %+v", tt.wantUA, tt.wantSize, cfg.UserAgent, cfg.Viewport)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCancelJob_StopsRemainingAccounts(t *testing.T) {
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.MaxConcurrency = 1
+
+	svc.ProcessAsync(context.Background(), "cancel-job", []string{"user1:pass1", "user2:pass2", "user3:pass3"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+
+	if err := svc.CancelJob("cancel-job"); err != nil {
+		t.Fatalf("CancelJob failed: %v", err)
+	}
+	close(gate)
+	job := waitForJob(t, svc, "cancel-job", 5*time.Second)
+
+	if job.Status != "cancelled" || job.ErrorMessage != services.ErrJobCancelled.Error() {
+		t.Errorf("expected cancelled with %q, got %s %q", services.ErrJobCancelled, job.Status, job.ErrorMessage)
+	}
+	if got := factory.createdScrapers(); got != 1 {
+		t.Errorf("expected only the in-flight account to run, got %d", got)
+	}
+	if len(job.AccountResults) != 1 {
+		t.Errorf("expected the in-flight account to finish, got %d results", len(job.AccountResults))
+	}
+}
+
+func TestCancelJob_PausedJob(t *testing.T) {
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Delay: 100 * time.Millisecond}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.MaxConcurrency = 1
+
+	svc.ProcessAsync(context.Background(), "paused-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+	if err := svc.PauseJob("paused-job"); err != nil {
+		t.Fatalf("PauseJob failed: %v", err)
+	}
+	if err := svc.CancelJob("paused-job"); err != nil {
+		t.Fatalf("CancelJob failed: %v", err)
+	}
+
+	job := waitForJob(t, svc, "paused-job", 5*time.Second)
+	if job.Status != "cancelled" || factory.createdScrapers() != 1 {
+		t.Errorf("expected paused job to be cancelled without resuming, got %s after %d accounts", job.Status, factory.createdScrapers())
+	}
+}
+
+func TestProcessAsync_ParentContextCancellation(t *testing.T) {
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.MaxConcurrency = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	svc.ProcessAsync(ctx, "ctx-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+	cancel()
+	close(gate)
+
+	job := waitForJob(t, svc, "ctx-job", 5*time.Second)
+	if job.Status != "cancelled" || job.ErrorMessage != context.Canceled.Error() {
+		t.Errorf("expected cancelled with %q, got %s %q", context.Canceled, job.Status, job.ErrorMessage)
+	}
+}
+
+func TestDownloadAsync_OutlivesRequestContext(t *testing.T) {
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Delay: 50 * time.Millisecond}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resp, err := grpcSvc.DownloadAsync(ctx, &pb.DownloadRequest{Accounts: []string{"user1:pass1", "user2:pass2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel() // the RPC has returned
+
+	job := waitForJob(t, svc, resp.JobId, 5*time.Second)
+	if job.Status != "completed" {
+		t.Errorf("expected job to complete after the request ended, got %s %q", job.Status, job.ErrorMessage)
+	}
+}
+
+func TestCancelJob_InvalidStates(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	if err := svc.CancelJob("missing"); !errors.Is(err, services.ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+	_, err := grpcSvc.CancelJob(context.Background(), &pb.CancelJobRequest{JobId: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+
+	svc.ProcessAsync(context.Background(), "done-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "done-job", 5*time.Second)
+	_, err = grpcSvc.CancelJob(context.Background(), &pb.CancelJobRequest{JobId: "done-job"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition for completed job, got %v", err)
+	}
+}
+
+func TestDownloadSync_ClientCancelClosesScraper(t *testing.T) {
+	gate := make(chan struct{})
+	defer close(gate)
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.MaxConcurrency = 1
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+	grpcSvc.IDGenerator = func() string { return "sync-job" }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := grpcSvc.DownloadSync(ctx, &pb.DownloadRequest{Accounts: []string{"user1:pass1", "user2:pass2"}})
+		errCh <- err
+	}()
+	waitFor(t, func() bool { return factory.downloadAttempts("user1") == 1 })
+	cancel()
+
+	// the gate stays closed: only closing the scraper can end the in-flight download
+	select {
+	case err := <-errCh:
+		if status.Code(err) != codes.Canceled {
+			t.Errorf("expected Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("DownloadSync did not return after the client cancelled")
+	}
+	waitFor(t, func() bool { return factory.closeAttempts("user1") > 0 })
+
+	job := waitForJob(t, svc, "sync-job", 5*time.Second)
+	if job.Status != "cancelled" || len(job.FailedAccounts) != 0 {
+		t.Errorf("expected cancelled without failed accounts, got %s %v", job.Status, job.FailedAccounts)
+	}
+	if job.PerAccount["user1"] != "cancelled" || factory.createdScrapers() != 1 {
+		t.Errorf("expected only user1 to start and be cancelled, got %v after %d scrapers", job.PerAccount, factory.createdScrapers())
+	}
+}
@@ -0,0 +1,69 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+const cardsCSV = "利用年月日（自）,通行料金,ETCカード番号\n" +
+	"2024/01/05,1200,1111-2222-3333-4444\n" +
+	"2024/01/06,800,5555-6666-7777-8888\n" +
+	"2024/01/07,950,1111-2222-3333-4444\n" +
+	"2024/01/08,700,9999-0000-1111-2222\n"
+
+func TestDownloadAsync_CardNumbersFilter(t *testing.T) {
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: cardsCSV})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+	logs := recordLogs(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts: []string{"user1:pass1"},
+		// 全角・空白の表記揺れは無視する
+		CardNumbers: []string{"１１１１ ２２２２ ３３３３ ４４４４", "9999000011112222"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, resp.JobId, 5*time.Second)
+
+	records, err := svc.GetJobRecords(resp.JobId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records for the selected cards, got %d", len(records))
+	}
+	for _, r := range records {
+		if r.EtcCardNumber == "5555-6666-7777-8888" {
+			t.Errorf("unexpected record for an unselected card: %v", r)
+		}
+	}
+	if !logs.contains("Filtered out 1 records for account user1") {
+		t.Error("expected the filtered-out count to be logged")
+	}
+}
+
+func TestDownloadAsync_EmptyCardNumbersKeepsAllCards(t *testing.T) {
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: cardsCSV})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, resp.JobId, 5*time.Second)
+
+	records, err := svc.GetJobRecords(resp.JobId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 {
+		t.Errorf("expected all 4 records, got %d", len(records))
+	}
+}
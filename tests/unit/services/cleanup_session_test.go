@@ -0,0 +1,163 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestCleanupSession_RemovesFolderAndLogsFreedBytes(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	svc.DownloadDir = t.TempDir()
+	logs := recordLogs(svc)
+
+	folder := filepath.Join(svc.DownloadDir, "20240101_000000")
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(folder, "a.csv"), make([]byte, 100), 0644)
+	os.WriteFile(filepath.Join(folder, "b.csv"), make([]byte, 23), 0644)
+
+	if err := svc.CleanupSession(folder); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(folder); !os.IsNotExist(err) {
+		t.Errorf("expected session folder to be removed, stat err=%v", err)
+	}
+	if !logs.contains("freed 123 bytes") {
+		t.Errorf("expected freed bytes in logs, got %v", logs.lines)
+	}
+}
+
+func TestCleanupSession_MissingFolderIsNotAnError(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	svc.DownloadDir = t.TempDir()
+
+	if err := svc.CleanupSession(filepath.Join(svc.DownloadDir, "never-created")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCleanupSession_RefusesFoldersOutsideDownloadDir(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	svc.DownloadDir = filepath.Join(t.TempDir(), "downloads")
+	outside := t.TempDir()
+
+	for _, folder := range []string{outside, svc.DownloadDir, filepath.Join(svc.DownloadDir, "..")} {
+		err := svc.CleanupSession(folder)
+		if err == nil || !strings.Contains(err.Error(), "refusing to remove") {
+			t.Errorf("CleanupSession(%s): expected refusal, got %v", folder, err)
+		}
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("folder outside DownloadDir must be kept: %v", err)
+	}
+}
+
+func TestProcessAsync_CleanupDownloads(t *testing.T) {
+	tests := []struct {
+		name        string
+		cleanup     bool
+		loginErrors map[string]error
+		wantRemoved bool
+	}{
+		{"disabled by default", false, nil, false},
+		{"removed after success", true, nil, true},
+		{"kept on failure", true, map[string]error{"user2": &scraper.AuthError{Reason: "bad password"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := &fakeScraperFactory{CSV: "header\nrow\n", LoginErrors: tt.loginErrors}
+			svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+			svc.DownloadDir = t.TempDir()
+			svc.AccountDelay = 0
+			svc.CleanupDownloads = tt.cleanup
+
+			svc.ProcessAsync(context.Background(), "job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+			waitForJob(t, svc, "job", 5*time.Second)
+
+			folder := factory.configs[0].SessionFolder
+			// 完了ステータスの設定後に削除されるため、削除を待つ
+			if tt.wantRemoved {
+				waitFor(t, func() bool {
+					_, err := os.Stat(folder)
+					return os.IsNotExist(err)
+				})
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+			if _, err := os.Stat(filepath.Join(folder, "user1_meisai.csv")); err != nil {
+				t.Errorf("expected session folder to be kept: %v", err)
+			}
+		})
+	}
+}
+
+func TestCleanupOldDownloads_RemovesOnlyOldSessionFolders(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	svc.DownloadDir = t.TempDir()
+
+	old := time.Now().Add(-48 * time.Hour).Format("20060102_150405")
+	recent := time.Now().Add(-time.Hour).Format("20060102_150405")
+	folders := map[string]bool{
+		old:                                true,  // legacy timestamp-only folder
+		old + "_job-1_0a1b2c3d":            true,  // timestamp_jobID_random
+		recent + "_job-2_0a1b2c3d":         false, // too recent
+		old + "_notes":                     false, // not the naming scheme
+		"account_test_" + old:              false,
+		"backup":                           false,
+		old + "_job-3_0a1b2c3d" + "_extra": false,
+	}
+	for name := range folders {
+		if err := os.MkdirAll(filepath.Join(svc.DownloadDir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a file matching the naming scheme is not a session folder
+	os.WriteFile(filepath.Join(svc.DownloadDir, old+"_job-4_0a1b2c3d"), []byte("x"), 0644)
+
+	removed, err := svc.CleanupOldDownloads(24 * time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("expected 2 folders removed, got %d", removed)
+	}
+	for name, wantRemoved := range folders {
+		_, err := os.Stat(filepath.Join(svc.DownloadDir, name))
+		if gone := os.IsNotExist(err); gone != wantRemoved {
+			t.Errorf("%s: removed=%v, want %v", name, gone, wantRemoved)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(svc.DownloadDir, old+"_job-4_0a1b2c3d")); err != nil {
+		t.Errorf("expected the file to be kept: %v", err)
+	}
+}
+
+func TestCleanupDownloadsOnStart_FromEnv(t *testing.T) {
+	t.Setenv("ETC_DOWNLOAD_DIR", t.TempDir())
+	old := filepath.Join(os.Getenv("ETC_DOWNLOAD_DIR"), time.Now().Add(-48*time.Hour).Format("20060102_150405"))
+	if err := os.MkdirAll(old, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if removed, err := svc.CleanupDownloadsOnStart(); removed != 0 || err != nil {
+		t.Fatalf("expected no cleanup without ETC_CLEANUP_ON_START, got %d %v", removed, err)
+	}
+
+	t.Setenv("ETC_CLEANUP_ON_START", "24h")
+	svc = services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if removed, err := svc.CleanupDownloadsOnStart(); removed != 1 || err != nil {
+		t.Fatalf("expected the old folder to be removed, got %d %v", removed, err)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, stat err=%v", old, err)
+	}
+}
@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_PasswordWithColons(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+
+	svc.ProcessAsync(context.Background(), "job-colon", []string{"user1:pa:ss:"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "job-colon", 5*time.Second)
+
+	if len(job.FailedAccounts) != 0 {
+		t.Fatalf("unexpected failures: %+v", job.FailedAccounts)
+	}
+	cfg := factory.configs[0]
+	if cfg.UserID != "user1" || cfg.Password != "pa:ss:" {
+		t.Errorf("expected user1 / pa:ss:, got %s / %s", cfg.UserID, cfg.Password)
+	}
+	if _, ok := job.PerAccount["user1"]; !ok {
+		t.Errorf("expected per-account status keyed by user1, got %v", job.PerAccount)
+	}
+}
+
+func TestValidateAccountFormat_PasswordWithColons(t *testing.T) {
+	if err := services.ValidateAccountFormat("user1:pa:ss"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := services.ValidateAccountFormat("user1:")
+	if err == nil {
+		t.Fatal("expected error for empty password")
+	}
+}
+
+func TestGetAllAccountIDs_PasswordWithColons(t *testing.T) {
+	t.Setenv("ETC_CORP_ACCOUNTS", `["user1:pa:ss","user2:p:w:d"]`)
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+
+	ids := svc.GetAllAccountIDs()
+	if len(ids) != 2 || ids[0] != "user1" || ids[1] != "user2" {
+		t.Errorf("unexpected account IDs: %v", ids)
+	}
+}
+
+func TestGetEnvironmentVariables_MasksPasswordWithColons(t *testing.T) {
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:pa:ss,user2:p:w:d")
+	grpcSvc := services.NewDownloadServiceGRPCWithService(services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{}))
+
+	resp, err := grpcSvc.GetEnvironmentVariables(context.Background(), &pb.GetEnvironmentVariablesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.EtcCorpAccounts != "user1:*******,user2:*******" {
+		t.Errorf("unexpected masked accounts %q", resp.EtcCorpAccounts)
+	}
+	for _, secret := range []string{"pa", "ss", "w:d"} {
+		if strings.Contains(resp.EtcCorpAccounts, secret) {
+			t.Errorf("password fragment %q leaked: %s", secret, resp.EtcCorpAccounts)
+		}
+	}
+}
+
+func TestGetEnvironmentVariables_DownloadSettings(t *testing.T) {
+	t.Setenv("ETC_DOWNLOAD_DIR", "/data/etc")
+	t.Setenv("ETC_MAX_CONCURRENCY", "4")
+	t.Setenv("ETC_TIMEOUT_MS", "60000")
+	grpcSvc := services.NewDownloadServiceGRPCWithService(services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{}))
+
+	resp, err := grpcSvc.GetEnvironmentVariables(context.Background(), &pb.GetEnvironmentVariablesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.EtcDownloadDir != "/data/etc" || resp.EtcMaxConcurrency != "4" || resp.EtcTimeoutMs != "60000" {
+		t.Errorf("unexpected settings: dir=%q concurrency=%q timeout=%q", resp.EtcDownloadDir, resp.EtcMaxConcurrency, resp.EtcTimeoutMs)
+	}
+}
@@ -0,0 +1,98 @@
+package services_test
+
+import (
+	"encoding/base64"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+var testCredKey = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+func TestGetAllAccountsWithCredentials_DecryptsEncryptedAccounts(t *testing.T) {
+	t.Setenv("ETC_CRED_KEY", testCredKey)
+	encrypted, err := services.EncryptAccounts([]string{"user1:pass1", "user2:pa,ss:2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, account := range encrypted {
+		if !strings.HasPrefix(account, "enc:") || strings.Contains(account, "pass") {
+			t.Fatalf("expected an opaque enc: value, got %q", account)
+		}
+	}
+
+	t.Run("env", func(t *testing.T) {
+		// encrypted and plaintext accounts can be mixed while migrating
+		t.Setenv("ETC_CORP_ACCOUNTS", strings.Join(append(encrypted, "user3:pass3"), ","))
+		svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+		want := []string{"user1:pass1", "user2:pa,ss:2", "user3:pass3"}
+		if got := svc.GetAllAccountsWithCredentials(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		if got := svc.GetAllAccountIDs(); !reflect.DeepEqual(got, []string{"user1", "user2", "user3"}) {
+			t.Errorf("expected the decrypted account IDs, got %q", got)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		t.Setenv("ETC_CORP_ACCOUNTS", "")
+		t.Setenv("ETC_ACCOUNTS_FILE", writeAccountsFile(t, strings.Join(encrypted, "\n")+"\n"))
+		svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+		if got := svc.GetAllAccountsWithCredentials(); !reflect.DeepEqual(got, []string{"user1:pass1", "user2:pa,ss:2"}) {
+			t.Errorf("got %q", got)
+		}
+	})
+}
+
+func TestGetAllAccountsWithCredentials_DecryptionErrors(t *testing.T) {
+	t.Setenv("ETC_CRED_KEY", testCredKey)
+	encrypted, err := services.EncryptAccounts([]string{"user1:pass1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey := base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+
+	tests := []struct {
+		name     string
+		key      string
+		accounts string
+		want     string
+	}{
+		{"wrong key", otherKey, encrypted[0], "wrong ETC_CRED_KEY or corrupted value"},
+		{"missing key", "", encrypted[0], "ETC_CRED_KEY is not set"},
+		{"truncated", testCredKey, encrypted[0][:20], "failed to decrypt account at index 0"},
+		{"not base64", testCredKey, "user2:pass2,enc:!!!", "failed to decrypt account at index 1"},
+		{"bad key", "c2hvcnQ=", encrypted[0], "expected a 16, 24 or 32 byte key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ETC_CRED_KEY", tt.key)
+			t.Setenv("ETC_CORP_ACCOUNTS", tt.accounts)
+			var logs strings.Builder
+			svc := services.NewDownloadServiceWithFactory(nil, log.New(&logs, "", 0), &fakeScraperFactory{})
+
+			// the ciphertext must never be used as an account
+			if got := svc.GetAllAccountsWithCredentials(); len(got) != 0 {
+				t.Errorf("expected no accounts, got %q", got)
+			}
+			if !strings.Contains(logs.String(), tt.want) {
+				t.Errorf("expected the startup log to contain %q, got %q", tt.want, logs.String())
+			}
+		})
+	}
+}
+
+func TestEncryptAccounts_RequiresKeyAndValidAccounts(t *testing.T) {
+	t.Setenv("ETC_CRED_KEY", "")
+	if _, err := services.EncryptAccounts([]string{"user1:pass1"}); err == nil {
+		t.Error("expected an error without ETC_CRED_KEY")
+	}
+
+	t.Setenv("ETC_CRED_KEY", testCredKey)
+	if _, err := services.EncryptAccounts([]string{"user1:pass1", "user2"}); err == nil || !strings.Contains(err.Error(), "index 1") {
+		t.Errorf("expected the invalid account to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestParseCSVEncoding(t *testing.T) {
+	tests := map[string]services.CSVEncoding{
+		"auto":      services.CSVEncodingAuto,
+		"UTF-8":     services.CSVEncodingUTF8,
+		"utf8":      services.CSVEncodingUTF8,
+		"Shift_JIS": services.CSVEncodingShiftJIS,
+		" sjis ":    services.CSVEncodingShiftJIS,
+	}
+	for name, want := range tests {
+		if got, err := services.ParseCSVEncoding(name); err != nil || got != want {
+			t.Errorf("ParseCSVEncoding(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := services.ParseCSVEncoding("euc-jp"); err == nil {
+		t.Error("expected an error for an unsupported encoding")
+	}
+}
+
+func TestProcessAsync_CSVEncoding(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         string
+		wantLog     string
+		wantRecords int
+	}{
+		{name: "detected", env: "", wantLog: "as Shift-JIS (detected)", wantRecords: 2},
+		{name: "invalid env falls back to detection", env: "latin1", wantLog: "as Shift-JIS (detected)", wantRecords: 2},
+		// Shift-JISのヘッダーをUTF-8として読むと列名が一致せず解析に失敗する
+		{name: "override", env: "utf-8", wantLog: "as UTF-8 (ETC_CSV_ENCODING)", wantRecords: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			t.Setenv("ETC_CSV_ENCODING", tt.env)
+
+			factory := &fakeScraperFactory{CSV: string(toShiftJIS(t, meisaiCSV("\r\n")))}
+			svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+			logs := recordLogs(svc)
+			svc.ProcessAsync(context.Background(), "job-encoding", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+			waitForJob(t, svc, "job-encoding", 5*time.Second)
+
+			if !logs.contains("Reading user1_meisai.csv " + tt.wantLog) {
+				t.Errorf("expected log %q", tt.wantLog)
+			}
+			records, err := svc.GetJobRecords("job-encoding")
+			if err != nil || len(records) != tt.wantRecords {
+				t.Errorf("expected %d records, got %d (%v)", tt.wantRecords, len(records), err)
+			}
+		})
+	}
+}
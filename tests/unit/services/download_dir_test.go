@@ -0,0 +1,73 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_UsesDownloadDirFromEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+	base := filepath.Join(t.TempDir(), "nested", "downloads")
+	t.Setenv("ETC_DOWNLOAD_DIR", base)
+
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	if svc.DownloadDir != base {
+		t.Fatalf("expected DownloadDir %s, got %s", base, svc.DownloadDir)
+	}
+
+	svc.ProcessAsync(context.Background(), "job-dir", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "job-dir", 5*time.Second)
+	if job.Status != "completed" {
+		t.Fatalf("expected completed job, got %s (%s)", job.Status, job.ErrorMessage)
+	}
+
+	if info, err := os.Stat(base); err != nil || !info.IsDir() {
+		t.Fatalf("download directory was not created: %v", err)
+	}
+	cfg := factory.configs[0]
+	if cfg.DownloadPath != base {
+		t.Errorf("expected DownloadPath %s, got %s", base, cfg.DownloadPath)
+	}
+	if filepath.Dir(cfg.SessionFolder) != base {
+		t.Errorf("expected session folder under %s, got %s", base, cfg.SessionFolder)
+	}
+}
+
+func TestProcessAsync_DefaultDownloadDir(t *testing.T) {
+	t.Setenv("ETC_DOWNLOAD_DIR", "")
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if svc.DownloadDir != "./downloads" {
+		t.Errorf("expected default ./downloads, got %s", svc.DownloadDir)
+	}
+}
+
+func TestProcessAsync_FailsWhenDownloadDirCannotBeCreated(t *testing.T) {
+	// 既存ファイルの下にはディレクトリを作成できない
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.DownloadDir = filepath.Join(blocker, "downloads")
+
+	svc.ProcessAsync(context.Background(), "job-baddir", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "job-baddir", 5*time.Second)
+	if job.Status != "failed" {
+		t.Fatalf("expected failed job, got %s", job.Status)
+	}
+	if !strings.Contains(job.ErrorMessage, "failed to create download directory") {
+		t.Errorf("unexpected error message %q", job.ErrorMessage)
+	}
+	if factory.createdScrapers() != 0 {
+		t.Errorf("no scraper should be created, got %d", factory.createdScrapers())
+	}
+}
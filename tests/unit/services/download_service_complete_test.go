@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -39,7 +40,7 @@ func TestDownloadService_ProcessAsync_CompleteFlow(t *testing.T) {
 	toDate := "2024-01-31"
 
 	// Start async processing
-	service.ProcessAsync(jobID, accounts, fromDate, toDate)
+	service.ProcessAsync(context.Background(), jobID, accounts, fromDate, toDate)
 
 	// Check initial status
 	job, exists := service.GetJobStatus(jobID)
@@ -76,7 +77,7 @@ func TestDownloadService_ProcessAsync_InvalidAccount(t *testing.T) {
 	fromDate := "2024-01-01"
 	toDate := "2024-01-31"
 
-	service.ProcessAsync(jobID, accounts, fromDate, toDate)
+	service.ProcessAsync(context.Background(), jobID, accounts, fromDate, toDate)
 
 	// Give time for processing
 	time.Sleep(3 * time.Second)
@@ -103,7 +104,7 @@ func TestDownloadService_ProcessAsync_Panic_Recovery(t *testing.T) {
 	toDate := "2024-01-31"
 
 	// This should not panic
-	service.ProcessAsync(jobID, accounts, fromDate, toDate)
+	service.ProcessAsync(context.Background(), jobID, accounts, fromDate, toDate)
 
 	// Check that job was created
 	_, exists := service.GetJobStatus(jobID)
@@ -223,7 +224,7 @@ func TestDownloadService_ConcurrentAccess(t *testing.T) {
 			jobID := fmt.Sprintf("concurrent-job-%d", id)
 			accounts := []string{fmt.Sprintf("account%d:pass%d", id, id)}
 
-			service.ProcessAsync(jobID, accounts, "2024-01-01", "2024-01-31")
+			service.ProcessAsync(context.Background(), jobID, accounts, "2024-01-01", "2024-01-31")
 
 			// Check status multiple times
 			for j := 0; j < 5; j++ {
@@ -253,7 +254,7 @@ func TestDownloadService_UpdateJobProgress(t *testing.T) {
 
 	// Create a job
 	jobID := "progress-test-job"
-	service.ProcessAsync(jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
+	service.ProcessAsync(context.Background(), jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
 
 	// Give it a moment to start
 	time.Sleep(50 * time.Millisecond)
@@ -286,7 +287,7 @@ func TestDownloadService_ErrorMessage(t *testing.T) {
 	jobID := "error-test-job"
 	accounts := []string{"invalid"} // Missing password part
 
-	service.ProcessAsync(jobID, accounts, "2024-01-01", "2024-01-31")
+	service.ProcessAsync(context.Background(), jobID, accounts, "2024-01-01", "2024-01-31")
 
 	// Wait for processing
 	time.Sleep(2 * time.Second)
@@ -9,6 +9,8 @@ import (
 
 	pb "github.com/yhonda-ohishi/etc_meisai_scraper/src/pb"
 	"github.com/yhonda-ohishi/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestDownloadServiceGRPC_GetJobStatus_With_CompletedAt_Coverage(t *testing.T) {
@@ -101,12 +103,12 @@ func TestDownloadServiceGRPC_GetJobStatus_With_CompletedAt_Coverage(t *testing.T
 		}
 	})
 
-	// Test 3: Non-existent job (ensures nil response path)
-	t.Run("non-existent job returns nil", func(t *testing.T) {
+	// Test 3: Non-existent job (ensures NotFound path)
+	t.Run("non-existent job returns NotFound", func(t *testing.T) {
 		req := &pb.GetJobStatusRequest{JobId: "non-existent-job-12345"}
 		resp, err := service.GetJobStatus(ctx, req)
-		if err != nil {
-			t.Fatalf("GetJobStatus failed: %v", err)
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Expected NotFound, got %v", err)
 		}
 
 		if resp != nil {
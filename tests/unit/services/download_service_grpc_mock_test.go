@@ -7,13 +7,19 @@ import (
 
 	pb "github.com/yhonda-ohishi/etc_meisai_scraper/src/pb"
 	"github.com/yhonda-ohishi/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // MockDownloadService implements DownloadServiceInterface for testing
+// Methods the tests do not use come from the embedded UnimplementedDownloadService
 type MockDownloadService struct {
+	services.UnimplementedDownloadService
 	jobs map[string]*services.DownloadJob
 }
 
+var _ services.DownloadServiceInterface = (*MockDownloadService)(nil)
+
 func NewMockDownloadService() *MockDownloadService {
 	return &MockDownloadService{
 		jobs: make(map[string]*services.DownloadJob),
@@ -24,7 +30,7 @@ func (m *MockDownloadService) GetAllAccountIDs() []string {
 	return []string{"test1", "test2"}
 }
 
-func (m *MockDownloadService) ProcessAsync(jobID string, accounts []string, fromDate, toDate string) {
+func (m *MockDownloadService) ProcessAsync(ctx context.Context, jobID string, accounts []string, fromDate, toDate string) {
 	now := time.Now()
 	m.jobs[jobID] = &services.DownloadJob{
 		ID:           jobID,
@@ -130,8 +136,8 @@ func TestDownloadServiceGRPC_GetJobStatus_WithMock(t *testing.T) {
 
 		req := &pb.GetJobStatusRequest{JobId: "non-existent"}
 		resp, err := grpcService.GetJobStatus(ctx, req)
-		if err != nil {
-			t.Fatalf("GetJobStatus failed: %v", err)
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Expected NotFound, got %v", err)
 		}
 
 		// Should return nil for non-existent job
@@ -8,32 +8,68 @@ import (
 	"time"
 
 	pb "github.com/yhonda-ohishi/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi/etc_meisai_scraper/src/scraper"
 	"github.com/yhonda-ohishi/etc_meisai_scraper/src/services"
+	"github.com/yhonda-ohishi/etc_meisai_scraper/src/services/scrapertest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func TestDownloadServiceGRPC_DownloadSync(t *testing.T) {
-	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
-	service := services.NewDownloadServiceGRPC(nil, logger)
+// newFakeDownloadServiceGRPC returns a gRPC service whose scrapers never start a browser
+func newFakeDownloadServiceGRPC(t *testing.T, factory *scrapertest.FakeScraperFactory) *services.DownloadServiceGRPC {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	return services.NewDownloadServiceGRPCWithService(svc)
+}
 
-	ctx := context.Background()
-	req := &pb.DownloadRequest{
-		Accounts: []string{"test1:pass1"},
-		FromDate: "2024-01-01",
-		ToDate:   "2024-01-31",
+func TestDownloadServiceGRPC_DownloadSync(t *testing.T) {
+	tests := []struct {
+		name        string
+		behavior    scrapertest.Behavior
+		wantSuccess bool
+		wantCode    pb.ErrorCode
+		wantRecords int32
+	}{
+		{
+			name:        "success",
+			behavior:    scrapertest.Behavior{CSV: threeRowCSV},
+			wantSuccess: true,
+			wantCode:    pb.ErrorCode_ERROR_CODE_UNSPECIFIED,
+			wantRecords: 3,
+		},
+		{
+			name:     "login failure",
+			behavior: scrapertest.Behavior{LoginErr: &scraper.AuthError{Reason: "invalid password"}},
+			wantCode: pb.ErrorCode_ERROR_CODE_AUTH_FAILED,
+		},
 	}
 
-	resp, err := service.DownloadSync(ctx, req)
-	if err != nil {
-		t.Fatalf("DownloadSync failed: %v", err)
-	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newFakeDownloadServiceGRPC(t, &scrapertest.FakeScraperFactory{Behavior: tt.behavior})
 
-	if resp == nil {
-		t.Fatal("Expected non-nil response")
-	}
+			resp, err := service.DownloadSync(context.Background(), &pb.DownloadRequest{
+				Accounts: []string{"test1:pass1"},
+				FromDate: "2024-01-01",
+				ToDate:   "2024-01-31",
+			})
+			if err != nil {
+				t.Fatalf("DownloadSync failed: %v", err)
+			}
 
-	if !resp.Success {
-		t.Error("Expected success to be true")
+			if resp.Success != tt.wantSuccess || resp.ErrorCode != tt.wantCode || resp.RecordCount != tt.wantRecords {
+				t.Errorf("Expected success=%t error_code=%v record_count=%d, got %+v", tt.wantSuccess, tt.wantCode, tt.wantRecords, resp)
+			}
+			if tt.wantSuccess && resp.ErrorMessage != "" {
+				t.Errorf("Expected no error message on success, got %q", resp.ErrorMessage)
+			}
+			if !tt.wantSuccess && (resp.ErrorMessage == "" || resp.Error != resp.ErrorMessage) {
+				t.Errorf("Expected error_message (and the same error), got error_message=%q error=%q", resp.ErrorMessage, resp.Error)
+			}
+		})
 	}
 }
 
@@ -170,8 +206,8 @@ func TestDownloadServiceGRPC_GetJobStatus(t *testing.T) {
 		}
 
 		resp, err := service.GetJobStatus(ctx, req)
-		if err != nil {
-			t.Fatalf("GetJobStatus failed: %v", err)
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Expected NotFound, got %v", err)
 		}
 
 		if resp != nil {
@@ -217,8 +253,7 @@ func TestDownloadServiceGRPC_GetAllAccountIDs(t *testing.T) {
 }
 
 func TestDownloadServiceGRPC_SetDefaultDates(t *testing.T) {
-	logger := log.New(os.Stdout, "[TEST] ", log.LstdFlags)
-	service := services.NewDownloadServiceGRPC(nil, logger)
+	service := newFakeDownloadServiceGRPC(t, &scrapertest.FakeScraperFactory{Behavior: scrapertest.Behavior{CSV: threeRowCSV}})
 
 	// Test with empty dates (should set defaults)
 	ctx := context.Background()
@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"context"
 	"errors"
 	"log"
 	"os"
@@ -49,7 +50,7 @@ func TestDownloadService_WithMockScraper_Success(t *testing.T) {
 	fromDate := "2024-01-01"
 	toDate := "2024-01-31"
 
-	service.ProcessAsync(jobID, accounts, fromDate, toDate)
+	service.ProcessAsync(context.Background(), jobID, accounts, fromDate, toDate)
 
 	// Wait for processing
 	time.Sleep(3 * time.Second)
@@ -95,7 +96,7 @@ func TestDownloadService_WithMockScraper_InitializeError(t *testing.T) {
 
 	// Execute
 	jobID := "init-error-job"
-	service.ProcessAsync(jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
+	service.ProcessAsync(context.Background(), jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
 
 	// Wait for processing
 	time.Sleep(2 * time.Second)
@@ -132,7 +133,7 @@ func TestDownloadService_WithMockScraper_LoginError(t *testing.T) {
 
 	// Execute
 	jobID := "login-error-job"
-	service.ProcessAsync(jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
+	service.ProcessAsync(context.Background(), jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
 
 	// Wait for processing
 	time.Sleep(2 * time.Second)
@@ -168,7 +169,7 @@ func TestDownloadService_WithMockScraper_DownloadError(t *testing.T) {
 
 	// Execute
 	jobID := "download-error-job"
-	service.ProcessAsync(jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
+	service.ProcessAsync(context.Background(), jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
 
 	// Wait for processing
 	time.Sleep(2 * time.Second)
@@ -211,7 +212,7 @@ func TestDownloadService_WithMockScraper_ScraperCreateError(t *testing.T) {
 
 	// Execute
 	jobID := "create-error-job"
-	service.ProcessAsync(jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
+	service.ProcessAsync(context.Background(), jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
 
 	// Wait for processing
 	time.Sleep(2 * time.Second)
@@ -250,7 +251,7 @@ func TestDownloadService_UpdateJobStatus(t *testing.T) {
 
 	// Execute - this should trigger panic recovery and updateJobStatus
 	jobID := "panic-recovery-job"
-	service.ProcessAsync(jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
+	service.ProcessAsync(context.Background(), jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
 
 	// Wait for panic recovery
 	time.Sleep(2 * time.Second)
@@ -287,7 +288,7 @@ func TestDownloadService_WithConfigurableMock(t *testing.T) {
 
 	// Execute
 	jobID := "configurable-test"
-	service.ProcessAsync(jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
+	service.ProcessAsync(context.Background(), jobID, []string{"test:pass"}, "2024-01-01", "2024-01-31")
 
 	// Wait for processing
 	time.Sleep(2 * time.Second)
@@ -345,7 +346,7 @@ func TestDownloadService_MultipleAccounts_WithMock(t *testing.T) {
 	// Execute with multiple accounts
 	jobID := "multi-account-job"
 	accounts := []string{"acc1:pass1", "acc2:pass2", "acc3:pass3"}
-	service.ProcessAsync(jobID, accounts, "2024-01-01", "2024-01-31")
+	service.ProcessAsync(context.Background(), jobID, accounts, "2024-01-01", "2024-01-31")
 
 	// Wait for processing
 	time.Sleep(3500 * time.Millisecond)
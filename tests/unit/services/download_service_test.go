@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"context"
 	"log"
 	"os"
 	"testing"
@@ -47,7 +48,7 @@ func TestDownloadService_ProcessAsync(t *testing.T) {
 	toDate := "2024-01-31"
 
 	// This should not panic or error
-	service.ProcessAsync(jobID, accounts, fromDate, toDate)
+	service.ProcessAsync(context.Background(), jobID, accounts, fromDate, toDate)
 
 	// Check job status
 	job, exists := service.GetJobStatus(jobID)
@@ -0,0 +1,219 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newSyncService(t *testing.T, factory *fakeScraperFactory) *services.DownloadServiceGRPC {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.RetryBaseDelay = time.Millisecond
+	return services.NewDownloadServiceGRPCWithService(svc)
+}
+
+func TestDownloadSync_ReturnsRecords(t *testing.T) {
+	grpcSvc := newSyncService(t, &fakeScraperFactory{CSV: meisaiCSV("\n")})
+
+	resp, err := grpcSvc.DownloadSync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Success || resp.ErrorCode != pb.ErrorCode_ERROR_CODE_UNSPECIFIED || resp.Error != "" {
+		t.Errorf("expected success without error code, got %+v", resp)
+	}
+	if resp.RecordCount != int32(len(meisaiCSVRows)) || len(resp.Records) != len(meisaiCSVRows) {
+		t.Errorf("expected %d records, got %d (%d)", len(meisaiCSVRows), resp.RecordCount, len(resp.Records))
+	}
+}
+
+func TestDownloadSync_OutputFormat(t *testing.T) {
+	tests := []struct {
+		format      pb.OutputFormat
+		wantRecords bool
+		wantCSV     bool
+	}{
+		{pb.OutputFormat_OUTPUT_FORMAT_UNSPECIFIED, true, true},
+		{pb.OutputFormat_OUTPUT_FORMAT_BOTH, true, true},
+		{pb.OutputFormat_OUTPUT_FORMAT_RECORDS, true, false},
+		{pb.OutputFormat_OUTPUT_FORMAT_CSV_PATH, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			// user2 uses another ETC card, so its records are not merged with those of user1
+			grpcSvc := newSyncService(t, &fakeScraperFactory{
+				CSV:  meisaiCSV("\n"),
+				CSVs: map[string]string{"user2": strings.ReplaceAll(meisaiCSV("\n"), "1234567890123456", "6543210987654321")},
+			})
+
+			resp, err := grpcSvc.DownloadSync(context.Background(), &pb.DownloadRequest{
+				Accounts:     []string{"user1:pass1", "user2:pass2"},
+				OutputFormat: tt.format,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !resp.Success || resp.RecordCount != int32(2*len(meisaiCSVRows)) {
+				t.Fatalf("expected %d records counted, got %+v", 2*len(meisaiCSVRows), resp)
+			}
+			if got := len(resp.Records) > 0; got != tt.wantRecords {
+				t.Errorf("expected records %t, got %d", tt.wantRecords, len(resp.Records))
+			}
+			if !tt.wantCSV {
+				if len(resp.CsvPaths) != 0 || resp.CsvPath != "" {
+					t.Errorf("expected no CSV paths, got %q %q", resp.CsvPath, resp.CsvPaths)
+				}
+				job, _ := grpcSvc.GetJobStatus(context.Background(), &pb.GetJobStatusRequest{JobId: resp.JobId})
+				if job.TotalBytes != 0 || job.FileCount != 0 {
+					t.Errorf("expected the removed CSVs not to be counted, got %d bytes in %d files", job.TotalBytes, job.FileCount)
+				}
+				return
+			}
+			if len(resp.CsvPaths) != 2 || resp.CsvPath != resp.CsvPaths[0] || !strings.HasSuffix(resp.CsvPath, "user1_meisai.csv") {
+				t.Fatalf("expected the CSV of each account, got %q %q", resp.CsvPath, resp.CsvPaths)
+			}
+			for _, path := range resp.CsvPaths {
+				if _, err := os.Stat(path); err != nil {
+					t.Errorf("expected %s to be kept: %v", path, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDownloadSync_RejectsCardNumbersWithCSVPath(t *testing.T) {
+	grpcSvc := newSyncService(t, &fakeScraperFactory{CSV: meisaiCSV("\n")})
+
+	_, err := grpcSvc.DownloadSync(context.Background(), &pb.DownloadRequest{
+		Accounts:     []string{"user1:pass1"},
+		CardNumbers:  []string{"1234"},
+		OutputFormat: pb.OutputFormat_OUTPUT_FORMAT_CSV_PATH,
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestDownloadSync_ErrorCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		factory  *fakeScraperFactory
+		accounts []string
+		want     pb.ErrorCode
+		wantMsg  string
+	}{
+		{
+			name:     "auth failed",
+			factory:  &fakeScraperFactory{CSV: meisaiCSV("\n"), LoginErrors: map[string]error{"user1": &scraper.AuthError{Reason: "invalid password"}}},
+			accounts: []string{"user1:pass1"},
+			want:     pb.ErrorCode_ERROR_CODE_AUTH_FAILED,
+			wantMsg:  "user1: ",
+		},
+		{
+			name:     "auth failure takes priority over timeout",
+			factory:  &fakeScraperFactory{CSV: meisaiCSV("\n"), DownloadTimeouts: map[string]int{"user1": 10}, LoginErrors: map[string]error{"user2": &scraper.AuthError{Reason: "locked"}}},
+			accounts: []string{"user1:pass1", "user2:pass2"},
+			want:     pb.ErrorCode_ERROR_CODE_AUTH_FAILED,
+			wantMsg:  "user2: ",
+		},
+		{
+			name:     "timeout",
+			factory:  &fakeScraperFactory{CSV: meisaiCSV("\n"), DownloadTimeouts: map[string]int{"user1": 10}},
+			accounts: []string{"user1:pass1"},
+			want:     pb.ErrorCode_ERROR_CODE_TIMEOUT,
+			wantMsg:  "download timeout",
+		},
+		{
+			name:     "parse error",
+			factory:  &fakeScraperFactory{CSV: "a,b\n1,2\n"},
+			accounts: []string{"user1:pass1"},
+			want:     pb.ErrorCode_ERROR_CODE_PARSE_ERROR,
+			wantMsg:  "user1",
+		},
+		{
+			name:     "other failure",
+			factory:  &fakeScraperFactory{ReportWrongPath: true},
+			accounts: []string{"user1:pass1"},
+			want:     pb.ErrorCode_ERROR_CODE_DOWNLOAD_FAILED,
+			wantMsg:  "file not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ETC_RECOVER_MISSING_DOWNLOAD", "false")
+			grpcSvc := newSyncService(t, tt.factory)
+
+			resp, err := grpcSvc.DownloadSync(context.Background(), &pb.DownloadRequest{Accounts: tt.accounts})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Success || resp.ErrorCode != tt.want {
+				t.Errorf("expected success=false with %v, got success=%t %v", tt.want, resp.Success, resp.ErrorCode)
+			}
+			if !strings.Contains(resp.ErrorMessage, tt.wantMsg) || resp.Error != resp.ErrorMessage {
+				t.Errorf("expected error_message (and error) to contain %q, got %q / %q", tt.wantMsg, resp.ErrorMessage, resp.Error)
+			}
+		})
+	}
+}
+
+func TestDownloadSync_NoAccounts(t *testing.T) {
+	t.Setenv("ETC_CORP_ACCOUNTS", "")
+	t.Setenv("ETC_CORPORATE_ACCOUNTS", "")
+	t.Setenv("ETC_PERSONAL_ACCOUNTS", "")
+	grpcSvc := newSyncService(t, &fakeScraperFactory{})
+
+	resp, err := grpcSvc.DownloadSync(context.Background(), &pb.DownloadRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Success || resp.ErrorCode != pb.ErrorCode_ERROR_CODE_NO_ACCOUNTS {
+		t.Errorf("expected NO_ACCOUNTS, got %+v", resp)
+	}
+}
+
+func TestDownloadSync_MaxInlineRecords(t *testing.T) {
+	grpcSvc := newSyncService(t, &fakeScraperFactory{CSV: meisaiCSV("\n")})
+	total := int32(len(meisaiCSVRows))
+
+	resp, err := grpcSvc.DownloadSync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}, MaxInlineRecords: total - 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Success || !resp.Truncated || len(resp.Records) != 0 || resp.RecordCount != total || resp.JobId == "" {
+		t.Fatalf("expected only the count and job ID, got success=%t truncated=%t records=%d count=%d job=%q",
+			resp.Success, resp.Truncated, len(resp.Records), resp.RecordCount, resp.JobId)
+	}
+	result, err := grpcSvc.GetJobResult(context.Background(), &pb.GetJobResultRequest{JobId: resp.JobId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalRecords != total || len(result.Records) != int(total) {
+		t.Errorf("expected the records to be available from GetJobResult, got %d of %d", len(result.Records), result.TotalRecords)
+	}
+
+	// at the limit the records are returned inline
+	resp, err = grpcSvc.DownloadSync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}, MaxInlineRecords: total})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Truncated || len(resp.Records) != int(total) {
+		t.Errorf("expected %d inline records, got truncated=%t records=%d", total, resp.Truncated, len(resp.Records))
+	}
+
+	if _, err := grpcSvc.DownloadSync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}, MaxInlineRecords: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a negative limit, got %v", err)
+	}
+}
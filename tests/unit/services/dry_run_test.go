@@ -0,0 +1,62 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestDownloadAsync_DryRunOnlyLogsIn(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{
+		CSV:         "header\nrow\n",
+		LoginErrors: map[string]error{"user2": &scraper.AuthError{Reason: "invalid password"}},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts: []string{"user1:pass1", "user2:pass2"},
+		DryRun:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp.Message, "Dry run job started for ") {
+		t.Errorf("unexpected message %q", resp.Message)
+	}
+
+	job := waitForJob(t, svc, resp.JobId, 5*time.Second)
+	if !job.DryRun || job.Status != "partial" {
+		t.Errorf("expected partial dry run job, got status=%s dry_run=%v", job.Status, job.DryRun)
+	}
+	if job.PerAccount["user1"] != "authenticated" || job.PerAccount["user2"] != "failed" {
+		t.Errorf("unexpected per-account statuses: %v", job.PerAccount)
+	}
+	if len(job.FailedAccounts) != 1 || !job.FailedAccounts[0].AuthError {
+		t.Errorf("expected user2 to be reported as an auth failure, got %+v", job.FailedAccounts)
+	}
+	if len(job.AccountResults) != 0 || job.TotalRecords != 0 {
+		t.Errorf("dry run must not record download results, got %+v", job.AccountResults)
+	}
+	if got := factory.downloadAttempts("user1"); got != 0 {
+		t.Errorf("dry run must not call DownloadMeisai, got %d calls", got)
+	}
+	if got := factory.loginAttempts("user1"); got != 1 {
+		t.Errorf("expected one login for user1, got %d", got)
+	}
+
+	status, err := grpcSvc.GetJobStatus(context.Background(), &pb.GetJobStatusRequest{JobId: resp.JobId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.DryRun || status.PerAccount["user1"] != "authenticated" {
+		t.Errorf("unexpected JobStatus for dry run: %+v", status)
+	}
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_RecordsFailedAccounts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{
+		CSV: "header\nrow\n",
+		LoginErrors: map[string]error{
+			"baduser": &scraper.AuthError{Reason: "ログインIDまたはパスワードが誤っています"},
+			"broken":  errors.New("could not click login button"),
+		},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "failed-job", []string{"good:pass", "baduser:secret1", "broken:secret2"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "failed-job", 5*time.Second)
+
+	if len(job.FailedAccounts) != 2 {
+		t.Fatalf("expected 2 failed accounts, got %+v", job.FailedAccounts)
+	}
+	failed := map[string]services.FailedAccount{}
+	for _, f := range job.FailedAccounts {
+		failed[f.AccountID] = f
+	}
+	if f := failed["baduser"]; !f.AuthError || !strings.Contains(f.Reason, "パスワードが誤っています") {
+		t.Errorf("expected baduser to be an auth failure, got %+v", f)
+	}
+	if f := failed["broken"]; f.AuthError || f.Reason == "" {
+		t.Errorf("expected broken to be a non-auth failure, got %+v", f)
+	}
+	for _, secret := range []string{"secret1", "secret2"} {
+		if logs.contains(secret) {
+			t.Errorf("password %q leaked into logs: %v", secret, logs.lines)
+		}
+		for _, f := range job.FailedAccounts {
+			if strings.Contains(f.Reason, secret) {
+				t.Errorf("password %q leaked into failure reason: %q", secret, f.Reason)
+			}
+		}
+	}
+
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+	status, err := grpcSvc.GetJobStatus(context.Background(), &pb.GetJobStatusRequest{JobId: "failed-job"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authFailures := 0
+	for _, f := range status.FailedAccounts {
+		if f.AuthError {
+			authFailures++
+			if f.AccountId != "baduser" {
+				t.Errorf("unexpected auth failure for %s", f.AccountId)
+			}
+		}
+	}
+	if len(status.FailedAccounts) != 2 || authFailures != 1 {
+		t.Errorf("unexpected FailedAccounts in JobStatus: %v", status.FailedAccounts)
+	}
+}
+
+func TestAuthError_IsDetectableThroughWrapping(t *testing.T) {
+	err := fmt.Errorf("login failed for account user1: %w", &scraper.AuthError{Reason: "bad password"})
+	if !scraper.IsAuthError(err) || !errors.Is(err, scraper.ErrAuthentication) {
+		t.Error("expected wrapped AuthError to be detected")
+	}
+	var authErr *scraper.AuthError
+	if !errors.As(err, &authErr) || authErr.Reason != "bad password" {
+		t.Errorf("expected errors.As to extract the reason, got %+v", authErr)
+	}
+	if scraper.IsTransientError(err) {
+		t.Error("auth errors must never be transient")
+	}
+}
+
+func TestGetJobStatus_ReportsTimedOutAccounts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: meisaiCSV("\n"), DownloadTimeouts: map[string]int{"user1": 10}, LoginErrors: map[string]error{"user2": &scraper.AuthError{Reason: "locked"}}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.RetryBaseDelay = time.Millisecond
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsync(context.Background(), "timeout-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "timeout-job", 5*time.Second)
+
+	status, err := grpcSvc.GetJobStatus(context.Background(), &pb.GetJobStatusRequest{JobId: "timeout-job"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeouts := map[string]bool{}
+	for _, f := range status.FailedAccounts {
+		timeouts[f.AccountId] = f.Timeout
+	}
+	if len(timeouts) != 2 || !timeouts["user1"] || timeouts["user2"] {
+		t.Errorf("expected only user1 to be reported as timed out, got %v", timeouts)
+	}
+}
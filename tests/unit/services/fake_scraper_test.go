@@ -0,0 +1,264 @@
+package services_test
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+// fakeScraperFactory creates fakeScrapers and records how they were used
+type fakeScraperFactory struct {
+	mu          sync.Mutex
+	Delay       time.Duration
+	LoginErrors map[string]error
+	// Delays adds a per-user delay to DownloadMeisai on top of Delay
+	Delays map[string]time.Duration
+	// InitErrors are returned by successive Initialize calls for a user
+	InitErrors map[string][]error
+	initCalls  map[string]int
+	// CSV is written to <session>/<user>_meisai.csv when non-empty
+	CSV string
+	// CSVs overrides CSV for a user
+	CSVs map[string]string
+	// ReportWrongPath makes DownloadMeisai return a path that does not exist
+	ReportWrongPath bool
+	// LoginTimeouts and DownloadTimeouts make the first N calls for a user fail with a timeout
+	LoginTimeouts    map[string]int
+	DownloadTimeouts map[string]int
+	// LoginMaintenance makes the first N logins for a user find the site under maintenance
+	LoginMaintenance map[string]int
+	loginCalls       map[string]int
+	downloadCalls    map[string]int
+	closeCalls       map[string]int
+	// Gate blocks DownloadMeisai until it is closed when non-nil
+	Gate chan struct{}
+	// Pages makes DownloadMeisai report progress over N pages for a user;
+	// the first page is reported before waiting on Gate
+	Pages map[string]int
+	// Expected maps a user ID to the record count the site displays; absent means unknown
+	Expected map[string]int
+	// Searched maps a user ID to the date range the site reports having searched;
+	// absent means unknown
+	Searched  map[string][2]time.Time
+	configs   []*scraper.ScraperConfig
+	ranges    [][2]string
+	active    int
+	maxActive int
+}
+
+func (f *fakeScraperFactory) CreateScraper(config *scraper.ScraperConfig, logger *log.Logger) (scraper.ScraperInterface, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.configs = append(f.configs, config)
+	return &fakeScraper{factory: f, config: config, closed: make(chan struct{})}, nil
+}
+
+func (f *fakeScraperFactory) begin() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.active++
+	if f.active > f.maxActive {
+		f.maxActive = f.active
+	}
+}
+
+func (f *fakeScraperFactory) end() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.active--
+}
+
+func (f *fakeScraperFactory) recordRange(fromDate, toDate string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ranges = append(f.ranges, [2]string{fromDate, toDate})
+}
+
+// countCall increments and returns the call count for a user
+func (f *fakeScraperFactory) countCall(calls *map[string]int, userID string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if *calls == nil {
+		*calls = make(map[string]int)
+	}
+	(*calls)[userID]++
+	return (*calls)[userID]
+}
+
+// loginAttempts returns how many times Login was called for a user
+func (f *fakeScraperFactory) loginAttempts(userID string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.loginCalls[userID]
+}
+
+// initAttempts returns how many times Initialize was called for a user
+func (f *fakeScraperFactory) initAttempts(userID string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.initCalls[userID]
+}
+
+// downloadAttempts returns how many times DownloadMeisai was called for a user
+func (f *fakeScraperFactory) downloadAttempts(userID string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.downloadCalls[userID]
+}
+
+// closeAttempts returns how many times Close was called for a user
+func (f *fakeScraperFactory) closeAttempts(userID string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closeCalls[userID]
+}
+
+// fakeScraper is a ScraperInterface implementation driven by its factory
+type fakeScraper struct {
+	factory   *fakeScraperFactory
+	config    *scraper.ScraperConfig
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (s *fakeScraper) Initialize() error {
+	call := s.factory.countCall(&s.factory.initCalls, s.config.UserID)
+	if errs := s.factory.InitErrors[s.config.UserID]; call <= len(errs) {
+		return errs[call-1]
+	}
+	return nil
+}
+
+func (s *fakeScraper) Login() error {
+	call := s.factory.countCall(&s.factory.loginCalls, s.config.UserID)
+	if call <= s.factory.LoginTimeouts[s.config.UserID] {
+		return errors.New("failed to navigate to top page: timeout 30000ms exceeded")
+	}
+	if call <= s.factory.LoginMaintenance[s.config.UserID] {
+		return &scraper.MaintenanceError{Message: "システムメンテナンス"}
+	}
+	if err, ok := s.factory.LoginErrors[s.config.UserID]; ok {
+		return err
+	}
+	return nil
+}
+
+func (s *fakeScraper) IsLoggedIn() (bool, error) {
+	return false, nil
+}
+
+func (s *fakeScraper) DownloadMeisai(fromDate, toDate string) (scraper.DownloadResult, error) {
+	s.factory.begin()
+	defer s.factory.end()
+	s.factory.recordRange(fromDate, toDate)
+	if s.factory.countCall(&s.factory.downloadCalls, s.config.UserID) <= s.factory.DownloadTimeouts[s.config.UserID] {
+		return scraper.DownloadResult{}, errors.New("download timeout after 60 seconds")
+	}
+	pages := s.factory.Pages[s.config.UserID]
+	if pages > 0 && s.config.OnProgress != nil {
+		s.config.OnProgress(scraper.DownloadProgress{Page: 1, Pages: pages, Rows: 10})
+	}
+	if s.factory.Gate != nil {
+		// like a real browser, closing the scraper fails the pending download
+		select {
+		case <-s.factory.Gate:
+		case <-s.closed:
+			return scraper.DownloadResult{}, errors.New("target page, context or browser has been closed")
+		}
+	}
+	time.Sleep(s.factory.Delay + s.factory.Delays[s.config.UserID])
+	for page := 2; page <= pages && s.config.OnProgress != nil; page++ {
+		s.config.OnProgress(scraper.DownloadProgress{Page: page, Pages: pages, Rows: 10 * page})
+	}
+
+	path := filepath.Join(s.config.SessionFolder, s.config.UserID+"_meisai.csv")
+	content, ok := s.factory.CSVs[s.config.UserID]
+	if !ok {
+		content = s.factory.CSV
+	}
+	if content != "" {
+		if err := os.MkdirAll(s.config.SessionFolder, 0755); err != nil {
+			return scraper.DownloadResult{}, err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return scraper.DownloadResult{}, err
+		}
+	}
+	if s.factory.ReportWrongPath {
+		path = filepath.Join(s.config.SessionFolder, "elsewhere.csv")
+	}
+	searched := s.factory.Searched[s.config.UserID]
+	return scraper.DownloadResult{
+		Path:             path,
+		Bytes:            int64(len(content)),
+		Pages:            max(pages, 1),
+		RowCountEstimate: -1,
+		ActualFrom:       searched[0],
+		ActualTo:         searched[1],
+	}, nil
+}
+
+func (s *fakeScraper) DownloadCertificates(fromDate, toDate string) ([]string, error) {
+	return nil, nil
+}
+
+func (s *fakeScraper) ExpectedRecordCount() (int, bool) {
+	count, ok := s.factory.Expected[s.config.UserID]
+	return count, ok
+}
+
+func (s *fakeScraper) Close() error {
+	s.factory.countCall(&s.factory.closeCalls, s.config.UserID)
+	s.closeOnce.Do(func() { close(s.closed) })
+	return nil
+}
+
+// logRecorder collects messages sent to the service log callback
+type logRecorder struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func recordLogs(svc *services.DownloadService) *logRecorder {
+	rec := &logRecorder{}
+	svc.SetLogCallback(func(msg string) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.lines = append(rec.lines, msg)
+	})
+	return rec
+}
+
+// contains reports whether any recorded line contains substr
+func (r *logRecorder) contains(substr string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range r.lines {
+		if strings.Contains(line, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForJob polls until the job reaches a terminal state or the timeout expires
+func waitForJob(t *testing.T, svc *services.DownloadService, jobID string, timeout time.Duration) *services.DownloadJob {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if job, ok := svc.GetJobStatus(jobID); ok && job.CompletedAt != nil {
+			return job
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish within %v", jobID, timeout)
+	return nil
+}
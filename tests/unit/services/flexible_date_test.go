@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDownloadAsync_NormalizesDateFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		fromDate string
+		toDate   string
+	}{
+		{"hyphen", "2024-01-15", "2024-02-14"},
+		{"slash", "2024/01/15", "2024/02/14"},
+		{"compact", "20240115", "20240214"},
+		{"mixed", "2024/01/15", "20240214"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+			svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+			grpcSvc := services.NewDownloadServiceGRPCWithMock(svc)
+
+			resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+				Accounts: []string{"user1:pass1"},
+				FromDate: tt.fromDate,
+				ToDate:   tt.toDate,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			waitForJob(t, svc, resp.JobId, 5*time.Second)
+
+			factory.mu.Lock()
+			defer factory.mu.Unlock()
+			if len(factory.ranges) != 1 {
+				t.Fatalf("expected one download, got %d", len(factory.ranges))
+			}
+			if got := factory.ranges[0]; got != [2]string{"2024-01-15", "2024-02-14"} {
+				t.Errorf("expected canonical dates, got %v", got)
+			}
+		})
+	}
+}
+
+func TestDownloadAsync_RejectsUnparseableDates(t *testing.T) {
+	factory := &fakeScraperFactory{}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	grpcSvc := services.NewDownloadServiceGRPCWithMock(svc)
+
+	for _, req := range []*pb.DownloadRequest{
+		{Accounts: []string{"user1:pass1"}, FromDate: "15.01.2024"},
+		{Accounts: []string{"user1:pass1"}, ToDate: "2024-13-01"},
+	} {
+		_, err := grpcSvc.DownloadAsync(context.Background(), req)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument for %+v, got %v", req, err)
+		}
+	}
+	if factory.createdScrapers() != 0 {
+		t.Errorf("no job should have started")
+	}
+}
+
+func TestDownload_ReportsEffectiveDateRange(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts: []string{"user1:pass1"},
+		FromDate: "2024/01/15",
+		ToDate:   "20240214",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, resp.JobId, 5*time.Second)
+	if resp.Message != "Download job started for 2024-01-15 to 2024-02-14" {
+		t.Errorf("unexpected message %q", resp.Message)
+	}
+	if resp.FromDate != "2024-01-15" || resp.ToDate != "2024-02-14" {
+		t.Errorf("expected the normalized range, got %s to %s", resp.FromDate, resp.ToDate)
+	}
+
+	now := time.Now()
+	wantFrom, wantTo := now.AddDate(0, -1, 0).Format("2006-01-02"), now.Format("2006-01-02")
+	resp, err = grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, resp.JobId, 5*time.Second)
+	if want := "Download job started for " + wantFrom + " to " + wantTo + " (default from_date, to_date)"; resp.Message != want {
+		t.Errorf("expected %q, got %q", want, resp.Message)
+	}
+
+	sync, err := grpcSvc.DownloadSync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}, ToDate: "2024-02-14"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sync.FromDate != wantFrom || sync.ToDate != "2024-02-14" {
+		t.Errorf("expected %s to 2024-02-14, got %s to %s", wantFrom, sync.FromDate, sync.ToDate)
+	}
+}
@@ -0,0 +1,46 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDownloadAsync_HeadlessPrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		headless *bool
+		want     bool
+	}{
+		{"default", "", nil, true},
+		{"env", "false", nil, false},
+		{"request overrides env", "true", proto.Bool(false), false},
+		{"request overrides env (visible to headless)", "false", proto.Bool(true), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ETC_HEADLESS", tt.env)
+			factory := &fakeScraperFactory{CSV: threeRowCSV}
+			svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+			grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+			resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+				Accounts: []string{"user1:pass1"},
+				Headless: tt.headless,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			waitForJob(t, svc, resp.JobId, 5*time.Second)
+
+			if got := factory.configs[0].Headless; got != tt.want {
+				t.Errorf("expected headless=%v, got %v", tt.want, got)
+			}
+		})
+	}
+}
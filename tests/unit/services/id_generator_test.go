@@ -0,0 +1,49 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestDownloadAsync_UsesIDGenerator(t *testing.T) {
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+	next := 0
+	grpcSvc.IDGenerator = func() string {
+		next++
+		return fmt.Sprintf("tenant-a-%d", next)
+	}
+
+	for _, want := range []string{"tenant-a-1", "tenant-a-2"} {
+		resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.JobId != want {
+			t.Errorf("expected job ID %s, got %s", want, resp.JobId)
+		}
+		waitForJob(t, svc, want, 5*time.Second)
+	}
+}
+
+func TestDownloadAsync_DefaultIDIsUUID(t *testing.T) {
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uuid.Parse(resp.JobId); err != nil {
+		t.Errorf("expected a UUID job ID, got %q", resp.JobId)
+	}
+	waitForJob(t, svc, resp.JobId, 5*time.Second)
+}
@@ -0,0 +1,126 @@
+package services_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestDownloadAsync_IdempotencyKeyReturnsExistingJob(t *testing.T) {
+	t.Chdir(t.TempDir())
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	req := &pb.DownloadRequest{Accounts: []string{"user1:pass1"}, IdempotencyKey: "retry-key"}
+	ids := make([]string, 4)
+	var wg sync.WaitGroup
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := grpcSvc.DownloadAsync(context.Background(), req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ids[i] = resp.JobId
+		}()
+	}
+	wg.Wait()
+	for _, id := range ids[1:] {
+		if id != ids[0] {
+			t.Fatalf("expected one job for the key, got %v", ids)
+		}
+	}
+
+	close(gate)
+	waitForJob(t, svc, ids[0], 5*time.Second)
+	resp, err := grpcSvc.DownloadAsync(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.JobId != ids[0] || resp.Status != "completed" {
+		t.Errorf("expected the recently completed job, got %s (%s)", resp.JobId, resp.Status)
+	}
+	if n := factory.downloadAttempts("user1"); n != 1 {
+		t.Errorf("expected a single download, got %d", n)
+	}
+}
+
+func TestDownloadAsync_IdempotencyKeyReturnsDatesOfExistingJob(t *testing.T) {
+	t.Chdir(t.TempDir())
+	gate := make(chan struct{})
+	defer close(gate)
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV, Gate: gate})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	first, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}, IdempotencyKey: "retry-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the retry asks for other dates, but the existing job keeps the dates it was started with
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts: []string{"user1:pass1"}, IdempotencyKey: "retry-key", FromDate: "2024-01-01", ToDate: "2024-01-31",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.JobId != first.JobId || resp.FromDate != first.FromDate || resp.ToDate != first.ToDate {
+		t.Errorf("expected job %s for %s to %s, got %s for %s to %s",
+			first.JobId, first.FromDate, first.ToDate, resp.JobId, resp.FromDate, resp.ToDate)
+	}
+	want := "Existing job returned for idempotency key for " + first.FromDate + " to " + first.ToDate + " (default from_date)"
+	if resp.Message != want {
+		t.Errorf("expected message %q, got %q", want, resp.Message)
+	}
+}
+
+func TestDownloadAsync_IdempotencyKeyExpiresWithJob(t *testing.T) {
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	svc.JobTTL = time.Millisecond
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	req := &pb.DownloadRequest{Accounts: []string{"user1:pass1"}, IdempotencyKey: "retry-key"}
+	first, err := grpcSvc.DownloadAsync(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, first.JobId, 5*time.Second)
+	time.Sleep(10 * time.Millisecond)
+
+	second, err := grpcSvc.DownloadAsync(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.JobId == first.JobId {
+		t.Error("expected a new job once the previous one is past the job TTL")
+	}
+	waitForJob(t, svc, second.JobId, 5*time.Second)
+}
+
+func TestDownloadAsync_DifferentKeysStartSeparateJobs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	a, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}, IdempotencyKey: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}, IdempotencyKey: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.JobId == b.JobId {
+		t.Error("expected distinct jobs for distinct keys")
+	}
+	waitForJob(t, svc, a.JobId, 5*time.Second)
+	waitForJob(t, svc, b.JobId, 5*time.Second)
+}
@@ -0,0 +1,139 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestIncremental_StartsFromLastDownloadDate(t *testing.T) {
+	t.Setenv("ETC_INCREMENTAL", "true")
+	t.Setenv("ETC_INCREMENTAL_WITHOUT_SAVE", "true")
+	db := openJobsDB(t)
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(db, nil, factory)
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	if _, ok := svc.GetLastDownloadDate("user1"); ok {
+		t.Fatal("expected no watermark before the first download")
+	}
+
+	// 初回は開始日を指定して取得し、終了日が記録される
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts: []string{"user1:pass1"},
+		FromDate: "2024-01-01",
+		ToDate:   "2024-01-31",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, resp.JobId, 5*time.Second)
+
+	last, ok := svc.GetLastDownloadDate("user1")
+	if !ok || last.Format("2006-01-02") != "2024-01-31" {
+		t.Fatalf("expected watermark 2024-01-31, got %v (ok=%v)", last, ok)
+	}
+
+	// 開始日を省略すると前回の終了日から、記録のないアカウントは従来どおり1か月前から取得する
+	resp, err = grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts: []string{"user1:pass1", "user2:pass2"},
+		ToDate:   "2024-02-29",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, resp.JobId, 5*time.Second)
+
+	// MaxConcurrency=1のためアカウントは指定順に処理される
+	if len(factory.ranges) != 3 {
+		t.Fatalf("expected 3 downloads, got %d", len(factory.ranges))
+	}
+	from := map[string]string{"user1": factory.ranges[1][0], "user2": factory.ranges[2][0]}
+	if from["user1"] != "2024-01-31" {
+		t.Errorf("expected user1 to start from its watermark, got %q", from["user1"])
+	}
+	wantDefault := time.Now().AddDate(0, -1, 0).Format("2006-01-02")
+	if from["user2"] != wantDefault {
+		t.Errorf("expected user2 to start one month ago (%s), got %q", wantDefault, from["user2"])
+	}
+	if last, _ := svc.GetLastDownloadDate("user1"); last.Format("2006-01-02") != "2024-02-29" {
+		t.Errorf("expected watermark to advance to 2024-02-29, got %v", last)
+	}
+}
+
+func TestIncremental_ParseFailureKeepsWatermark(t *testing.T) {
+	t.Setenv("ETC_INCREMENTAL", "true")
+	t.Setenv("ETC_INCREMENTAL_WITHOUT_SAVE", "true")
+	db := openJobsDB(t)
+	svc := services.NewDownloadServiceWithFactory(db, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	svc.AccountDelay = 0
+	svc.ProcessAsync(context.Background(), "parsed-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "parsed-job", 5*time.Second)
+
+	// 解析できないCSVの場合、明細を取得し直せるよう前回の終了日のまま
+	broken := services.NewDownloadServiceWithFactory(db, nil, &fakeScraperFactory{CSV: "header\nrow\n"})
+	broken.AccountDelay = 0
+	broken.ProcessAsync(context.Background(), "broken-job", []string{"user1:pass1"}, "2024-02-01", "2024-02-29")
+	job := waitForJob(t, broken, "broken-job", 5*time.Second)
+	if len(job.AccountResults) != 1 || !job.AccountResults[0].ParseFailed {
+		t.Fatalf("expected the CSV to fail to parse, got %+v", job.AccountResults)
+	}
+
+	if last, ok := svc.GetLastDownloadDate("user1"); !ok || last.Format("2006-01-02") != "2024-01-31" {
+		t.Errorf("expected watermark to stay at 2024-01-31, got %v (ok=%v)", last, ok)
+	}
+}
+
+func TestIncremental_WatermarkAfterToDateUsesDefaultFrom(t *testing.T) {
+	t.Setenv("ETC_INCREMENTAL", "true")
+	t.Setenv("ETC_INCREMENTAL_WITHOUT_SAVE", "true")
+	db := openJobsDB(t)
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(db, nil, factory)
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsync(context.Background(), "february-job", []string{"user1:pass1"}, "2024-02-01", "2024-02-29")
+	waitForJob(t, svc, "february-job", 5*time.Second)
+
+	// 1月を取得し直す場合、前回の終了日（2024-02-29）からでは期間が逆転するため既定の開始日を使う
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts: []string{"user1:pass1"},
+		ToDate:   "2024-01-31",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, resp.JobId, 5*time.Second)
+
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+	if len(factory.ranges) != 2 {
+		t.Fatalf("expected 2 downloads, got %d", len(factory.ranges))
+	}
+	if want := resp.FromDate; factory.ranges[1][0] != want {
+		t.Errorf("expected the default from_date %s instead of the watermark, got %q", want, factory.ranges[1][0])
+	}
+}
+
+func TestIncremental_RequiresOptInWithoutSave(t *testing.T) {
+	t.Setenv("ETC_INCREMENTAL", "true")
+	db := openJobsDB(t)
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(db, nil, factory)
+	svc.AccountDelay = 0
+	if svc.Incremental {
+		t.Fatal("expected ETC_INCREMENTAL to be ignored without ETC_INCREMENTAL_WITHOUT_SAVE")
+	}
+
+	// 明細を保存しないため、許可していない場合は前回の終了日を記録しない
+	svc.ProcessAsync(context.Background(), "january-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "january-job", 5*time.Second)
+	if last, ok := svc.GetLastDownloadDate("user1"); ok {
+		t.Errorf("expected no watermark without the opt-in, got %v", last)
+	}
+}
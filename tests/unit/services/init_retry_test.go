@@ -0,0 +1,83 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func notReady() error {
+	return fmt.Errorf("could not start playwright: %w: driver exited", scraper.ErrDriverNotReady)
+}
+
+func TestInitialize_RetriesWhileDriverNotReady(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: threeRowCSV, InitErrors: map[string][]error{"user1": {notReady(), notReady()}}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.InitRetryDelay = time.Millisecond
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "job-1", 5*time.Second)
+
+	if job.Status != "completed" || factory.initAttempts("user1") != 3 {
+		t.Errorf("expected success on the third attempt, got %s after %d attempts", job.Status, factory.initAttempts("user1"))
+	}
+	if !logs.contains("Initialize attempt 2/3 failed for account user1") {
+		t.Error("expected the retries to be logged")
+	}
+}
+
+func TestInitialize_DoesNotRetryMissingDriver(t *testing.T) {
+	t.Chdir(t.TempDir())
+	notInstalled := fmt.Errorf("could not start playwright: %w: please install the driver (v1.52.0) first", scraper.ErrDriverNotInstalled)
+	factory := &fakeScraperFactory{CSV: threeRowCSV, InitErrors: map[string][]error{"user1": {notInstalled}}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.InitRetryDelay = time.Millisecond
+
+	svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "job-1", 5*time.Second)
+
+	if factory.initAttempts("user1") != 1 {
+		t.Errorf("expected a single attempt, got %d", factory.initAttempts("user1"))
+	}
+	if len(job.FailedAccounts) != 1 || !strings.Contains(job.FailedAccounts[0].Reason, "playwright driver not installed") {
+		t.Errorf("expected the missing driver as the failure reason, got %+v", job.FailedAccounts)
+	}
+}
+
+func TestInitialize_GivesUpAfterInitRetryCount(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: threeRowCSV, InitErrors: map[string][]error{"user1": {notReady(), notReady(), notReady()}}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.InitRetryCount = 1
+	svc.InitRetryDelay = time.Millisecond
+
+	svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "job-1", 5*time.Second)
+
+	if factory.initAttempts("user1") != 2 || job.Status != "failed" {
+		t.Errorf("expected the job to fail after 2 attempts, got %s after %d", job.Status, factory.initAttempts("user1"))
+	}
+}
+
+func TestGetInitRetryConfig(t *testing.T) {
+	t.Setenv("ETC_INIT_RETRY_COUNT", "5")
+	t.Setenv("ETC_INIT_RETRY_DELAY_MS", "250")
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if svc.InitRetryCount != 5 || svc.InitRetryDelay != 250*time.Millisecond {
+		t.Errorf("expected 5 retries every 250ms, got %d every %v", svc.InitRetryCount, svc.InitRetryDelay)
+	}
+
+	t.Setenv("ETC_INIT_RETRY_COUNT", "-1")
+	t.Setenv("ETC_INIT_RETRY_DELAY_MS", "soon")
+	svc = services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if svc.InitRetryCount != 2 || svc.InitRetryDelay != time.Second {
+		t.Errorf("expected defaults for invalid values, got %d every %v", svc.InitRetryCount, svc.InitRetryDelay)
+	}
+}
@@ -0,0 +1,126 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCallbackClient fails the first Failures requests and records every request body
+type fakeCallbackClient struct {
+	mu       sync.Mutex
+	Failures int
+	urls     []string
+	bodies   [][]byte
+}
+
+func (c *fakeCallbackClient) Do(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.urls = append(c.urls, req.URL.String())
+	c.bodies = append(c.bodies, body)
+	if len(c.bodies) <= c.Failures {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{StatusCode: http.StatusNoContent, Status: "204 No Content", Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func (c *fakeCallbackClient) requests() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.bodies)
+}
+
+func TestDownloadAsync_CallbackOnCompletion(t *testing.T) {
+	factory := &fakeScraperFactory{CSV: threeRowCSV, LoginErrors: map[string]error{"user2": errors.New("invalid password")}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.RetryBaseDelay = 0
+	svc.CallbackRetryDelay = 0
+	client := &fakeCallbackClient{Failures: 1}
+	svc.CallbackClient = client
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts:    []string{"user1:pass1", "user2:pass2"},
+		CallbackUrl: "https://example.com/hooks/etc?token=secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := waitForJob(t, svc, resp.JobId, 5*time.Second)
+	waitFor(t, func() bool { return client.requests() == 2 })
+
+	var payload services.JobCallbackPayload
+	if err := json.Unmarshal(client.bodies[1], &payload); err != nil {
+		t.Fatalf("invalid callback body %s: %v", client.bodies[1], err)
+	}
+	if payload.JobID != resp.JobId || payload.Status != "partial" || payload.RecordCount != 3 {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+	if len(payload.FailedAccounts) != 1 || payload.FailedAccounts[0].AccountID != "user2" {
+		t.Errorf("expected user2 to be reported as failed, got %+v", payload.FailedAccounts)
+	}
+	if client.urls[1] != "https://example.com/hooks/etc?token=secret" {
+		t.Errorf("unexpected callback URL %s", client.urls[1])
+	}
+	if job.Status != "partial" {
+		t.Errorf("expected callback delivery not to change job status, got %s", job.Status)
+	}
+}
+
+func TestDownloadAsync_CallbackFailureKeepsJobStatus(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	svc.CallbackRetryDelay = 0
+	client := &fakeCallbackClient{Failures: 10}
+	svc.CallbackClient = client
+	logs := recordLogs(svc)
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts:    []string{"user1:pass1"},
+		CallbackUrl: "http://user:pw@example.com/hook?token=secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, resp.JobId, 5*time.Second)
+	waitFor(t, func() bool { return logs.contains("Giving up on callback") })
+
+	if client.requests() != 3 {
+		t.Errorf("expected 3 delivery attempts, got %d", client.requests())
+	}
+	if job, _ := svc.GetJobStatus(resp.JobId); job.Status != "completed" {
+		t.Errorf("expected job to stay completed, got %s", job.Status)
+	}
+	if logs.contains("secret") || logs.contains("pw@") {
+		t.Error("expected callback credentials and query to be omitted from logs")
+	}
+}
+
+func TestDownloadAsync_InvalidCallbackURL(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	for _, u := range []string{"ftp://example.com/hook", "/relative/hook", "http://"} {
+		_, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+			Accounts:    []string{"user1:pass1"},
+			CallbackUrl: u,
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument for %q, got %v", u, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeExportStream struct {
+	grpc.ServerStream
+	data   bytes.Buffer
+	chunks int
+}
+
+func (f *fakeExportStream) Context() context.Context { return context.Background() }
+
+func (f *fakeExportStream) Send(chunk *pb.ExportJobCSVChunk) error {
+	f.chunks++
+	f.data.Write(chunk.Data)
+	return nil
+}
+
+func TestExportJobCSV_MergesAccounts(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV, CSVs: map[string]string{"user2": otherThreeRowCSV}})
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsync(context.Background(), "export-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "export-job", 5*time.Second)
+
+	stream := &fakeExportStream{}
+	if err := grpcSvc.ExportJobCSV(&pb.ExportJobCSVRequest{JobId: "export-job"}, stream); err != nil {
+		t.Fatalf("ExportJobCSV failed: %v", err)
+	}
+
+	rows, err := csv.NewReader(&stream.data).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid CSV: %v", err)
+	}
+	if len(rows) != 7 {
+		t.Fatalf("expected header and 6 rows, got %d rows", len(rows))
+	}
+	if rows[0][0] != "account_id" {
+		t.Errorf("expected header row first, got %v", rows[0])
+	}
+	perAccount := map[string]int{}
+	for _, row := range rows[1:] {
+		perAccount[row[0]]++
+		if row[1] == "" || row[6] == "" || row[6] == "0" {
+			t.Errorf("expected usage date and amount in row %v", row)
+		}
+	}
+	if perAccount["user1"] != 3 || perAccount["user2"] != 3 {
+		t.Errorf("expected 3 rows per account, got %v", perAccount)
+	}
+}
+
+func TestExportJobCSV_EmptyResultWritesHeader(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	svc.ProcessAsyncWithOptions(context.Background(), "dry-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31", services.JobOptions{DryRun: true})
+	waitForJob(t, svc, "dry-job", 5*time.Second)
+
+	var buf bytes.Buffer
+	if err := svc.ExportJobCSV("dry-job", &buf); err != nil {
+		t.Fatalf("ExportJobCSV failed: %v", err)
+	}
+	want := "account_id,usage_date,entry_ic,exit_ic,vehicle_number,etc_card_number,amount,csv_file_name\n"
+	if buf.String() != want {
+		t.Errorf("expected only the header, got %q", buf.String())
+	}
+}
+
+func TestExportJobCSV_Errors(t *testing.T) {
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	stream := &fakeExportStream{}
+	err := grpcSvc.ExportJobCSV(&pb.ExportJobCSVRequest{JobId: "missing"}, stream)
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+
+	svc.ProcessAsync(context.Background(), "running-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+	err = grpcSvc.ExportJobCSV(&pb.ExportJobCSVRequest{JobId: "running-job"}, stream)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition, got %v", err)
+	}
+	if stream.chunks != 0 {
+		t.Errorf("expected nothing to be sent on error, got %d chunks", stream.chunks)
+	}
+
+	close(gate)
+	waitForJob(t, svc, "running-job", 5*time.Second)
+}
@@ -0,0 +1,149 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services/scrapertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeFileStream struct {
+	grpc.ServerStream
+	data   bytes.Buffer
+	chunks int
+}
+
+func (f *fakeFileStream) Context() context.Context { return context.Background() }
+
+func (f *fakeFileStream) Send(chunk *pb.DownloadFileChunk) error {
+	f.chunks++
+	f.data.Write(chunk.Data)
+	return nil
+}
+
+func TestGetJobFiles_ListsAndDownloadsSessionFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &scrapertest.FakeScraperFactory{Behavior: scrapertest.Behavior{CSV: threeRowCSV}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsyncWithOptions(context.Background(), "files-job", []string{"user1:pass1", "user1_sub:pass2"}, "2024-01-01", "2024-01-31",
+		services.JobOptions{DownloadCertificates: true})
+	job := waitForJob(t, svc, "files-job", 5*time.Second)
+
+	resp, err := grpcSvc.GetJobFiles(context.Background(), &pb.GetJobFilesRequest{JobId: "files-job"})
+	if err != nil {
+		t.Fatalf("GetJobFiles failed: %v", err)
+	}
+	owners := map[string]string{}
+	for _, f := range resp.Files {
+		owners[f.Name] = f.AccountId
+		if f.Name == "user1_meisai.csv" && f.Size != int64(len(threeRowCSV)) {
+			t.Errorf("expected the size of the CSV, got %d", f.Size)
+		}
+	}
+	want := map[string]string{
+		"user1_meisai.csv":          "user1",
+		"user1_certificate.pdf":     "user1",
+		"user1_sub_meisai.csv":      "user1_sub",
+		"user1_sub_certificate.pdf": "user1_sub",
+	}
+	if len(owners) != len(want) {
+		t.Fatalf("expected %d files, got %v", len(want), owners)
+	}
+	for name, account := range want {
+		if owners[name] != account {
+			t.Errorf("expected %s to belong to %s, got %q", name, account, owners[name])
+		}
+	}
+
+	// a file left by an account without a recorded path is matched by its prefix
+	folder := filepath.Dir(job.AccountResults[0].CSVPath)
+	if err := os.WriteFile(filepath.Join(folder, "user1_sub_partial.csv"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files, err := svc.GetJobFiles("files-job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if f.Name == "user1_sub_partial.csv" && f.AccountID != "user1_sub" {
+			t.Errorf("expected the longest matching account, got %q", f.AccountID)
+		}
+	}
+
+	stream := &fakeFileStream{}
+	if err := grpcSvc.DownloadFile(&pb.DownloadFileRequest{JobId: "files-job", Name: "user1_meisai.csv"}, stream); err != nil {
+		t.Fatalf("DownloadFile failed: %v", err)
+	}
+	if stream.data.String() != threeRowCSV {
+		t.Errorf("expected the CSV content, got %q", stream.data.String())
+	}
+}
+
+func TestDownloadFile_Errors(t *testing.T) {
+	t.Chdir(t.TempDir())
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsync(context.Background(), "files-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+	if _, err := grpcSvc.GetJobFiles(context.Background(), &pb.GetJobFilesRequest{JobId: "files-job"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition while the job is running, got %v", err)
+	}
+	close(gate)
+	job := waitForJob(t, svc, "files-job", 5*time.Second)
+
+	// a file next to the session folder must not be reachable
+	folder := filepath.Dir(job.AccountResults[0].CSVPath)
+	if err := os.WriteFile(filepath.Join(folder, "..", "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(folder, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		jobID, name string
+		want        codes.Code
+	}{
+		{"missing", "user1_meisai.csv", codes.NotFound},
+		{"files-job", "../secret.txt", codes.InvalidArgument},
+		{"files-job", "sub/../../secret.txt", codes.InvalidArgument},
+		{"files-job", `..\secret.txt`, codes.InvalidArgument},
+		{"files-job", "..", codes.InvalidArgument},
+		{"files-job", "", codes.InvalidArgument},
+		{"files-job", "sub", codes.NotFound},
+		{"files-job", "other.csv", codes.NotFound},
+	}
+	for _, tt := range tests {
+		stream := &fakeFileStream{}
+		err := grpcSvc.DownloadFile(&pb.DownloadFileRequest{JobId: tt.jobID, Name: tt.name}, stream)
+		if status.Code(err) != tt.want {
+			t.Errorf("%s %q: expected %v, got %v", tt.jobID, tt.name, tt.want, err)
+		}
+		if stream.chunks != 0 {
+			t.Errorf("%s %q: expected nothing to be sent on error, got %d chunks", tt.jobID, tt.name, stream.chunks)
+		}
+	}
+
+	if err := os.RemoveAll(folder); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := grpcSvc.GetJobFiles(context.Background(), &pb.GetJobFilesRequest{JobId: "files-job"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound after the session folder was removed, got %v", err)
+	}
+}
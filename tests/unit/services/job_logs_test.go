@@ -0,0 +1,81 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetJobLogs_SeparatesConcurrentJobs(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+	svc.ProcessAsync(context.Background(), "job-a", []string{"user-a:pass"}, "2024-01-01", "2024-01-31")
+	svc.ProcessAsync(context.Background(), "job-b", []string{"user-b:pass"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-a", 5*time.Second)
+	waitForJob(t, svc, "job-b", 5*time.Second)
+
+	for jobID, other := range map[string]string{"job-a": "user-b", "job-b": "user-a"} {
+		entries, err := svc.GetJobLogs(jobID, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) == 0 {
+			t.Fatalf("expected logs for %s", jobID)
+		}
+		for _, entry := range entries {
+			if strings.Contains(entry.Message, other) {
+				t.Errorf("%s logs contain a line from the other job: %s", jobID, entry.Message)
+			}
+		}
+	}
+
+	resp, err := grpcSvc.GetServerLogs(context.Background(), &pb.GetServerLogsRequest{TailLines: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := strings.Join(resp.LogLines, "\n")
+	if !strings.Contains(all, "user-a") || !strings.Contains(all, "user-b") {
+		t.Errorf("expected the server logs to keep every job's lines, got:\n%s", all)
+	}
+}
+
+func TestGetJobLogs_Tail(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+	svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-1", 5*time.Second)
+
+	all, err := svc.GetJobLogs("job-1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := grpcSvc.GetJobLogs(context.Background(), &pb.GetJobLogsRequest{JobId: "job-1", TailLines: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.LogLines) != 2 || resp.LogLines[1] != all[len(all)-1].Message {
+		t.Errorf("expected the last 2 lines, got %v", resp.LogLines)
+	}
+	if len(resp.Entries) != 2 || resp.Entries[0].Timestamp == nil {
+		t.Errorf("expected entries with timestamps, got %v", resp.Entries)
+	}
+}
+
+func TestGetJobLogs_UnknownJob(t *testing.T) {
+	grpcSvc := services.NewDownloadServiceGRPCWithService(services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{}))
+
+	_, err := grpcSvc.GetJobLogs(context.Background(), &pb.GetJobLogsRequest{JobId: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
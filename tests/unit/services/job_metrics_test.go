@@ -0,0 +1,161 @@
+package services_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services/scrapertest"
+)
+
+func TestJobMetrics_WritePrometheus(t *testing.T) {
+	m := services.NewJobMetrics()
+	m.JobStarted()
+	m.JobStarted()
+	m.JobStarted()
+	m.JobFinished("completed")
+	m.JobFinished("failed")
+	m.ObserveAccountDuration(500 * time.Millisecond)
+	m.ObserveAccountDuration(20 * time.Second)
+	m.ObserveAccountDuration(time.Hour)
+
+	var out strings.Builder
+	if err := m.WritePrometheus(&out); err != nil {
+		t.Fatal(err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"# TYPE etc_meisai_jobs_started_total counter",
+		"etc_meisai_jobs_started_total 3\n",
+		"etc_meisai_jobs_completed_total 1\n",
+		"etc_meisai_jobs_failed_total 1\n",
+		"# TYPE etc_meisai_jobs_running gauge",
+		"etc_meisai_jobs_running 1\n",
+		"# TYPE etc_meisai_account_download_duration_seconds histogram",
+		`etc_meisai_account_download_duration_seconds_bucket{le="1"} 1` + "\n",
+		`etc_meisai_account_download_duration_seconds_bucket{le="10"} 1` + "\n",
+		`etc_meisai_account_download_duration_seconds_bucket{le="30"} 2` + "\n",
+		`etc_meisai_account_download_duration_seconds_bucket{le="600"} 2` + "\n",
+		`etc_meisai_account_download_duration_seconds_bucket{le="+Inf"} 3` + "\n",
+		"etc_meisai_account_download_duration_seconds_sum 3620.5\n",
+		"etc_meisai_account_download_duration_seconds_count 3\n",
+		"etc_meisai_csv_parse_errors_total 0\n",
+		`etc_meisai_csv_parse_column_errors_total{column="amount"} 0` + "\n",
+		"etc_meisai_csv_last_parse_success_timestamp_seconds 0.000\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestJobMetrics_NilIsNoop(t *testing.T) {
+	var m *services.JobMetrics
+	m.JobStarted()
+	m.JobFinished("completed")
+	m.ObserveAccountDuration(time.Second)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Body.Len() != 0 {
+		t.Errorf("expected empty output, got %q", rec.Body.String())
+	}
+}
+
+func TestProcessAsync_RecordsJobMetrics(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_METRICS_ENABLED", "")
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+
+	svc.ProcessAsync(context.Background(), "job-metrics", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-metrics", 5*time.Second)
+
+	rec := httptest.NewRecorder()
+	services.NewDownloadServiceGRPCWithService(svc).Metrics().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	text := rec.Body.String()
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+	for _, want := range []string{
+		"etc_meisai_jobs_started_total 1\n",
+		"etc_meisai_jobs_completed_total 1\n",
+		"etc_meisai_jobs_running 0\n",
+		"etc_meisai_account_download_duration_seconds_count 2\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestProcessAsync_FailedJobCountsAsFailed(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	svc.DownloadDir = "/dev/null/downloads"
+
+	svc.ProcessAsync(context.Background(), "job-failed", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-failed", 5*time.Second)
+
+	var out strings.Builder
+	svc.Metrics().WritePrometheus(&out)
+	if !strings.Contains(out.String(), "etc_meisai_jobs_failed_total 1\n") || !strings.Contains(out.String(), "etc_meisai_jobs_running 0\n") {
+		t.Errorf("unexpected metrics:\n%s", out.String())
+	}
+}
+
+func TestProcessAsync_CountsCSVParseErrors(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_METRICS_ENABLED", "")
+	factory := &scrapertest.FakeScraperFactory{
+		Behavior: scrapertest.Behavior{CSV: threeRowCSV},
+		Accounts: map[string]scrapertest.Behavior{
+			"user2": {CSV: "利用年月日（自）,通行料金\n2024/01/05,無料\n"},
+			"user3": {CSV: "日付,料金\n2024/01/05,1200\n"},
+		},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+
+	before := time.Now()
+	svc.ProcessAsync(context.Background(), "job-csv", []string{"user1:pass1", "user2:pass2", "user3:pass3"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-csv", 5*time.Second)
+
+	var out strings.Builder
+	svc.Metrics().WritePrometheus(&out)
+	text := out.String()
+	for _, want := range []string{
+		"# TYPE etc_meisai_csv_parse_errors_total counter",
+		"etc_meisai_csv_parse_errors_total 2\n",
+		// user2 has an invalid amount, the header of user3 has neither column
+		`etc_meisai_csv_parse_column_errors_total{column="usage_date"} 1` + "\n",
+		`etc_meisai_csv_parse_column_errors_total{column="amount"} 2` + "\n",
+		"# TYPE etc_meisai_csv_last_parse_success_timestamp_seconds gauge",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+
+	var lastParsed float64
+	for _, line := range strings.Split(text, "\n") {
+		if value, ok := strings.CutPrefix(line, "etc_meisai_csv_last_parse_success_timestamp_seconds "); ok {
+			lastParsed, _ = strconv.ParseFloat(value, 64)
+		}
+	}
+	if lastParsed < float64(before.Unix()) {
+		t.Errorf("expected the time user1 was parsed, got %v", lastParsed)
+	}
+}
+
+func TestMetrics_DisabledByEnv(t *testing.T) {
+	t.Setenv("ETC_METRICS_ENABLED", "false")
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if svc.Metrics() != nil {
+		t.Error("expected nil metrics when disabled")
+	}
+}
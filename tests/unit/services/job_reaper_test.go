@@ -0,0 +1,51 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestReapExpiredJobs_RemovesOnlyExpiredTerminalJobs(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	svc.JobTTL = 50 * time.Millisecond
+
+	svc.ProcessAsync(context.Background(), "done-job", []string{"a:1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "done-job", 5*time.Second)
+
+	if removed := svc.ReapExpiredJobs(); removed != 0 {
+		t.Fatalf("expected fresh job to be kept, removed %d", removed)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if removed := svc.ReapExpiredJobs(); removed != 1 {
+		t.Fatalf("expected 1 expired job to be removed, removed %d", removed)
+	}
+	if _, ok := svc.GetJobStatus("done-job"); ok {
+		t.Error("expected expired job to be gone")
+	}
+}
+
+func TestStartJobReaper_SweepsPeriodically(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	svc.JobTTL = time.Millisecond
+
+	svc.ProcessAsync(context.Background(), "reaped-job", []string{"a:1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "reaped-job", 5*time.Second)
+
+	stop := svc.StartJobReaper(10 * time.Millisecond)
+	defer stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, ok := svc.GetJobStatus("reaped-job"); !ok {
+			stop()
+			stop() // stop must be idempotent
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("expected reaper to remove the expired job")
+}
@@ -0,0 +1,149 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetJobResult_PagesThroughRecords(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV, CSVs: map[string]string{"user2": otherThreeRowCSV}})
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-1", 5*time.Second)
+
+	var records []*pb.ETCMeisaiRecord
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("expected paging to finish within 3 pages")
+		}
+		resp, err := grpcSvc.GetJobResult(context.Background(), &pb.GetJobResultRequest{
+			JobId:     "job-1",
+			PageSize:  4,
+			PageToken: token,
+		})
+		if err != nil {
+			t.Fatalf("GetJobResult failed: %v", err)
+		}
+		if resp.TotalRecords != 6 {
+			t.Errorf("expected 6 total records, got %d", resp.TotalRecords)
+		}
+		records = append(records, resp.Records...)
+		token = resp.NextPageToken
+		if token == "" {
+			break
+		}
+	}
+
+	if len(records) != 6 {
+		t.Fatalf("expected 6 records across pages, got %d", len(records))
+	}
+	perAccount := map[string]int{}
+	for _, r := range records {
+		perAccount[r.AccountId]++
+		if r.Amount == 0 || r.CsvFileName == "" || r.DownloadedAt == nil {
+			t.Errorf("expected populated record, got %+v", r)
+		}
+	}
+	if perAccount["user1"] != 3 || perAccount["user2"] != 3 {
+		t.Errorf("expected 3 records per account, got %v", perAccount)
+	}
+}
+
+func TestGetJobResult_MergesDuplicateRecordsAcrossAccounts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_MAX_CONCURRENCY", "1")
+	// user2 downloads the usage of 2024/01/07 again along with a new one
+	overlapping := "利用年月日（自）,通行料金\n2024/01/07,950\n2024/01/08,700\n"
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV, CSVs: map[string]string{"user2": overlapping}})
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsync(context.Background(), "dup-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "dup-job", 5*time.Second)
+
+	resp, err := grpcSvc.GetJobResult(context.Background(), &pb.GetJobResultRequest{JobId: "dup-job"})
+	if err != nil {
+		t.Fatalf("GetJobResult failed: %v", err)
+	}
+	if resp.TotalRecords != 4 || len(resp.Records) != 4 {
+		t.Fatalf("expected 4 records without the duplicate, got %d (%d)", resp.TotalRecords, len(resp.Records))
+	}
+	perAccount := map[string]int{}
+	for _, r := range resp.Records {
+		perAccount[r.AccountId]++
+	}
+	if perAccount["user1"] != 3 || perAccount["user2"] != 1 {
+		t.Errorf("expected the duplicate to be kept for the account processed first, got %v", perAccount)
+	}
+
+	var buf bytes.Buffer
+	if err := svc.ExportJobCSV("dup-job", &buf); err != nil {
+		t.Fatalf("ExportJobCSV failed: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 5 {
+		t.Errorf("expected header and 4 rows in the export, got %d lines", lines)
+	}
+}
+
+func TestGetJobResult_InvalidPageToken(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-1", 5*time.Second)
+
+	_, err := grpcSvc.GetJobResult(context.Background(), &pb.GetJobResultRequest{JobId: "job-1", PageToken: "abc"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestGetJobResult_RunningJobIsFailedPrecondition(t *testing.T) {
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsync(context.Background(), "job-running", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+
+	_, err := grpcSvc.GetJobResult(context.Background(), &pb.GetJobResultRequest{JobId: "job-running"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition, got %v", err)
+	}
+
+	close(gate)
+	waitForJob(t, svc, "job-running", 5*time.Second)
+}
+
+func TestGetJobResult_ExpiredJobIsNotFound(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	svc.JobTTL = time.Millisecond
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	_, err := grpcSvc.GetJobResult(context.Background(), &pb.GetJobResultRequest{JobId: "missing-job"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for unknown job, got %v", err)
+	}
+
+	svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-1", 5*time.Second)
+	time.Sleep(10 * time.Millisecond)
+	svc.ReapExpiredJobs()
+
+	_, err = grpcSvc.GetJobResult(context.Background(), &pb.GetJobResultRequest{JobId: "job-1"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for expired job, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services/scrapertest"
+)
+
+func newRetryPassService(t *testing.T) (*services.DownloadService, *scrapertest.FakeScraperFactory) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	factory := &scrapertest.FakeScraperFactory{
+		Behavior: scrapertest.Behavior{CSV: threeRowCSV},
+		Accounts: map[string]scrapertest.Behavior{
+			// fails once with an error that is not retried within the pass
+			"user2": {DownloadErr: errors.New("unexpected search page"), DownloadFailures: 1},
+			"user3": {LoginErr: &scraper.AuthError{Reason: "invalid password"}},
+			"user4": {DownloadErr: errors.New("unexpected search page")},
+		},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.RetryBaseDelay = time.Millisecond
+	svc.RetryPassDelay = time.Millisecond
+	return svc, factory
+}
+
+var retryPassAccounts = []string{"user1:pass1", "user2:pass2", "user3:pass3", "user4:pass4"}
+
+func TestProcessAsync_RetryPassesRetryOnlyTransientFailures(t *testing.T) {
+	svc, factory := newRetryPassService(t)
+	svc.RetryPasses = 2
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "retry-job", retryPassAccounts, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "retry-job", 5*time.Second)
+
+	if job.Status != "partial" || job.Passes != 3 {
+		t.Errorf("expected a partial job after 3 passes, got %s after %d", job.Status, job.Passes)
+	}
+	failed := map[string]int{}
+	for _, f := range job.FailedAccounts {
+		failed[f.AccountID]++
+	}
+	if len(failed) != 2 || failed["user3"] != 1 || failed["user4"] != 1 {
+		t.Errorf("expected user3 and user4 to be reported once each, got %+v", job.FailedAccounts)
+	}
+	if job.PerAccount["user2"] != "completed" || job.TotalRecords != 6 {
+		t.Errorf("expected user2 to succeed on the second pass, got %s with %d records", job.PerAccount["user2"], job.TotalRecords)
+	}
+	if calls := factory.Calls("user2"); calls.Download != 2 {
+		t.Errorf("expected user2 to be retried once, got %+v", calls)
+	}
+	if calls := factory.Calls("user3"); calls.Login != 1 {
+		t.Errorf("expected the auth error not to be retried, got %+v", calls)
+	}
+	if calls := factory.Calls("user4"); calls.Download != 3 {
+		t.Errorf("expected user4 to be tried on every pass, got %+v", calls)
+	}
+	if calls := factory.Calls("user1"); calls.Download != 1 {
+		t.Errorf("expected the successful account not to be retried, got %+v", calls)
+	}
+	if !logs.contains("Retrying 2 failed accounts of job retry-job") || !logs.contains("Retrying 1 failed accounts of job retry-job") {
+		t.Errorf("expected both retry passes to be logged, got %q", logs.lines)
+	}
+	if job.ErrorMessage != "2 of 4 accounts failed" {
+		t.Errorf("unexpected error message %q", job.ErrorMessage)
+	}
+}
+
+func TestProcessAsync_NoRetryPassesByDefault(t *testing.T) {
+	svc, factory := newRetryPassService(t)
+
+	svc.ProcessAsync(context.Background(), "single-pass", retryPassAccounts, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "single-pass", 5*time.Second)
+
+	if job.Passes != 1 || len(job.FailedAccounts) != 3 {
+		t.Errorf("expected one pass with 3 failed accounts, got %d passes and %+v", job.Passes, job.FailedAccounts)
+	}
+	if calls := factory.Calls("user2"); calls.Download != 1 {
+		t.Errorf("expected no retry pass, got %+v", calls)
+	}
+}
+
+func TestProcessAsync_CancelDuringRetryPassDelay(t *testing.T) {
+	svc, factory := newRetryPassService(t)
+	svc.RetryPasses = 1
+	svc.RetryPassDelay = time.Minute
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "cancel-retry", retryPassAccounts, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return logs.contains("Retrying 2 failed accounts of job cancel-retry") })
+	if err := svc.CancelJob("cancel-retry"); err != nil {
+		t.Fatal(err)
+	}
+	job := waitForJob(t, svc, "cancel-retry", 5*time.Second)
+
+	if job.Status != "cancelled" || job.Passes != 1 {
+		t.Errorf("expected the job to be cancelled before the second pass, got %s after %d passes", job.Status, job.Passes)
+	}
+	if calls := factory.Calls("user2"); calls.Download != 1 {
+		t.Errorf("expected no retry after cancelling, got %+v", calls)
+	}
+}
@@ -0,0 +1,29 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetJobStatus_UnknownJobReturnsNotFound(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	resp, err := grpcSvc.GetJobStatus(context.Background(), &pb.GetJobStatusRequest{JobId: "missing-job"})
+	if resp != nil {
+		t.Errorf("expected nil response, got %+v", resp)
+	}
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	if !strings.Contains(st.Message(), "missing-job") {
+		t.Errorf("expected job ID in message, got %q", st.Message())
+	}
+}
@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestGetJobStatuses_ReportsUnknownJobsAsNotFound(t *testing.T) {
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+	svc.ProcessAsync(context.Background(), "job-a", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	svc.ProcessAsync(context.Background(), "job-b", []string{"user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-a", 5*time.Second)
+	waitForJob(t, svc, "job-b", 5*time.Second)
+
+	resp, err := grpcSvc.GetJobStatuses(context.Background(), &pb.GetJobStatusesRequest{JobIds: []string{"job-b", "missing", "job-a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Entries) != 3 {
+		t.Fatalf("expected an entry per requested ID, got %d", len(resp.Entries))
+	}
+	for i, want := range []struct {
+		id    string
+		found bool
+	}{{"job-b", true}, {"missing", false}, {"job-a", true}} {
+		entry := resp.Entries[i]
+		if entry.JobId != want.id || entry.Found != want.found {
+			t.Errorf("entry %d: expected %s found=%t, got %s found=%t", i, want.id, want.found, entry.JobId, entry.Found)
+		}
+		if want.found && (entry.Status.GetJobId() != want.id || entry.Status.GetStatus() != "completed") {
+			t.Errorf("entry %d: unexpected status %+v", i, entry.Status)
+		}
+		if !want.found && entry.Status != nil {
+			t.Errorf("entry %d: expected no status for an unknown job, got %+v", i, entry.Status)
+		}
+	}
+}
+
+func TestGetJobStatuses_ReturnsCopies(t *testing.T) {
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	svc.ProcessAsync(context.Background(), "job-a", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-a", 5*time.Second)
+
+	jobs := svc.GetJobStatuses([]string{"job-a", "job-a"})
+	if len(jobs) != 1 {
+		t.Fatalf("expected duplicate IDs to collapse, got %d jobs", len(jobs))
+	}
+	jobs["job-a"].PerAccount["user1"] = "tampered"
+	if job, _ := svc.GetJobStatus("job-a"); job.PerAccount["user1"] != "completed" {
+		t.Errorf("expected the service's job to be unaffected, got %s", job.PerAccount["user1"])
+	}
+}
@@ -0,0 +1,341 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services/scrapertest"
+)
+
+// fakeJobsDriver is an in-memory stand-in for the download_jobs, download_watermarks and
+// account_run_status tables. It understands only the statements issued by the services' stores.
+type fakeJobsDriver struct {
+	mu          sync.Mutex
+	tables      map[string]map[string][]driver.Value // dsn -> id -> row
+	watermarks  map[string]map[string]time.Time      // dsn -> account_id -> last_download_date
+	accountRuns map[string]map[string]string         // dsn -> account_id -> details
+}
+
+var jobsDriver = &fakeJobsDriver{
+	tables:      make(map[string]map[string][]driver.Value),
+	watermarks:  make(map[string]map[string]time.Time),
+	accountRuns: make(map[string]map[string]string),
+}
+
+func init() {
+	sql.Register("fake-download-jobs", jobsDriver)
+}
+
+func (d *fakeJobsDriver) Open(dsn string) (driver.Conn, error) {
+	if strings.HasPrefix(dsn, "unreachable/") {
+		return nil, errors.New("dial tcp 127.0.0.1:3306: connect: connection refused")
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.tables[dsn] == nil {
+		d.tables[dsn] = make(map[string][]driver.Value)
+		d.watermarks[dsn] = make(map[string]time.Time)
+		d.accountRuns[dsn] = make(map[string]string)
+	}
+	return &fakeJobsConn{d: d, dsn: dsn}, nil
+}
+
+type fakeJobsConn struct {
+	d   *fakeJobsDriver
+	dsn string
+}
+
+func (c *fakeJobsConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeJobsStmt{c: c, query: query}, nil
+}
+func (c *fakeJobsConn) Close() error              { return nil }
+func (c *fakeJobsConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type fakeJobsStmt struct {
+	c     *fakeJobsConn
+	query string
+}
+
+func (s *fakeJobsStmt) Close() error  { return nil }
+func (s *fakeJobsStmt) NumInput() int { return -1 }
+
+func (s *fakeJobsStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	table := s.c.d.tables[s.c.dsn]
+
+	switch {
+	case strings.HasPrefix(s.query, "INSERT INTO download_jobs"):
+		row := append([]driver.Value(nil), args...)
+		if old, ok := table[args[0].(string)]; ok {
+			row[7] = old[7] // started_at is not updated
+		}
+		table[args[0].(string)] = row
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(s.query, "UPDATE download_jobs"):
+		var n int64
+		for _, row := range table {
+			if row[1] == "pending" || row[1] == "processing" || row[1] == "paused" {
+				row[1], row[8] = args[0], args[1]
+				n++
+			}
+		}
+		return driver.RowsAffected(n), nil
+	case strings.HasPrefix(s.query, "INSERT INTO download_watermarks"):
+		watermarks := s.c.d.watermarks[s.c.dsn]
+		account, date := args[0].(string), args[1].(time.Time)
+		if date.After(watermarks[account]) {
+			watermarks[account] = date
+		}
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(s.query, "INSERT INTO account_run_status"):
+		s.c.d.accountRuns[s.c.dsn][args[0].(string)] = args[1].(string)
+		return driver.RowsAffected(1), nil
+	}
+	return nil, errors.New("unexpected exec: " + s.query)
+}
+
+func (s *fakeJobsStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if !strings.HasPrefix(s.query, "SELECT") {
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	if strings.Contains(s.query, "FROM download_watermarks") {
+		rows := &fakeJobsRows{columns: []string{"last_download_date"}}
+		if date, ok := s.c.d.watermarks[s.c.dsn][args[0].(string)]; ok {
+			rows.rows = [][]driver.Value{{date}}
+		}
+		return rows, nil
+	}
+	if strings.Contains(s.query, "FROM account_run_status") {
+		rows := &fakeJobsRows{columns: []string{"details"}}
+		if details, ok := s.c.d.accountRuns[s.c.dsn][args[0].(string)]; ok {
+			rows.rows = [][]driver.Value{{details}}
+		}
+		return rows, nil
+	}
+	rows := &fakeJobsRows{columns: []string{"id", "status", "progress", "total_records", "error_message", "dry_run", "details", "started_at", "completed_at"}}
+	if row, ok := s.c.d.tables[s.c.dsn][args[0].(string)]; ok {
+		rows.rows = [][]driver.Value{append([]driver.Value(nil), row...)}
+	}
+	return rows, nil
+}
+
+type fakeJobsRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeJobsRows) Columns() []string { return r.columns }
+func (r *fakeJobsRows) Close() error      { return nil }
+func (r *fakeJobsRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func openJobsDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("fake-download-jobs", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestJobStore_StatusSurvivesRestart(t *testing.T) {
+	t.Chdir(t.TempDir())
+	db := openJobsDB(t)
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(db, nil, factory)
+	svc.AccountDelay = 0
+
+	svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	before := waitForJob(t, svc, "job-1", 5*time.Second)
+
+	// 再起動をシミュレート（メモリ上のジョブは失われる）
+	restarted := services.NewDownloadServiceWithFactory(db, nil, factory)
+	after, ok := restarted.GetJobStatus("job-1")
+	if !ok {
+		t.Fatal("expected job to be loaded from the database")
+	}
+	if after.Status != "completed" || after.Progress != 100 || after.TotalRecords != before.TotalRecords {
+		t.Errorf("unexpected loaded job: %+v", after)
+	}
+	if after.CompletedAt == nil || !after.StartedAt.Equal(before.StartedAt) {
+		t.Errorf("timestamps not restored: started=%v completed=%v", after.StartedAt, after.CompletedAt)
+	}
+	if len(after.AccountResults) != 2 || after.PerAccount["user2"] != "completed" {
+		t.Errorf("account details not restored: %+v", after)
+	}
+
+	if _, ok := restarted.GetJobStatus("unknown"); ok {
+		t.Error("unknown job should not be found")
+	}
+}
+
+func TestJobStore_MarksInProgressJobsInterruptedOnStartup(t *testing.T) {
+	t.Chdir(t.TempDir())
+	db := openJobsDB(t)
+	gate := make(chan struct{})
+	defer close(gate)
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(db, nil, factory)
+
+	svc.ProcessAsync(context.Background(), "job-running", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+
+	restarted := services.NewDownloadServiceWithFactory(db, nil, &fakeScraperFactory{})
+	job, ok := restarted.GetJobStatus("job-running")
+	if !ok {
+		t.Fatal("expected job to be loaded from the database")
+	}
+	if job.Status != "interrupted" || job.CompletedAt == nil {
+		t.Errorf("expected interrupted job with completion time, got status=%s completed_at=%v", job.Status, job.CompletedAt)
+	}
+	if job.PerAccount["user1"] != "processing" {
+		t.Errorf("expected last persisted per-account state, got %v", job.PerAccount)
+	}
+}
+
+func TestResumeJob_ContinuesInterruptedJobFromCheckpoint(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_MAX_CONCURRENCY", "1")
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:pass1,user2:pass2")
+	db := openJobsDB(t)
+	// user2 never finishes before the simulated crash
+	first := &scrapertest.FakeScraperFactory{
+		Behavior: scrapertest.Behavior{CSV: threeRowCSV},
+		Accounts: map[string]scrapertest.Behavior{"user2": {Latency: time.Hour}},
+	}
+	svc := services.NewDownloadServiceWithFactory(db, nil, first)
+	svc.AccountDelay = 0
+	svc.ProcessAsync(context.Background(), "long-job", []string{"user1:pass1", "user2:pass2", "user3:pass3"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool {
+		job, _ := svc.GetJobStatus("long-job")
+		return job.PerAccount["user2"] == "processing"
+	})
+
+	second := &scrapertest.FakeScraperFactory{Behavior: scrapertest.Behavior{CSV: threeRowCSV}}
+	restarted := services.NewDownloadServiceWithFactory(db, nil, second)
+	restarted.AccountDelay = 0
+	if err := restarted.ResumeJob("long-job"); !errors.Is(err, services.ErrAccountNotFound) || !strings.Contains(err.Error(), "user3") {
+		t.Fatalf("expected the unconfigured account to block resuming, got %v", err)
+	}
+
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:pass1,user2:pass2,user3:pass3")
+	if err := restarted.ResumeJob("long-job"); err != nil {
+		t.Fatalf("ResumeJob failed: %v", err)
+	}
+	job := waitForJob(t, restarted, "long-job", 5*time.Second)
+	if job.Status != "completed" || job.Progress != 100 || job.TotalRecords != 9 || len(job.AccountResults) != 3 {
+		t.Errorf("expected the resumed job to complete with every account, got %s %d%% %d records %+v",
+			job.Status, job.Progress, job.TotalRecords, job.AccountResults)
+	}
+	for id, want := range map[string]int{"user1": 0, "user2": 1, "user3": 1} {
+		if got := second.Calls(id).Download; got != want {
+			t.Errorf("expected %d downloads for %s after resuming, got %d", want, id, got)
+		}
+	}
+	folder := first.Scrapers()[0].Config().SessionFolder
+	for _, s := range second.Scrapers() {
+		if s.Config().SessionFolder != folder {
+			t.Errorf("expected the original session folder %s, got %s", folder, s.Config().SessionFolder)
+		}
+	}
+
+	if err := restarted.ResumeJob("long-job"); !errors.Is(err, services.ErrInvalidJobState) {
+		t.Errorf("expected a finished job not to be resumed, got %v", err)
+	}
+	if err := restarted.ResumeJob("unknown"); !errors.Is(err, services.ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestResumeJob_KeepsOutputFormat(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_MAX_CONCURRENCY", "1")
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:pass1,user2:pass2")
+	db := openJobsDB(t)
+	first := &scrapertest.FakeScraperFactory{
+		Behavior: scrapertest.Behavior{CSV: threeRowCSV},
+		Accounts: map[string]scrapertest.Behavior{"user2": {Latency: time.Hour}},
+	}
+	svc := services.NewDownloadServiceWithFactory(db, nil, first)
+	svc.AccountDelay = 0
+	svc.ProcessAsyncWithOptions(context.Background(), "csv-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31",
+		services.JobOptions{OutputFormat: services.OutputFormatCSVPath})
+	waitFor(t, func() bool {
+		job, _ := svc.GetJobStatus("csv-job")
+		return job.PerAccount["user2"] == "processing"
+	})
+
+	restarted := services.NewDownloadServiceWithFactory(db, nil, &scrapertest.FakeScraperFactory{Behavior: scrapertest.Behavior{CSV: threeRowCSV}})
+	restarted.AccountDelay = 0
+	if err := restarted.ResumeJob("csv-job"); err != nil {
+		t.Fatalf("ResumeJob failed: %v", err)
+	}
+	job := waitForJob(t, restarted, "csv-job", 5*time.Second)
+	if job.Status != "completed" || len(job.AccountResults) != 2 {
+		t.Fatalf("expected the resumed job to complete with every account, got %s %+v", job.Status, job.AccountResults)
+	}
+	for _, result := range job.AccountResults {
+		if result.CSVPath == "" {
+			t.Errorf("expected the CSV path of %s to be kept", result.AccountID)
+		}
+	}
+	records, err := restarted.GetJobRecords("csv-job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected the resumed account not to be parsed with output format CSV_PATH, got %d records", len(records))
+	}
+}
+
+func TestLoadJob_WithoutDatabase(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if _, err := svc.LoadJob("job"); !errors.Is(err, services.ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestProcessAsync_FailsFastWhenDatabaseIsUnreachable(t *testing.T) {
+	t.Chdir(t.TempDir())
+	db, err := sql.Open("fake-download-jobs", "unreachable/"+t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(db, nil, factory)
+
+	svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job, ok := svc.GetJobStatus("job-1")
+	if !ok || job.Status != "failed" || !strings.Contains(job.ErrorMessage, "database unavailable") {
+		t.Fatalf("expected the job to fail immediately, got %+v", job)
+	}
+	if factory.createdScrapers() != 0 {
+		t.Error("expected no account to be processed")
+	}
+
+	// DBを使わない構成などで確認を無効にした場合はジョブを開始する
+	svc.CheckDB = false
+	svc.ProcessAsync(context.Background(), "job-2", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	if job := waitForJob(t, svc, "job-2", 5*time.Second); job.Status != "completed" {
+		t.Errorf("expected the job to run without the DB check, got %s: %s", job.Status, job.ErrorMessage)
+	}
+}
@@ -0,0 +1,77 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+// lockedWriter はテスト中に複数のgoroutineから書き込まれるログを保持する
+type lockedWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *lockedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestLogFormatJSON_EmitsStructuredLines(t *testing.T) {
+	t.Setenv("ETC_LOG_FORMAT", "json")
+	out := &lockedWriter{}
+	svc := services.NewDownloadServiceWithFactory(nil, log.New(out, "[TEST] ", log.LstdFlags), &fakeScraperFactory{CSV: threeRowCSV})
+	svc.AccountDelay = 0
+	raw := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "json-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "json-job", 5*time.Second)
+
+	var sawAccount bool
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var entry struct {
+			Ts      string `json:"ts"`
+			Level   string `json:"level"`
+			JobID   string `json:"job_id"`
+			Account string `json:"account"`
+			Msg     string `json:"msg"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("expected JSON log line, got %q: %v", line, err)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, entry.Ts); err != nil {
+			t.Errorf("expected RFC3339 ts, got %q", entry.Ts)
+		}
+		if entry.Level == "" || entry.Msg == "" {
+			t.Errorf("expected level and msg, got %+v", entry)
+		}
+		if entry.JobID != "json-job" {
+			t.Errorf("expected job_id on job log line, got %+v", entry)
+		}
+		if entry.Account == "user1" && strings.HasPrefix(entry.Msg, "Successfully downloaded") {
+			sawAccount = true
+		}
+	}
+	if !sawAccount {
+		t.Errorf("expected a download log line carrying the account, got:\n%s", out.String())
+	}
+
+	// ログバッファには元のメッセージがそのまま渡される
+	if !raw.contains("Completed download job json-job") || raw.contains(`"msg"`) {
+		t.Error("expected raw messages in log callback")
+	}
+}
@@ -0,0 +1,63 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestLogBuffer_ResizeKeepsMostRecentLines(t *testing.T) {
+	lb := services.NewLogBuffer(4)
+	for i := 1; i <= 6; i++ {
+		lb.Add(fmt.Sprintf("line %d", i))
+	}
+
+	lb.Resize(2)
+	if got := lb.GetAll(); !reflect.DeepEqual(got, []string{"line 5", "line 6"}) {
+		t.Errorf("expected most recent lines after shrinking, got %v", got)
+	}
+
+	lb.Resize(3)
+	lb.Add("line 7")
+	lb.Add("line 8")
+	if got := lb.GetAll(); !reflect.DeepEqual(got, []string{"line 6", "line 7", "line 8"}) {
+		t.Errorf("expected buffer to hold 3 lines after growing, got %v", got)
+	}
+	if lb.Capacity() != 3 {
+		t.Errorf("expected capacity 3, got %d", lb.Capacity())
+	}
+
+	lb.Resize(0)
+	if lb.Capacity() != 3 {
+		t.Errorf("expected non-positive size to be ignored, got capacity %d", lb.Capacity())
+	}
+}
+
+func TestNewDownloadServiceGRPC_LogBufferSizeFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int32
+	}{
+		{"", 1000},
+		{"50", 50},
+		{"0", 1000},
+		{"abc", 1000},
+	}
+	for _, tt := range tests {
+		t.Setenv("ETC_LOG_BUFFER_SIZE", tt.value)
+		svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+		grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+		metrics, err := grpcSvc.GetRuntimeMetrics(context.Background(), &pb.GetRuntimeMetricsRequest{})
+		if err != nil {
+			t.Fatalf("GetRuntimeMetrics failed: %v", err)
+		}
+		if metrics.LogBufferCapacity != tt.want {
+			t.Errorf("ETC_LOG_BUFFER_SIZE=%q: expected capacity %d, got %d", tt.value, tt.want, metrics.LogBufferCapacity)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestLogBuffer_GetTailEntriesFiltersByLevel(t *testing.T) {
+	lb := services.NewLogBuffer(10)
+	now := time.Now()
+	lb.AddEntry(services.LogEntry{Timestamp: now, Level: services.LogLevelDebug, Message: "debug"})
+	lb.AddEntry(services.LogEntry{Timestamp: now, Level: services.LogLevelError, Message: "error 1"})
+	lb.Add("info")
+	lb.AddEntry(services.LogEntry{Timestamp: now, Level: services.LogLevelWarn, Message: "warn"})
+	lb.AddEntry(services.LogEntry{Timestamp: now, Level: services.LogLevelError, Message: "error 2"})
+
+	entries := lb.GetTailEntries(0, services.LogLevelWarn)
+	want := []string{"error 1", "warn", "error 2"}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Message != want[i] {
+			t.Errorf("entry %d: expected %q, got %q", i, want[i], entry.Message)
+		}
+	}
+
+	// Tail applies after filtering
+	entries = lb.GetTailEntries(2, services.LogLevelError)
+	if len(entries) != 2 || entries[0].Message != "error 1" || entries[1].Message != "error 2" {
+		t.Errorf("unexpected tail entries: %+v", entries)
+	}
+
+	// Lines added through Add default to Info
+	info := lb.GetTailEntries(1, services.LogLevelDebug)
+	if info[0].Level != services.LogLevelError {
+		t.Errorf("expected last entry to be error, got %v", info[0].Level)
+	}
+	all := lb.GetTailEntries(0, services.LogLevelDebug)
+	if all[2].Level != services.LogLevelInfo || all[2].Timestamp.IsZero() {
+		t.Errorf("expected Add to record an Info entry with a timestamp, got %+v", all[2])
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    services.LogLevel
+		wantErr bool
+	}{
+		{"debug", services.LogLevelDebug, false},
+		{"INFO", services.LogLevelInfo, false},
+		{"warning", services.LogLevelWarn, false},
+		{" error ", services.LogLevelError, false},
+		{"verbose", services.LogLevelInfo, true},
+	}
+	for _, tt := range tests {
+		got, err := services.ParseLogLevel(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLogLevel(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestGetServerLogs_MinLevelFilter(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: "header\nrow\n", Expected: map[string]int{"user1": 5}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsync(context.Background(), "level-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "level-job", 5*time.Second)
+
+	all, err := grpcSvc.GetServerLogs(context.Background(), &pb.GetServerLogsRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	warnings, err := grpcSvc.GetServerLogs(context.Background(), &pb.GetServerLogsRequest{
+		MinLevel: pb.LogLevel_LOG_LEVEL_WARN,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(warnings.Entries) == 0 || len(warnings.Entries) >= len(all.Entries) {
+		t.Fatalf("expected the WARN filter to keep a strict subset, got %d of %d", len(warnings.Entries), len(all.Entries))
+	}
+	for i, entry := range warnings.Entries {
+		if entry.Level < pb.LogLevel_LOG_LEVEL_WARN {
+			t.Errorf("unexpected entry below WARN: %+v", entry)
+		}
+		if entry.Timestamp == nil {
+			t.Errorf("expected entry timestamp to be set")
+		}
+		if warnings.LogLines[i] != entry.Message {
+			t.Errorf("log_lines and entries should be in the same order")
+		}
+	}
+	if warnings.Entries[0].Level != pb.LogLevel_LOG_LEVEL_WARN {
+		t.Errorf("expected the count mismatch to be logged as WARN, got %v", warnings.Entries[0].Level)
+	}
+}
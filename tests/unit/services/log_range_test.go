@@ -0,0 +1,83 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogBuffer_GetRangeAcrossWraparound(t *testing.T) {
+	lb := services.NewLogBuffer(5)
+	for i := 1; i <= 8; i++ {
+		lb.Add(fmt.Sprintf("line %d", i))
+	}
+
+	if got := lb.GetAll(); !reflect.DeepEqual(got, []string{"line 4", "line 5", "line 6", "line 7", "line 8"}) {
+		t.Fatalf("unexpected buffer contents after wraparound: %v", got)
+	}
+
+	tests := []struct {
+		offset, count int
+		want          []string
+		hasMore       bool
+	}{
+		{0, 2, []string{"line 7", "line 8"}, true},
+		{2, 2, []string{"line 5", "line 6"}, true},
+		{4, 2, []string{"line 4"}, false},
+		{1, 0, []string{"line 4", "line 5", "line 6", "line 7"}, false},
+		{5, 2, []string{}, false},
+	}
+	for _, tt := range tests {
+		got, hasMore := lb.GetRange(tt.offset, tt.count)
+		if !reflect.DeepEqual(got, tt.want) || hasMore != tt.hasMore {
+			t.Errorf("GetRange(%d, %d) = %v, %v; want %v, %v", tt.offset, tt.count, got, hasMore, tt.want, tt.hasMore)
+		}
+	}
+}
+
+func TestGetServerLogs_PagesBackward(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	svc.ProcessAsync(context.Background(), "log-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "log-job", 5*time.Second)
+
+	all, err := grpcSvc.GetServerLogs(context.Background(), &pb.GetServerLogsRequest{TailLines: 1000})
+	if err != nil {
+		t.Fatalf("GetServerLogs failed: %v", err)
+	}
+	if all.HasMore || len(all.LogLines) < 3 {
+		t.Fatalf("expected several lines with nothing older, got %d lines (has_more=%v)", len(all.LogLines), all.HasMore)
+	}
+
+	// 2行ずつ遡って取得し、古い順に並べ直すと全件と一致する
+	var paged []string
+	offset := int32(0)
+	for {
+		resp, err := grpcSvc.GetServerLogs(context.Background(), &pb.GetServerLogsRequest{TailLines: 2, Offset: offset})
+		if err != nil {
+			t.Fatalf("GetServerLogs failed: %v", err)
+		}
+		paged = append(append([]string{}, resp.LogLines...), paged...)
+		if !resp.HasMore {
+			break
+		}
+		offset = resp.NextOffset
+	}
+	if !reflect.DeepEqual(paged, all.LogLines) {
+		t.Errorf("expected paged lines to match full history\npaged: %v\nall:   %v", paged, all.LogLines)
+	}
+
+	_, err = grpcSvc.GetServerLogs(context.Background(), &pb.GetServerLogsRequest{Offset: -1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for negative offset, got %v", err)
+	}
+}
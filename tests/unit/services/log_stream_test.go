@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc"
+)
+
+func TestLogBuffer_SubscribeFansOutNewLines(t *testing.T) {
+	lb := services.NewLogBuffer(10)
+	lb.Add("before")
+
+	first, unsubFirst := lb.Subscribe()
+	second, unsubSecond := lb.Subscribe()
+	defer unsubSecond()
+
+	lb.Add("after")
+	for _, ch := range []<-chan string{first, second} {
+		select {
+		case line := <-ch:
+			if line != "after" {
+				t.Errorf("expected only lines added after Subscribe, got %q", line)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("subscriber did not receive line")
+		}
+	}
+
+	unsubFirst()
+	unsubFirst() // idempotent
+	if _, ok := <-first; ok {
+		t.Error("expected channel to be closed after unsubscribe")
+	}
+	if lb.SubscriberCount() != 1 {
+		t.Errorf("expected 1 subscriber, got %d", lb.SubscriberCount())
+	}
+
+	// Adding after an unsubscribe must not panic on the closed channel
+	lb.Add("later")
+	if line := <-second; line != "later" {
+		t.Errorf("expected remaining subscriber to keep receiving, got %q", line)
+	}
+}
+
+func TestLogBuffer_SlowSubscriberDropsAndCounts(t *testing.T) {
+	lb := services.NewLogBuffer(10)
+	_, unsubscribe := lb.Subscribe()
+	defer unsubscribe()
+
+	// Never read from the channel; Add must not block
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 300; i++ {
+			lb.Add(fmt.Sprintf("line %d", i))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Add blocked on a slow subscriber")
+	}
+
+	if lb.DroppedLines() != 300-256 {
+		t.Errorf("expected %d dropped lines, got %d", 300-256, lb.DroppedLines())
+	}
+	if got := len(lb.GetAll()); got != 10 {
+		t.Errorf("buffer should still hold its own history, got %d lines", got)
+	}
+}
+
+// fakeLogStream captures lines sent by StreamServerLogs
+type fakeLogStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	mu    sync.Mutex
+	lines []string
+}
+
+func (f *fakeLogStream) Context() context.Context { return f.ctx }
+
+func (f *fakeLogStream) Send(resp *pb.StreamServerLogsResponse) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lines = append(f.lines, resp.Line)
+	return nil
+}
+
+func (f *fakeLogStream) received() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.lines...)
+}
+
+func TestStreamServerLogs_SendsTailThenNewLines(t *testing.T) {
+	grpcSvc := services.NewDownloadServiceGRPC(nil, nil)
+	for i := 1; i <= 3; i++ {
+		grpcSvc.LogMessage(fmt.Sprintf("old %d", i))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeLogStream{ctx: ctx}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- grpcSvc.StreamServerLogs(&pb.StreamServerLogsRequest{TailLines: 2}, stream)
+	}()
+
+	waitFor(t, func() bool { return len(stream.received()) == 2 })
+	grpcSvc.LogMessage("new 1")
+	waitFor(t, func() bool { return len(stream.received()) == 3 })
+
+	want := []string{"old 2", "old 3", "new 1"}
+	for i, line := range stream.received() {
+		if line != want[i] {
+			t.Errorf("line %d: expected %q, got %q", i, want[i], line)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end after context cancellation")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("condition not met in time")
+}
@@ -0,0 +1,68 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_MaintenanceMarksAccounts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: threeRowCSV, LoginMaintenance: map[string]int{"user1": 1}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+
+	svc.ProcessAsync(context.Background(), "maint-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "maint-job", 5*time.Second)
+
+	if job.PerAccount["user1"] != "maintenance" || job.PerAccount["user2"] != "completed" {
+		t.Errorf("unexpected per-account status: %v", job.PerAccount)
+	}
+	if job.Status != "partial" || len(job.FailedAccounts) != 1 || !job.FailedAccounts[0].Maintenance {
+		t.Errorf("expected a partial job with one maintenance failure, got %s %+v", job.Status, job.FailedAccounts)
+	}
+	if n := factory.loginAttempts("user1"); n != 1 {
+		t.Errorf("expected no login retries during maintenance, got %d attempts", n)
+	}
+	if job.RescheduledJobID != "" {
+		t.Errorf("expected no reschedule without ETC_MAINTENANCE_RETRY_DELAY, got %s", job.RescheduledJobID)
+	}
+
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+	status, err := grpcSvc.GetJobStatus(context.Background(), &pb.GetJobStatusRequest{JobId: "maint-job"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.PerAccount["user1"] != "maintenance" || !status.FailedAccounts[0].Maintenance {
+		t.Errorf("expected maintenance in JobStatus, got %v %v", status.PerAccount, status.FailedAccounts)
+	}
+}
+
+func TestProcessAsync_MaintenanceReschedulesAccounts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_MAINTENANCE_RETRY_DELAY", "50ms")
+	factory := &fakeScraperFactory{CSV: threeRowCSV, LoginMaintenance: map[string]int{"user1": 1}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+
+	svc.ProcessAsync(context.Background(), "maint-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "maint-job", 5*time.Second)
+	if job.RescheduledJobID == "" || job.RescheduledAt == nil || job.RescheduledAt.Before(*job.CompletedAt) {
+		t.Fatalf("expected the job to be rescheduled, got id=%q at=%v", job.RescheduledJobID, job.RescheduledAt)
+	}
+
+	waitFor(t, func() bool {
+		_, ok := svc.GetJobStatus(job.RescheduledJobID)
+		return ok
+	})
+	retry := waitForJob(t, svc, job.RescheduledJobID, 5*time.Second)
+	if retry.Status != "completed" || len(retry.PerAccount) != 1 || retry.PerAccount["user1"] != "completed" {
+		t.Errorf("expected the retry to download only user1, got %s %v", retry.Status, retry.PerAccount)
+	}
+	if n := factory.loginAttempts("user2"); n != 1 {
+		t.Errorf("expected user2 not to be retried, got %d logins", n)
+	}
+}
@@ -0,0 +1,99 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+// jobStatus returns the in-memory status of a job
+func jobStatus(t *testing.T, svc *services.DownloadService, jobID string) string {
+	t.Helper()
+	job, ok := svc.GetJobStatus(jobID)
+	if !ok {
+		t.Fatalf("job %s not found", jobID)
+	}
+	return job.Status
+}
+
+func TestMaxJobsRunning_QueuesJobsInOrder(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_MAX_JOBS_RUNNING", "1")
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	if svc.MaxJobsRunning != 1 {
+		t.Fatalf("expected MaxJobsRunning from ETC_MAX_JOBS_RUNNING, got %d", svc.MaxJobsRunning)
+	}
+
+	for i := 1; i <= 3; i++ {
+		svc.ProcessAsync(context.Background(), fmt.Sprintf("job-%d", i), []string{fmt.Sprintf("user%d:pass%d", i, i)}, "2024-01-01", "2024-01-31")
+	}
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+	if got := []string{jobStatus(t, svc, "job-1"), jobStatus(t, svc, "job-2"), jobStatus(t, svc, "job-3")}; got[0] != "processing" || got[1] != "pending" || got[2] != "pending" {
+		t.Errorf("expected job-1 processing and the rest pending, got %v", got)
+	}
+	if m := svc.GetRuntimeMetrics(); m.RunningJobs != 1 || m.QueuedJobs != 2 {
+		t.Errorf("expected 1 running and 2 queued jobs, got %d running and %d queued", m.RunningJobs, m.QueuedJobs)
+	}
+
+	// each job starts only after the previous one finishes, in submission order
+	for i := 1; i <= 3; i++ {
+		jobID := fmt.Sprintf("job-%d", i)
+		waitFor(t, func() bool { return jobStatus(t, svc, jobID) == "processing" })
+		for j := i + 1; j <= 3; j++ {
+			if got := jobStatus(t, svc, fmt.Sprintf("job-%d", j)); got != "pending" {
+				t.Errorf("expected job-%d to wait for %s, got %s", j, jobID, got)
+			}
+		}
+		gate <- struct{}{}
+		if job := waitForJob(t, svc, jobID, 5*time.Second); job.Status != "completed" {
+			t.Errorf("expected %s to complete, got %s", jobID, job.Status)
+		}
+	}
+	if got := factory.createdScrapers(); got != 3 {
+		t.Errorf("expected 3 scrapers, got %d", got)
+	}
+}
+
+func TestMaxJobsRunning_CancelPendingJob(t *testing.T) {
+	t.Chdir(t.TempDir())
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.MaxJobsRunning = 1
+
+	svc.ProcessAsync(context.Background(), "running", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	svc.ProcessAsync(context.Background(), "cancelled", []string{"user2:pass2"}, "2024-01-01", "2024-01-31")
+	svc.ProcessAsync(context.Background(), "next", []string{"user3:pass3"}, "2024-01-01", "2024-01-31")
+	if err := svc.CancelJob("cancelled"); err != nil {
+		t.Fatalf("CancelJob failed: %v", err)
+	}
+	job := waitForJob(t, svc, "cancelled", 5*time.Second)
+	if job.Status != "cancelled" || job.ErrorMessage != services.ErrJobCancelled.Error() {
+		t.Errorf("expected cancelled with %q, got %s %q", services.ErrJobCancelled, job.Status, job.ErrorMessage)
+	}
+
+	close(gate)
+	waitForJob(t, svc, "running", 5*time.Second)
+	if job := waitForJob(t, svc, "next", 5*time.Second); job.Status != "completed" {
+		t.Errorf("expected the job queued after the cancelled one to run, got %s", job.Status)
+	}
+	if got := factory.createdScrapers(); got != 2 {
+		t.Errorf("expected the cancelled job to start no scraper, got %d scrapers", got)
+	}
+}
+
+func TestMaxJobsRunning_InvalidValueIsUnlimited(t *testing.T) {
+	t.Setenv("ETC_MAX_JOBS_RUNNING", "many")
+
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if svc.MaxJobsRunning != 0 {
+		t.Errorf("expected invalid ETC_MAX_JOBS_RUNNING to fall back to 0, got %d", svc.MaxJobsRunning)
+	}
+}
@@ -0,0 +1,65 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestMaxJobs_EvictsOldestTerminalJobs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_MAX_JOBS", "2")
+
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	if svc.MaxJobs != 2 {
+		t.Fatalf("expected MaxJobs from ETC_MAX_JOBS, got %d", svc.MaxJobs)
+	}
+	for i := 1; i <= 3; i++ {
+		jobID := fmt.Sprintf("job-%d", i)
+		svc.ProcessAsync(context.Background(), jobID, []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+		waitForJob(t, svc, jobID, 5*time.Second)
+	}
+
+	if _, ok := svc.GetJobStatus("job-1"); ok {
+		t.Error("expected the oldest job to be evicted")
+	}
+	for _, jobID := range []string{"job-2", "job-3"} {
+		if _, ok := svc.GetJobStatus(jobID); !ok {
+			t.Errorf("expected %s to be kept", jobID)
+		}
+	}
+	if _, err := svc.GetJobLogs("job-1", 0); err == nil {
+		t.Error("expected the evicted job's logs to be removed")
+	}
+}
+
+func TestMaxJobs_NeverEvictsRunningJobs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	gate := make(chan struct{})
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV, Gate: gate})
+	svc.MaxJobs = 1
+
+	svc.ProcessAsync(context.Background(), "running-1", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	svc.ProcessAsync(context.Background(), "running-2", []string{"user2:pass2"}, "2024-01-01", "2024-01-31")
+	for _, jobID := range []string{"running-1", "running-2"} {
+		if _, ok := svc.GetJobStatus(jobID); !ok {
+			t.Errorf("expected running job %s to be kept over the cap", jobID)
+		}
+	}
+
+	close(gate)
+	waitForJob(t, svc, "running-1", 5*time.Second)
+	waitForJob(t, svc, "running-2", 5*time.Second)
+}
+
+func TestMaxJobs_InvalidValueIsUnlimited(t *testing.T) {
+	t.Setenv("ETC_MAX_JOBS", "-3")
+
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	if svc.MaxJobs != 0 {
+		t.Errorf("expected 0 (unlimited) for an invalid value, got %d", svc.MaxJobs)
+	}
+}
@@ -0,0 +1,70 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_MaxRecordsPerAccount(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV, CSVs: map[string]string{"user2": otherThreeRowCSV}})
+	svc.AccountDelay = 0
+	svc.MaxRecordsPerAccount = 2
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "max-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "max-job", 5*time.Second)
+
+	if job.Status != "completed" {
+		t.Fatalf("expected truncated accounts to complete, got %s", job.Status)
+	}
+	for _, r := range job.AccountResults {
+		if !r.Truncated || r.ActualRecords != 2 {
+			t.Errorf("expected %s to be truncated to 2 records, got %+v", r.AccountID, r)
+		}
+	}
+	if job.TotalRecords != 4 {
+		t.Errorf("expected total records to reflect truncation, got %d", job.TotalRecords)
+	}
+	records, err := svc.GetJobRecords("max-job")
+	if err != nil || len(records) != 4 {
+		t.Errorf("expected 4 records, got %d (%v)", len(records), err)
+	}
+	if !logs.contains("Truncated records for account user1: CSV has 3 records, kept the first 2") {
+		t.Error("expected a truncation warning")
+	}
+}
+
+func TestProcessAsync_MaxRecordsNotReached(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV})
+	svc.MaxRecordsPerAccount = 3
+
+	svc.ProcessAsync(context.Background(), "max-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "max-job", 5*time.Second)
+
+	if r := job.AccountResults[0]; r.Truncated || r.ActualRecords != 3 {
+		t.Errorf("expected all 3 records without truncation, got %+v", r)
+	}
+}
+
+func TestMaxRecordsPerAccountEnv(t *testing.T) {
+	tests := []struct {
+		env  string
+		want int
+	}{
+		{"", 0},
+		{"500", 500},
+		{"0", 0},
+		{"-1", 0},
+		{"many", 0},
+	}
+	for _, tt := range tests {
+		t.Setenv("ETC_MAX_RECORDS", tt.env)
+		svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+		if svc.MaxRecordsPerAccount != tt.want {
+			t.Errorf("ETC_MAX_RECORDS=%q: expected %d, got %d", tt.env, tt.want, svc.MaxRecordsPerAccount)
+		}
+	}
+}
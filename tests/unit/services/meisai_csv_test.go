@@ -0,0 +1,213 @@
+package services_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"golang.org/x/text/encoding/japanese"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const meisaiCSVHeader = "利用年月日（自）,時分（自）,利用年月日（至）,時分（至）,利用ＩＣ（自）,利用ＩＣ（至）,割引前料金,ＥＴＣ割引額,通行料金,車種,車両番号,ＥＴＣカード番号,備考"
+
+var meisaiCSVRows = []string{
+	"2024/01/05,08:10,2024/01/05,08:45,東京,横浜町田,\"1,500\",300,\"1,200\",普通,品川 300 あ 12-34,1234567890123456,",
+	"2024/01/06,17:02,2024/01/06,17:30,横浜町田,東京,1500,0,1500,普通,品川 300 あ 12-34,1234567890123456,深夜割引",
+}
+
+func meisaiCSV(lineEnding string) string {
+	return strings.Join(append([]string{meisaiCSVHeader}, meisaiCSVRows...), lineEnding) + lineEnding
+}
+
+func toShiftJIS(t *testing.T, s string) []byte {
+	t.Helper()
+	encoded, err := japanese.ShiftJIS.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatalf("failed to encode Shift-JIS: %v", err)
+	}
+	return encoded
+}
+
+func assertMeisaiRecords(t *testing.T, records []*pb.ETCMeisaiRecord) {
+	t.Helper()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	first := records[0]
+	jst := time.FixedZone("JST", 9*60*60)
+	if want := time.Date(2024, 1, 5, 8, 45, 0, 0, jst); !first.UsageDate.AsTime().Equal(want) {
+		t.Errorf("expected usage date %v, got %v", want, first.UsageDate.AsTime())
+	}
+	if first.EntryIc != "東京" || first.ExitIc != "横浜町田" {
+		t.Errorf("unexpected ICs: %q -> %q", first.EntryIc, first.ExitIc)
+	}
+	if first.Amount != 1200 {
+		t.Errorf("expected amount 1200, got %d", first.Amount)
+	}
+	if first.VehicleNumber != "品川 300 あ 12-34" || first.EtcCardNumber != "1234567890123456" {
+		t.Errorf("unexpected vehicle/card: %q %q", first.VehicleNumber, first.EtcCardNumber)
+	}
+	if records[1].Amount != 1500 || records[1].EntryIc != "横浜町田" {
+		t.Errorf("unexpected second record: %+v", records[1])
+	}
+}
+
+func TestParseMeisaiCSV_CRLFShiftJIS(t *testing.T) {
+	data := toShiftJIS(t, meisaiCSV("\r\n"))
+	records, err := services.ParseMeisaiCSV(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertMeisaiRecords(t, records)
+}
+
+func TestParseMeisaiCSV_LFUTF8(t *testing.T) {
+	records, err := services.ParseMeisaiCSV(strings.NewReader(meisaiCSV("\n")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertMeisaiRecords(t, records)
+}
+
+func TestParseMeisaiCSV_BOMPrefixedUTF8(t *testing.T) {
+	data := append([]byte{0xEF, 0xBB, 0xBF}, meisaiCSV("\r\n")...)
+	records, err := services.ParseMeisaiCSV(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertMeisaiRecords(t, records)
+}
+
+func TestParseMeisaiCSV_CROnlyLineEndings(t *testing.T) {
+	records, err := services.ParseMeisaiCSV(strings.NewReader(meisaiCSV("\r")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertMeisaiRecords(t, records)
+}
+
+func TestParseMeisaiCSVWithEncoding_ExplicitShiftJIS(t *testing.T) {
+	data := toShiftJIS(t, meisaiCSV("\n"))
+	records, err := services.ParseMeisaiCSVWithEncoding(bytes.NewReader(data), services.CSVEncodingShiftJIS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertMeisaiRecords(t, records)
+}
+
+func TestParseMeisaiCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"missing amount column", "利用年月日（至）,時分（至）\n2024/01/05,08:45\n"},
+		{"bad date", meisaiCSVHeader + "\n2024/13/05,08:10,2024/13/05,08:45,東京,横浜町田,0,0,100,普通,,,\n"},
+		{"bad amount", meisaiCSVHeader + "\n2024/01/05,08:10,2024/01/05,08:45,東京,横浜町田,0,0,abc,普通,,,\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := services.ParseMeisaiCSV(strings.NewReader(tt.csv)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParseMeisaiCSVWithColumnMap_RenamedAndReorderedColumns(t *testing.T) {
+	// another account type: columns reordered, amount and card number renamed, an unknown column added
+	data := strings.Join([]string{
+		"料金,ＥＴＣカード番号（下４桁）,車両番号,利用ＩＣ（至）,利用ＩＣ（自）,時分（至）,利用年月日（至）,時分（自）,利用年月日（自）,ポイント",
+		"\"1,200\",1234567890123456,品川 300 あ 12-34,横浜町田,東京,08:45,2024/01/05,08:10,2024/01/05,12",
+		"1500,1234567890123456,品川 300 あ 12-34,東京,横浜町田,17:30,2024/01/06,17:02,2024/01/06,0",
+	}, "\n") + "\n"
+
+	if _, err := services.ParseMeisaiCSV(strings.NewReader(data)); err == nil || !strings.Contains(err.Error(), "missing required columns: 通行料金") {
+		t.Errorf("expected the renamed amount column to be reported, got %v", err)
+	}
+
+	columnMap, err := services.ParseCSVColumnMap("通行料金=通行料|料金, ＥＴＣカード番号=ETCカード番号(下4桁)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := services.ParseMeisaiCSVWithColumnMap(strings.NewReader(data), services.CSVEncodingAuto, columnMap)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertMeisaiRecords(t, records)
+}
+
+func TestParseCSVColumnMap(t *testing.T) {
+	columnMap, err := services.ParseCSVColumnMap(`{"通行料金":["料金"," 通行料 "],"車両番号":["車番"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := columnMap["通行料金"]; len(got) != 2 || got[1] != "通行料" || columnMap["車両番号"][0] != "車番" {
+		t.Errorf("unexpected column map %v", columnMap)
+	}
+
+	for _, value := range []string{"料金", "金額=料金", `{"通行料金":"料金"}`} {
+		if _, err := services.ParseCSVColumnMap(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestParseMeisaiCSV_EmptyInputAndBlankLines(t *testing.T) {
+	records, err := services.ParseMeisaiCSV(strings.NewReader(""))
+	if err != nil || len(records) != 0 {
+		t.Errorf("expected no records for empty input, got %v, %v", records, err)
+	}
+
+	records, err = services.ParseMeisaiCSV(strings.NewReader(meisaiCSVHeader + "\n\n,,,\n"))
+	if err != nil || len(records) != 0 {
+		t.Errorf("expected blank rows to be skipped, got %v, %v", records, err)
+	}
+}
+
+func TestRecordDedupKey(t *testing.T) {
+	records, err := services.ParseMeisaiCSV(strings.NewReader(meisaiCSV("\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := records[0]
+	key := services.RecordDedupKey(first)
+	if len(key) != 64 {
+		t.Fatalf("expected a hex SHA-256 key, got %q", key)
+	}
+
+	// ダウンロード元の情報はキーに含めない
+	same := proto.Clone(first).(*pb.ETCMeisaiRecord)
+	same.AccountId = "other-account"
+	same.CsvFileName = "other.csv"
+	same.VehicleNumber = ""
+	same.UsageDate = timestamppb.New(first.UsageDate.AsTime().UTC())
+	if got := services.RecordDedupKey(same); got != key {
+		t.Errorf("expected the same key regardless of account, file and time zone, got %q and %q", key, got)
+	}
+
+	changes := map[string]func(r *pb.ETCMeisaiRecord){
+		"usage time": func(r *pb.ETCMeisaiRecord) { r.UsageDate = timestamppb.New(r.UsageDate.AsTime().Add(time.Minute)) },
+		"entry IC":   func(r *pb.ETCMeisaiRecord) { r.EntryIc = "川崎" },
+		"exit IC":    func(r *pb.ETCMeisaiRecord) { r.ExitIc = "川崎" },
+		"amount":     func(r *pb.ETCMeisaiRecord) { r.Amount++ },
+		"card":       func(r *pb.ETCMeisaiRecord) { r.EtcCardNumber = "9999999999999999" },
+		"shifted":    func(r *pb.ETCMeisaiRecord) { r.EntryIc, r.ExitIc = "東京横浜町田", "" },
+	}
+	for name, change := range changes {
+		changed := proto.Clone(first).(*pb.ETCMeisaiRecord)
+		change(changed)
+		if services.RecordDedupKey(changed) == key {
+			t.Errorf("expected a different key when %s changes", name)
+		}
+	}
+
+	if services.RecordDedupKey(records[1]) == key {
+		t.Error("expected different records to have different keys")
+	}
+}
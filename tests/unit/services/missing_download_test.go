@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_MissingDownloadFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{ReportWrongPath: true}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "missing-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "missing-job", 5*time.Second)
+
+	if !logs.contains("download reported success but file not found at") {
+		t.Errorf("expected missing file error, got logs: %v", logs.lines)
+	}
+}
+
+func TestProcessAsync_RecoversDownloadFileFromSessionFolder(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: "header\nrow\n", ReportWrongPath: true}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "recover-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "recover-job", 5*time.Second)
+
+	if logs.contains("download reported success but file not found") {
+		t.Errorf("expected file to be recovered, got logs: %v", logs.lines)
+	}
+	if !logs.contains("recovered") || !logs.contains("user1_meisai.csv") {
+		t.Errorf("expected recovered path in logs, got: %v", logs.lines)
+	}
+}
+
+func TestProcessAsync_MissingDownloadRecoveryDisabled(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: "header\nrow\n", ReportWrongPath: true}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.RecoverMissingDownload = false
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "norecover-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "norecover-job", 5*time.Second)
+
+	if !logs.contains("download reported success but file not found at") {
+		t.Errorf("expected missing file error, got logs: %v", logs.lines)
+	}
+}
@@ -0,0 +1,48 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_FinalStatusReflectsFailedAccounts(t *testing.T) {
+	authErr := &scraper.AuthError{Reason: "invalid password"}
+	tests := []struct {
+		name        string
+		loginErrors map[string]error
+		wantStatus  string
+		wantMessage string
+	}{
+		{"all succeed", nil, "completed", ""},
+		{"some fail", map[string]error{"user2": authErr}, "partial", "1 of 3 accounts failed"},
+		{"all fail", map[string]error{"user1": authErr, "user2": authErr, "user3": authErr}, "failed", "3 of 3 accounts failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := &fakeScraperFactory{CSV: threeRowCSV, LoginErrors: tt.loginErrors}
+			svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+			svc.AccountDelay = 0
+			grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+			svc.ProcessAsync(context.Background(), "job-1", []string{"user1:pass1", "user2:pass2", "user3:pass3"}, "2024-01-01", "2024-01-31")
+			waitForJob(t, svc, "job-1", 5*time.Second)
+
+			status, err := grpcSvc.GetJobStatus(context.Background(), &pb.GetJobStatusRequest{JobId: "job-1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.Status != tt.wantStatus || status.ErrorMessage != tt.wantMessage {
+				t.Errorf("expected status=%s message=%q, got status=%s message=%q",
+					tt.wantStatus, tt.wantMessage, status.Status, status.ErrorMessage)
+			}
+			if status.CompletedAt == nil || status.Progress != 100 {
+				t.Errorf("expected finished job, got progress=%d completed_at=%v", status.Progress, status.CompletedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// createdScrapers returns how many scrapers the factory has created so far
+func (f *fakeScraperFactory) createdScrapers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.configs)
+}
+
+func TestPauseJob_MidJobAndResumeToCompletion(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: "header\nrow\n", Delay: 100 * time.Millisecond}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+
+	accounts := []string{"user1:pass1", "user2:pass2", "user3:pass3"}
+	svc.ProcessAsync(context.Background(), "pause-job", accounts, "2024-01-01", "2024-01-31")
+
+	// Pause while the first account is still downloading
+	time.Sleep(30 * time.Millisecond)
+	if err := svc.PauseJob("pause-job"); err != nil {
+		t.Fatalf("PauseJob failed: %v", err)
+	}
+	if job, _ := svc.GetJobStatus("pause-job"); job.Status != "paused" {
+		t.Errorf("expected status paused, got %s", job.Status)
+	}
+
+	// The in-flight account finishes, but no further account is started
+	time.Sleep(300 * time.Millisecond)
+	job, _ := svc.GetJobStatus("pause-job")
+	if got := factory.createdScrapers(); got != 1 {
+		t.Errorf("expected only the in-flight account to run while paused, got %d", got)
+	}
+	if len(job.AccountResults) != 1 {
+		t.Errorf("expected the in-flight account to complete, got %d results", len(job.AccountResults))
+	}
+	if job.CompletedAt != nil {
+		t.Fatalf("paused job should not complete")
+	}
+
+	if err := svc.ResumeJob("pause-job"); err != nil {
+		t.Fatalf("ResumeJob failed: %v", err)
+	}
+	job = waitForJob(t, svc, "pause-job", 5*time.Second)
+	if job.Status != "completed" || len(job.AccountResults) != len(accounts) {
+		t.Errorf("expected all accounts to complete after resume, got %s with %d results",
+			job.Status, len(job.AccountResults))
+	}
+}
+
+func TestPauseJob_InvalidStates(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+
+	if err := svc.PauseJob("missing"); !errors.Is(err, services.ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+	if err := svc.ResumeJob("missing"); !errors.Is(err, services.ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+
+	svc.ProcessAsync(context.Background(), "done-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "done-job", 5*time.Second)
+
+	if err := svc.PauseJob("done-job"); !errors.Is(err, services.ErrInvalidJobState) {
+		t.Errorf("expected ErrInvalidJobState for completed job, got %v", err)
+	}
+	if err := svc.ResumeJob("done-job"); !errors.Is(err, services.ErrInvalidJobState) {
+		t.Errorf("expected ErrInvalidJobState for completed job, got %v", err)
+	}
+}
+
+func TestPauseJob_GRPCStatusCodes(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: "header\nrow\n", Delay: 100 * time.Millisecond}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithMock(svc)
+	ctx := context.Background()
+
+	_, err := grpcSvc.PauseJob(ctx, &pb.PauseJobRequest{JobId: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+
+	svc.ProcessAsync(context.Background(), "grpc-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	_, err = grpcSvc.ResumeJob(ctx, &pb.ResumeJobRequest{JobId: "grpc-job"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition when resuming a running job, got %v", err)
+	}
+
+	paused, err := grpcSvc.PauseJob(ctx, &pb.PauseJobRequest{JobId: "grpc-job"})
+	if err != nil || paused.Status != "paused" {
+		t.Fatalf("expected paused status, got %v, %v", paused, err)
+	}
+	resumed, err := grpcSvc.ResumeJob(ctx, &pb.ResumeJobRequest{JobId: "grpc-job"})
+	if err != nil || resumed.Status != "processing" {
+		t.Fatalf("expected processing status, got %v, %v", resumed, err)
+	}
+	waitForJob(t, svc, "grpc-job", 5*time.Second)
+}
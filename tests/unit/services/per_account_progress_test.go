@@ -0,0 +1,127 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_PerAccountProgress(t *testing.T) {
+	t.Chdir(t.TempDir())
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{
+		CSV:         "header\nrow\n",
+		Gate:        gate,
+		LoginErrors: map[string]error{"user3": errors.New("bad password")},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+
+	svc.ProcessAsync(context.Background(), "progress-job", []string{"user1:pass1", "user2:pass2", "user3:pass3"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return svc.GetRuntimeMetrics().BusyWorkers == 1 })
+
+	job, _ := svc.GetJobStatus("progress-job")
+	if job.CurrentAccount != "user1" {
+		t.Errorf("expected current account user1, got %q", job.CurrentAccount)
+	}
+	want := map[string]string{"user1": "processing", "user2": "pending", "user3": "pending"}
+	for accountID, status := range want {
+		if job.PerAccount[accountID] != status {
+			t.Errorf("%s: expected %s, got %s", accountID, status, job.PerAccount[accountID])
+		}
+	}
+	for key := range job.PerAccount {
+		if key != "user1" && key != "user2" && key != "user3" {
+			t.Errorf("per-account keys must not contain passwords, got %q", key)
+		}
+	}
+
+	close(gate)
+	job = waitForJob(t, svc, "progress-job", 5*time.Second)
+	want = map[string]string{"user1": "completed", "user2": "completed", "user3": "failed"}
+	for accountID, status := range want {
+		if job.PerAccount[accountID] != status {
+			t.Errorf("%s: expected %s, got %s", accountID, status, job.PerAccount[accountID])
+		}
+	}
+	if job.CurrentAccount != "user3" {
+		t.Errorf("expected current account user3, got %q", job.CurrentAccount)
+	}
+
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+	status, err := grpcSvc.GetJobStatus(context.Background(), &pb.GetJobStatusRequest{JobId: "progress-job"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.CurrentAccount != "user3" || status.PerAccount["user3"] != "failed" || len(status.PerAccount) != 3 {
+		t.Errorf("unexpected JobStatus: current=%q per_account=%v", status.CurrentAccount, status.PerAccount)
+	}
+}
+
+func TestGetJobStatus_PerAccountIsCopied(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: "header\nrow\n", Delay: 5 * time.Millisecond}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.MaxConcurrency = 4
+	svc.AccountDelay = 0
+
+	accounts := []string{"a:p", "b:p", "c:p", "d:p", "e:p", "f:p", "g:p", "h:p"}
+	svc.ProcessAsync(context.Background(), "copy-map-job", accounts, "2024-01-01", "2024-01-31")
+
+	// Readers iterate and mutate their copies while workers update the job; -race flags torn reads
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if job, ok := svc.GetJobStatus("copy-map-job"); ok {
+					for accountID := range job.PerAccount {
+						job.PerAccount[accountID] = "tampered"
+					}
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	job := waitForJob(t, svc, "copy-map-job", 5*time.Second)
+	for accountID, status := range job.PerAccount {
+		if status != "completed" {
+			t.Errorf("%s: expected completed, got %s", accountID, status)
+		}
+	}
+}
+
+func TestProcessAsync_ProgressWithinAccount(t *testing.T) {
+	t.Chdir(t.TempDir())
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: threeRowCSV, Gate: gate, Pages: map[string]int{"user1": 2}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	logs := recordLogs(svc)
+	svc.AccountDelay = 0
+
+	svc.ProcessAsync(context.Background(), "pages-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-02-29")
+	// user1 has searched 1 of 2 pages: half of its download share out of 2 accounts
+	waitFor(t, func() bool {
+		job, _ := svc.GetJobStatus("pages-job")
+		return job.Progress > 0
+	})
+	if job, _ := svc.GetJobStatus("pages-job"); job.Progress != 22 {
+		t.Errorf("expected 22%% while user1 is on page 1/2, got %d", job.Progress)
+	}
+	if !logs.contains("Account user1: 10 rows so far (page 1/2)") {
+		t.Errorf("expected a rows-so-far log line, got %v", logs.lines)
+	}
+
+	close(gate)
+	if job := waitForJob(t, svc, "pages-job", 5*time.Second); job.Progress != 100 {
+		t.Errorf("expected 100%% at the end, got %d", job.Progress)
+	}
+}
@@ -0,0 +1,155 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services/scrapertest"
+)
+
+func TestProcessAsync_ProxyFromEnv(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_PROXY_URL", "http://proxy.example.com:8080")
+	t.Setenv("ETC_PROXY_USERNAME", "proxyuser")
+	t.Setenv("ETC_PROXY_PASSWORD", "s3cret")
+
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.ProcessAsync(context.Background(), "job-proxy", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-proxy", 5*time.Second)
+
+	cfg := factory.configs[0]
+	if cfg.ProxyURL != "http://proxy.example.com:8080" || cfg.ProxyUsername != "proxyuser" || cfg.ProxyPassword != "s3cret" {
+		t.Errorf("proxy settings not applied: %+v", cfg)
+	}
+}
+
+func TestProcessAsync_NoProxyByDefault(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_PROXY_URL", "")
+	t.Setenv("ETC_PROXY_PASSWORD", "ignored")
+
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.ProcessAsync(context.Background(), "job-noproxy", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-noproxy", 5*time.Second)
+
+	cfg := factory.configs[0]
+	if cfg.ProxyURL != "" || cfg.ProxyPassword != "" {
+		t.Errorf("expected no proxy, got %+v", cfg)
+	}
+}
+
+func TestProcessAsync_AccountProxies(t *testing.T) {
+	for name, value := range map[string]string{
+		"pairs": "user1=socks5://proxy1.example.com:1080, bad-entry",
+		"json":  `{"user1":"socks5://proxy1.example.com:1080"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			t.Setenv("ETC_PROXY_URL", "http://proxy.example.com:8080")
+			t.Setenv("ETC_PROXY_USERNAME", "proxyuser")
+			t.Setenv("ETC_PROXY_PASSWORD", "s3cret")
+			t.Setenv("ETC_ACCOUNT_PROXIES", value)
+
+			factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+			svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+			svc.AccountDelay = 0
+			svc.ProcessAsync(context.Background(), "job-account-proxy", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+			waitForJob(t, svc, "job-account-proxy", 5*time.Second)
+
+			byUser := map[string]*scraper.ScraperConfig{}
+			for _, cfg := range factory.configs {
+				byUser[cfg.UserID] = cfg
+			}
+			if cfg := byUser["user1"]; cfg.ProxyURL != "socks5://proxy1.example.com:1080" || cfg.ProxyUsername != "" || cfg.ProxyPassword != "" {
+				t.Errorf("expected account proxy for user1, got %+v", cfg)
+			}
+			if cfg := byUser["user2"]; cfg.ProxyURL != "http://proxy.example.com:8080" || cfg.ProxyUsername != "proxyuser" {
+				t.Errorf("expected global proxy for unmapped user2, got %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestProcessAsync_ProxyPoolRoundRobin(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_MAX_CONCURRENCY", "1")
+	t.Setenv("ETC_PROXY_URL", "http://proxy.example.com:8080")
+	t.Setenv("ETC_PROXY_USERNAME", "proxyuser")
+	t.Setenv("ETC_PROXY_POOL", "http://pool1.example.com:8080, not a url, http://u:p@pool2.example.com:8080")
+	t.Setenv("ETC_ACCOUNT_PROXIES", "user3=socks5://own.example.com:1080")
+
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.ProcessAsync(context.Background(), "job-pool", []string{"user1:pass1", "user2:pass2", "user3:pass3", "user4:pass4"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-pool", 5*time.Second)
+
+	var got []string
+	for _, cfg := range factory.configs {
+		got = append(got, cfg.ProxyURL+" "+cfg.ProxyUsername)
+	}
+	want := []string{
+		"http://pool1.example.com:8080 ",
+		"http://u:p@pool2.example.com:8080 ",
+		"socks5://own.example.com:1080 ", // account proxies take precedence over the pool
+		"http://pool1.example.com:8080 ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected proxies %q, got %q", want, got)
+	}
+}
+
+func TestProcessAsync_ProxyPoolSkipsFailingProxy(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_MAX_CONCURRENCY", "1")
+	t.Setenv("ETC_PROXY_POOL", "http://bad.example.com:8080,http://good.example.com:8080")
+
+	// the odd accounts always get the bad proxy until it has failed three times
+	proxyErr := errors.New("failed to navigate to top page: net::ERR_PROXY_CONNECTION_FAILED")
+	factory := &scrapertest.FakeScraperFactory{
+		Behavior: scrapertest.Behavior{CSV: threeRowCSV},
+		Accounts: map[string]scrapertest.Behavior{
+			"user1": {LoginErr: proxyErr},
+			"user3": {LoginErr: proxyErr},
+			"user5": {LoginErr: proxyErr},
+		},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.RetryBaseDelay = time.Millisecond
+	logs := recordLogs(svc)
+
+	var accounts []string
+	for i := 1; i <= 8; i++ {
+		accounts = append(accounts, fmt.Sprintf("user%d:pass%d", i, i))
+	}
+	svc.ProcessAsync(context.Background(), "job-pool", accounts, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-pool", 5*time.Second)
+
+	byUser := map[string]string{}
+	for _, s := range factory.Scrapers() {
+		byUser[s.Config().UserID] = s.Config().ProxyURL
+	}
+	for user, want := range map[string]string{
+		"user5": "http://bad.example.com:8080",
+		"user6": "http://good.example.com:8080",
+		"user7": "http://good.example.com:8080",
+		"user8": "http://good.example.com:8080",
+	} {
+		if byUser[user] != want {
+			t.Errorf("expected %s for %s, got %s", want, user, byUser[user])
+		}
+	}
+	if !logs.contains("Pooled proxy http://bad.example.com:8080 failed 3 times in a row (account user5), skipping it") ||
+		!logs.contains("Skipping pooled proxy http://bad.example.com:8080 for account user7") {
+		t.Errorf("expected the rotation decisions to be logged, got %q", logs.lines)
+	}
+}
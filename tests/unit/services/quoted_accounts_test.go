@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestGetAllAccountsWithCredentials_QuotedAccounts(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want []string
+	}{
+		{"plain", "user1:pass1,user2:pass2", []string{"user1:pass1", "user2:pass2"}},
+		{"quoted comma", `"user1:pa,ss1",user2:pass2`, []string{"user1:pa,ss1", "user2:pass2"}},
+		{"escaped quote", `"user1:pa""ss",user2:pass2`, []string{`user1:pa"ss`, "user2:pass2"}},
+		{"space after comma", `user1:pass1, "user2:p,w"`, []string{"user1:pass1", "user2:p,w"}},
+		{"bare quote in unquoted field", `user1:pa"ss,user2:pass2`, []string{`user1:pa"ss`, "user2:pass2"}},
+		{"json array", `["user1:pa,ss1","user2:pass2"]`, []string{"user1:pa,ss1", "user2:pass2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ETC_CORP_ACCOUNTS", tt.env)
+			svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+			if got := svc.GetAllAccountsWithCredentials(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetEnvironmentVariables_MasksQuotedAccounts(t *testing.T) {
+	t.Setenv("ETC_CORP_ACCOUNTS", `"user1:pa,ss1",user2:pass2`)
+	grpcSvc := services.NewDownloadServiceGRPCWithService(services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{}))
+
+	resp, err := grpcSvc.GetEnvironmentVariables(context.Background(), &pb.GetEnvironmentVariablesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.EtcCorpAccounts != "user1:*******,user2:*******" {
+		t.Errorf("unexpected masked accounts %q", resp.EtcCorpAccounts)
+	}
+	if strings.Contains(resp.EtcCorpAccounts, "ss1") {
+		t.Errorf("password fragment leaked: %s", resp.EtcCorpAccounts)
+	}
+}
+
+func TestGetEnvironmentVariables_MasksAccountFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		want    string
+		secrets []string
+	}{
+		{"json array", `["user1:pa,ss1","user2:p:w:d"]`, `["user1:*******","user2:*******"]`, []string{"ss1", "w:d"}},
+		{"json array with quotes", `["user1:pa\"ss","user2:pass2"]`, `["user1:*******","user2:*******"]`, []string{"pa", "pass2"}},
+		{"json array without password", `["user1","user2:pass2"]`, `["user1","user2:*******"]`, []string{"pass2"}},
+		{"comma separated with colons", "user1:pa:ss,user2:pw", "user1:*******,user2:*******", []string{"ss", "pw"}},
+		{"invalid json falls back to comma form", `[user1:pass1,user2:pass2`, "[user1:*******,user2:*******", []string{"pass1", "pass2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ETC_CORP_ACCOUNTS", tt.env)
+			grpcSvc := services.NewDownloadServiceGRPCWithService(services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{}))
+
+			resp, err := grpcSvc.GetEnvironmentVariables(context.Background(), &pb.GetEnvironmentVariablesRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.EtcCorpAccounts != tt.want {
+				t.Errorf("got %q, want %q", resp.EtcCorpAccounts, tt.want)
+			}
+			for _, secret := range tt.secrets {
+				if strings.Contains(resp.EtcCorpAccounts, secret) {
+					t.Errorf("password fragment %q leaked: %s", secret, resp.EtcCorpAccounts)
+				}
+			}
+		})
+	}
+}
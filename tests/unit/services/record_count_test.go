@@ -0,0 +1,122 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+const threeRowCSV = "利用年月日（自）,通行料金\n2024/01/05,1200\n2024/01/06,800\n2024/01/07,950\n"
+
+// otherThreeRowCSV has three usages different from threeRowCSV, for a second account
+// whose records must not be merged away as duplicates
+const otherThreeRowCSV = "利用年月日（自）,通行料金\n2024/01/15,1200\n2024/01/16,800\n2024/01/17,950\n"
+
+func TestProcessAsync_RecordCountMismatch(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{
+		CSV:      threeRowCSV,
+		Expected: map[string]int{"user1": 5, "user2": 3},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "count-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "count-job", 5*time.Second)
+
+	if len(job.AccountResults) != 2 {
+		t.Fatalf("expected 2 account results, got %d", len(job.AccountResults))
+	}
+	results := map[string]services.AccountResult{}
+	for _, r := range job.AccountResults {
+		results[r.AccountID] = r
+	}
+
+	mismatched := results["user1"]
+	if !mismatched.ExpectedKnown || mismatched.ExpectedRecords != 5 || mismatched.ActualRecords != 3 {
+		t.Errorf("unexpected result for user1: %+v", mismatched)
+	}
+	if !mismatched.CountMismatch {
+		t.Errorf("expected user1 to be flagged as mismatched")
+	}
+
+	matched := results["user2"]
+	if matched.CountMismatch || matched.ActualRecords != 3 {
+		t.Errorf("unexpected result for user2: %+v", matched)
+	}
+
+	if job.TotalRecords != 6 {
+		t.Errorf("expected TotalRecords 6, got %d", job.TotalRecords)
+	}
+	if !logs.contains("Record count mismatch for account user1") {
+		t.Errorf("expected mismatch to be logged, got: %v", logs.lines)
+	}
+}
+
+func TestProcessAsync_UnknownExpectedCountIsNotMismatch(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+
+	svc.ProcessAsync(context.Background(), "unknown-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "unknown-job", 5*time.Second)
+
+	if len(job.AccountResults) != 1 {
+		t.Fatalf("expected 1 account result, got %d", len(job.AccountResults))
+	}
+	result := job.AccountResults[0]
+	if result.ExpectedKnown || result.CountMismatch || result.ActualRecords != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestGetJobStatus_AccountResultsAreCopied(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+
+	svc.ProcessAsync(context.Background(), "copy-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "copy-job", 5*time.Second)
+	job.AccountResults[0].ActualRecords = 99
+
+	again, _ := svc.GetJobStatus("copy-job")
+	if again.AccountResults[0].ActualRecords != 3 {
+		t.Errorf("GetJobStatus should return a copy of account results")
+	}
+
+	// sanity check that the CSV actually landed in the session folder
+	matches, _ := filepath.Glob(filepath.Join("downloads", "*", "user1_meisai.csv"))
+	if len(matches) != 1 {
+		t.Fatalf("expected one CSV in the session folder, got %v", matches)
+	}
+	if _, err := os.Stat(matches[0]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcessAsync_WarnsWhenSiteSearchedNarrowerRange(t *testing.T) {
+	t.Chdir(t.TempDir())
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	factory := &fakeScraperFactory{
+		CSV:      threeRowCSV,
+		Searched: map[string][2]time.Time{"user1": {day(15), day(31)}, "user2": {day(1), day(31)}},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "range-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "range-job", 5*time.Second)
+
+	if !logs.contains("Site searched 2024-01-15 to 2024-01-31 for account user1 instead of the requested 2024-01-01 to 2024-01-31") {
+		t.Errorf("expected the narrower range to be logged, got: %v", logs.lines)
+	}
+	if logs.contains("for account user2 instead of") {
+		t.Errorf("expected no warning for the account whose range was searched in full, got: %v", logs.lines)
+	}
+}
@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func newRetryTestService(t *testing.T, factory *fakeScraperFactory) *services.DownloadService {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.RetryBaseDelay = time.Millisecond
+	return svc
+}
+
+func TestProcessAsync_RetriesTransientLoginAndDownloadErrors(t *testing.T) {
+	factory := &fakeScraperFactory{
+		CSV:              "header\nrow\n",
+		LoginTimeouts:    map[string]int{"user1": 2},
+		DownloadTimeouts: map[string]int{"user1": 1},
+	}
+	svc := newRetryTestService(t, factory)
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "retry-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "retry-job", 5*time.Second)
+
+	if job.PerAccount["user1"] != "completed" {
+		t.Fatalf("expected account to succeed after retries, got %s", job.PerAccount["user1"])
+	}
+	loginCalls := factory.loginAttempts("user1")
+	downloadCalls := factory.downloadAttempts("user1")
+	if loginCalls != 3 || downloadCalls != 2 {
+		t.Errorf("expected 3 login and 2 download attempts, got %d and %d", loginCalls, downloadCalls)
+	}
+	if !logs.contains("Login attempt 1/4 failed for account user1") ||
+		!logs.contains("Login attempt 2/4 failed") ||
+		!logs.contains("Download attempt 1/4 failed") {
+		t.Errorf("expected each failed attempt to be logged, got: %v", logs.lines)
+	}
+}
+
+func TestProcessAsync_GivesUpAfterRetryCount(t *testing.T) {
+	factory := &fakeScraperFactory{
+		CSV:           "header\nrow\n",
+		LoginTimeouts: map[string]int{"user1": 10},
+	}
+	svc := newRetryTestService(t, factory)
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "exhausted-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "exhausted-job", 5*time.Second)
+
+	if job.PerAccount["user1"] != "failed" {
+		t.Errorf("expected account to fail, got %s", job.PerAccount["user1"])
+	}
+	if got := factory.loginAttempts("user1"); got != 4 {
+		t.Errorf("expected 1 attempt + 3 retries, got %d", got)
+	}
+	if !logs.contains("Login failed after 4 attempts") {
+		t.Errorf("expected exhausted retries to be reported, got: %v", logs.lines)
+	}
+}
+
+func TestProcessAsync_AuthFailureIsNotRetried(t *testing.T) {
+	factory := &fakeScraperFactory{
+		CSV:         "header\nrow\n",
+		LoginErrors: map[string]error{"user1": errors.New("login failed: パスワードが違います")},
+	}
+	svc := newRetryTestService(t, factory)
+
+	svc.ProcessAsync(context.Background(), "auth-job", []string{"user1:wrong"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "auth-job", 5*time.Second)
+
+	if job.PerAccount["user1"] != "failed" {
+		t.Errorf("expected account to fail, got %s", job.PerAccount["user1"])
+	}
+	if got := factory.loginAttempts("user1"); got != 1 {
+		t.Errorf("authentication failures must fail fast, got %d login attempts", got)
+	}
+}
+
+func TestProcessAsync_LogsRetryBudgetSummary(t *testing.T) {
+	factory := &fakeScraperFactory{
+		CSV:              "header\nrow\n",
+		LoginTimeouts:    map[string]int{"user1": 2, "user2": 10},
+		DownloadTimeouts: map[string]int{"user1": 1},
+	}
+	svc := newRetryTestService(t, factory)
+	svc.InitRetryCount = 2
+
+	svc.ProcessAsync(context.Background(), "summary-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "summary-job", 5*time.Second)
+
+	entries, err := svc.GetJobLogs("summary-job", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for _, entry := range entries {
+		messages = append(messages, entry.Message)
+	}
+	logs := strings.Join(messages, "\n")
+	for _, want := range []string{
+		"Account user1 finished as completed: 6 attempts (initialize=1 login=3 download=2), 3/8 retries used",
+		"Account user2 finished as failed: 5 attempts (initialize=1 login=4 download=0), 3/8 retries used",
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("expected %q in the job logs, got:\n%s", want, logs)
+		}
+	}
+
+	// the final line alone tells how every account went
+	last := messages[len(messages)-1]
+	if !strings.HasPrefix(last, "Finished download job summary-job as partial: 1 of 2 accounts failed [") ||
+		!strings.Contains(last, "user1 completed (6 attempts, 3/8 retries, ") ||
+		!strings.Contains(last, "; user2 failed (5 attempts, 3/8 retries, ") {
+		t.Errorf("expected the final line to summarize each account, got %q", last)
+	}
+}
+
+func TestGetRetryBaseDelay_FromEnv(t *testing.T) {
+	tests := []struct {
+		env  string
+		want time.Duration
+	}{
+		{"", 2 * time.Second},
+		{"500", 500 * time.Millisecond},
+		{"0", 0},
+		{"-1", 2 * time.Second},
+		{"abc", 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Setenv("ETC_RETRY_BASE_DELAY_MS", tt.env)
+		svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+		if svc.RetryBaseDelay != tt.want {
+			t.Errorf("ETC_RETRY_BASE_DELAY_MS=%q: expected %v, got %v", tt.env, tt.want, svc.RetryBaseDelay)
+		}
+	}
+}
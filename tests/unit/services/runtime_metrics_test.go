@@ -0,0 +1,83 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestGetRuntimeMetrics_ReflectsRunningJobsAndWorkers(t *testing.T) {
+	t.Chdir(t.TempDir())
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: "header\nrow\n", Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.MaxConcurrency = 2
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	// Job A: 3 accounts on 2 workers, job B: 1 account on 1 worker
+	svc.ProcessAsync(context.Background(), "job-a", []string{"a1:p", "a2:p", "a3:p"}, "2024-01-01", "2024-01-31")
+	svc.ProcessAsync(context.Background(), "job-b", []string{"b1:p"}, "2024-01-01", "2024-01-31")
+
+	waitFor(t, func() bool { return svc.GetRuntimeMetrics().BusyWorkers == 3 })
+	m := svc.GetRuntimeMetrics()
+	want := services.RuntimeMetrics{
+		RunningJobs:         2,
+		QueuedAccounts:      1,
+		BusyWorkers:         3,
+		IdleWorkers:         0,
+		ActiveBrowsers:      3,
+		RateBudgetRemaining: -1,
+	}
+	if m != want {
+		t.Errorf("unexpected snapshot while blocked:\n got  %+v\n want %+v", m, want)
+	}
+
+	resp, err := grpcSvc.GetRuntimeMetrics(context.Background(), &pb.GetRuntimeMetricsRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.RunningJobs != 2 || resp.BusyWorkers != 3 || resp.LogBufferCapacity != 1000 || resp.LogBufferLines == 0 {
+		t.Errorf("unexpected RPC snapshot: %+v", resp)
+	}
+
+	close(gate)
+	waitForJob(t, svc, "job-a", 5*time.Second)
+	waitForJob(t, svc, "job-b", 5*time.Second)
+	waitFor(t, func() bool { return svc.GetRuntimeMetrics().IdleWorkers == 0 })
+
+	m = svc.GetRuntimeMetrics()
+	if m != (services.RuntimeMetrics{RateBudgetRemaining: -1}) {
+		t.Errorf("expected an empty snapshot after all jobs finished, got %+v", m)
+	}
+}
+
+func TestGetRuntimeMetrics_PausedJobWorkersAreIdle(t *testing.T) {
+	t.Chdir(t.TempDir())
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: "header\nrow\n", Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+
+	svc.ProcessAsync(context.Background(), "paused-job", []string{"u1:p", "u2:p"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return svc.GetRuntimeMetrics().BusyWorkers == 1 })
+	if err := svc.PauseJob("paused-job"); err != nil {
+		t.Fatal(err)
+	}
+	close(gate)
+
+	// The in-flight account finishes and the worker then waits on the pause
+	waitFor(t, func() bool { return svc.GetRuntimeMetrics().IdleWorkers == 1 })
+	m := svc.GetRuntimeMetrics()
+	if m.PausedJobs != 1 || m.RunningJobs != 0 || m.BusyWorkers != 0 || m.ActiveBrowsers != 0 {
+		t.Errorf("unexpected snapshot for paused job: %+v", m)
+	}
+
+	if err := svc.ResumeJob("paused-job"); err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, "paused-job", 5*time.Second)
+}
@@ -0,0 +1,64 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestNewScraperFactory_BrowserPoolSize(t *testing.T) {
+	tests := []struct {
+		env    string
+		pooled bool
+	}{
+		{"", false},
+		{"0", false},
+		{"-1", false},
+		{"many", false},
+		{"2", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("ETC_BROWSER_POOL_SIZE", tt.env)
+		factory := services.NewScraperFactory()
+		_, pooled := factory.(*services.PooledScraperFactory)
+		if pooled != tt.pooled {
+			t.Errorf("ETC_BROWSER_POOL_SIZE=%q: expected pooled=%v, got %T", tt.env, tt.pooled, factory)
+		}
+	}
+}
+
+// closingScraperFactory records when the service closes it
+type closingScraperFactory struct {
+	*fakeScraperFactory
+	closed chan struct{}
+}
+
+func (f *closingScraperFactory) Close() error {
+	close(f.closed)
+	return nil
+}
+
+func TestShutdown_ClosesScraperFactory(t *testing.T) {
+	factory := &closingScraperFactory{
+		fakeScraperFactory: &fakeScraperFactory{CSV: threeRowCSV, Delay: 50 * time.Millisecond},
+		closed:             make(chan struct{}),
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+
+	svc.ProcessAsync(context.Background(), "pool-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+
+	if err := svc.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-factory.closed:
+	default:
+		t.Fatal("expected Shutdown to close the scraper factory")
+	}
+	if job, _ := svc.GetJobStatus("pool-job"); job.CompletedAt == nil {
+		t.Error("expected the factory to be closed only after the running job finished")
+	}
+}
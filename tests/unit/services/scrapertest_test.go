@@ -0,0 +1,164 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/scraper"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services/scrapertest"
+)
+
+func TestScraperTest_PerAccountBehavior(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &scrapertest.FakeScraperFactory{
+		Behavior: scrapertest.Behavior{CSV: threeRowCSV},
+		Accounts: map[string]scrapertest.Behavior{
+			"user2": {LoginErr: &scraper.AuthError{Reason: "invalid password"}},
+			// the first download times out and the retry succeeds
+			"user3": {DownloadErr: errors.New("download timeout after 60 seconds"), DownloadFailures: 1},
+		},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.RetryBaseDelay = time.Millisecond
+
+	svc.ProcessAsync(context.Background(), "fake-job", []string{"user1:pass1", "user2:pass2", "user3:pass3"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "fake-job", 5*time.Second)
+
+	if job.Status != "partial" || len(job.FailedAccounts) != 1 || job.FailedAccounts[0].AccountID != "user2" {
+		t.Errorf("expected user2 to fail and the job to be partial, got %s %+v", job.Status, job.FailedAccounts)
+	}
+	if job.TotalRecords != 6 {
+		t.Errorf("expected 3 records from each of user1 and user3, got %d", job.TotalRecords)
+	}
+	if calls := factory.Calls("user2"); calls.Login != 1 || calls.Download != 0 || calls.Close != 1 {
+		t.Errorf("expected an auth error not to be retried, got %+v", calls)
+	}
+	if calls := factory.Calls("user3"); calls.Download != 2 {
+		t.Errorf("expected the timed out download to be retried once, got %+v", calls)
+	}
+	if got := len(factory.Scrapers()); got != 3 {
+		t.Errorf("expected one scraper per account, got %d", got)
+	}
+}
+
+func TestScraperTest_LatencyIsInterruptedByClose(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &scrapertest.FakeScraperFactory{Behavior: scrapertest.Behavior{Latency: time.Minute}}
+	s, err := factory.CreateScraper(&scraper.ScraperConfig{UserID: "user1", SessionFolder: t.TempDir()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.DownloadMeisai("2024-01-01", "2024-01-31")
+		done <- err
+	}()
+	waitFor(t, func() bool { return factory.Calls("user1").Download == 1 })
+	s.Close()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, scrapertest.ErrClosed) {
+			t.Errorf("expected ErrClosed, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("DownloadMeisai did not return after Close")
+	}
+}
+
+func TestDownload_SkipsLoginForValidSession(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &scrapertest.FakeScraperFactory{
+		Behavior: scrapertest.Behavior{CSV: threeRowCSV},
+		Accounts: map[string]scrapertest.Behavior{
+			"user1": {LoggedIn: true},
+			// an inconclusive check falls back to logging in
+			"user2": {LoggedInErr: errors.New("page closed")},
+		},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	logs := recordLogs(svc)
+
+	svc.ProcessAsync(context.Background(), "session-job", []string{"user1:pass1", "user2:pass2", "user3:pass3"}, "2024-01-01", "2024-01-31")
+	if job := waitForJob(t, svc, "session-job", 5*time.Second); job.Status != "completed" || job.TotalRecords != 9 {
+		t.Fatalf("expected all accounts to download, got %s with %d records", job.Status, job.TotalRecords)
+	}
+	if calls := factory.Calls("user1"); calls.Login != 0 || calls.Download != 1 {
+		t.Errorf("expected the logged-in session to be reused, got %+v", calls)
+	}
+	for _, account := range []string{"user2", "user3"} {
+		if calls := factory.Calls(account); calls.Login != 1 {
+			t.Errorf("expected %s to log in, got %+v", account, calls)
+		}
+	}
+	if !logs.contains("Reusing logged-in session for account user1") || !logs.contains("Could not check session for account user2") {
+		t.Errorf("expected the session checks to be logged, got %q", logs.lines)
+	}
+}
+
+func TestDownload_RecordsCertificatePaths(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &scrapertest.FakeScraperFactory{
+		Behavior: scrapertest.Behavior{CSV: threeRowCSV},
+		Accounts: map[string]scrapertest.Behavior{
+			"user2": {CertificateErr: errors.New("usage certificate link not found")},
+		},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+
+	svc.ProcessAsync(context.Background(), "plain-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "plain-job", 5*time.Second)
+	if calls := factory.Calls("user1"); calls.Certificates != 0 {
+		t.Errorf("expected no certificates without the option, got %+v", calls)
+	}
+
+	svc.ProcessAsyncWithOptions(context.Background(), "cert-job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31",
+		services.JobOptions{DownloadCertificates: true})
+	job := waitForJob(t, svc, "cert-job", 5*time.Second)
+	if job.Status != "completed" || job.TotalRecords != 6 {
+		t.Fatalf("expected a failed certificate not to fail the account, got %s with %d records", job.Status, job.TotalRecords)
+	}
+	for _, r := range job.AccountResults {
+		switch r.AccountID {
+		case "user1":
+			if len(r.CertificatePaths) != 1 || filepath.Base(r.CertificatePaths[0]) != "user1_certificate.pdf" || r.CertificatesFailed {
+				t.Errorf("expected the certificate path of user1, got %+v", r)
+			}
+		case "user2":
+			if len(r.CertificatePaths) != 0 || !r.CertificatesFailed {
+				t.Errorf("expected the certificate failure of user2, got %+v", r)
+			}
+		}
+	}
+}
+
+func TestDownload_CountsSavedBytesAndFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &scrapertest.FakeScraperFactory{
+		Behavior: scrapertest.Behavior{CSV: threeRowCSV},
+		Accounts: map[string]scrapertest.Behavior{
+			"user2": {CertificateErr: errors.New("usage certificate link not found")},
+			"user3": {DownloadErr: errors.New("download failed")},
+		},
+	}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+
+	svc.ProcessAsyncWithOptions(context.Background(), "sized-job", []string{"user1:pass1", "user2:pass2", "user3:pass3"}, "2024-01-01", "2024-01-31",
+		services.JobOptions{DownloadCertificates: true})
+	job := waitForJob(t, svc, "sized-job", 5*time.Second)
+
+	// two CSVs and the certificate of user1; the failed account saves nothing
+	wantBytes := int64(2*len(threeRowCSV) + len("%PDF-1.4\n"))
+	if job.TotalBytes != wantBytes || job.FileCount != 3 {
+		t.Errorf("expected %d bytes in 3 files, got %d bytes in %d files", wantBytes, job.TotalBytes, job.FileCount)
+	}
+}
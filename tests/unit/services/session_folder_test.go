@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services/scrapertest"
+)
+
+func TestProcessAsync_JobsInSameSecondGetDistinctSessionFolders(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_DOWNLOAD_DIR", t.TempDir())
+
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.ProcessAsync(context.Background(), "job-a", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	svc.ProcessAsync(context.Background(), "job-b", []string{"user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-a", 5*time.Second)
+	waitForJob(t, svc, "job-b", 5*time.Second)
+
+	folders := map[string]string{}
+	for _, cfg := range factory.configs {
+		folders[cfg.UserID] = cfg.SessionFolder
+	}
+	if folders["user1"] == folders["user2"] {
+		t.Fatalf("expected distinct session folders, both jobs used %s", folders["user1"])
+	}
+	if !strings.Contains(filepath.Base(folders["user1"]), "_job-a_") || !strings.Contains(filepath.Base(folders["user2"]), "_job-b_") {
+		t.Errorf("expected session folders named after the jobs, got %v", folders)
+	}
+	for userID, folder := range folders {
+		entries, err := os.ReadDir(folder)
+		if err != nil || len(entries) != 1 || entries[0].Name() != userID+"_meisai.csv" {
+			t.Errorf("expected only %s's CSV in %s, got %v (%v)", userID, folder, entries, err)
+		}
+	}
+}
+
+func TestProcessAsync_SessionFolderStaysUnderDownloadDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+	base := t.TempDir()
+	t.Setenv("ETC_DOWNLOAD_DIR", base)
+
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.ProcessAsync(context.Background(), "../escape/job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "../escape/job", 5*time.Second)
+
+	if folder := factory.configs[0].SessionFolder; filepath.Dir(folder) != base {
+		t.Errorf("expected session folder directly under %s, got %s", base, folder)
+	}
+}
+
+func TestProcessAsync_RejectsAccountIDsThatTraverse(t *testing.T) {
+	t.Chdir(t.TempDir())
+	root := t.TempDir()
+	base := filepath.Join(root, "downloads")
+	t.Setenv("ETC_DOWNLOAD_DIR", base)
+
+	factory := &scrapertest.FakeScraperFactory{Behavior: scrapertest.Behavior{CSV: threeRowCSV}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	accounts := []string{"../../escape:pass1", "..:pass2", `sub\..\..\escape:pass3`, "user1:pass4"}
+	svc.ProcessAsync(context.Background(), "traverse-job", accounts, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "traverse-job", 5*time.Second)
+
+	if job.Status != "partial" || len(job.FailedAccounts) != 3 {
+		t.Errorf("expected the 3 malicious accounts to fail, got %s %+v", job.Status, job.FailedAccounts)
+	}
+	if got := len(factory.Scrapers()); got != 1 {
+		t.Errorf("expected a scraper only for user1, got %d", got)
+	}
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !strings.HasPrefix(path, base+string(filepath.Separator)) {
+			t.Errorf("expected every file under %s, found %s", base, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if errs := services.ValidateAccounts(accounts); len(errs) != 3 {
+		t.Errorf("expected ValidateAccounts to reject the 3 malicious accounts, got %v", errs)
+	}
+}
@@ -0,0 +1,110 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestShutdown_WaitsForInFlightAccountAndCancelsRest(t *testing.T) {
+	t.Chdir(t.TempDir())
+	gate := make(chan struct{})
+	factory := &fakeScraperFactory{CSV: "header\nrow\n", Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+
+	svc.ProcessAsync(context.Background(), "job", []string{"user1:pass1", "user2:pass2", "user3:pass3"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+
+	done := make(chan error, 1)
+	go func() { done <- svc.Shutdown(context.Background()) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Shutdown returned before the in-flight account finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gate)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Shutdown failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return")
+	}
+
+	job, _ := svc.GetJobStatus("job")
+	if job.Status != "cancelled" || job.CompletedAt == nil {
+		t.Errorf("expected cancelled job, got status=%s completed_at=%v", job.Status, job.CompletedAt)
+	}
+	if job.PerAccount["user1"] != "completed" {
+		t.Errorf("in-flight account should complete, got %v", job.PerAccount)
+	}
+	if got := factory.createdScrapers(); got != 1 {
+		t.Errorf("no further accounts should start after Shutdown, got %d scrapers", got)
+	}
+	if m := svc.GetRuntimeMetrics(); m.QueuedAccounts != 0 || m.BusyWorkers != 0 {
+		t.Errorf("runtime counters not released: %+v", m)
+	}
+}
+
+func TestShutdown_TimesOut(t *testing.T) {
+	t.Chdir(t.TempDir())
+	gate := make(chan struct{})
+	defer close(gate)
+	factory := &fakeScraperFactory{CSV: "header\nrow\n", Gate: gate}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+
+	svc.ProcessAsync(context.Background(), "job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := svc.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestShutdown_CancelsPausedJob(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: "header\nrow\n", Delay: 50 * time.Millisecond}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+
+	svc.ProcessAsync(context.Background(), "job", []string{"user1:pass1", "user2:pass2"}, "2024-01-01", "2024-01-31")
+	waitFor(t, func() bool { return factory.createdScrapers() == 1 })
+	if err := svc.PauseJob("job"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := svc.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if job, _ := svc.GetJobStatus("job"); job.Status != "cancelled" {
+		t.Errorf("expected cancelled job, got %s", job.Status)
+	}
+}
+
+func TestShutdown_RejectsNewJobs(t *testing.T) {
+	factory := &fakeScraperFactory{}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	if err := svc.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	svc.ProcessAsync(context.Background(), "late-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+	job, ok := svc.GetJobStatus("late-job")
+	if !ok || job.Status != "failed" || job.ErrorMessage != services.ErrShuttingDown.Error() {
+		t.Errorf("expected rejected job, got %+v", job)
+	}
+	if factory.createdScrapers() != 0 {
+		t.Error("no scraper should be created after Shutdown")
+	}
+}
@@ -0,0 +1,88 @@
+package services_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func processedUserIDs(f *fakeScraperFactory) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, 0, len(f.configs))
+	for _, c := range f.configs {
+		ids = append(ids, c.UserID)
+	}
+	return ids
+}
+
+func TestProcessAsync_SortAccounts(t *testing.T) {
+	accounts := []string{"charlie:pass:with:colons", "alice:pass1", "bob:pass2"}
+
+	tests := []struct {
+		name string
+		env  string
+		opts services.JobOptions
+		want []string
+	}{
+		{"arrival order by default", "", services.JobOptions{}, []string{"charlie", "alice", "bob"}},
+		{"option", "", services.JobOptions{SortAccounts: true}, []string{"alice", "bob", "charlie"}},
+		{"env", "true", services.JobOptions{}, []string{"alice", "bob", "charlie"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ETC_SORT_ACCOUNTS", tt.env)
+			t.Setenv("ETC_MAX_CONCURRENCY", "1")
+			factory := &fakeScraperFactory{CSV: threeRowCSV}
+			svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+			svc.AccountDelay = 0
+
+			input := append([]string(nil), accounts...)
+			svc.ProcessAsyncWithOptions(context.Background(), "sort-job", input, "2024-01-01", "2024-01-31", tt.opts)
+			job := waitForJob(t, svc, "sort-job", 5*time.Second)
+
+			if got := processedUserIDs(factory); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected processing order %v, got %v", tt.want, got)
+			}
+			if !reflect.DeepEqual(input, accounts) {
+				t.Errorf("expected caller's slice to be left unchanged, got %v", input)
+			}
+			if job.Status != "completed" || job.PerAccount["charlie"] != "completed" {
+				t.Errorf("expected all accounts to complete, got %s %v", job.Status, job.PerAccount)
+			}
+		})
+	}
+}
+
+func TestProcessAsync_RotatesAccountsAcrossJobs(t *testing.T) {
+	accounts := []string{"alice:pass1", "bob:pass2", "charlie:pass3"}
+
+	tests := []struct {
+		name string
+		env  string
+		want []string
+	}{
+		{"rotation by default", "", []string{"alice", "bob", "charlie", "bob", "charlie", "alice", "charlie", "alice", "bob"}},
+		{"opt out", "false", []string{"alice", "bob", "charlie", "alice", "bob", "charlie", "alice", "bob", "charlie"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ETC_ROTATE_ACCOUNTS", tt.env)
+			t.Setenv("ETC_MAX_CONCURRENCY", "1")
+			factory := &fakeScraperFactory{CSV: threeRowCSV}
+			svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+			svc.AccountDelay = 0
+
+			for _, jobID := range []string{"job-1", "job-2", "job-3"} {
+				svc.ProcessAsync(context.Background(), jobID, accounts, "2024-01-01", "2024-01-31")
+				waitForJob(t, svc, jobID, 5*time.Second)
+			}
+			if got := processedUserIDs(factory); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected processing order %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
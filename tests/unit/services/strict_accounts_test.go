@@ -0,0 +1,89 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDownloadAsync_StrictAccountsRejectsMalformedEntries(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	grpcSvc := services.NewDownloadServiceGRPCWithMock(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts:       []string{"user1:pass1", "broken", ":nouser"},
+		StrictAccounts: true,
+	})
+	if resp != nil {
+		t.Errorf("expected no response, got %+v", resp)
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	msg := status.Convert(err).Message()
+	if !strings.Contains(msg, "accounts[1]") || !strings.Contains(msg, "accounts[2]") {
+		t.Errorf("expected both bad entries to be listed, got %q", msg)
+	}
+	if strings.Contains(msg, "accounts[0]") {
+		t.Errorf("valid entry should not be listed, got %q", msg)
+	}
+	if strings.Contains(msg, "nouser") {
+		t.Errorf("password should be masked, got %q", msg)
+	}
+
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+	if len(factory.configs) != 0 {
+		t.Errorf("no job should have started, but %d scrapers were created", len(factory.configs))
+	}
+}
+
+func TestDownloadAsync_NonStrictSkipsMalformedEntriesWithWarnings(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: "header\nrow\n"}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	grpcSvc := services.NewDownloadServiceGRPCWithMock(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts: []string{"user1:pass1", "broken", "user2:pass2"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.JobId == "" || resp.Status != "pending" {
+		t.Fatalf("expected job to start, got %+v", resp)
+	}
+	if len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "accounts[1]") {
+		t.Errorf("expected one warning for accounts[1], got %v", resp.Warnings)
+	}
+
+	job := waitForJob(t, svc, resp.JobId, 5*time.Second)
+	if len(job.AccountResults) != 2 {
+		t.Errorf("expected the 2 valid accounts to be processed, got %d", len(job.AccountResults))
+	}
+}
+
+func TestDownloadAsync_NonStrictWithOnlyMalformedEntries(t *testing.T) {
+	factory := &fakeScraperFactory{}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	grpcSvc := services.NewDownloadServiceGRPCWithMock(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts: []string{"broken"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.JobId != "" || resp.Status != "failed" || len(resp.Warnings) != 1 {
+		t.Errorf("expected failed response with a warning, got %+v", resp)
+	}
+}
@@ -0,0 +1,46 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestDownloadSync_DeadlineReturnsPartialRecords(t *testing.T) {
+	t.Chdir(t.TempDir())
+	factory := &fakeScraperFactory{CSV: meisaiCSV("\n"), Delays: map[string]time.Duration{"user2": 2 * time.Second}}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.AccountDelay = 0
+	svc.MaxConcurrency = 1
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	resp, err := grpcSvc.DownloadSync(ctx, &pb.DownloadRequest{Accounts: []string{"user1:pass1", "user2:pass2"}})
+	if err != nil {
+		t.Fatalf("expected partial results before the deadline, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed >= 1500*time.Millisecond {
+		t.Errorf("expected a response before the deadline, took %v", elapsed)
+	}
+	if !resp.Truncated || resp.Success || resp.ErrorCode != pb.ErrorCode_ERROR_CODE_DEADLINE_EXCEEDED {
+		t.Errorf("expected a truncated response, got success=%t truncated=%t code=%s", resp.Success, resp.Truncated, resp.ErrorCode)
+	}
+	if len(resp.Records) != len(meisaiCSVRows) || resp.RecordCount != int32(len(meisaiCSVRows)) {
+		t.Errorf("expected user1's %d records, got %d", len(meisaiCSVRows), len(resp.Records))
+	}
+
+	// 中止したジョブが処理中のアカウントを終えるまで待ってから設定を確認する
+	if err := svc.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, cfg := range factory.configs {
+		if cfg.Timeout <= 0 || cfg.Timeout > 1500 {
+			t.Errorf("expected the Playwright timeout of %s to be bounded by the deadline, got %vms", cfg.UserID, cfg.Timeout)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTimeout_FromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 30 * time.Second},
+		{"60000", 60 * time.Second},
+		{"1000", 30 * time.Second},
+		{"600000", 30 * time.Second},
+		{"abc", 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Setenv("ETC_TIMEOUT_MS", tt.value)
+		svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+		if svc.Timeout != tt.want {
+			t.Errorf("ETC_TIMEOUT_MS=%q: expected %v, got %v", tt.value, tt.want, svc.Timeout)
+		}
+	}
+}
+
+func TestDownloadAsync_TimeoutOverride(t *testing.T) {
+	t.Setenv("ETC_TIMEOUT_MS", "60000")
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+	logs := recordLogs(svc)
+
+	resp, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+		Accounts:  []string{"user1:pass1"},
+		TimeoutMs: 120000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, resp.JobId, 5*time.Second)
+
+	if got := factory.configs[0].Timeout; got != 120000 {
+		t.Errorf("expected scraper timeout 120000ms, got %v", got)
+	}
+	if !logs.contains("Using Playwright timeout 2m0s for account user1") {
+		t.Error("expected effective timeout to be logged")
+	}
+
+	resp, err = grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForJob(t, svc, resp.JobId, 5*time.Second)
+	if got := factory.configs[1].Timeout; got != 60000 {
+		t.Errorf("expected ETC_TIMEOUT_MS default 60000ms, got %v", got)
+	}
+}
+
+func TestDownloadAsync_TimeoutOutOfRange(t *testing.T) {
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{})
+	grpcSvc := services.NewDownloadServiceGRPCWithService(svc)
+
+	for _, timeoutMs := range []int32{1000, 301000, -1} {
+		_, err := grpcSvc.DownloadAsync(context.Background(), &pb.DownloadRequest{
+			Accounts:  []string{"user1:pass1"},
+			TimeoutMs: timeoutMs,
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("timeout_ms=%d: expected InvalidArgument, got %v", timeoutMs, err)
+		}
+	}
+}
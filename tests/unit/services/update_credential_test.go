@@ -0,0 +1,79 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdateAccountCredential_OverridesEnvPassword(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:old-pw,user2:pass2")
+
+	factory := &fakeScraperFactory{CSV: threeRowCSV}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	if err := svc.UpdateAccountCredential("user1", "new:pw"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(svc.GetAllAccountsWithCredentials(), ",")
+	if got != "user1:new:pw,user2:pass2" {
+		t.Errorf("expected the override on top of env accounts, got %s", got)
+	}
+
+	svc.ProcessAsync(context.Background(), "job-1", svc.GetAllAccountsWithCredentials(), "2024-01-01", "2024-01-31")
+	waitForJob(t, svc, "job-1", 5*time.Second)
+	for _, cfg := range factory.configs {
+		if cfg.UserID == "user1" && cfg.Password != "new:pw" {
+			t.Errorf("expected the job to log in with the updated password, got %q", cfg.Password)
+		}
+	}
+}
+
+func TestUpdateCredential_Errors(t *testing.T) {
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:pass1")
+	grpcSvc := services.NewDownloadServiceGRPCWithService(services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{}))
+
+	tests := []struct {
+		name string
+		req  *pb.UpdateCredentialRequest
+		code codes.Code
+	}{
+		{"unknown account", &pb.UpdateCredentialRequest{AccountId: "user9", NewPassword: "pw"}, codes.NotFound},
+		{"empty password", &pb.UpdateCredentialRequest{AccountId: "user1"}, codes.InvalidArgument},
+		{"empty account", &pb.UpdateCredentialRequest{NewPassword: "pw"}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := grpcSvc.UpdateCredential(context.Background(), tt.req)
+			if status.Code(err) != tt.code {
+				t.Errorf("expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestGetEnvironmentVariables_MasksCredentialOverrides(t *testing.T) {
+	t.Setenv("ETC_CORP_ACCOUNTS", "user1:pass1")
+	grpcSvc := services.NewDownloadServiceGRPCWithService(services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{}))
+
+	if _, err := grpcSvc.UpdateCredential(context.Background(), &pb.UpdateCredentialRequest{AccountId: "user1", NewPassword: "s3cret-pw"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := grpcSvc.GetEnvironmentVariables(context.Background(), &pb.GetEnvironmentVariablesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.CredentialOverrides) != 1 || resp.CredentialOverrides[0] != "user1:*******" {
+		t.Errorf("expected the masked override, got %v", resp.CredentialOverrides)
+	}
+	if strings.Contains(resp.String(), "s3cret-pw") {
+		t.Errorf("password exposed in response: %s", resp)
+	}
+}
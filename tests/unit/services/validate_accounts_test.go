@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateAccounts(t *testing.T) {
+	errs := services.ValidateAccounts([]string{"user1:pass1", "nocolon", ":secret", "user2:", "user3:pa:ss"})
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", errs)
+	}
+
+	wantIndexes := []int{1, 2, 3}
+	for i, e := range errs {
+		if e.Index != wantIndexes[i] {
+			t.Errorf("errs[%d].Index = %d, want %d", i, e.Index, wantIndexes[i])
+		}
+		if e.Err == nil || !strings.HasPrefix(e.Error(), "accounts[") {
+			t.Errorf("unexpected error %v", e)
+		}
+		if strings.Contains(e.Error(), "secret") || strings.Contains(e.Account, "secret") {
+			t.Errorf("password should be masked: %v / %s", e, e.Account)
+		}
+	}
+
+	if errs := services.ValidateAccounts([]string{"user1:pass1"}); errs != nil {
+		t.Errorf("expected nil for valid accounts, got %v", errs)
+	}
+}
+
+func TestDownloadSync_RejectsInvalidAccounts(t *testing.T) {
+	grpcSvc := services.NewDownloadServiceGRPCWithService(services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{}))
+
+	_, err := grpcSvc.DownloadSync(context.Background(), &pb.DownloadRequest{
+		Accounts: []string{"user1:pass1", ":secret"},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+	msg := status.Convert(err).Message()
+	if !strings.Contains(msg, "accounts[1]") || strings.Contains(msg, "secret") {
+		t.Errorf("unexpected message %q", msg)
+	}
+
+	if _, err := grpcSvc.DownloadSync(context.Background(), &pb.DownloadRequest{Accounts: []string{"user1:pass1"}}); err != nil {
+		t.Errorf("valid accounts should be accepted: %v", err)
+	}
+}
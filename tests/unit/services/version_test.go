@@ -0,0 +1,42 @@
+package services_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/buildinfo"
+	pb "github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestGetVersion_ReportsBuildInfo(t *testing.T) {
+	version := buildinfo.Version
+	buildinfo.Version = "v1.2.3"
+	t.Cleanup(func() { buildinfo.Version = version })
+
+	grpcSvc := services.NewDownloadServiceGRPCWithService(services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{}))
+	resp, err := grpcSvc.GetVersion(context.Background(), &pb.GetVersionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Version != "v1.2.3" || resp.GoVersion != runtime.Version() {
+		t.Errorf("unexpected version info: %+v", resp)
+	}
+	if resp.BuildTime != "unknown" || resp.Commit == "" {
+		t.Errorf("expected defaults for values not set by the linker, got %+v", resp)
+	}
+	if resp.PlaywrightDriverVersion == "" {
+		t.Error("expected the Playwright driver version")
+	}
+}
+
+func TestGetVersion_EmptyLinkerValuesUseDefaults(t *testing.T) {
+	version := buildinfo.Version
+	buildinfo.Version = ""
+	t.Cleanup(func() { buildinfo.Version = version })
+
+	if got := buildinfo.Get().Version; got != "dev" {
+		t.Errorf("expected dev, got %q", got)
+	}
+}
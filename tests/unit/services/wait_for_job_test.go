@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/pb"
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newWaitService(t *testing.T, gate chan struct{}) (*services.DownloadService, *services.DownloadServiceGRPC) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	svc := services.NewDownloadServiceWithFactory(nil, nil, &fakeScraperFactory{CSV: threeRowCSV, Gate: gate})
+	svc.AccountDelay = 0
+	t.Cleanup(func() { svc.Shutdown(context.Background()) })
+	return svc, services.NewDownloadServiceGRPCWithService(svc)
+}
+
+func TestWaitForJob_ReturnsWhenJobFinishes(t *testing.T) {
+	gate := make(chan struct{})
+	svc, grpcSvc := newWaitService(t, gate)
+	svc.ProcessAsync(context.Background(), "wait-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+
+	done := make(chan *pb.JobStatus, 1)
+	go func() {
+		resp, err := grpcSvc.WaitForJob(context.Background(), &pb.WaitForJobRequest{JobId: "wait-job", LastStatus: "processing"})
+		if err != nil {
+			t.Error(err)
+		}
+		done <- resp
+	}()
+
+	select {
+	case resp := <-done:
+		t.Fatalf("expected WaitForJob to block while the job is processing, got %s", resp.GetStatus())
+	case <-time.After(200 * time.Millisecond):
+	}
+	close(gate)
+
+	select {
+	case resp := <-done:
+		if resp.GetStatus() != "completed" || resp.GetTotalRecords() != 3 {
+			t.Errorf("expected the completed job, got %s with %d records", resp.GetStatus(), resp.GetTotalRecords())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitForJob did not return after the job finished")
+	}
+}
+
+func TestWaitForJob_ReturnsOnPause(t *testing.T) {
+	gate := make(chan struct{})
+	svc, grpcSvc := newWaitService(t, gate)
+	defer close(gate)
+	svc.ProcessAsync(context.Background(), "pause-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+
+	time.AfterFunc(100*time.Millisecond, func() { svc.PauseJob("pause-job") })
+	resp, err := grpcSvc.WaitForJob(context.Background(), &pb.WaitForJobRequest{JobId: "pause-job"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != "paused" {
+		t.Errorf("expected the status change to paused, got %s", resp.Status)
+	}
+}
+
+func TestWaitForJob_ReturnsCurrentStatusBeforeDeadline(t *testing.T) {
+	gate := make(chan struct{})
+	svc, grpcSvc := newWaitService(t, gate)
+	defer close(gate)
+	svc.ProcessAsync(context.Background(), "slow-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+	resp, err := grpcSvc.WaitForJob(ctx, &pb.WaitForJobRequest{JobId: "slow-job"})
+	if err != nil {
+		t.Fatalf("expected the latest status instead of an error, got %v", err)
+	}
+	if resp.Status != "processing" || ctx.Err() != nil {
+		t.Errorf("expected processing before the deadline, got %s (ctx err %v)", resp.Status, ctx.Err())
+	}
+}
+
+func TestWaitForJob_ReturnsImmediately(t *testing.T) {
+	gate := make(chan struct{})
+	svc, grpcSvc := newWaitService(t, gate)
+	svc.ProcessAsync(context.Background(), "known-job", []string{"user1:pass1"}, "2024-01-01", "2024-01-31")
+
+	// the status already differs from the one the client saw last
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	resp, err := grpcSvc.WaitForJob(ctx, &pb.WaitForJobRequest{JobId: "known-job", LastStatus: "pending"})
+	cancel()
+	if err != nil || resp.Status != "processing" {
+		t.Errorf("expected processing right away, got %v %v", resp.GetStatus(), err)
+	}
+
+	close(gate)
+	waitForJob(t, svc, "known-job", 5*time.Second)
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	started := time.Now()
+	if resp, err := grpcSvc.WaitForJob(ctx, &pb.WaitForJobRequest{JobId: "known-job", LastStatus: "completed"}); err != nil || resp.Status != "completed" {
+		t.Errorf("expected the finished job, got %v %v", resp.GetStatus(), err)
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("expected a finished job not to wait, took %v", elapsed)
+	}
+
+	if _, err := grpcSvc.WaitForJob(context.Background(), &pb.WaitForJobRequest{JobId: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yhonda-ohishi-pub-dev/etc_meisai_scraper/src/services"
+)
+
+func TestProcessAsync_RunsAccountsConcurrently(t *testing.T) {
+	factory := &fakeScraperFactory{Delay: 200 * time.Millisecond}
+	svc := services.NewDownloadServiceWithFactory(nil, nil, factory)
+	svc.MaxConcurrency = 3
+
+	svc.ProcessAsync(context.Background(), "pool-job", []string{"a:1", "b:2", "c:3"}, "2024-01-01", "2024-01-31")
+	job := waitForJob(t, svc, "pool-job", 10*time.Second)
+
+	if job.Progress != 100 {
+		t.Errorf("expected progress 100, got %d", job.Progress)
+	}
+	if factory.maxActive != 3 {
+		t.Errorf("expected 3 concurrent downloads, got %d", factory.maxActive)
+	}
+
+	folder := factory.configs[0].SessionFolder
+	for _, cfg := range factory.configs {
+		if cfg.SessionFolder != folder {
+			t.Errorf("expected shared session folder %s, got %s", folder, cfg.SessionFolder)
+		}
+	}
+}
+
+func TestGetMaxConcurrency(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"", 1},
+		{"4", 4},
+		{"0", 1},
+		{"abc", 1},
+	}
+	for _, tt := range tests {
+		t.Setenv("ETC_MAX_CONCURRENCY", tt.value)
+		if got := services.GetMaxConcurrency(); got != tt.want {
+			t.Errorf("ETC_MAX_CONCURRENCY=%q: got %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}